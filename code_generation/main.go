@@ -32,13 +32,22 @@ func (m *GenerateDataMethod) BlockForMultiThreaded() bool {
 		m.Name == "FPDF_RenderPageBitmap_Start" ||
 		m.Name == "FPDF_RenderPage_Continue" ||
 		m.Name == "FPDF_RenderPage_Close" ||
-		m.Name == "FPDFDOC_InitFormFillEnvironment" ||
 		strings.HasPrefix(m.Name, "FPDFAvail_") {
 		return true
 	}
 	return false
 }
 
+// CustomRPC returns whether the RPC client and server of the method are
+// implemented by hand in the commons package, because the request can't be
+// serialized as is.
+func (m *GenerateDataMethod) CustomRPC() bool {
+	if m.Name == "FPDFDOC_InitFormFillEnvironment" {
+		return true
+	}
+	return false
+}
+
 type GenerateData struct {
 	Methods []GenerateDataMethod
 }
@@ -0,0 +1,856 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/klippa-app/go-pdfium"
+	"github.com/klippa-app/go-pdfium/enums"
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// FormSessionConfig configures a FormSession.
+type FormSessionConfig struct {
+	DPI      int // The resolution of the pixel coordinates of the events and of the rendered tiles, 72 when 0.
+	TileSize int // The width and height of the rendered tiles in pixels, 256 when 0.
+}
+
+// FormSessionTile is a rendered part of a page.
+type FormSessionTile struct {
+	Page  int         // The page of the tile (0-index based).
+	X     int         // The left position of the tile on the page in pixels.
+	Y     int         // The top position of the tile on the page in pixels.
+	Image *image.RGBA // The rendered tile, tiles at the right and bottom edge of a page can be smaller than the tile size.
+}
+
+// FormSessionUpdate is the state of a FormSession after an event.
+type FormSessionUpdate struct {
+	Tiles        []FormSessionTile // The re-rendered tiles of the regions that PDFium invalidated, sorted by page and position.
+	Cursor       enums.FXCT        // The cursor that PDFium asked for.
+	FocusedText  string            // The text of the focused form field. Only filled when the experimental API is available.
+	SelectedText string            // The selected text of the focused form field.
+	CanUndo      bool              // Whether the focused form field can undo.
+	CanRedo      bool              // Whether the focused form field can redo.
+	Changed      bool              // Whether the value of a form field changed since the previous update.
+	URIActions   []string          // The URIs that the document asked to navigate to since the previous update.
+	NamedActions []string          // The named actions that the document asked to execute since the previous update.
+}
+
+// formSessionPage is a page that is loaded into the form fill environment.
+type formSessionPage struct {
+	page   references.FPDF_PAGE
+	width  int // The width in pixels.
+	height int // The height in pixels.
+}
+
+// formSessionTimer is a timer that PDFium installed with FFI_SetTimer.
+type formSessionTimer struct {
+	elapse    time.Duration
+	next      time.Time
+	timerFunc func(idEvent int)
+}
+
+// formSessionRect is a region that PDFium invalidated, in page coordinates.
+type formSessionRect struct {
+	page                     references.FPDF_PAGE
+	left, top, right, bottom float64
+}
+
+// FormSession is a headless interactive form session on a document, for
+// building a server rendered form editor. Events in pixel coordinates, like
+// the ones of a browser, are translated into page coordinates and sent to the
+// form fill environment of PDFium. The regions that PDFium invalidates while
+// handling an event are re-rendered as tiles, and returned with the focus and
+// undo state. Keyboard events are sent to the page of the last mouse event.
+//
+// Timers of PDFium, like the one that makes the caret blink, are not run in
+// the background, call Tick to fire the timers that elapsed.
+// This works for single-threaded and multi-threaded instances.
+type FormSession struct {
+	instance   pdfium.Pdfium
+	document   references.FPDF_DOCUMENT
+	formHandle references.FPDF_FORMHANDLE
+	dpi        int
+	tileSize   int
+
+	// lock ensures only one event is handled at a time.
+	lock      sync.Mutex
+	pages     map[int]*formSessionPage
+	focusPage *int
+	closed    bool
+
+	// callbackLock protects the state that is changed by the callbacks of
+	// PDFium, which can be called from another goroutine in multi-threaded
+	// usage while lock is held.
+	callbackLock sync.Mutex
+	pageIndexes  map[references.FPDF_PAGE]int
+	rotations    map[references.FPDF_PAGE]enums.FPDF_PAGE_ROTATION
+	invalidated  []formSessionRect
+	cursor       enums.FXCT
+	changed      bool
+	uriActions   []string
+	namedActions []string
+	timers       map[int]*formSessionTimer
+	nextTimerID  int
+}
+
+// NewFormSession initializes the form fill environment of a document and
+// returns a session to send events to. The session must be closed with Close,
+// before the document is closed.
+func NewFormSession(instance pdfium.Pdfium, document references.FPDF_DOCUMENT, config FormSessionConfig) (*FormSession, error) {
+	if instance == nil {
+		return nil, errors.New("instance not given")
+	}
+
+	if config.DPI < 0 {
+		return nil, errors.New("DPI can't be negative")
+	}
+
+	if config.TileSize < 0 {
+		return nil, errors.New("tile size can't be negative")
+	}
+
+	session := &FormSession{
+		instance:    instance,
+		document:    document,
+		dpi:         config.DPI,
+		tileSize:    config.TileSize,
+		pages:       map[int]*formSessionPage{},
+		pageIndexes: map[references.FPDF_PAGE]int{},
+		rotations:   map[references.FPDF_PAGE]enums.FPDF_PAGE_ROTATION{},
+		timers:      map[int]*formSessionTimer{},
+	}
+
+	if session.dpi == 0 {
+		session.dpi = 72
+	}
+
+	if session.tileSize == 0 {
+		session.tileSize = 256
+	}
+
+	formFillEnvironment, err := instance.FPDFDOC_InitFormFillEnvironment(&requests.FPDFDOC_InitFormFillEnvironment{
+		Document:     document,
+		FormFillInfo: session.getFormFillInfo(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	session.formHandle = formFillEnvironment.FormHandle
+
+	return session, nil
+}
+
+// getFormFillInfo returns the callbacks of the session for the form fill
+// environment. The callbacks are called while the instance is busy, so they
+// can't call the instance.
+func (s *FormSession) getFormFillInfo() structs.FPDF_FORMFILLINFO {
+	return structs.FPDF_FORMFILLINFO{
+		FFI_Invalidate: func(page references.FPDF_PAGE, left, top, right, bottom float64) {
+			s.callbackLock.Lock()
+			defer s.callbackLock.Unlock()
+			s.invalidated = append(s.invalidated, formSessionRect{page: page, left: left, top: top, right: right, bottom: bottom})
+		},
+		FFI_SetCursor: func(cursorType enums.FXCT) {
+			s.callbackLock.Lock()
+			defer s.callbackLock.Unlock()
+			s.cursor = cursorType
+		},
+		FFI_SetTimer: func(elapse int, timerFunc func(idEvent int)) int {
+			s.callbackLock.Lock()
+			defer s.callbackLock.Unlock()
+
+			// Timer IDs can't be 0.
+			s.nextTimerID++
+			s.timers[s.nextTimerID] = &formSessionTimer{
+				elapse:    time.Duration(elapse) * time.Millisecond,
+				next:      time.Now().Add(time.Duration(elapse) * time.Millisecond),
+				timerFunc: timerFunc,
+			}
+			return s.nextTimerID
+		},
+		FFI_KillTimer: func(timerID int) {
+			s.callbackLock.Lock()
+			defer s.callbackLock.Unlock()
+			delete(s.timers, timerID)
+		},
+		FFI_GetLocalTime: func() structs.FPDF_SYSTEMTIME {
+			now := time.Now()
+			return structs.FPDF_SYSTEMTIME{
+				Year:         uint16(now.Year() - 1900),
+				Month:        uint16(now.Month() - 1),
+				DayOfWeek:    uint16(now.Weekday()),
+				Day:          uint16(now.Day()),
+				Hour:         uint16(now.Hour()),
+				Minute:       uint16(now.Minute()),
+				Second:       uint16(now.Second()),
+				Milliseconds: uint16(now.Nanosecond() / int(time.Millisecond)),
+			}
+		},
+		FFI_OnChange: func() {
+			s.callbackLock.Lock()
+			defer s.callbackLock.Unlock()
+			s.changed = true
+		},
+		FFI_GetPage: func(document references.FPDF_DOCUMENT, index int) *references.FPDF_PAGE {
+			// Pages can't be loaded while the instance is busy, only return
+			// the pages that the session loaded.
+			s.callbackLock.Lock()
+			defer s.callbackLock.Unlock()
+			for page, pageIndex := range s.pageIndexes {
+				if pageIndex == index {
+					return &page
+				}
+			}
+			return nil
+		},
+		FFI_GetRotation: func(page references.FPDF_PAGE) enums.FPDF_PAGE_ROTATION {
+			// The rotation can't be requested while the instance is busy, it's
+			// read when the session loads the page.
+			s.callbackLock.Lock()
+			defer s.callbackLock.Unlock()
+			return s.rotations[page]
+		},
+		FFI_ExecuteNamedAction: func(namedAction string) {
+			s.callbackLock.Lock()
+			defer s.callbackLock.Unlock()
+			s.namedActions = append(s.namedActions, namedAction)
+		},
+		FFI_DoURIAction: func(bsURI string) {
+			s.callbackLock.Lock()
+			defer s.callbackLock.Unlock()
+			s.uriActions = append(s.uriActions, bsURI)
+		},
+	}
+}
+
+// PageSize returns the size of a page in pixels.
+func (s *FormSession) PageSize(page int) (int, int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sessionPage, err := s.loadPage(page)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return sessionPage.width, sessionPage.height, nil
+}
+
+// RenderPage renders a complete page with its form fields, for the initial
+// display of a page.
+func (s *FormSession) RenderPage(page int) (*image.RGBA, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sessionPage, err := s.loadPage(page)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.renderTile(sessionPage, image.Rect(0, 0, sessionPage.width, sessionPage.height))
+}
+
+// MouseMove sends a mouse move event at pixel position x, y of a page.
+func (s *FormSession) MouseMove(page, x, y int, modifiers enums.FWL_EVENTFLAG) (*FormSessionUpdate, error) {
+	return s.handleMouseEvent(page, x, y, func(pageRef references.FPDF_PAGE, pageX, pageY float64) error {
+		_, err := s.instance.FORM_OnMouseMove(&requests.FORM_OnMouseMove{
+			FormHandle: s.formHandle,
+			Page:       requests.Page{ByReference: &pageRef},
+			Modifier:   int(modifiers),
+			PageX:      pageX,
+			PageY:      pageY,
+		})
+		return err
+	})
+}
+
+// MouseDown sends a left mouse button down event at pixel position x, y of a
+// page.
+func (s *FormSession) MouseDown(page, x, y int, modifiers enums.FWL_EVENTFLAG) (*FormSessionUpdate, error) {
+	return s.handleMouseEvent(page, x, y, func(pageRef references.FPDF_PAGE, pageX, pageY float64) error {
+		_, err := s.instance.FORM_OnLButtonDown(&requests.FORM_OnLButtonDown{
+			FormHandle: s.formHandle,
+			Page:       requests.Page{ByReference: &pageRef},
+			Modifier:   int(modifiers),
+			PageX:      pageX,
+			PageY:      pageY,
+		})
+		return err
+	})
+}
+
+// MouseUp sends a left mouse button up event at pixel position x, y of a page.
+func (s *FormSession) MouseUp(page, x, y int, modifiers enums.FWL_EVENTFLAG) (*FormSessionUpdate, error) {
+	return s.handleMouseEvent(page, x, y, func(pageRef references.FPDF_PAGE, pageX, pageY float64) error {
+		_, err := s.instance.FORM_OnLButtonUp(&requests.FORM_OnLButtonUp{
+			FormHandle: s.formHandle,
+			Page:       requests.Page{ByReference: &pageRef},
+			Modifier:   int(modifiers),
+			PageX:      pageX,
+			PageY:      pageY,
+		})
+		return err
+	})
+}
+
+// DoubleClick sends a left mouse button double click event at pixel position
+// x, y of a page.
+func (s *FormSession) DoubleClick(page, x, y int, modifiers enums.FWL_EVENTFLAG) (*FormSessionUpdate, error) {
+	return s.handleMouseEvent(page, x, y, func(pageRef references.FPDF_PAGE, pageX, pageY float64) error {
+		_, err := s.instance.FORM_OnLButtonDoubleClick(&requests.FORM_OnLButtonDoubleClick{
+			FormHandle: s.formHandle,
+			Page:       requests.Page{ByReference: &pageRef},
+			Modifier:   int(modifiers),
+			PageX:      pageX,
+			PageY:      pageY,
+		})
+		return err
+	})
+}
+
+// KeyDown sends a key down event to the page of the last mouse event.
+func (s *FormSession) KeyDown(keyCode enums.FWL_VKEYCODE, modifiers enums.FWL_EVENTFLAG) (*FormSessionUpdate, error) {
+	return s.handleKeyboardEvent(func(pageRef references.FPDF_PAGE) error {
+		_, err := s.instance.FORM_OnKeyDown(&requests.FORM_OnKeyDown{
+			FormHandle: s.formHandle,
+			Page:       requests.Page{ByReference: &pageRef},
+			NKeyCode:   keyCode,
+			Modifier:   modifiers,
+		})
+		return err
+	})
+}
+
+// KeyUp sends a key up event to the page of the last mouse event.
+func (s *FormSession) KeyUp(keyCode enums.FWL_VKEYCODE, modifiers enums.FWL_EVENTFLAG) (*FormSessionUpdate, error) {
+	return s.handleKeyboardEvent(func(pageRef references.FPDF_PAGE) error {
+		_, err := s.instance.FORM_OnKeyUp(&requests.FORM_OnKeyUp{
+			FormHandle: s.formHandle,
+			Page:       requests.Page{ByReference: &pageRef},
+			NKeyCode:   keyCode,
+			Modifier:   modifiers,
+		})
+		return err
+	})
+}
+
+// Char sends a typed character to the page of the last mouse event.
+func (s *FormSession) Char(char rune, modifiers enums.FWL_EVENTFLAG) (*FormSessionUpdate, error) {
+	return s.handleKeyboardEvent(func(pageRef references.FPDF_PAGE) error {
+		_, err := s.instance.FORM_OnChar(&requests.FORM_OnChar{
+			FormHandle: s.formHandle,
+			Page:       requests.Page{ByReference: &pageRef},
+			NChar:      int(char),
+			Modifier:   modifiers,
+		})
+		return err
+	})
+}
+
+// ReplaceSelection replaces the selected text of the focused form field, or
+// inserts the text at the caret, for pasting text.
+func (s *FormSession) ReplaceSelection(text string) (*FormSessionUpdate, error) {
+	return s.handleKeyboardEvent(func(pageRef references.FPDF_PAGE) error {
+		_, err := s.instance.FORM_ReplaceSelection(&requests.FORM_ReplaceSelection{
+			FormHandle: s.formHandle,
+			Page:       requests.Page{ByReference: &pageRef},
+			Text:       text,
+		})
+		return err
+	})
+}
+
+// Undo undoes the last change of the focused form field.
+func (s *FormSession) Undo() (*FormSessionUpdate, error) {
+	return s.handleKeyboardEvent(func(pageRef references.FPDF_PAGE) error {
+		_, err := s.instance.FORM_Undo(&requests.FORM_Undo{
+			FormHandle: s.formHandle,
+			Page:       requests.Page{ByReference: &pageRef},
+		})
+		return err
+	})
+}
+
+// Redo redoes the last undone change of the focused form field.
+func (s *FormSession) Redo() (*FormSessionUpdate, error) {
+	return s.handleKeyboardEvent(func(pageRef references.FPDF_PAGE) error {
+		_, err := s.instance.FORM_Redo(&requests.FORM_Redo{
+			FormHandle: s.formHandle,
+			Page:       requests.Page{ByReference: &pageRef},
+		})
+		return err
+	})
+}
+
+// Tick fires the timers of PDFium that elapsed, and returns the update. Call
+// this regularly to make the caret blink.
+func (s *FormSession) Tick() (*FormSessionUpdate, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return nil, errors.New("form session is closed")
+	}
+
+	now := time.Now()
+	s.callbackLock.Lock()
+	timerIDs := []int{}
+	for timerID, timer := range s.timers {
+		if !timer.next.After(now) {
+			timerIDs = append(timerIDs, timerID)
+		}
+	}
+	sort.Ints(timerIDs)
+	s.callbackLock.Unlock()
+
+	// Timer functions call PDFium, which can call the callbacks. They take the
+	// lock of the instance like the other calls to the instance, so they are
+	// serialized with the calls of other users of the instance.
+	for _, timerID := range timerIDs {
+		s.callbackLock.Lock()
+		timer, ok := s.timers[timerID]
+		if ok {
+			timer.next = now.Add(timer.elapse)
+		}
+		s.callbackLock.Unlock()
+
+		if ok {
+			timer.timerFunc(timerID)
+		}
+	}
+
+	return s.getUpdate()
+}
+
+// Close closes the pages of the session and exits the form fill environment.
+func (s *FormSession) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return errors.New("form session is already closed")
+	}
+	s.closed = true
+
+	pageIndexes := make([]int, 0, len(s.pages))
+	for pageIndex := range s.pages {
+		pageIndexes = append(pageIndexes, pageIndex)
+	}
+	sort.Ints(pageIndexes)
+
+	for _, pageIndex := range pageIndexes {
+		page := s.pages[pageIndex].page
+		_, err := s.instance.FORM_OnBeforeClosePage(&requests.FORM_OnBeforeClosePage{
+			Page:       requests.Page{ByReference: &page},
+			FormHandle: s.formHandle,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = s.instance.FPDF_ClosePage(&requests.FPDF_ClosePage{
+			Page: page,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := s.instance.FPDFDOC_ExitFormFillEnvironment(&requests.FPDFDOC_ExitFormFillEnvironment{
+		FormHandle: s.formHandle,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// handleMouseEvent translates pixel position x, y of a page into page
+// coordinates and sends the event.
+func (s *FormSession) handleMouseEvent(page, x, y int, sendEvent func(pageRef references.FPDF_PAGE, pageX, pageY float64) error) (*FormSessionUpdate, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return nil, errors.New("form session is closed")
+	}
+
+	sessionPage, err := s.loadPage(page)
+	if err != nil {
+		return nil, err
+	}
+
+	pagePosition, err := s.instance.FPDF_DeviceToPage(&requests.FPDF_DeviceToPage{
+		Page:    requests.Page{ByReference: &sessionPage.page},
+		SizeX:   sessionPage.width,
+		SizeY:   sessionPage.height,
+		Rotate:  enums.FPDF_PAGE_ROTATION_NONE,
+		DeviceX: x,
+		DeviceY: y,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sendEvent(sessionPage.page, pagePosition.PageX, pagePosition.PageY); err != nil {
+		return nil, err
+	}
+
+	s.focusPage = &page
+
+	return s.getUpdate()
+}
+
+// handleKeyboardEvent sends an event to the page of the last mouse event. No
+// event is sent when there was no mouse event yet, since nothing can have
+// focus.
+func (s *FormSession) handleKeyboardEvent(sendEvent func(pageRef references.FPDF_PAGE) error) (*FormSessionUpdate, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return nil, errors.New("form session is closed")
+	}
+
+	if s.focusPage != nil {
+		if err := sendEvent(s.pages[*s.focusPage].page); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.getUpdate()
+}
+
+// loadPage loads a page into the form fill environment, when it isn't loaded
+// yet.
+func (s *FormSession) loadPage(page int) (*formSessionPage, error) {
+	if s.closed {
+		return nil, errors.New("form session is closed")
+	}
+
+	if sessionPage, ok := s.pages[page]; ok {
+		return sessionPage, nil
+	}
+
+	loadedPage, err := s.instance.FPDF_LoadPage(&requests.FPDF_LoadPage{
+		Document: s.document,
+		Index:    page,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize, err := s.instance.GetPageSize(&requests.GetPageSize{
+		Page: requests.Page{ByReference: &loadedPage.Page},
+	})
+	if err != nil {
+		s.instance.FPDF_ClosePage(&requests.FPDF_ClosePage{Page: loadedPage.Page})
+		return nil, err
+	}
+
+	pageRotation, err := s.instance.FPDFPage_GetRotation(&requests.FPDFPage_GetRotation{
+		Page: requests.Page{ByReference: &loadedPage.Page},
+	})
+	if err != nil {
+		s.instance.FPDF_ClosePage(&requests.FPDF_ClosePage{Page: loadedPage.Page})
+		return nil, err
+	}
+
+	// PDFium can ask for the rotation while the page is being loaded into the
+	// form fill environment.
+	s.callbackLock.Lock()
+	s.rotations[loadedPage.Page] = pageRotation.PageRotation
+	s.callbackLock.Unlock()
+
+	_, err = s.instance.FORM_OnAfterLoadPage(&requests.FORM_OnAfterLoadPage{
+		Page:       requests.Page{ByReference: &loadedPage.Page},
+		FormHandle: s.formHandle,
+	})
+	if err != nil {
+		s.callbackLock.Lock()
+		delete(s.rotations, loadedPage.Page)
+		s.callbackLock.Unlock()
+		s.instance.FPDF_ClosePage(&requests.FPDF_ClosePage{Page: loadedPage.Page})
+		return nil, err
+	}
+
+	sessionPage := &formSessionPage{
+		page:   loadedPage.Page,
+		width:  int(math.Round(pageSize.Width * float64(s.dpi) / 72)),
+		height: int(math.Round(pageSize.Height * float64(s.dpi) / 72)),
+	}
+	s.pages[page] = sessionPage
+
+	s.callbackLock.Lock()
+	s.pageIndexes[loadedPage.Page] = page
+	s.callbackLock.Unlock()
+
+	return sessionPage, nil
+}
+
+// getUpdate renders the invalidated regions and returns the state of the
+// session.
+func (s *FormSession) getUpdate() (*FormSessionUpdate, error) {
+	s.callbackLock.Lock()
+	invalidated := s.invalidated
+	update := &FormSessionUpdate{
+		Tiles:        []FormSessionTile{},
+		Cursor:       s.cursor,
+		Changed:      s.changed,
+		URIActions:   s.uriActions,
+		NamedActions: s.namedActions,
+	}
+	s.invalidated = nil
+	s.changed = false
+	s.uriActions = nil
+	s.namedActions = nil
+	s.callbackLock.Unlock()
+
+	tiles, err := s.getInvalidatedTiles(invalidated)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tile := range tiles {
+		sessionPage := s.pages[tile.page]
+		tileImage, err := s.renderTile(sessionPage, tile.rect)
+		if err != nil {
+			return nil, err
+		}
+
+		update.Tiles = append(update.Tiles, FormSessionTile{
+			Page:  tile.page,
+			X:     tile.rect.Min.X,
+			Y:     tile.rect.Min.Y,
+			Image: tileImage,
+		})
+	}
+
+	if s.focusPage == nil {
+		return update, nil
+	}
+
+	page := requests.Page{ByReference: &s.pages[*s.focusPage].page}
+
+	focusedText, err := s.instance.FORM_GetFocusedText(&requests.FORM_GetFocusedText{
+		FormHandle: s.formHandle,
+		Page:       page,
+	})
+	if err == nil {
+		update.FocusedText = focusedText.FocusedText
+	} else if err.Error() != pdfium_errors.ErrExperimentalUnsupported.Error() {
+		return nil, err
+	}
+
+	selectedText, err := s.instance.FORM_GetSelectedText(&requests.FORM_GetSelectedText{
+		FormHandle: s.formHandle,
+		Page:       page,
+	})
+	if err != nil {
+		return nil, err
+	}
+	update.SelectedText = selectedText.SelectedText
+
+	canUndo, err := s.instance.FORM_CanUndo(&requests.FORM_CanUndo{
+		FormHandle: s.formHandle,
+		Page:       page,
+	})
+	if err != nil {
+		return nil, err
+	}
+	update.CanUndo = canUndo.CanUndo
+
+	canRedo, err := s.instance.FORM_CanRedo(&requests.FORM_CanRedo{
+		FormHandle: s.formHandle,
+		Page:       page,
+	})
+	if err != nil {
+		return nil, err
+	}
+	update.CanRedo = canRedo.CanRedo
+
+	return update, nil
+}
+
+// formSessionTileRect is a tile that needs to be rendered.
+type formSessionTileRect struct {
+	page int
+	rect image.Rectangle
+}
+
+// getInvalidatedTiles returns the tiles that overlap with the invalidated
+// regions, sorted by page and position.
+func (s *FormSession) getInvalidatedTiles(invalidated []formSessionRect) ([]formSessionTileRect, error) {
+	type tileKey struct {
+		page, x, y int
+	}
+	tileKeys := map[tileKey]bool{}
+
+	for _, rect := range invalidated {
+		s.callbackLock.Lock()
+		pageIndex, ok := s.pageIndexes[rect.page]
+		s.callbackLock.Unlock()
+		if !ok {
+			continue
+		}
+
+		sessionPage := s.pages[pageIndex]
+		deviceRect := image.Rectangle{}
+		for i, corner := range [][2]float64{{rect.left, rect.top}, {rect.right, rect.bottom}} {
+			devicePosition, err := s.instance.FPDF_PageToDevice(&requests.FPDF_PageToDevice{
+				Page:   requests.Page{ByReference: &sessionPage.page},
+				SizeX:  sessionPage.width,
+				SizeY:  sessionPage.height,
+				Rotate: enums.FPDF_PAGE_ROTATION_NONE,
+				PageX:  corner[0],
+				PageY:  corner[1],
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			point := image.Pt(devicePosition.DeviceX, devicePosition.DeviceY)
+			if i == 0 {
+				deviceRect = image.Rectangle{Min: point, Max: point}
+			} else {
+				deviceRect = deviceRect.Union(image.Rectangle{Min: point, Max: point}.Canon())
+			}
+		}
+
+		// Include the pixels on the edges, the conversion rounds positions.
+		deviceRect = image.Rect(deviceRect.Min.X-1, deviceRect.Min.Y-1, deviceRect.Max.X+1, deviceRect.Max.Y+1)
+		deviceRect = deviceRect.Canon().Intersect(image.Rect(0, 0, sessionPage.width, sessionPage.height))
+		if deviceRect.Empty() {
+			continue
+		}
+
+		for y := deviceRect.Min.Y / s.tileSize; y <= (deviceRect.Max.Y-1)/s.tileSize; y++ {
+			for x := deviceRect.Min.X / s.tileSize; x <= (deviceRect.Max.X-1)/s.tileSize; x++ {
+				tileKeys[tileKey{page: pageIndex, x: x, y: y}] = true
+			}
+		}
+	}
+
+	keys := make([]tileKey, 0, len(tileKeys))
+	for key := range tileKeys {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].page != keys[j].page {
+			return keys[i].page < keys[j].page
+		}
+		if keys[i].y != keys[j].y {
+			return keys[i].y < keys[j].y
+		}
+		return keys[i].x < keys[j].x
+	})
+
+	tiles := make([]formSessionTileRect, len(keys))
+	for i, key := range keys {
+		sessionPage := s.pages[key.page]
+		tiles[i] = formSessionTileRect{
+			page: key.page,
+			rect: image.Rect(key.x*s.tileSize, key.y*s.tileSize, (key.x+1)*s.tileSize, (key.y+1)*s.tileSize).Intersect(image.Rect(0, 0, sessionPage.width, sessionPage.height)),
+		}
+	}
+
+	return tiles, nil
+}
+
+// renderTile renders a part of a page with its form fields.
+func (s *FormSession) renderTile(sessionPage *formSessionPage, rect image.Rectangle) (*image.RGBA, error) {
+	bitmap, err := s.instance.FPDFBitmap_Create(&requests.FPDFBitmap_Create{
+		Width:  rect.Dx(),
+		Height: rect.Dy(),
+		Alpha:  0,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.instance.FPDFBitmap_Destroy(&requests.FPDFBitmap_Destroy{
+		Bitmap: bitmap.Bitmap,
+	})
+
+	_, err = s.instance.FPDFBitmap_FillRect(&requests.FPDFBitmap_FillRect{
+		Bitmap: bitmap.Bitmap,
+		Width:  rect.Dx(),
+		Height: rect.Dy(),
+		Color:  0xFFFFFFFF,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Render the complete page, moved so that the tile is in the bitmap.
+	_, err = s.instance.FPDF_RenderPageBitmap(&requests.FPDF_RenderPageBitmap{
+		Bitmap: bitmap.Bitmap,
+		Page:   requests.Page{ByReference: &sessionPage.page},
+		StartX: -rect.Min.X,
+		StartY: -rect.Min.Y,
+		SizeX:  sessionPage.width,
+		SizeY:  sessionPage.height,
+		Rotate: enums.FPDF_PAGE_ROTATION_NONE,
+		Flags:  enums.FPDF_RENDER_FLAG_ANNOT | enums.FPDF_RENDER_FLAG_REVERSE_BYTE_ORDER,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.instance.FPDF_FFLDraw(&requests.FPDF_FFLDraw{
+		FormHandle: s.formHandle,
+		Bitmap:     bitmap.Bitmap,
+		Page:       requests.Page{ByReference: &sessionPage.page},
+		StartX:     -rect.Min.X,
+		StartY:     -rect.Min.Y,
+		SizeX:      sessionPage.width,
+		SizeY:      sessionPage.height,
+		Rotate:     enums.FPDF_PAGE_ROTATION_NONE,
+		Flags:      enums.FPDF_RENDER_FLAG_ANNOT | enums.FPDF_RENDER_FLAG_REVERSE_BYTE_ORDER,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stride, err := s.instance.FPDFBitmap_GetStride(&requests.FPDFBitmap_GetStride{
+		Bitmap: bitmap.Bitmap,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	buffer, err := s.instance.FPDFBitmap_GetBuffer(&requests.FPDFBitmap_GetBuffer{
+		Bitmap: bitmap.Bitmap,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(buffer.Buffer) < stride.Stride*rect.Dy() {
+		return nil, fmt.Errorf("bitmap buffer of %d bytes is too small", len(buffer.Buffer))
+	}
+
+	// The bitmap has no alpha channel, the unused byte is set to opaque.
+	img := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	for y := 0; y < rect.Dy(); y++ {
+		row := buffer.Buffer[y*stride.Stride : y*stride.Stride+rect.Dx()*4]
+		copy(img.Pix[y*img.Stride:], row)
+		for x := 0; x < rect.Dx(); x++ {
+			img.Pix[y*img.Stride+x*4+3] = 255
+		}
+	}
+
+	return img, nil
+}
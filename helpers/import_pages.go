@@ -0,0 +1,147 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/klippa-app/go-pdfium"
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+)
+
+// ImportPagesSource describes the pages of a document that should be
+// imported by ImportPages.
+type ImportPagesSource struct {
+	Instance  pdfium.Pdfium            // The instance that holds the source document, this can be a different instance than the destination.
+	Document  references.FPDF_DOCUMENT // The source document.
+	PageRange *string                  // The pages to import, in the format "1,3,5-7" (1-index based). All pages when nil.
+	Password  *string                  // The password of the source document. Required when an encrypted source document is in another instance, because its pages are transferred as an encrypted copy.
+}
+
+// ImportPages imports pages from one or more documents into the destination
+// document, the source documents don't have to be in the same instance as the
+// destination document. When a source document lives in another instance, the
+// pages are saved into a new document in that instance, and the bytes of that
+// document are opened in the destination instance to import the pages from.
+// The pages are inserted at the given index (0-index based) in the order of the
+// sources. This works for single-threaded and multi-threaded instances.
+func ImportPages(destination pdfium.Pdfium, destinationDocument references.FPDF_DOCUMENT, index int, sources ...ImportPagesSource) error {
+	if destination == nil {
+		return errors.New("destination instance not given")
+	}
+
+	for i := range sources {
+		if sources[i].Instance == nil {
+			return fmt.Errorf("instance of source %d not given", i)
+		}
+
+		pageCountBefore, err := destination.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+			Document: destinationDocument,
+		})
+		if err != nil {
+			return err
+		}
+
+		if sources[i].Instance == destination {
+			_, err = destination.FPDF_ImportPages(&requests.FPDF_ImportPages{
+				Source:      sources[i].Document,
+				Destination: destinationDocument,
+				PageRange:   sources[i].PageRange,
+				Index:       index,
+			})
+		} else {
+			err = importPagesFromInstance(destination, destinationDocument, index, sources[i])
+		}
+		if err != nil {
+			return fmt.Errorf("could not import pages of source %d: %w", i, err)
+		}
+
+		pageCountAfter, err := destination.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+			Document: destinationDocument,
+		})
+		if err != nil {
+			return err
+		}
+
+		index += pageCountAfter.PageCount - pageCountBefore.PageCount
+	}
+
+	return nil
+}
+
+// importPagesFromInstance transfers the pages of a source document in another
+// instance into the destination document.
+func importPagesFromInstance(destination pdfium.Pdfium, destinationDocument references.FPDF_DOCUMENT, index int, source ImportPagesSource) error {
+	fileBytes, err := getImportPagesSourceBytes(source)
+	if err != nil {
+		return err
+	}
+
+	transferDocument, err := destination.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+		Data:     fileBytes,
+		Password: source.Password,
+	})
+	if err != nil {
+		// The error could come from another process, so compare the message.
+		if err.Error() == pdfium_errors.ErrPassword.Error() {
+			return errors.New("source document is encrypted, the password of the source document is required")
+		}
+		return err
+	}
+
+	defer destination.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+		Document: transferDocument.Document,
+	})
+
+	_, err = destination.FPDF_ImportPages(&requests.FPDF_ImportPages{
+		Source:      transferDocument.Document,
+		Destination: destinationDocument,
+		Index:       index,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getImportPagesSourceBytes returns the bytes of a document that only contains
+// the pages of the source, so that we don't transfer more than needed.
+func getImportPagesSourceBytes(source ImportPagesSource) (*[]byte, error) {
+	sourceDocument := source.Document
+	if source.PageRange != nil {
+		newDocument, err := source.Instance.FPDF_CreateNewDocument(&requests.FPDF_CreateNewDocument{})
+		if err != nil {
+			return nil, err
+		}
+
+		defer source.Instance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+			Document: newDocument.Document,
+		})
+
+		_, err = source.Instance.FPDF_ImportPages(&requests.FPDF_ImportPages{
+			Source:      source.Document,
+			Destination: newDocument.Document,
+			PageRange:   source.PageRange,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		sourceDocument = newDocument.Document
+	}
+
+	savedDocument, err := source.Instance.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+		Document: sourceDocument,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if savedDocument.FileBytes == nil {
+		return nil, errors.New("could not get bytes of source document")
+	}
+
+	return savedDocument.FileBytes, nil
+}
@@ -0,0 +1,455 @@
+package commons
+
+import (
+	"fmt"
+	"net/rpc"
+	"sync"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// The callbacks of FPDF_FORMFILLINFO can't be serialized, so the host serves
+// them over a connection of the plugin broker. The worker dials that
+// connection and proxies the callbacks of PDFium to the host. Timers are
+// fired by the host through the plugin connection.
+
+// FPDFDOC_InitFormFillEnvironmentRPCRequest is the request that is sent to the
+// worker instead of requests.FPDFDOC_InitFormFillEnvironment.
+type FPDFDOC_InitFormFillEnvironmentRPCRequest struct {
+	Document  references.FPDF_DOCUMENT
+	BrokerID  uint32   // The ID of the broker connection that serves the callbacks.
+	Callbacks []string // The names of the callbacks that are set.
+}
+
+type FormFillInfoRect struct {
+	Page   references.FPDF_PAGE
+	Left   float64
+	Top    float64
+	Right  float64
+	Bottom float64
+}
+
+type FormFillInfoSetCursor struct {
+	CursorType enums.FXCT
+}
+
+type FormFillInfoSetTimer struct {
+	Elapse     int
+	CallbackID int // The ID of the timer callback in the worker.
+}
+
+type FormFillInfoKillTimer struct {
+	TimerID int
+}
+
+type FormFillInfoGetPage struct {
+	Document references.FPDF_DOCUMENT
+	Index    int
+}
+
+type FormFillInfoGetCurrentPage struct {
+	Document references.FPDF_DOCUMENT
+}
+
+type FormFillInfoPage struct {
+	Page *references.FPDF_PAGE
+}
+
+type FormFillInfoGetRotation struct {
+	Page references.FPDF_PAGE
+}
+
+type FormFillInfoExecuteNamedAction struct {
+	NamedAction string
+}
+
+type FormFillInfoSetTextFieldFocus struct {
+	Value   string
+	IsFocus bool
+}
+
+type FormFillInfoDoURIAction struct {
+	URI string
+}
+
+type FormFillInfoDoGoToAction struct {
+	PageIndex int
+	ZoomMode  enums.FPDF_ZOOM_MODE
+	Pos       []float32
+}
+
+type FormFillTimerRequest struct {
+	CallbackID int // The ID of the timer callback in the worker.
+	IDEvent    int // The ID of the timer.
+}
+
+// getFormFillInfoCallbacks returns the names of the callbacks that are set.
+func getFormFillInfoCallbacks(formFillInfo *structs.FPDF_FORMFILLINFO) []string {
+	callbacks := []string{}
+	isSet := map[string]bool{
+		"Release":                formFillInfo.Release != nil,
+		"FFI_Invalidate":         formFillInfo.FFI_Invalidate != nil,
+		"FFI_OutputSelectedRect": formFillInfo.FFI_OutputSelectedRect != nil,
+		"FFI_SetCursor":          formFillInfo.FFI_SetCursor != nil,
+		"FFI_SetTimer":           formFillInfo.FFI_SetTimer != nil,
+		"FFI_KillTimer":          formFillInfo.FFI_KillTimer != nil,
+		"FFI_GetLocalTime":       formFillInfo.FFI_GetLocalTime != nil,
+		"FFI_OnChange":           formFillInfo.FFI_OnChange != nil,
+		"FFI_GetPage":            formFillInfo.FFI_GetPage != nil,
+		"FFI_GetCurrentPage":     formFillInfo.FFI_GetCurrentPage != nil,
+		"FFI_GetRotation":        formFillInfo.FFI_GetRotation != nil,
+		"FFI_ExecuteNamedAction": formFillInfo.FFI_ExecuteNamedAction != nil,
+		"FFI_SetTextFieldFocus":  formFillInfo.FFI_SetTextFieldFocus != nil,
+		"FFI_DoURIAction":        formFillInfo.FFI_DoURIAction != nil,
+		"FFI_DoGoToAction":       formFillInfo.FFI_DoGoToAction != nil,
+	}
+
+	for callback, set := range isSet {
+		if set {
+			callbacks = append(callbacks, callback)
+		}
+	}
+
+	return callbacks
+}
+
+func (g *PdfiumRPC) FPDFDOC_InitFormFillEnvironment(request *requests.FPDFDOC_InitFormFillEnvironment) (*responses.FPDFDOC_InitFormFillEnvironment, error) {
+	// Copy the callbacks, so that changes to the request after this call don't
+	// change the callbacks.
+	formFillInfo := request.FormFillInfo
+
+	brokerID := g.broker.NextId()
+	go g.broker.AcceptAndServe(brokerID, &FormFillInfoRPCServer{
+		Impl:   &formFillInfo,
+		client: g.client,
+	})
+
+	resp := &responses.FPDFDOC_InitFormFillEnvironment{}
+	err := g.client.Call("Plugin.FPDFDOC_InitFormFillEnvironment", &FPDFDOC_InitFormFillEnvironmentRPCRequest{
+		Document:  request.Document,
+		BrokerID:  brokerID,
+		Callbacks: getFormFillInfoCallbacks(&formFillInfo),
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *PdfiumRPCServer) FPDFDOC_InitFormFillEnvironment(request *FPDFDOC_InitFormFillEnvironmentRPCRequest, resp *responses.FPDFDOC_InitFormFillEnvironment) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FPDFDOC_InitFormFillEnvironment", panicError)
+		}
+	}()
+
+	conn, err := s.broker.Dial(request.BrokerID)
+	if err != nil {
+		return err
+	}
+	client := rpc.NewClient(conn)
+
+	implResp, err := s.Impl.FPDFDOC_InitFormFillEnvironment(&requests.FPDFDOC_InitFormFillEnvironment{
+		Document:     request.Document,
+		FormFillInfo: s.getFormFillInfoProxy(client, request.Callbacks),
+	})
+	if err != nil {
+		client.Close()
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+// FormFillTimer fires a timer callback of the worker, it's called by the host
+// when a timer that was installed with FFI_SetTimer elapses. The timer
+// callback takes the lock of the implementation, so it's serialized with the
+// other calls of the worker.
+func (s *PdfiumRPCServer) FormFillTimer(request *FormFillTimerRequest, resp *interface{}) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FormFillTimer", panicError)
+		}
+	}()
+
+	timerFunc := s.timers.get(request.CallbackID)
+	if timerFunc == nil {
+		return nil
+	}
+
+	timerFunc(request.IDEvent)
+	return nil
+}
+
+// getFormFillInfoProxy returns a FPDF_FORMFILLINFO with the given callbacks,
+// that calls the callbacks of the host over client. Errors of the host can't
+// be returned to PDFium, callbacks return their zero value on errors.
+func (s *PdfiumRPCServer) getFormFillInfoProxy(client *rpc.Client, callbacks []string) structs.FPDF_FORMFILLINFO {
+	isSet := map[string]bool{}
+	for _, callback := range callbacks {
+		isSet[callback] = true
+	}
+
+	// Release is always proxied, since it's the moment to close the connection.
+	formFillInfo := structs.FPDF_FORMFILLINFO{
+		Release: func() {
+			if isSet["Release"] {
+				client.Call("Plugin.Release", new(interface{}), new(interface{}))
+			}
+			client.Close()
+		},
+	}
+
+	if isSet["FFI_Invalidate"] {
+		formFillInfo.FFI_Invalidate = func(page references.FPDF_PAGE, left, top, right, bottom float64) {
+			client.Call("Plugin.FFI_Invalidate", &FormFillInfoRect{Page: page, Left: left, Top: top, Right: right, Bottom: bottom}, new(interface{}))
+		}
+	}
+
+	if isSet["FFI_OutputSelectedRect"] {
+		formFillInfo.FFI_OutputSelectedRect = func(page references.FPDF_PAGE, left, top, right, bottom float64) {
+			client.Call("Plugin.FFI_OutputSelectedRect", &FormFillInfoRect{Page: page, Left: left, Top: top, Right: right, Bottom: bottom}, new(interface{}))
+		}
+	}
+
+	if isSet["FFI_SetCursor"] {
+		formFillInfo.FFI_SetCursor = func(cursorType enums.FXCT) {
+			client.Call("Plugin.FFI_SetCursor", &FormFillInfoSetCursor{CursorType: cursorType}, new(interface{}))
+		}
+	}
+
+	if isSet["FFI_SetTimer"] {
+		formFillInfo.FFI_SetTimer = func(elapse int, timerFunc func(idEvent int)) int {
+			callbackID := s.timers.add(timerFunc)
+
+			var timerID int
+			err := client.Call("Plugin.FFI_SetTimer", &FormFillInfoSetTimer{Elapse: elapse, CallbackID: callbackID}, &timerID)
+			if err != nil || timerID == 0 {
+				s.timers.remove(callbackID)
+				return 0
+			}
+
+			s.timers.setTimerID(timerID, callbackID)
+			return timerID
+		}
+	}
+
+	if isSet["FFI_KillTimer"] {
+		formFillInfo.FFI_KillTimer = func(timerID int) {
+			client.Call("Plugin.FFI_KillTimer", &FormFillInfoKillTimer{TimerID: timerID}, new(interface{}))
+			s.timers.removeTimerID(timerID)
+		}
+	}
+
+	if isSet["FFI_GetLocalTime"] {
+		formFillInfo.FFI_GetLocalTime = func() structs.FPDF_SYSTEMTIME {
+			localTime := structs.FPDF_SYSTEMTIME{}
+			client.Call("Plugin.FFI_GetLocalTime", new(interface{}), &localTime)
+			return localTime
+		}
+	}
+
+	if isSet["FFI_OnChange"] {
+		formFillInfo.FFI_OnChange = func() {
+			client.Call("Plugin.FFI_OnChange", new(interface{}), new(interface{}))
+		}
+	}
+
+	if isSet["FFI_GetPage"] {
+		formFillInfo.FFI_GetPage = func(document references.FPDF_DOCUMENT, index int) *references.FPDF_PAGE {
+			page := &FormFillInfoPage{}
+			client.Call("Plugin.FFI_GetPage", &FormFillInfoGetPage{Document: document, Index: index}, page)
+			return page.Page
+		}
+	}
+
+	if isSet["FFI_GetCurrentPage"] {
+		formFillInfo.FFI_GetCurrentPage = func(document references.FPDF_DOCUMENT) *references.FPDF_PAGE {
+			page := &FormFillInfoPage{}
+			client.Call("Plugin.FFI_GetCurrentPage", &FormFillInfoGetCurrentPage{Document: document}, page)
+			return page.Page
+		}
+	}
+
+	if isSet["FFI_GetRotation"] {
+		formFillInfo.FFI_GetRotation = func(page references.FPDF_PAGE) enums.FPDF_PAGE_ROTATION {
+			rotation := enums.FPDF_PAGE_ROTATION_NONE
+			client.Call("Plugin.FFI_GetRotation", &FormFillInfoGetRotation{Page: page}, &rotation)
+			return rotation
+		}
+	}
+
+	if isSet["FFI_ExecuteNamedAction"] {
+		formFillInfo.FFI_ExecuteNamedAction = func(namedAction string) {
+			client.Call("Plugin.FFI_ExecuteNamedAction", &FormFillInfoExecuteNamedAction{NamedAction: namedAction}, new(interface{}))
+		}
+	}
+
+	if isSet["FFI_SetTextFieldFocus"] {
+		formFillInfo.FFI_SetTextFieldFocus = func(value string, isFocus bool) {
+			client.Call("Plugin.FFI_SetTextFieldFocus", &FormFillInfoSetTextFieldFocus{Value: value, IsFocus: isFocus}, new(interface{}))
+		}
+	}
+
+	if isSet["FFI_DoURIAction"] {
+		formFillInfo.FFI_DoURIAction = func(bsURI string) {
+			client.Call("Plugin.FFI_DoURIAction", &FormFillInfoDoURIAction{URI: bsURI}, new(interface{}))
+		}
+	}
+
+	if isSet["FFI_DoGoToAction"] {
+		formFillInfo.FFI_DoGoToAction = func(pageIndex int, zoomMode enums.FPDF_ZOOM_MODE, pos []float32) {
+			client.Call("Plugin.FFI_DoGoToAction", &FormFillInfoDoGoToAction{PageIndex: pageIndex, ZoomMode: zoomMode, Pos: pos}, new(interface{}))
+		}
+	}
+
+	return formFillInfo
+}
+
+// formFillTimers keeps track of the timer callbacks of PDFium in the worker.
+type formFillTimers struct {
+	lock           sync.Mutex
+	nextCallbackID int
+	callbacks      map[int]func(idEvent int)
+	timerIDs       map[int]int
+}
+
+func (t *formFillTimers) add(timerFunc func(idEvent int)) int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.callbacks == nil {
+		t.callbacks = map[int]func(idEvent int){}
+		t.timerIDs = map[int]int{}
+	}
+
+	t.nextCallbackID++
+	t.callbacks[t.nextCallbackID] = timerFunc
+	return t.nextCallbackID
+}
+
+func (t *formFillTimers) get(callbackID int) func(idEvent int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.callbacks[callbackID]
+}
+
+func (t *formFillTimers) remove(callbackID int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.callbacks, callbackID)
+}
+
+func (t *formFillTimers) setTimerID(timerID, callbackID int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.timerIDs[timerID] = callbackID
+}
+
+func (t *formFillTimers) removeTimerID(timerID int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if callbackID, ok := t.timerIDs[timerID]; ok {
+		delete(t.callbacks, callbackID)
+		delete(t.timerIDs, timerID)
+	}
+}
+
+// FormFillInfoRPCServer serves the callbacks of a FPDF_FORMFILLINFO on the
+// host, for the worker to call.
+type FormFillInfoRPCServer struct {
+	Impl   *structs.FPDF_FORMFILLINFO
+	client *rpc.Client // The plugin connection, to fire timers in the worker.
+}
+
+func (s *FormFillInfoRPCServer) Release(args interface{}, resp *interface{}) error {
+	if s.Impl.Release != nil {
+		s.Impl.Release()
+	}
+	return nil
+}
+
+func (s *FormFillInfoRPCServer) FFI_Invalidate(args *FormFillInfoRect, resp *interface{}) error {
+	s.Impl.FFI_Invalidate(args.Page, args.Left, args.Top, args.Right, args.Bottom)
+	return nil
+}
+
+func (s *FormFillInfoRPCServer) FFI_OutputSelectedRect(args *FormFillInfoRect, resp *interface{}) error {
+	s.Impl.FFI_OutputSelectedRect(args.Page, args.Left, args.Top, args.Right, args.Bottom)
+	return nil
+}
+
+func (s *FormFillInfoRPCServer) FFI_SetCursor(args *FormFillInfoSetCursor, resp *interface{}) error {
+	s.Impl.FFI_SetCursor(args.CursorType)
+	return nil
+}
+
+func (s *FormFillInfoRPCServer) FFI_SetTimer(args *FormFillInfoSetTimer, resp *int) error {
+	callbackID := args.CallbackID
+	*resp = s.Impl.FFI_SetTimer(args.Elapse, func(idEvent int) {
+		s.client.Call("Plugin.FormFillTimer", &FormFillTimerRequest{CallbackID: callbackID, IDEvent: idEvent}, new(interface{}))
+	})
+	return nil
+}
+
+func (s *FormFillInfoRPCServer) FFI_KillTimer(args *FormFillInfoKillTimer, resp *interface{}) error {
+	s.Impl.FFI_KillTimer(args.TimerID)
+	return nil
+}
+
+func (s *FormFillInfoRPCServer) FFI_GetLocalTime(args interface{}, resp *structs.FPDF_SYSTEMTIME) error {
+	*resp = s.Impl.FFI_GetLocalTime()
+	return nil
+}
+
+func (s *FormFillInfoRPCServer) FFI_OnChange(args interface{}, resp *interface{}) error {
+	s.Impl.FFI_OnChange()
+	return nil
+}
+
+func (s *FormFillInfoRPCServer) FFI_GetPage(args *FormFillInfoGetPage, resp *FormFillInfoPage) error {
+	resp.Page = s.Impl.FFI_GetPage(args.Document, args.Index)
+	return nil
+}
+
+func (s *FormFillInfoRPCServer) FFI_GetCurrentPage(args *FormFillInfoGetCurrentPage, resp *FormFillInfoPage) error {
+	resp.Page = s.Impl.FFI_GetCurrentPage(args.Document)
+	return nil
+}
+
+func (s *FormFillInfoRPCServer) FFI_GetRotation(args *FormFillInfoGetRotation, resp *enums.FPDF_PAGE_ROTATION) error {
+	*resp = s.Impl.FFI_GetRotation(args.Page)
+	return nil
+}
+
+func (s *FormFillInfoRPCServer) FFI_ExecuteNamedAction(args *FormFillInfoExecuteNamedAction, resp *interface{}) error {
+	s.Impl.FFI_ExecuteNamedAction(args.NamedAction)
+	return nil
+}
+
+func (s *FormFillInfoRPCServer) FFI_SetTextFieldFocus(args *FormFillInfoSetTextFieldFocus, resp *interface{}) error {
+	s.Impl.FFI_SetTextFieldFocus(args.Value, args.IsFocus)
+	return nil
+}
+
+func (s *FormFillInfoRPCServer) FFI_DoURIAction(args *FormFillInfoDoURIAction, resp *interface{}) error {
+	s.Impl.FFI_DoURIAction(args.URI)
+	return nil
+}
+
+func (s *FormFillInfoRPCServer) FFI_DoGoToAction(args *FormFillInfoDoGoToAction, resp *interface{}) error {
+	s.Impl.FFI_DoGoToAction(args.PageIndex, args.ZoomMode, args.Pos)
+	return nil
+}
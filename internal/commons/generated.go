@@ -12,6 +12,12 @@ import (
 
 type Pdfium interface {
 	Ping() (string, error)
+	AddHeaderFooter(*requests.AddHeaderFooter) (*responses.AddHeaderFooter, error)
+	AddPageTextLayer(*requests.AddPageTextLayer) (*responses.AddPageTextLayer, error)
+	AuditFormAccessibility(*requests.AuditFormAccessibility) (*responses.AuditFormAccessibility, error)
+	DrawPage(*requests.DrawPage) (*responses.DrawPage, error)
+	EditFormFields(*requests.EditFormFields) (*responses.EditFormFields, error)
+	ExportFormData(*requests.ExportFormData) (*responses.ExportFormData, error)
 	FORM_CanRedo(*requests.FORM_CanRedo) (*responses.FORM_CanRedo, error)
 	FORM_CanUndo(*requests.FORM_CanUndo) (*responses.FORM_CanUndo, error)
 	FORM_DoDocumentAAction(*requests.FORM_DoDocumentAAction) (*responses.FORM_DoDocumentAAction, error)
@@ -429,25 +435,106 @@ type Pdfium interface {
 	FSDK_SetLocaltimeFunction(*requests.FSDK_SetLocaltimeFunction) (*responses.FSDK_SetLocaltimeFunction, error)
 	FSDK_SetTimeFunction(*requests.FSDK_SetTimeFunction) (*responses.FSDK_SetTimeFunction, error)
 	FSDK_SetUnSpObjProcessHandler(*requests.FSDK_SetUnSpObjProcessHandler) (*responses.FSDK_SetUnSpObjProcessHandler, error)
+	FillForm(*requests.FillForm) (*responses.FillForm, error)
+	FlattenAnnotations(*requests.FlattenAnnotations) (*responses.FlattenAnnotations, error)
 	GetActionInfo(*requests.GetActionInfo) (*responses.GetActionInfo, error)
 	GetAttachments(*requests.GetAttachments) (*responses.GetAttachments, error)
 	GetBookmarks(*requests.GetBookmarks) (*responses.GetBookmarks, error)
 	GetDestInfo(*requests.GetDestInfo) (*responses.GetDestInfo, error)
+	GetDocumentImages(*requests.GetDocumentImages) (*responses.GetDocumentImages, error)
+	GetDocumentMarkup(*requests.GetDocumentMarkup) (*responses.GetDocumentMarkup, error)
+	GetFormFields(*requests.GetFormFields) (*responses.GetFormFields, error)
+	GetFormSchema(*requests.GetFormSchema) (*responses.GetFormSchema, error)
 	GetJavaScriptActions(*requests.GetJavaScriptActions) (*responses.GetJavaScriptActions, error)
 	GetMetaData(*requests.GetMetaData) (*responses.GetMetaData, error)
+	GetPageImages(*requests.GetPageImages) (*responses.GetPageImages, error)
 	GetPageSize(*requests.GetPageSize) (*responses.GetPageSize, error)
 	GetPageSizeInPixels(*requests.GetPageSizeInPixels) (*responses.GetPageSizeInPixels, error)
 	GetPageText(*requests.GetPageText) (*responses.GetPageText, error)
+	GetPageTextQuality(*requests.GetPageTextQuality) (*responses.GetPageTextQuality, error)
 	GetPageTextStructured(*requests.GetPageTextStructured) (*responses.GetPageTextStructured, error)
+	ImportFormData(*requests.ImportFormData) (*responses.ImportFormData, error)
+	ImposeBooklet(*requests.ImposeBooklet) (*responses.ImposeBooklet, error)
+	ImposeNUp(*requests.ImposeNUp) (*responses.ImposeNUp, error)
+	ImposeTiles(*requests.ImposeTiles) (*responses.ImposeTiles, error)
+	MergeDocuments(*requests.MergeDocuments) (*responses.MergeDocuments, error)
+	NormalizePages(*requests.NormalizePages) (*responses.NormalizePages, error)
 	OpenDocument(*requests.OpenDocument) (*responses.OpenDocument, error)
+	OptimizeImages(*requests.OptimizeImages) (*responses.OptimizeImages, error)
+	RedactDocument(*requests.RedactDocument) (*responses.RedactDocument, error)
 	RenderPageInDPI(*requests.RenderPageInDPI) (*responses.RenderPageInDPI, error)
 	RenderPageInPixels(*requests.RenderPageInPixels) (*responses.RenderPageInPixels, error)
 	RenderPagesInDPI(*requests.RenderPagesInDPI) (*responses.RenderPagesInDPI, error)
 	RenderPagesInPixels(*requests.RenderPagesInPixels) (*responses.RenderPagesInPixels, error)
 	RenderToFile(*requests.RenderToFile) (*responses.RenderToFile, error)
+	ReorderPages(*requests.ReorderPages) (*responses.ReorderPages, error)
+	RunFormActions(*requests.RunFormActions) (*responses.RunFormActions, error)
+	SearchPageText(*requests.SearchPageText) (*responses.SearchPageText, error)
+	SplitDocument(*requests.SplitDocument) (*responses.SplitDocument, error)
+	StampDocument(*requests.StampDocument) (*responses.StampDocument, error)
 	Close() error
 }
 
+func (g *PdfiumRPC) AddHeaderFooter(request *requests.AddHeaderFooter) (*responses.AddHeaderFooter, error) {
+	resp := &responses.AddHeaderFooter{}
+	err := g.client.Call("Plugin.AddHeaderFooter", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) AddPageTextLayer(request *requests.AddPageTextLayer) (*responses.AddPageTextLayer, error) {
+	resp := &responses.AddPageTextLayer{}
+	err := g.client.Call("Plugin.AddPageTextLayer", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) AuditFormAccessibility(request *requests.AuditFormAccessibility) (*responses.AuditFormAccessibility, error) {
+	resp := &responses.AuditFormAccessibility{}
+	err := g.client.Call("Plugin.AuditFormAccessibility", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) DrawPage(request *requests.DrawPage) (*responses.DrawPage, error) {
+	resp := &responses.DrawPage{}
+	err := g.client.Call("Plugin.DrawPage", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) EditFormFields(request *requests.EditFormFields) (*responses.EditFormFields, error) {
+	resp := &responses.EditFormFields{}
+	err := g.client.Call("Plugin.EditFormFields", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) ExportFormData(request *requests.ExportFormData) (*responses.ExportFormData, error) {
+	resp := &responses.ExportFormData{}
+	err := g.client.Call("Plugin.ExportFormData", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 func (g *PdfiumRPC) FORM_CanRedo(request *requests.FORM_CanRedo) (*responses.FORM_CanRedo, error) {
 	resp := &responses.FORM_CanRedo{}
 	err := g.client.Call("Plugin.FORM_CanRedo", request, resp)
@@ -1698,16 +1785,6 @@ func (g *PdfiumRPC) FPDFDOC_ExitFormFillEnvironment(request *requests.FPDFDOC_Ex
 	return resp, nil
 }
 
-func (g *PdfiumRPC) FPDFDOC_InitFormFillEnvironment(request *requests.FPDFDOC_InitFormFillEnvironment) (*responses.FPDFDOC_InitFormFillEnvironment, error) {
-	resp := &responses.FPDFDOC_InitFormFillEnvironment{}
-	err := g.client.Call("Plugin.FPDFDOC_InitFormFillEnvironment", request, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
-}
-
 func (g *PdfiumRPC) FPDFDest_GetDestPageIndex(request *requests.FPDFDest_GetDestPageIndex) (*responses.FPDFDest_GetDestPageIndex, error) {
 	resp := &responses.FPDFDest_GetDestPageIndex{}
 	err := g.client.Call("Plugin.FPDFDest_GetDestPageIndex", request, resp)
@@ -4618,6 +4695,26 @@ func (g *PdfiumRPC) FSDK_SetUnSpObjProcessHandler(request *requests.FSDK_SetUnSp
 	return resp, nil
 }
 
+func (g *PdfiumRPC) FillForm(request *requests.FillForm) (*responses.FillForm, error) {
+	resp := &responses.FillForm{}
+	err := g.client.Call("Plugin.FillForm", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) FlattenAnnotations(request *requests.FlattenAnnotations) (*responses.FlattenAnnotations, error) {
+	resp := &responses.FlattenAnnotations{}
+	err := g.client.Call("Plugin.FlattenAnnotations", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 func (g *PdfiumRPC) GetActionInfo(request *requests.GetActionInfo) (*responses.GetActionInfo, error) {
 	resp := &responses.GetActionInfo{}
 	err := g.client.Call("Plugin.GetActionInfo", request, resp)
@@ -4658,6 +4755,46 @@ func (g *PdfiumRPC) GetDestInfo(request *requests.GetDestInfo) (*responses.GetDe
 	return resp, nil
 }
 
+func (g *PdfiumRPC) GetDocumentImages(request *requests.GetDocumentImages) (*responses.GetDocumentImages, error) {
+	resp := &responses.GetDocumentImages{}
+	err := g.client.Call("Plugin.GetDocumentImages", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) GetDocumentMarkup(request *requests.GetDocumentMarkup) (*responses.GetDocumentMarkup, error) {
+	resp := &responses.GetDocumentMarkup{}
+	err := g.client.Call("Plugin.GetDocumentMarkup", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) GetFormFields(request *requests.GetFormFields) (*responses.GetFormFields, error) {
+	resp := &responses.GetFormFields{}
+	err := g.client.Call("Plugin.GetFormFields", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) GetFormSchema(request *requests.GetFormSchema) (*responses.GetFormSchema, error) {
+	resp := &responses.GetFormSchema{}
+	err := g.client.Call("Plugin.GetFormSchema", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 func (g *PdfiumRPC) GetJavaScriptActions(request *requests.GetJavaScriptActions) (*responses.GetJavaScriptActions, error) {
 	resp := &responses.GetJavaScriptActions{}
 	err := g.client.Call("Plugin.GetJavaScriptActions", request, resp)
@@ -4678,6 +4815,16 @@ func (g *PdfiumRPC) GetMetaData(request *requests.GetMetaData) (*responses.GetMe
 	return resp, nil
 }
 
+func (g *PdfiumRPC) GetPageImages(request *requests.GetPageImages) (*responses.GetPageImages, error) {
+	resp := &responses.GetPageImages{}
+	err := g.client.Call("Plugin.GetPageImages", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 func (g *PdfiumRPC) GetPageSize(request *requests.GetPageSize) (*responses.GetPageSize, error) {
 	resp := &responses.GetPageSize{}
 	err := g.client.Call("Plugin.GetPageSize", request, resp)
@@ -4708,6 +4855,16 @@ func (g *PdfiumRPC) GetPageText(request *requests.GetPageText) (*responses.GetPa
 	return resp, nil
 }
 
+func (g *PdfiumRPC) GetPageTextQuality(request *requests.GetPageTextQuality) (*responses.GetPageTextQuality, error) {
+	resp := &responses.GetPageTextQuality{}
+	err := g.client.Call("Plugin.GetPageTextQuality", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 func (g *PdfiumRPC) GetPageTextStructured(request *requests.GetPageTextStructured) (*responses.GetPageTextStructured, error) {
 	resp := &responses.GetPageTextStructured{}
 	err := g.client.Call("Plugin.GetPageTextStructured", request, resp)
@@ -4718,6 +4875,66 @@ func (g *PdfiumRPC) GetPageTextStructured(request *requests.GetPageTextStructure
 	return resp, nil
 }
 
+func (g *PdfiumRPC) ImportFormData(request *requests.ImportFormData) (*responses.ImportFormData, error) {
+	resp := &responses.ImportFormData{}
+	err := g.client.Call("Plugin.ImportFormData", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) ImposeBooklet(request *requests.ImposeBooklet) (*responses.ImposeBooklet, error) {
+	resp := &responses.ImposeBooklet{}
+	err := g.client.Call("Plugin.ImposeBooklet", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) ImposeNUp(request *requests.ImposeNUp) (*responses.ImposeNUp, error) {
+	resp := &responses.ImposeNUp{}
+	err := g.client.Call("Plugin.ImposeNUp", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) ImposeTiles(request *requests.ImposeTiles) (*responses.ImposeTiles, error) {
+	resp := &responses.ImposeTiles{}
+	err := g.client.Call("Plugin.ImposeTiles", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) MergeDocuments(request *requests.MergeDocuments) (*responses.MergeDocuments, error) {
+	resp := &responses.MergeDocuments{}
+	err := g.client.Call("Plugin.MergeDocuments", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) NormalizePages(request *requests.NormalizePages) (*responses.NormalizePages, error) {
+	resp := &responses.NormalizePages{}
+	err := g.client.Call("Plugin.NormalizePages", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 func (g *PdfiumRPC) OpenDocument(request *requests.OpenDocument) (*responses.OpenDocument, error) {
 	resp := &responses.OpenDocument{}
 	err := g.client.Call("Plugin.OpenDocument", request, resp)
@@ -4728,6 +4945,26 @@ func (g *PdfiumRPC) OpenDocument(request *requests.OpenDocument) (*responses.Ope
 	return resp, nil
 }
 
+func (g *PdfiumRPC) OptimizeImages(request *requests.OptimizeImages) (*responses.OptimizeImages, error) {
+	resp := &responses.OptimizeImages{}
+	err := g.client.Call("Plugin.OptimizeImages", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) RedactDocument(request *requests.RedactDocument) (*responses.RedactDocument, error) {
+	resp := &responses.RedactDocument{}
+	err := g.client.Call("Plugin.RedactDocument", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 func (g *PdfiumRPC) RenderPageInDPI(request *requests.RenderPageInDPI) (*responses.RenderPageInDPI, error) {
 	resp := &responses.RenderPageInDPI{}
 	err := g.client.Call("Plugin.RenderPageInDPI", request, resp)
@@ -4778,14 +5015,64 @@ func (g *PdfiumRPC) RenderToFile(request *requests.RenderToFile) (*responses.Ren
 	return resp, nil
 }
 
-func (s *PdfiumRPCServer) FORM_CanRedo(request *requests.FORM_CanRedo, resp *responses.FORM_CanRedo) (err error) {
+func (g *PdfiumRPC) ReorderPages(request *requests.ReorderPages) (*responses.ReorderPages, error) {
+	resp := &responses.ReorderPages{}
+	err := g.client.Call("Plugin.ReorderPages", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) RunFormActions(request *requests.RunFormActions) (*responses.RunFormActions, error) {
+	resp := &responses.RunFormActions{}
+	err := g.client.Call("Plugin.RunFormActions", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) SearchPageText(request *requests.SearchPageText) (*responses.SearchPageText, error) {
+	resp := &responses.SearchPageText{}
+	err := g.client.Call("Plugin.SearchPageText", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) SplitDocument(request *requests.SplitDocument) (*responses.SplitDocument, error) {
+	resp := &responses.SplitDocument{}
+	err := g.client.Call("Plugin.SplitDocument", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) StampDocument(request *requests.StampDocument) (*responses.StampDocument, error) {
+	resp := &responses.StampDocument{}
+	err := g.client.Call("Plugin.StampDocument", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *PdfiumRPCServer) AddHeaderFooter(request *requests.AddHeaderFooter, resp *responses.AddHeaderFooter) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_CanRedo", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "AddHeaderFooter", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_CanRedo(request)
+	implResp, err := s.Impl.AddHeaderFooter(request)
 	if err != nil {
 		return err
 	}
@@ -4796,14 +5083,14 @@ func (s *PdfiumRPCServer) FORM_CanRedo(request *requests.FORM_CanRedo, resp *res
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_CanUndo(request *requests.FORM_CanUndo, resp *responses.FORM_CanUndo) (err error) {
+func (s *PdfiumRPCServer) AddPageTextLayer(request *requests.AddPageTextLayer, resp *responses.AddPageTextLayer) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_CanUndo", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "AddPageTextLayer", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_CanUndo(request)
+	implResp, err := s.Impl.AddPageTextLayer(request)
 	if err != nil {
 		return err
 	}
@@ -4814,14 +5101,14 @@ func (s *PdfiumRPCServer) FORM_CanUndo(request *requests.FORM_CanUndo, resp *res
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_DoDocumentAAction(request *requests.FORM_DoDocumentAAction, resp *responses.FORM_DoDocumentAAction) (err error) {
+func (s *PdfiumRPCServer) AuditFormAccessibility(request *requests.AuditFormAccessibility, resp *responses.AuditFormAccessibility) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_DoDocumentAAction", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "AuditFormAccessibility", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_DoDocumentAAction(request)
+	implResp, err := s.Impl.AuditFormAccessibility(request)
 	if err != nil {
 		return err
 	}
@@ -4832,14 +5119,14 @@ func (s *PdfiumRPCServer) FORM_DoDocumentAAction(request *requests.FORM_DoDocume
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_DoDocumentJSAction(request *requests.FORM_DoDocumentJSAction, resp *responses.FORM_DoDocumentJSAction) (err error) {
+func (s *PdfiumRPCServer) DrawPage(request *requests.DrawPage, resp *responses.DrawPage) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_DoDocumentJSAction", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "DrawPage", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_DoDocumentJSAction(request)
+	implResp, err := s.Impl.DrawPage(request)
 	if err != nil {
 		return err
 	}
@@ -4850,14 +5137,14 @@ func (s *PdfiumRPCServer) FORM_DoDocumentJSAction(request *requests.FORM_DoDocum
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_DoDocumentOpenAction(request *requests.FORM_DoDocumentOpenAction, resp *responses.FORM_DoDocumentOpenAction) (err error) {
+func (s *PdfiumRPCServer) EditFormFields(request *requests.EditFormFields, resp *responses.EditFormFields) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_DoDocumentOpenAction", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "EditFormFields", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_DoDocumentOpenAction(request)
+	implResp, err := s.Impl.EditFormFields(request)
 	if err != nil {
 		return err
 	}
@@ -4868,14 +5155,14 @@ func (s *PdfiumRPCServer) FORM_DoDocumentOpenAction(request *requests.FORM_DoDoc
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_DoPageAAction(request *requests.FORM_DoPageAAction, resp *responses.FORM_DoPageAAction) (err error) {
+func (s *PdfiumRPCServer) ExportFormData(request *requests.ExportFormData, resp *responses.ExportFormData) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_DoPageAAction", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "ExportFormData", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_DoPageAAction(request)
+	implResp, err := s.Impl.ExportFormData(request)
 	if err != nil {
 		return err
 	}
@@ -4886,14 +5173,14 @@ func (s *PdfiumRPCServer) FORM_DoPageAAction(request *requests.FORM_DoPageAActio
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_ForceToKillFocus(request *requests.FORM_ForceToKillFocus, resp *responses.FORM_ForceToKillFocus) (err error) {
+func (s *PdfiumRPCServer) FORM_CanRedo(request *requests.FORM_CanRedo, resp *responses.FORM_CanRedo) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_ForceToKillFocus", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_CanRedo", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_ForceToKillFocus(request)
+	implResp, err := s.Impl.FORM_CanRedo(request)
 	if err != nil {
 		return err
 	}
@@ -4904,14 +5191,14 @@ func (s *PdfiumRPCServer) FORM_ForceToKillFocus(request *requests.FORM_ForceToKi
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_GetFocusedAnnot(request *requests.FORM_GetFocusedAnnot, resp *responses.FORM_GetFocusedAnnot) (err error) {
+func (s *PdfiumRPCServer) FORM_CanUndo(request *requests.FORM_CanUndo, resp *responses.FORM_CanUndo) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_GetFocusedAnnot", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_CanUndo", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_GetFocusedAnnot(request)
+	implResp, err := s.Impl.FORM_CanUndo(request)
 	if err != nil {
 		return err
 	}
@@ -4922,14 +5209,14 @@ func (s *PdfiumRPCServer) FORM_GetFocusedAnnot(request *requests.FORM_GetFocused
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_GetFocusedText(request *requests.FORM_GetFocusedText, resp *responses.FORM_GetFocusedText) (err error) {
+func (s *PdfiumRPCServer) FORM_DoDocumentAAction(request *requests.FORM_DoDocumentAAction, resp *responses.FORM_DoDocumentAAction) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_GetFocusedText", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_DoDocumentAAction", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_GetFocusedText(request)
+	implResp, err := s.Impl.FORM_DoDocumentAAction(request)
 	if err != nil {
 		return err
 	}
@@ -4940,14 +5227,14 @@ func (s *PdfiumRPCServer) FORM_GetFocusedText(request *requests.FORM_GetFocusedT
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_GetSelectedText(request *requests.FORM_GetSelectedText, resp *responses.FORM_GetSelectedText) (err error) {
+func (s *PdfiumRPCServer) FORM_DoDocumentJSAction(request *requests.FORM_DoDocumentJSAction, resp *responses.FORM_DoDocumentJSAction) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_GetSelectedText", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_DoDocumentJSAction", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_GetSelectedText(request)
+	implResp, err := s.Impl.FORM_DoDocumentJSAction(request)
 	if err != nil {
 		return err
 	}
@@ -4958,14 +5245,14 @@ func (s *PdfiumRPCServer) FORM_GetSelectedText(request *requests.FORM_GetSelecte
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_IsIndexSelected(request *requests.FORM_IsIndexSelected, resp *responses.FORM_IsIndexSelected) (err error) {
+func (s *PdfiumRPCServer) FORM_DoDocumentOpenAction(request *requests.FORM_DoDocumentOpenAction, resp *responses.FORM_DoDocumentOpenAction) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_IsIndexSelected", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_DoDocumentOpenAction", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_IsIndexSelected(request)
+	implResp, err := s.Impl.FORM_DoDocumentOpenAction(request)
 	if err != nil {
 		return err
 	}
@@ -4976,14 +5263,14 @@ func (s *PdfiumRPCServer) FORM_IsIndexSelected(request *requests.FORM_IsIndexSel
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_OnAfterLoadPage(request *requests.FORM_OnAfterLoadPage, resp *responses.FORM_OnAfterLoadPage) (err error) {
+func (s *PdfiumRPCServer) FORM_DoPageAAction(request *requests.FORM_DoPageAAction, resp *responses.FORM_DoPageAAction) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_OnAfterLoadPage", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_DoPageAAction", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_OnAfterLoadPage(request)
+	implResp, err := s.Impl.FORM_DoPageAAction(request)
 	if err != nil {
 		return err
 	}
@@ -4994,14 +5281,122 @@ func (s *PdfiumRPCServer) FORM_OnAfterLoadPage(request *requests.FORM_OnAfterLoa
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_OnBeforeClosePage(request *requests.FORM_OnBeforeClosePage, resp *responses.FORM_OnBeforeClosePage) (err error) {
+func (s *PdfiumRPCServer) FORM_ForceToKillFocus(request *requests.FORM_ForceToKillFocus, resp *responses.FORM_ForceToKillFocus) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_OnBeforeClosePage", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_ForceToKillFocus", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_OnBeforeClosePage(request)
+	implResp, err := s.Impl.FORM_ForceToKillFocus(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) FORM_GetFocusedAnnot(request *requests.FORM_GetFocusedAnnot, resp *responses.FORM_GetFocusedAnnot) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_GetFocusedAnnot", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.FORM_GetFocusedAnnot(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) FORM_GetFocusedText(request *requests.FORM_GetFocusedText, resp *responses.FORM_GetFocusedText) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_GetFocusedText", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.FORM_GetFocusedText(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) FORM_GetSelectedText(request *requests.FORM_GetSelectedText, resp *responses.FORM_GetSelectedText) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_GetSelectedText", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.FORM_GetSelectedText(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) FORM_IsIndexSelected(request *requests.FORM_IsIndexSelected, resp *responses.FORM_IsIndexSelected) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_IsIndexSelected", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.FORM_IsIndexSelected(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) FORM_OnAfterLoadPage(request *requests.FORM_OnAfterLoadPage, resp *responses.FORM_OnAfterLoadPage) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_OnAfterLoadPage", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.FORM_OnAfterLoadPage(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) FORM_OnBeforeClosePage(request *requests.FORM_OnBeforeClosePage, resp *responses.FORM_OnBeforeClosePage) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_OnBeforeClosePage", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.FORM_OnBeforeClosePage(request)
 	if err != nil {
 		return err
 	}
@@ -7028,24 +7423,6 @@ func (s *PdfiumRPCServer) FPDFDOC_ExitFormFillEnvironment(request *requests.FPDF
 	return nil
 }
 
-func (s *PdfiumRPCServer) FPDFDOC_InitFormFillEnvironment(request *requests.FPDFDOC_InitFormFillEnvironment, resp *responses.FPDFDOC_InitFormFillEnvironment) (err error) {
-	defer func() {
-		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FPDFDOC_InitFormFillEnvironment", panicError)
-		}
-	}()
-
-	implResp, err := s.Impl.FPDFDOC_InitFormFillEnvironment(request)
-	if err != nil {
-		return err
-	}
-
-	// Overwrite the target address of resp to the target address of implResp.
-	*resp = *implResp
-
-	return nil
-}
-
 func (s *PdfiumRPCServer) FPDFDest_GetDestPageIndex(request *requests.FPDFDest_GetDestPageIndex, resp *responses.FPDFDest_GetDestPageIndex) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
@@ -12284,6 +12661,42 @@ func (s *PdfiumRPCServer) FSDK_SetUnSpObjProcessHandler(request *requests.FSDK_S
 	return nil
 }
 
+func (s *PdfiumRPCServer) FillForm(request *requests.FillForm, resp *responses.FillForm) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FillForm", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.FillForm(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) FlattenAnnotations(request *requests.FlattenAnnotations, resp *responses.FlattenAnnotations) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FlattenAnnotations", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.FlattenAnnotations(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
 func (s *PdfiumRPCServer) GetActionInfo(request *requests.GetActionInfo, resp *responses.GetActionInfo) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
@@ -12356,6 +12769,78 @@ func (s *PdfiumRPCServer) GetDestInfo(request *requests.GetDestInfo, resp *respo
 	return nil
 }
 
+func (s *PdfiumRPCServer) GetDocumentImages(request *requests.GetDocumentImages, resp *responses.GetDocumentImages) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetDocumentImages", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.GetDocumentImages(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) GetDocumentMarkup(request *requests.GetDocumentMarkup, resp *responses.GetDocumentMarkup) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetDocumentMarkup", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.GetDocumentMarkup(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) GetFormFields(request *requests.GetFormFields, resp *responses.GetFormFields) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetFormFields", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.GetFormFields(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) GetFormSchema(request *requests.GetFormSchema, resp *responses.GetFormSchema) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetFormSchema", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.GetFormSchema(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
 func (s *PdfiumRPCServer) GetJavaScriptActions(request *requests.GetJavaScriptActions, resp *responses.GetJavaScriptActions) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
@@ -12392,6 +12877,24 @@ func (s *PdfiumRPCServer) GetMetaData(request *requests.GetMetaData, resp *respo
 	return nil
 }
 
+func (s *PdfiumRPCServer) GetPageImages(request *requests.GetPageImages, resp *responses.GetPageImages) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetPageImages", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.GetPageImages(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
 func (s *PdfiumRPCServer) GetPageSize(request *requests.GetPageSize, resp *responses.GetPageSize) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
@@ -12446,6 +12949,24 @@ func (s *PdfiumRPCServer) GetPageText(request *requests.GetPageText, resp *respo
 	return nil
 }
 
+func (s *PdfiumRPCServer) GetPageTextQuality(request *requests.GetPageTextQuality, resp *responses.GetPageTextQuality) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetPageTextQuality", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.GetPageTextQuality(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
 func (s *PdfiumRPCServer) GetPageTextStructured(request *requests.GetPageTextStructured, resp *responses.GetPageTextStructured) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
@@ -12464,6 +12985,114 @@ func (s *PdfiumRPCServer) GetPageTextStructured(request *requests.GetPageTextStr
 	return nil
 }
 
+func (s *PdfiumRPCServer) ImportFormData(request *requests.ImportFormData, resp *responses.ImportFormData) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "ImportFormData", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.ImportFormData(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) ImposeBooklet(request *requests.ImposeBooklet, resp *responses.ImposeBooklet) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "ImposeBooklet", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.ImposeBooklet(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) ImposeNUp(request *requests.ImposeNUp, resp *responses.ImposeNUp) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "ImposeNUp", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.ImposeNUp(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) ImposeTiles(request *requests.ImposeTiles, resp *responses.ImposeTiles) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "ImposeTiles", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.ImposeTiles(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) MergeDocuments(request *requests.MergeDocuments, resp *responses.MergeDocuments) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "MergeDocuments", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.MergeDocuments(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) NormalizePages(request *requests.NormalizePages, resp *responses.NormalizePages) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "NormalizePages", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.NormalizePages(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
 func (s *PdfiumRPCServer) OpenDocument(request *requests.OpenDocument, resp *responses.OpenDocument) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
@@ -12482,6 +13111,42 @@ func (s *PdfiumRPCServer) OpenDocument(request *requests.OpenDocument, resp *res
 	return nil
 }
 
+func (s *PdfiumRPCServer) OptimizeImages(request *requests.OptimizeImages, resp *responses.OptimizeImages) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "OptimizeImages", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.OptimizeImages(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) RedactDocument(request *requests.RedactDocument, resp *responses.RedactDocument) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "RedactDocument", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.RedactDocument(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
 func (s *PdfiumRPCServer) RenderPageInDPI(request *requests.RenderPageInDPI, resp *responses.RenderPageInDPI) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
@@ -12571,3 +13236,93 @@ func (s *PdfiumRPCServer) RenderToFile(request *requests.RenderToFile, resp *res
 
 	return nil
 }
+
+func (s *PdfiumRPCServer) ReorderPages(request *requests.ReorderPages, resp *responses.ReorderPages) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "ReorderPages", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.ReorderPages(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) RunFormActions(request *requests.RunFormActions, resp *responses.RunFormActions) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "RunFormActions", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.RunFormActions(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) SearchPageText(request *requests.SearchPageText, resp *responses.SearchPageText) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "SearchPageText", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.SearchPageText(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) SplitDocument(request *requests.SplitDocument, resp *responses.SplitDocument) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "SplitDocument", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.SplitDocument(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) StampDocument(request *requests.StampDocument, resp *responses.StampDocument) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "StampDocument", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.StampDocument(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
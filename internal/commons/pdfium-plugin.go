@@ -6,7 +6,10 @@ import (
 	"github.com/hashicorp/go-plugin"
 )
 
-type PdfiumRPC struct{ client *rpc.Client }
+type PdfiumRPC struct {
+	client *rpc.Client
+	broker *plugin.MuxBroker
+}
 
 func (g *PdfiumRPC) Ping() (string, error) {
 	var resp string
@@ -28,7 +31,9 @@ func (g *PdfiumRPC) Close() error {
 }
 
 type PdfiumRPCServer struct {
-	Impl Pdfium
+	Impl   Pdfium
+	broker *plugin.MuxBroker
+	timers formFillTimers
 }
 
 func (s *PdfiumRPCServer) Ping(args interface{}, resp *string) error {
@@ -53,10 +58,10 @@ type PdfiumPlugin struct {
 	Impl Pdfium
 }
 
-func (p *PdfiumPlugin) Server(*plugin.MuxBroker) (interface{}, error) {
-	return &PdfiumRPCServer{Impl: p.Impl}, nil
+func (p *PdfiumPlugin) Server(b *plugin.MuxBroker) (interface{}, error) {
+	return &PdfiumRPCServer{Impl: p.Impl, broker: b}, nil
 }
 
 func (PdfiumPlugin) Client(b *plugin.MuxBroker, c *rpc.Client) (interface{}, error) {
-	return &PdfiumRPC{client: c}, nil
+	return &PdfiumRPC{client: c, broker: b}, nil
 }
@@ -0,0 +1,628 @@
+package implementation
+
+/*
+#cgo pkg-config: pdfium
+#include "fpdfview.h"
+#include "fpdf_doc.h"
+#include "fpdf_edit.h"
+#include "fpdf_ppo.h"
+#include <stdlib.h>
+
+extern int go_read_seeker_cb(void *param, unsigned long position, unsigned char *pBuf, unsigned long size);
+
+static inline void FPDF_FILEACCESS_SET_GET_BLOCK(FPDF_FILEACCESS *fs, char *id) {
+	fs->m_GetBlock = &go_read_seeker_cb;
+	fs->m_Param = id;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	"github.com/google/uuid"
+)
+
+// assembleDocument is a document that is used as input of an assemble
+// helper. Documents that are opened by the helper are closed after use,
+// documents that were already opened in the instance are left alone.
+type assembleDocument struct {
+	handle        C.FPDF_DOCUMENT
+	data          *[]byte // Keep a reference to the data until the document is closed.
+	fileHandleRef *string // The reference of the reader of the document.
+	owned         bool
+}
+
+func (d *assembleDocument) close() {
+	if d.owned && d.handle != nil {
+		C.FPDF_CloseDocument(d.handle)
+		d.handle = nil
+		d.data = nil
+
+		// Cleanup file handle.
+		if d.fileHandleRef != nil {
+			Pdfium.fileReaders[*d.fileHandleRef].fileAccess = nil
+			C.free(Pdfium.fileReaders[*d.fileHandleRef].stringRef)
+			delete(Pdfium.fileReaders, *d.fileHandleRef)
+			d.fileHandleRef = nil
+		}
+	}
+}
+
+// openAssembleDocument opens the document of an assemble input.
+func (p *PdfiumImplementation) openAssembleDocument(input requests.DocumentInput) (*assembleDocument, error) {
+	if input.Document != "" {
+		documentHandle, err := p.getDocumentHandle(input.Document)
+		if err != nil {
+			return nil, err
+		}
+
+		return &assembleDocument{
+			handle: documentHandle.handle,
+		}, nil
+	}
+
+	var cPassword *C.char
+	if input.Password != nil {
+		cPassword = C.CString(*input.Password)
+		defer C.free(unsafe.Pointer(cPassword))
+	}
+
+	document := &assembleDocument{
+		data:  input.File,
+		owned: true,
+	}
+
+	if input.File != nil {
+		if len(*input.File) == 0 {
+			return nil, errors.New("file given is empty")
+		}
+
+		document.handle = C.FPDF_LoadMemDocument64(
+			unsafe.Pointer(&((*input.File)[0])),
+			C.size_t(len(*input.File)),
+			cPassword)
+	} else if input.FilePath != nil {
+		filePath := C.CString(*input.FilePath)
+		defer C.free(unsafe.Pointer(filePath))
+		document.handle = C.FPDF_LoadDocument(
+			filePath,
+			cPassword)
+	} else if input.FileReader != nil {
+		if input.FileReaderSize == 0 {
+			return nil, errors.New("FileReaderSize should be given when FileReader is set")
+		}
+
+		// Create a PDFium file access struct.
+		readerStruct := C.FPDF_FILEACCESS{}
+		readerStruct.m_FileLen = C.ulong(input.FileReaderSize)
+
+		readerRef := uuid.New()
+		readerRefString := readerRef.String()
+		cReaderRef := C.CString(readerRefString)
+
+		// Set the Go callback through cgo.
+		C.FPDF_FILEACCESS_SET_GET_BLOCK(&readerStruct, cReaderRef)
+
+		Pdfium.fileReaders[readerRefString] = &fileReaderRef{
+			stringRef:  unsafe.Pointer(cReaderRef),
+			reader:     input.FileReader,
+			fileAccess: &readerStruct,
+		}
+		document.fileHandleRef = &readerRefString
+
+		document.handle = C.FPDF_LoadCustomDocument(
+			&readerStruct,
+			cPassword)
+	} else {
+		return nil, errors.New("document not given")
+	}
+
+	if document.handle == nil {
+		pdfiumError := getLastError()
+
+		// Cleanup when file loading didn't work.
+		if document.fileHandleRef != nil {
+			C.free(Pdfium.fileReaders[*document.fileHandleRef].stringRef)
+			delete(Pdfium.fileReaders, *document.fileHandleRef)
+		}
+
+		return nil, pdfiumError
+	}
+
+	return document, nil
+}
+
+// parseAssemblePageRange parses a page range in the format "1,3,5-7"
+// (1-index based) into a list of page indexes (0-index based).
+func parseAssemblePageRange(pageRange string, pageCount int) ([]int, error) {
+	pages := []int{}
+	for _, part := range strings.Split(pageRange, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("invalid page range %q", pageRange)
+		}
+
+		start, end := part, part
+		if dashIndex := strings.Index(part, "-"); dashIndex != -1 {
+			start = strings.TrimSpace(part[:dashIndex])
+			end = strings.TrimSpace(part[dashIndex+1:])
+		}
+
+		startPage, err := strconv.Atoi(start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid page range %q", pageRange)
+		}
+
+		endPage, err := strconv.Atoi(end)
+		if err != nil {
+			return nil, fmt.Errorf("invalid page range %q", pageRange)
+		}
+
+		if startPage < 1 || endPage < startPage || endPage > pageCount {
+			return nil, fmt.Errorf("page range %q is out of bounds, document has %d pages", part, pageCount)
+		}
+
+		for page := startPage; page <= endPage; page++ {
+			pages = append(pages, page-1)
+		}
+	}
+
+	return pages, nil
+}
+
+// getAssemblePages returns the pages of an assemble input (0-index based).
+func getAssemblePages(pageRange *string, pageCount int) ([]int, error) {
+	if pageRange == nil {
+		pages := make([]int, pageCount)
+		for i := range pages {
+			pages[i] = i
+		}
+		return pages, nil
+	}
+
+	return parseAssemblePageRange(*pageRange, pageCount)
+}
+
+// importAssemblePages imports the given pages (0-index based) in the given
+// order at the end of the destination document.
+func (p *PdfiumImplementation) importAssemblePages(destination, source C.FPDF_DOCUMENT, pages []int) error {
+	if len(pages) == 0 {
+		return nil
+	}
+
+	pageRange := strings.Builder{}
+	for i, page := range pages {
+		if i > 0 {
+			pageRange.WriteString(",")
+		}
+		pageRange.WriteString(strconv.Itoa(page + 1))
+	}
+
+	cPageRange := C.CString(pageRange.String())
+	defer C.free(unsafe.Pointer(cPageRange))
+
+	success := C.FPDF_ImportPages(destination, source, cPageRange, C.FPDF_GetPageCount(destination))
+	if int(success) == 0 {
+		return errors.New("import of pages failed")
+	}
+
+	return nil
+}
+
+// createAssembleDocument creates a new document with the given pages of the
+// source document.
+func (p *PdfiumImplementation) createAssembleDocument(source C.FPDF_DOCUMENT, pages []int) (C.FPDF_DOCUMENT, error) {
+	doc := C.FPDF_CreateNewDocument()
+	if doc == nil {
+		return nil, errors.New("could not create new document")
+	}
+
+	err := p.importAssemblePages(doc, source, pages)
+	if err != nil {
+		C.FPDF_CloseDocument(doc)
+		return nil, err
+	}
+
+	C.FPDF_CopyViewerPreferences(doc, source)
+
+	return doc, nil
+}
+
+// MergeDocuments merges the pages of multiple documents into a new document.
+func (p *PdfiumImplementation) MergeDocuments(request *requests.MergeDocuments) (*responses.MergeDocuments, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	if len(request.Documents) == 0 {
+		return nil, errors.New("no documents given")
+	}
+
+	doc := C.FPDF_CreateNewDocument()
+	if doc == nil {
+		return nil, errors.New("could not create new document")
+	}
+	defer C.FPDF_CloseDocument(doc)
+
+	for i := range request.Documents {
+		err := func() error {
+			input, err := p.openAssembleDocument(request.Documents[i])
+			if err != nil {
+				return err
+			}
+			defer input.close()
+
+			pages, err := getAssemblePages(request.Documents[i].Pages, int(C.FPDF_GetPageCount(input.handle)))
+			if err != nil {
+				return err
+			}
+
+			if i == 0 {
+				C.FPDF_CopyViewerPreferences(doc, input.handle)
+			}
+
+			return p.importAssemblePages(doc, input.handle, pages)
+		}()
+		if err != nil {
+			return nil, fmt.Errorf("could not merge document %d: %w", i, err)
+		}
+	}
+
+	fileBytes, err := p.saveDocument(doc, request.Flags, request.FileVersion, request.FilePath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.MergeDocuments{
+		FileBytes: fileBytes,
+		FilePath:  request.FilePath,
+		PageCount: int(C.FPDF_GetPageCount(doc)),
+	}, nil
+}
+
+// ReorderPages creates a new document with the pages of a document in the given order.
+func (p *PdfiumImplementation) ReorderPages(request *requests.ReorderPages) (*responses.ReorderPages, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	if len(request.Order) == 0 {
+		return nil, errors.New("no page order given")
+	}
+
+	input, err := p.openAssembleDocument(request.Document)
+	if err != nil {
+		return nil, err
+	}
+	defer input.close()
+
+	pageCount := int(C.FPDF_GetPageCount(input.handle))
+	for _, page := range request.Order {
+		if page < 0 || page >= pageCount {
+			return nil, fmt.Errorf("page %d is out of bounds, document has %d pages", page, pageCount)
+		}
+	}
+
+	doc, err := p.createAssembleDocument(input.handle, request.Order)
+	if err != nil {
+		return nil, err
+	}
+	defer C.FPDF_CloseDocument(doc)
+
+	fileBytes, err := p.saveDocument(doc, request.Flags, request.FileVersion, request.FilePath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.ReorderPages{
+		FileBytes: fileBytes,
+		FilePath:  request.FilePath,
+		PageCount: int(C.FPDF_GetPageCount(doc)),
+	}, nil
+}
+
+type splitDocumentPart struct {
+	pages []int
+	title string
+}
+
+// SplitDocument splits a document into multiple documents.
+func (p *PdfiumImplementation) SplitDocument(request *requests.SplitDocument) (*responses.SplitDocument, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	input, err := p.openAssembleDocument(request.Document)
+	if err != nil {
+		return nil, err
+	}
+	defer input.close()
+
+	pages, err := getAssemblePages(request.Document.Pages, int(C.FPDF_GetPageCount(input.handle)))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pages) == 0 {
+		return nil, errors.New("document has no pages")
+	}
+
+	parts := []splitDocumentPart{}
+	switch request.Mode {
+	case requests.SplitDocumentModeRanges:
+		if len(request.Ranges) == 0 {
+			return nil, errors.New("no ranges given")
+		}
+
+		// The ranges select the pages of every part.
+		if request.Document.Pages != nil {
+			return nil, errors.New("document pages can't be combined with ranges, give the pages in the ranges")
+		}
+
+		for _, pageRange := range request.Ranges {
+			rangePages, err := parseAssemblePageRange(pageRange, int(C.FPDF_GetPageCount(input.handle)))
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, splitDocumentPart{pages: rangePages})
+		}
+	case requests.SplitDocumentModePageCount:
+		if request.PageCount < 1 {
+			return nil, errors.New("page count should be at least 1")
+		}
+
+		for i := 0; i < len(pages); i += request.PageCount {
+			end := i + request.PageCount
+			if end > len(pages) {
+				end = len(pages)
+			}
+			parts = append(parts, splitDocumentPart{pages: pages[i:end]})
+		}
+	case requests.SplitDocumentModeBookmarkLevel:
+		if request.BookmarkLevel < 1 {
+			return nil, errors.New("bookmark level should be at least 1")
+		}
+
+		parts, err = p.getSplitDocumentBookmarkParts(input.handle, pages, request.BookmarkLevel)
+		if err != nil {
+			return nil, err
+		}
+	case requests.SplitDocumentModeFileSize:
+		if request.MaxFileSize < 1 {
+			return nil, errors.New("max file size should be at least 1")
+		}
+
+		return p.splitDocumentByFileSize(input.handle, pages, request)
+	default:
+		return nil, fmt.Errorf("unsupported split mode %s", request.Mode)
+	}
+
+	resp := &responses.SplitDocument{
+		Parts: make([]responses.SplitDocumentPart, 0, len(parts)),
+	}
+
+	for i := range parts {
+		doc, err := p.createAssembleDocument(input.handle, parts[i].pages)
+		if err != nil {
+			return nil, err
+		}
+
+		part, err := p.saveSplitDocumentPart(doc, request, len(resp.Parts)+1)
+		C.FPDF_CloseDocument(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		part.Pages = parts[i].pages
+		part.Title = parts[i].title
+		resp.Parts = append(resp.Parts, *part)
+	}
+
+	return resp, nil
+}
+
+// saveSplitDocumentPart saves a part of a split document.
+func (p *PdfiumImplementation) saveSplitDocumentPart(doc C.FPDF_DOCUMENT, request *requests.SplitDocument, partNumber int) (*responses.SplitDocumentPart, error) {
+	var filePath *string
+	if request.FilePathFormat != nil {
+		partFilePath := fmt.Sprintf(*request.FilePathFormat, partNumber)
+		filePath = &partFilePath
+	}
+
+	fileBytes, err := p.saveDocument(doc, request.Flags, request.FileVersion, filePath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.SplitDocumentPart{
+		FileBytes: fileBytes,
+		FilePath:  filePath,
+	}, nil
+}
+
+// splitDocumentByFileSize creates parts with as many pages as possible
+// without exceeding the max file size. The size of a part grows with its
+// pages, so the page count of every part is found with an exponential and a
+// binary search, instead of saving the part again for every page.
+func (p *PdfiumImplementation) splitDocumentByFileSize(source C.FPDF_DOCUMENT, pages []int, request *requests.SplitDocument) (*responses.SplitDocument, error) {
+	resp := &responses.SplitDocument{
+		Parts: []responses.SplitDocumentPart{},
+	}
+
+	for start := 0; start < len(pages); {
+		remaining := len(pages) - start
+
+		fits := func(count int) (bool, error) {
+			doc, err := p.createAssembleDocument(source, pages[start:start+count])
+			if err != nil {
+				return false, err
+			}
+			defer C.FPDF_CloseDocument(doc)
+
+			fileBytes, err := p.saveDocument(doc, request.Flags, request.FileVersion, nil, nil)
+			if err != nil {
+				return false, err
+			}
+
+			return int64(len(*fileBytes)) <= request.MaxFileSize, nil
+		}
+
+		// A single page that is bigger than the max file size becomes its
+		// own part, so a part always has at least one page.
+		fitting := 1
+		tooBig := remaining + 1
+		for count := 2; fitting < remaining; count *= 2 {
+			if count > remaining {
+				count = remaining
+			}
+
+			ok, err := fits(count)
+			if err != nil {
+				return nil, err
+			}
+
+			if !ok {
+				tooBig = count
+				break
+			}
+			fitting = count
+		}
+
+		for tooBig-fitting > 1 {
+			count := (fitting + tooBig) / 2
+			ok, err := fits(count)
+			if err != nil {
+				return nil, err
+			}
+
+			if ok {
+				fitting = count
+			} else {
+				tooBig = count
+			}
+		}
+
+		partPages := pages[start : start+fitting]
+		doc, err := p.createAssembleDocument(source, partPages)
+		if err != nil {
+			return nil, err
+		}
+
+		part, err := p.saveSplitDocumentPart(doc, request, len(resp.Parts)+1)
+		C.FPDF_CloseDocument(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		part.Pages = partPages
+		resp.Parts = append(resp.Parts, *part)
+		start += fitting
+	}
+
+	return resp, nil
+}
+
+type splitDocumentBookmark struct {
+	pageIndex int
+	title     string
+}
+
+// getSplitDocumentBookmarkParts creates a part for every bookmark on the
+// given level, the pages before the first bookmark become the first part.
+func (p *PdfiumImplementation) getSplitDocumentBookmarkParts(document C.FPDF_DOCUMENT, pages []int, level int) ([]splitDocumentPart, error) {
+	bookmarks := []splitDocumentBookmark{}
+	err := p.collectSplitDocumentBookmarks(document, nil, 1, level, &bookmarks)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(bookmarks, func(i, j int) bool {
+		return bookmarks[i].pageIndex < bookmarks[j].pageIndex
+	})
+
+	parts := []splitDocumentPart{}
+	currentPart := splitDocumentPart{pages: []int{}}
+	bookmarkIndex := 0
+	for _, page := range pages {
+		startsPart := false
+		for bookmarkIndex < len(bookmarks) && bookmarks[bookmarkIndex].pageIndex <= page {
+			// Multiple bookmarks on the same page start one part.
+			if !startsPart {
+				startsPart = true
+				if len(currentPart.pages) > 0 {
+					parts = append(parts, currentPart)
+				}
+				currentPart = splitDocumentPart{pages: []int{}, title: bookmarks[bookmarkIndex].title}
+			}
+			bookmarkIndex++
+		}
+
+		currentPart.pages = append(currentPart.pages, page)
+	}
+
+	if len(currentPart.pages) > 0 {
+		parts = append(parts, currentPart)
+	}
+
+	return parts, nil
+}
+
+// collectSplitDocumentBookmarks collects the bookmarks with a page on the
+// given level.
+func (p *PdfiumImplementation) collectSplitDocumentBookmarks(document C.FPDF_DOCUMENT, parent C.FPDF_BOOKMARK, currentLevel, level int, bookmarks *[]splitDocumentBookmark) error {
+	// Prevent endless loops in broken documents.
+	seen := map[C.FPDF_BOOKMARK]bool{}
+
+	bookmark := C.FPDFBookmark_GetFirstChild(document, parent)
+	for bookmark != nil && !seen[bookmark] {
+		seen[bookmark] = true
+
+		if currentLevel == level {
+			dest := C.FPDFBookmark_GetDest(document, bookmark)
+			if dest == nil {
+				action := C.FPDFBookmark_GetAction(bookmark)
+				if action != nil && C.FPDFAction_GetType(action) == C.PDFACTION_GOTO {
+					dest = C.FPDFAction_GetDest(document, action)
+				}
+			}
+
+			if dest != nil {
+				pageIndex := int(C.FPDFDest_GetDestPageIndex(document, dest))
+				if pageIndex >= 0 {
+					title := ""
+					titleSize := C.FPDFBookmark_GetTitle(bookmark, C.NULL, 0)
+					if titleSize > 0 {
+						charData := make([]byte, titleSize)
+						C.FPDFBookmark_GetTitle(bookmark, unsafe.Pointer(&charData[0]), C.ulong(len(charData)))
+
+						transformedText, err := p.transformUTF16LEToUTF8(charData)
+						if err != nil {
+							return err
+						}
+						title = transformedText
+					}
+
+					*bookmarks = append(*bookmarks, splitDocumentBookmark{
+						pageIndex: pageIndex,
+						title:     title,
+					})
+				}
+			}
+		} else if currentLevel < level {
+			err := p.collectSplitDocumentBookmarks(document, bookmark, currentLevel+1, level, bookmarks)
+			if err != nil {
+				return err
+			}
+		}
+
+		bookmark = C.FPDFBookmark_GetNextSibling(document, bookmark)
+	}
+
+	return nil
+}
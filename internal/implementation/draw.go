@@ -0,0 +1,396 @@
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+// #include "fpdf_edit.h"
+// #include <stdlib.h>
+import "C"
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"unsafe"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+const drawDefaultFontSize = 12
+
+// DrawPage adds page objects for a list of drawing operations to a page and
+// generates the content of the page.
+func (p *PdfiumImplementation) DrawPage(request *requests.DrawPage) (*responses.DrawPage, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	pageHandle, err := p.loadPage(request.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	documentHandle, err := p.getDocumentHandle(pageHandle.documentRef)
+	if err != nil {
+		return nil, err
+	}
+
+	// Standard fonts are loaded once for all operations.
+	fonts := map[string]C.FPDF_FONT{}
+	defer func() {
+		for _, font := range fonts {
+			C.FPDFFont_Close(font)
+		}
+	}()
+
+	// Create all objects before adding any of them, so that nothing is
+	// drawn when one of the operations is invalid.
+	objects := make([]C.FPDF_PAGEOBJECT, 0, len(request.Operations))
+	destroyObjects := func() {
+		for _, object := range objects {
+			C.FPDFPageObj_Destroy(object)
+		}
+	}
+
+	for i, operation := range request.Operations {
+		object, err := p.createDrawObject(documentHandle.handle, operation, fonts)
+		if err != nil {
+			destroyObjects()
+			return nil, fmt.Errorf("could not draw operation %d: %w", i, err)
+		}
+
+		if operation.Matrix != nil {
+			C.FPDFPageObj_Transform(object, C.double(operation.Matrix.A), C.double(operation.Matrix.B), C.double(operation.Matrix.C), C.double(operation.Matrix.D), C.double(operation.Matrix.E), C.double(operation.Matrix.F))
+		}
+
+		objects = append(objects, object)
+	}
+
+	resp := &responses.DrawPage{
+		Page:        pageHandle.index,
+		PageObjects: make([]references.FPDF_PAGEOBJECT, len(objects)),
+	}
+
+	for i, object := range objects {
+		C.FPDFPage_InsertObject(pageHandle.handle, object)
+		resp.PageObjects[i] = p.registerPageObject(object).nativeRef
+	}
+
+	if len(objects) > 0 {
+		if int(C.FPDFPage_GenerateContent(pageHandle.handle)) == 0 {
+			return nil, errors.New("could not generate page content")
+		}
+	}
+
+	return resp, nil
+}
+
+// createDrawObject creates the page object of a drawing operation.
+func (p *PdfiumImplementation) createDrawObject(document C.FPDF_DOCUMENT, operation requests.DrawPageOperation, fonts map[string]C.FPDF_FONT) (C.FPDF_PAGEOBJECT, error) {
+	switch operation.Type {
+	case requests.DrawPageOperationTypePath:
+		return createDrawPathObject(operation)
+	case requests.DrawPageOperationTypeRect:
+		return createDrawRectObject(operation)
+	case requests.DrawPageOperationTypeText:
+		return p.createDrawTextObject(document, operation, fonts)
+	case requests.DrawPageOperationTypeImage:
+		return createDrawImageObject(document, operation.Image)
+	default:
+		return nil, fmt.Errorf("unsupported operation type %s", operation.Type)
+	}
+}
+
+// createDrawPathObject creates a path object from the segments of an operation.
+func createDrawPathObject(operation requests.DrawPageOperation) (C.FPDF_PAGEOBJECT, error) {
+	if len(operation.Path) == 0 {
+		return nil, errors.New("no path given")
+	}
+
+	if operation.Path[0].Type != requests.DrawPagePathSegmentTypeMoveTo {
+		return nil, errors.New("path should start with a move")
+	}
+
+	path := C.FPDFPageObj_CreateNewPath(C.float(operation.Path[0].X), C.float(operation.Path[0].Y))
+	if path == nil {
+		return nil, errors.New("could not create path object")
+	}
+
+	for i, segment := range operation.Path[1:] {
+		success := 0
+		switch segment.Type {
+		case requests.DrawPagePathSegmentTypeMoveTo:
+			success = int(C.FPDFPath_MoveTo(path, C.float(segment.X), C.float(segment.Y)))
+		case requests.DrawPagePathSegmentTypeLineTo:
+			success = int(C.FPDFPath_LineTo(path, C.float(segment.X), C.float(segment.Y)))
+		case requests.DrawPagePathSegmentTypeBezierTo:
+			success = int(C.FPDFPath_BezierTo(path, C.float(segment.X1), C.float(segment.Y1), C.float(segment.X2), C.float(segment.Y2), C.float(segment.X), C.float(segment.Y)))
+		case requests.DrawPagePathSegmentTypeClose:
+			success = int(C.FPDFPath_Close(path))
+		default:
+			C.FPDFPageObj_Destroy(path)
+			return nil, fmt.Errorf("unsupported path segment type %s", segment.Type)
+		}
+
+		if success == 0 {
+			C.FPDFPageObj_Destroy(path)
+			return nil, fmt.Errorf("could not add path segment %d", i+1)
+		}
+	}
+
+	if err := setDrawPathStyle(path, operation); err != nil {
+		C.FPDFPageObj_Destroy(path)
+		return nil, err
+	}
+
+	return path, nil
+}
+
+// createDrawRectObject creates a rectangle path object.
+func createDrawRectObject(operation requests.DrawPageOperation) (C.FPDF_PAGEOBJECT, error) {
+	left := math.Min(float64(operation.Rect.Left), float64(operation.Rect.Right))
+	bottom := math.Min(float64(operation.Rect.Bottom), float64(operation.Rect.Top))
+	width := math.Abs(float64(operation.Rect.Right - operation.Rect.Left))
+	height := math.Abs(float64(operation.Rect.Top - operation.Rect.Bottom))
+
+	rect := C.FPDFPageObj_CreateNewRect(C.float(left), C.float(bottom), C.float(width), C.float(height))
+	if rect == nil {
+		return nil, errors.New("could not create rect object")
+	}
+
+	if err := setDrawPathStyle(rect, operation); err != nil {
+		C.FPDFPageObj_Destroy(rect)
+		return nil, err
+	}
+
+	return rect, nil
+}
+
+// setDrawPathStyle sets the colors, the stroke and the draw mode of a path.
+func setDrawPathStyle(path C.FPDF_PAGEOBJECT, operation requests.DrawPageOperation) error {
+	fillMode := enums.FPDF_FILLMODE_NONE
+	if operation.FillColor != nil {
+		fillMode = operation.FillMode
+		if fillMode == enums.FPDF_FILLMODE_NONE {
+			fillMode = enums.FPDF_FILLMODE_WINDING
+		}
+
+		if int(C.FPDFPageObj_SetFillColor(path, C.uint(operation.FillColor.R), C.uint(operation.FillColor.G), C.uint(operation.FillColor.B), C.uint(operation.FillColor.A))) == 0 {
+			return errors.New("could not set fill color")
+		}
+	}
+
+	stroke := 0
+	if operation.StrokeColor != nil {
+		stroke = 1
+
+		if int(C.FPDFPageObj_SetStrokeColor(path, C.uint(operation.StrokeColor.R), C.uint(operation.StrokeColor.G), C.uint(operation.StrokeColor.B), C.uint(operation.StrokeColor.A))) == 0 {
+			return errors.New("could not set stroke color")
+		}
+
+		strokeWidth := operation.StrokeWidth
+		if strokeWidth <= 0 {
+			strokeWidth = 1
+		}
+
+		if int(C.FPDFPageObj_SetStrokeWidth(path, C.float(strokeWidth))) == 0 {
+			return errors.New("could not set stroke width")
+		}
+
+		if int(C.FPDFPageObj_SetLineJoin(path, C.int(operation.LineJoin))) == 0 {
+			return errors.New("could not set line join")
+		}
+
+		if int(C.FPDFPageObj_SetLineCap(path, C.int(operation.LineCap))) == 0 {
+			return errors.New("could not set line cap")
+		}
+	}
+
+	if int(C.FPDFPath_SetDrawMode(path, C.int(fillMode), C.FPDF_BOOL(stroke))) == 0 {
+		return errors.New("could not set draw mode")
+	}
+
+	return nil
+}
+
+// createDrawTextObject creates a text object for a text run.
+func (p *PdfiumImplementation) createDrawTextObject(document C.FPDF_DOCUMENT, operation requests.DrawPageOperation, fonts map[string]C.FPDF_FONT) (C.FPDF_PAGEOBJECT, error) {
+	text := operation.Text
+	if text.Text == "" {
+		return nil, errors.New("no text given")
+	}
+
+	var font C.FPDF_FONT
+	if len(text.FontData) > 0 {
+		font = loadFont(document, "", text.FontData, text.FontType)
+		if font == nil {
+			return nil, errors.New("could not load font")
+		}
+		defer C.FPDFFont_Close(font)
+	} else {
+		fontName := text.Font
+		if fontName == "" {
+			fontName = "Helvetica"
+		}
+
+		if _, ok := fonts[fontName]; !ok {
+			font = loadFont(document, fontName, nil, 0)
+			if font == nil {
+				return nil, errors.New("could not load font")
+			}
+			fonts[fontName] = font
+		}
+		font = fonts[fontName]
+	}
+
+	fontSize := text.FontSize
+	if fontSize <= 0 {
+		fontSize = drawDefaultFontSize
+	}
+
+	textObject := C.FPDFPageObj_CreateTextObj(document, font, C.float(fontSize))
+	if textObject == nil {
+		return nil, errors.New("could not create text object")
+	}
+
+	transformedText, err := p.transformUTF8ToUTF16LE(text.Text)
+	if err != nil {
+		C.FPDFPageObj_Destroy(textObject)
+		return nil, err
+	}
+
+	// Add the NULL terminator.
+	transformedText = append(transformedText, 0, 0)
+
+	if int(C.FPDFText_SetText(textObject, (C.FPDF_WIDESTRING)(unsafe.Pointer(&transformedText[0])))) == 0 {
+		C.FPDFPageObj_Destroy(textObject)
+		return nil, errors.New("could not set text")
+	}
+
+	color := structs.FPDF_COLOR{A: 255}
+	if operation.FillColor != nil {
+		color = *operation.FillColor
+	}
+
+	if int(C.FPDFPageObj_SetFillColor(textObject, C.uint(color.R), C.uint(color.G), C.uint(color.B), C.uint(color.A))) == 0 {
+		C.FPDFPageObj_Destroy(textObject)
+		return nil, errors.New("could not set text color")
+	}
+
+	C.FPDFPageObj_Transform(textObject, 1, 0, 0, 1, C.double(text.X), C.double(text.Y))
+
+	return textObject, nil
+}
+
+// createDrawImageObject creates an image object for an image.
+func createDrawImageObject(document C.FPDF_DOCUMENT, drawImage requests.DrawPageImage) (C.FPDF_PAGEOBJECT, error) {
+	if len(drawImage.Data) == 0 {
+		return nil, errors.New("no image given")
+	}
+
+	bgraImage, err := decodeBGRAImage(drawImage.Data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	width, height := getImageSizeInPoints(bgraImage.Bounds(), drawImage.Width, drawImage.Height)
+
+	imageObject, err := newBGRAImageObject(document, bgraImage)
+	if err != nil {
+		return nil, err
+	}
+
+	// Images are drawn in a 1x1 square, scale it to the requested size.
+	C.FPDFPageObj_Transform(imageObject, C.double(width), 0, 0, C.double(height), C.double(drawImage.X), C.double(drawImage.Y))
+
+	return imageObject, nil
+}
+
+// loadFont loads a font from font data, or a standard font by name when no
+// data is given. The font type defaults to TrueType.
+func loadFont(document C.FPDF_DOCUMENT, name string, data []byte, fontType enums.FPDF_FONT) C.FPDF_FONT {
+	if len(data) > 0 {
+		if fontType == 0 {
+			fontType = enums.FPDF_FONT_TRUETYPE
+		}
+
+		return C.FPDFText_LoadFont(document, (*C.uchar)(unsafe.Pointer(&data[0])), C.uint32_t(len(data)), C.int(fontType), C.FPDF_BOOL(1))
+	}
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	return C.FPDFText_LoadStandardFont(document, cName)
+}
+
+// decodeBGRAImage decodes a JPEG or PNG image into the BGRA pixels PDFium
+// expects, optionally applying an opacity to the alpha channel.
+func decodeBGRAImage(data []byte, opacity *float32) (*image.NRGBA, error) {
+	decodedImage, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode image: %w", err)
+	}
+
+	bounds := decodedImage.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return nil, errors.New("image has no size")
+	}
+
+	bgraImage := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(bgraImage, bgraImage.Bounds(), decodedImage, bounds.Min, draw.Src)
+	for i := 0; i < len(bgraImage.Pix); i += 4 {
+		bgraImage.Pix[i], bgraImage.Pix[i+2] = bgraImage.Pix[i+2], bgraImage.Pix[i]
+		if opacity != nil {
+			bgraImage.Pix[i+3] = uint8(math.Round(float64(bgraImage.Pix[i+3]) * float64(*opacity)))
+		}
+	}
+
+	return bgraImage, nil
+}
+
+// getImageSizeInPoints returns the size of an image in points. A missing
+// width or height is calculated from the aspect ratio, the size in pixels is
+// used when both are missing.
+func getImageSizeInPoints(bounds image.Rectangle, width, height float32) (float64, float64) {
+	pointWidth := float64(width)
+	pointHeight := float64(height)
+	if pointWidth <= 0 && pointHeight <= 0 {
+		return float64(bounds.Dx()), float64(bounds.Dy())
+	} else if pointWidth <= 0 {
+		pointWidth = pointHeight * float64(bounds.Dx()) / float64(bounds.Dy())
+	} else if pointHeight <= 0 {
+		pointHeight = pointWidth * float64(bounds.Dy()) / float64(bounds.Dx())
+	}
+
+	return pointWidth, pointHeight
+}
+
+// newBGRAImageObject creates an image object from BGRA pixels. The image of
+// the object is drawn in a 1x1 square.
+func newBGRAImageObject(document C.FPDF_DOCUMENT, bgraImage *image.NRGBA) (C.FPDF_PAGEOBJECT, error) {
+	bounds := bgraImage.Bounds()
+	bitmap := C.FPDFBitmap_CreateEx(C.int(bounds.Dx()), C.int(bounds.Dy()), C.FPDFBitmap_BGRA, unsafe.Pointer(&bgraImage.Pix[0]), C.int(bgraImage.Stride))
+	if bitmap == nil {
+		return nil, errors.New("could not create bitmap")
+	}
+	defer C.FPDFBitmap_Destroy(bitmap)
+
+	imageObject := C.FPDFPageObj_NewImageObj(document)
+	if imageObject == nil {
+		return nil, errors.New("could not create image object")
+	}
+
+	if int(C.FPDFImageObj_SetBitmap(nil, 0, imageObject, bitmap)) == 0 {
+		C.FPDFPageObj_Destroy(imageObject)
+		return nil, errors.New("could not set image")
+	}
+
+	return imageObject, nil
+}
@@ -0,0 +1,968 @@
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+// #include "fpdf_formfill.h"
+import "C"
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// The field flags that are specific to a field type.
+const (
+	formFieldFlagNoToggleToOff = 1 << 14
+	formFieldFlagRadio         = 1 << 15
+	formFieldFlagCombo         = 1 << 17
+)
+
+// EditFormFields creates, renames, moves, resizes and deletes form fields.
+// PDFium can't create widget annotations or change the form of a loaded
+// document, so the changes are written as an incremental update to a saved
+// copy of the document, which is loaded into the document handle again.
+// PDFium builds the appearances of the changed widgets.
+func (p *PdfiumImplementation) EditFormFields(request *requests.EditFormFields) (*responses.EditFormFields, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(request.Create) == 0 && len(request.Edit) == 0 {
+		return nil, errors.New("no fields to create or edit given")
+	}
+
+	// Save a copy without incremental updates, so that all objects are in a
+	// cross-reference table. PDFium doesn't write cross-reference streams or
+	// object streams then, which the update can't read. The strings of the
+	// new fields can't be encrypted, so the copy of an encrypted document is
+	// saved without its security.
+	saveFlags := requests.SaveFlagNoIncremental
+	if int(C.FPDF_GetSecurityHandlerRevision(documentHandle.handle)) != -1 {
+		saveFlags = requests.SaveFlagRemoveSecurity
+	}
+
+	data, err := p.saveDocument(documentHandle.handle, saveFlags, 0, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	editor, err := newFormFieldEditor(*data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, edit := range request.Edit {
+		if err := editor.editField(edit); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, create := range request.Create {
+		if err := editor.createField(create); err != nil {
+			return nil, err
+		}
+	}
+
+	fileBytes, err := editor.update.write()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.reloadDocument(documentHandle, fileBytes); err != nil {
+		return nil, err
+	}
+
+	if err := p.buildFormFieldAppearances(documentHandle, editor.changedPages); err != nil {
+		return nil, err
+	}
+
+	return &responses.EditFormFields{}, nil
+}
+
+// buildFormFieldAppearances loads pages into a form fill environment, PDFium
+// builds the appearances of the widgets on the pages that don't have an
+// appearance then.
+func (p *PdfiumImplementation) buildFormFieldAppearances(documentHandle *DocumentHandle, pages map[int]bool) error {
+	if len(pages) == 0 {
+		return nil
+	}
+
+	formHandle, closeFormHandle, err := p.getFormHandle(documentHandle)
+	if err != nil {
+		return err
+	}
+	defer closeFormHandle()
+
+	pageIndexes := []int{}
+	for pageIndex := range pages {
+		pageIndexes = append(pageIndexes, pageIndex)
+	}
+	sort.Ints(pageIndexes)
+
+	for _, pageIndex := range pageIndexes {
+		pageHandle, err := p.loadPage(requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: documentHandle.nativeRef,
+				Index:    pageIndex,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		C.FORM_OnAfterLoadPage(pageHandle.handle, formHandle)
+		C.FORM_OnBeforeClosePage(pageHandle.handle, formHandle)
+	}
+
+	return nil
+}
+
+// formFieldEditorField is a field of the form, fields are always indirect
+// objects.
+type formFieldEditorField struct {
+	reference fdfReference
+	dict      map[string]interface{}
+	name      string
+	parent    *formFieldEditorField // Nil for the fields in the AcroForm.
+}
+
+// formFieldEditor edits the form fields of a document through an incremental
+// update.
+type formFieldEditor struct {
+	update       *pdfUpdate
+	catalog      fdfReference
+	acroForm     fdfReference
+	pages        []fdfReference
+	fields       map[string]*formFieldEditorField
+	changedPages map[int]bool // The pages with widgets of which the appearance has to be built.
+}
+
+func newFormFieldEditor(data []byte) (*formFieldEditor, error) {
+	update, err := newPDFUpdate(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := update.trailer["Encrypt"]; ok {
+		return nil, errors.New("encrypted documents are not supported")
+	}
+
+	catalogReference, catalog, err := update.catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := update.pages()
+	if err != nil {
+		return nil, err
+	}
+
+	editor := &formFieldEditor{
+		update:       update,
+		catalog:      catalogReference,
+		pages:        pages,
+		fields:       map[string]*formFieldEditorField{},
+		changedPages: map[int]bool{},
+	}
+
+	// The AcroForm is made an indirect object, so that it can be changed
+	// without changing the catalog again.
+	switch acroForm := catalog["AcroForm"].(type) {
+	case fdfReference:
+		acroFormDict, err := update.dict(acroForm)
+		if err != nil {
+			return nil, err
+		}
+
+		if acroFormDict == nil {
+			acroFormDict = map[string]interface{}{}
+			update.set(int(acroForm), acroFormDict)
+		}
+		editor.acroForm = acroForm
+	case map[string]interface{}:
+		editor.acroForm = update.add(acroForm)
+		catalog["AcroForm"] = editor.acroForm
+		update.touch(catalogReference)
+	default:
+		editor.acroForm = update.add(map[string]interface{}{
+			"Fields": []interface{}{},
+		})
+		catalog["AcroForm"] = editor.acroForm
+		update.touch(catalogReference)
+	}
+
+	acroForm, err := update.dict(editor.acroForm)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := update.array(acroForm["Fields"])
+	if err != nil {
+		return nil, err
+	}
+
+	if err := editor.loadFields(fields, nil, map[fdfReference]bool{}); err != nil {
+		return nil, err
+	}
+
+	return editor, nil
+}
+
+// loadFields indexes the fields by their full name, kids without a partial
+// name are widgets.
+func (e *formFieldEditor) loadFields(references []interface{}, parent *formFieldEditorField, visited map[fdfReference]bool) error {
+	for _, item := range references {
+		reference, ok := item.(fdfReference)
+		if !ok || visited[reference] {
+			continue
+		}
+		visited[reference] = true
+
+		dict, err := e.update.dict(reference)
+		if err != nil {
+			return err
+		}
+
+		if dict == nil {
+			continue
+		}
+
+		partialName, ok := dict["T"].(string)
+		if !ok {
+			continue
+		}
+
+		name := decodePDFTextString(partialName)
+		if parent != nil {
+			name = parent.name + "." + name
+		}
+
+		field := &formFieldEditorField{
+			reference: reference,
+			dict:      dict,
+			name:      name,
+			parent:    parent,
+		}
+		e.fields[name] = field
+
+		kids, err := e.update.array(dict["Kids"])
+		if err != nil {
+			return err
+		}
+
+		if err := e.loadFields(kids, field, visited); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setArray sets an array of a dictionary, arrays that are indirect objects
+// are replaced.
+func (e *formFieldEditor) setArray(reference fdfReference, dict map[string]interface{}, key string, array []interface{}) {
+	if arrayReference, ok := dict[key].(fdfReference); ok {
+		e.update.set(int(arrayReference), array)
+		return
+	}
+
+	dict[key] = array
+	e.update.touch(reference)
+}
+
+// childDict returns a dictionary of a dictionary and the reference of the
+// object that contains it, the dictionary is created when it doesn't exist.
+func (e *formFieldEditor) childDict(reference fdfReference, dict map[string]interface{}, key string) (fdfReference, map[string]interface{}, error) {
+	if childReference, ok := dict[key].(fdfReference); ok {
+		child, err := e.update.dict(childReference)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if child == nil {
+			child = map[string]interface{}{}
+			e.update.set(int(childReference), child)
+		}
+
+		return childReference, child, nil
+	}
+
+	if child, ok := dict[key].(map[string]interface{}); ok {
+		return reference, child, nil
+	}
+
+	child := map[string]interface{}{}
+	dict[key] = child
+	e.update.touch(reference)
+
+	return reference, child, nil
+}
+
+// fieldType returns the type of a field, which can be inherited.
+func (e *formFieldEditor) fieldType(field *formFieldEditorField) fdfName {
+	for ; field != nil; field = field.parent {
+		if fieldType, ok := field.dict["FT"].(fdfName); ok {
+			return fieldType
+		}
+	}
+
+	return ""
+}
+
+// fieldWidgets returns the widgets of a field, which is the field itself when
+// the widget is merged into the field.
+func (e *formFieldEditor) fieldWidgets(field *formFieldEditorField) ([]fdfReference, error) {
+	if field.dict["Subtype"] == fdfName("Widget") {
+		return []fdfReference{field.reference}, nil
+	}
+
+	kids, err := e.update.array(field.dict["Kids"])
+	if err != nil {
+		return nil, err
+	}
+
+	widgets := []fdfReference{}
+	for _, kid := range kids {
+		kidReference, ok := kid.(fdfReference)
+		if !ok {
+			continue
+		}
+
+		kidDict, err := e.update.dict(kidReference)
+		if err != nil {
+			return nil, err
+		}
+
+		if kidDict == nil {
+			continue
+		}
+
+		if _, ok := kidDict["T"]; !ok {
+			widgets = append(widgets, kidReference)
+		}
+	}
+
+	return widgets, nil
+}
+
+// removeWidget removes a widget from the annotations of the pages.
+func (e *formFieldEditor) removeWidget(widget fdfReference) error {
+	widgetDict, err := e.update.dict(widget)
+	if err != nil {
+		return err
+	}
+
+	pages := e.pages
+	if page, ok := widgetDict["P"].(fdfReference); ok {
+		pages = []fdfReference{page}
+	}
+
+	for _, page := range pages {
+		pageDict, err := e.update.dict(page)
+		if err != nil {
+			return err
+		}
+
+		if pageDict == nil {
+			continue
+		}
+
+		annotations, err := e.update.array(pageDict["Annots"])
+		if err != nil {
+			return err
+		}
+
+		keptAnnotations := []interface{}{}
+		for _, annotation := range annotations {
+			if annotation != widget {
+				keptAnnotations = append(keptAnnotations, annotation)
+			}
+		}
+
+		if len(keptAnnotations) != len(annotations) {
+			e.setArray(page, pageDict, "Annots", keptAnnotations)
+		}
+	}
+
+	return nil
+}
+
+// removeField removes a field with its kids and widgets.
+func (e *formFieldEditor) removeField(field *formFieldEditorField) error {
+	for _, otherField := range e.fields {
+		if otherField.parent == field {
+			if err := e.removeField(otherField); err != nil {
+				return err
+			}
+		}
+	}
+	delete(e.fields, field.name)
+
+	widgets, err := e.fieldWidgets(field)
+	if err != nil {
+		return err
+	}
+
+	for _, widget := range widgets {
+		if err := e.removeWidget(widget); err != nil {
+			return err
+		}
+	}
+
+	parentReference, parentDict, key, err := e.fieldParent(field.parent)
+	if err != nil {
+		return err
+	}
+
+	siblings, err := e.update.array(parentDict[key])
+	if err != nil {
+		return err
+	}
+
+	keptSiblings := []interface{}{}
+	for _, sibling := range siblings {
+		if sibling != field.reference {
+			keptSiblings = append(keptSiblings, sibling)
+		}
+	}
+	e.setArray(parentReference, parentDict, key, keptSiblings)
+
+	// Removed fields can't be calculated.
+	acroForm, err := e.update.dict(e.acroForm)
+	if err != nil {
+		return err
+	}
+
+	calculationOrder, err := e.update.array(acroForm["CO"])
+	if err != nil {
+		return err
+	}
+
+	keptCalculationOrder := []interface{}{}
+	for _, calculatedField := range calculationOrder {
+		if calculatedField != field.reference {
+			keptCalculationOrder = append(keptCalculationOrder, calculatedField)
+		}
+	}
+
+	if len(keptCalculationOrder) != len(calculationOrder) {
+		e.setArray(e.acroForm, acroForm, "CO", keptCalculationOrder)
+	}
+
+	return nil
+}
+
+// fieldParent returns the dictionary and key of the array that contains the
+// kids of a parent field, the fields of the AcroForm when parent is nil.
+func (e *formFieldEditor) fieldParent(parent *formFieldEditorField) (fdfReference, map[string]interface{}, string, error) {
+	if parent != nil {
+		return parent.reference, parent.dict, "Kids", nil
+	}
+
+	acroForm, err := e.update.dict(e.acroForm)
+	if err != nil {
+		return 0, nil, "", err
+	}
+
+	return e.acroForm, acroForm, "Fields", nil
+}
+
+// widgetPage returns the index of the page of a widget, -1 when the widget is
+// not in the annotations of a page.
+func (e *formFieldEditor) widgetPage(widget fdfReference) (int, error) {
+	widgetDict, err := e.update.dict(widget)
+	if err != nil {
+		return -1, err
+	}
+
+	for i, page := range e.pages {
+		if widgetDict["P"] == page {
+			return i, nil
+		}
+	}
+
+	for i, page := range e.pages {
+		pageDict, err := e.update.dict(page)
+		if err != nil {
+			return -1, err
+		}
+
+		if pageDict == nil {
+			continue
+		}
+
+		annotations, err := e.update.array(pageDict["Annots"])
+		if err != nil {
+			return -1, err
+		}
+
+		for _, annotation := range annotations {
+			if annotation == widget {
+				return i, nil
+			}
+		}
+	}
+
+	return -1, nil
+}
+
+// formFont returns the font of the default resources of the form by its
+// resource name, the font is added when it doesn't exist.
+func (e *formFieldEditor) formFont(name string, baseFont string) (interface{}, error) {
+	acroForm, err := e.update.dict(e.acroForm)
+	if err != nil {
+		return nil, err
+	}
+
+	resourcesReference, resources, err := e.childDict(e.acroForm, acroForm, "DR")
+	if err != nil {
+		return nil, err
+	}
+
+	fontsReference, fonts, err := e.childDict(resourcesReference, resources, "Font")
+	if err != nil {
+		return nil, err
+	}
+
+	if font, ok := fonts[name]; ok {
+		return font, nil
+	}
+
+	font := map[string]interface{}{
+		"Type":     fdfName("Font"),
+		"Subtype":  fdfName("Type1"),
+		"BaseFont": fdfName(baseFont),
+	}
+
+	if baseFont != "ZapfDingbats" {
+		font["Encoding"] = fdfName("WinAnsiEncoding")
+	}
+
+	fonts[name] = e.update.add(font)
+	e.update.touch(fontsReference)
+
+	return fonts[name], nil
+}
+
+func (e *formFieldEditor) editField(edit requests.EditFormField) error {
+	field, ok := e.fields[edit.Name]
+	if !ok {
+		return fmt.Errorf("field %s not found", edit.Name)
+	}
+
+	if edit.Delete {
+		return e.removeField(field)
+	}
+
+	if edit.Rect != nil {
+		widgets, err := e.fieldWidgets(field)
+		if err != nil {
+			return err
+		}
+
+		if edit.Widget < 0 || edit.Widget >= len(widgets) {
+			return fmt.Errorf("widget %d of field %s doesn't exist", edit.Widget, edit.Name)
+		}
+
+		widget, err := e.update.dict(widgets[edit.Widget])
+		if err != nil {
+			return err
+		}
+
+		widget["Rect"] = getFormFieldRect(*edit.Rect)
+
+		// The appearances of buttons are scaled to the new rectangle, the
+		// appearances of other fields are built again.
+		if e.fieldType(field) != fdfName("Btn") {
+			delete(widget, "AP")
+
+			page, err := e.widgetPage(widgets[edit.Widget])
+			if err != nil {
+				return err
+			}
+
+			if page != -1 {
+				e.changedPages[page] = true
+			}
+		}
+
+		e.update.touch(widgets[edit.Widget])
+	}
+
+	if edit.NewName != "" && edit.NewName != edit.Name {
+		parentName, partialName := splitFormFieldName(edit.NewName)
+		currentParentName, _ := splitFormFieldName(edit.Name)
+		if parentName != currentParentName || partialName == "" {
+			return fmt.Errorf("field %s can only be renamed within its parent", edit.Name)
+		}
+
+		if _, ok := e.fields[edit.NewName]; ok {
+			return fmt.Errorf("field %s already exists", edit.NewName)
+		}
+
+		field.dict["T"] = pdfTextString(partialName)
+		e.update.touch(field.reference)
+
+		renamedFields := map[string]*formFieldEditorField{}
+		for name, otherField := range e.fields {
+			if name == edit.Name || strings.HasPrefix(name, edit.Name+".") {
+				otherField.name = edit.NewName + strings.TrimPrefix(name, edit.Name)
+			}
+			renamedFields[otherField.name] = otherField
+		}
+		e.fields = renamedFields
+	}
+
+	return nil
+}
+
+// parentField returns the parent field of a new field, the parent fields that
+// don't exist are created.
+func (e *formFieldEditor) parentField(name string) (*formFieldEditorField, error) {
+	parentName, _ := splitFormFieldName(name)
+	if parentName == "" {
+		return nil, nil
+	}
+
+	if parent, ok := e.fields[parentName]; ok {
+		if parent.dict["Subtype"] == fdfName("Widget") {
+			return nil, fmt.Errorf("field %s can't have kids", parentName)
+		}
+		return parent, nil
+	}
+
+	grandParent, err := e.parentField(parentName)
+	if err != nil {
+		return nil, err
+	}
+
+	_, partialName := splitFormFieldName(parentName)
+	parent := &formFieldEditorField{
+		dict: map[string]interface{}{
+			"T":    pdfTextString(partialName),
+			"Kids": []interface{}{},
+		},
+		name:   parentName,
+		parent: grandParent,
+	}
+
+	if err := e.addField(parent); err != nil {
+		return nil, err
+	}
+
+	return parent, nil
+}
+
+// addField adds a new field to the kids of its parent.
+func (e *formFieldEditor) addField(field *formFieldEditorField) error {
+	if field.parent != nil {
+		field.dict["Parent"] = field.parent.reference
+	}
+	field.reference = e.update.add(field.dict)
+
+	parentReference, parentDict, key, err := e.fieldParent(field.parent)
+	if err != nil {
+		return err
+	}
+
+	siblings, err := e.update.array(parentDict[key])
+	if err != nil {
+		return err
+	}
+
+	e.setArray(parentReference, parentDict, key, append(append([]interface{}{}, siblings...), field.reference))
+	e.fields[field.name] = field
+
+	return nil
+}
+
+func (e *formFieldEditor) createField(create requests.CreateFormField) error {
+	_, partialName := splitFormFieldName(create.Name)
+	if partialName == "" || strings.HasPrefix(create.Name, ".") || strings.Contains(create.Name, "..") {
+		return fmt.Errorf("invalid field name %s", create.Name)
+	}
+
+	if _, ok := e.fields[create.Name]; ok {
+		return fmt.Errorf("field %s already exists", create.Name)
+	}
+
+	if len(create.Widgets) == 0 {
+		return fmt.Errorf("field %s has no widgets", create.Name)
+	}
+
+	for _, widget := range create.Widgets {
+		if widget.Page < 0 || widget.Page >= len(e.pages) {
+			return fmt.Errorf("page %d is out of bounds, document has %d pages", widget.Page, len(e.pages))
+		}
+	}
+
+	dict := map[string]interface{}{
+		"T": pdfTextString(partialName),
+	}
+
+	flags := int(create.Flags)
+	isButton := false
+	switch create.Type {
+	case enums.FPDF_FORMFIELD_TYPE_TEXTFIELD:
+		dict["FT"] = fdfName("Tx")
+		if create.MaxLength > 0 {
+			dict["MaxLen"] = create.MaxLength
+		}
+		if create.Value != "" {
+			dict["V"] = pdfTextString(create.Value)
+		}
+	case enums.FPDF_FORMFIELD_TYPE_CHECKBOX:
+		dict["FT"] = fdfName("Btn")
+		isButton = true
+	case enums.FPDF_FORMFIELD_TYPE_RADIOBUTTON:
+		dict["FT"] = fdfName("Btn")
+		flags |= formFieldFlagRadio | formFieldFlagNoToggleToOff
+		isButton = true
+	case enums.FPDF_FORMFIELD_TYPE_COMBOBOX, enums.FPDF_FORMFIELD_TYPE_LISTBOX:
+		dict["FT"] = fdfName("Ch")
+		if create.Type == enums.FPDF_FORMFIELD_TYPE_COMBOBOX {
+			flags |= formFieldFlagCombo
+		}
+
+		options := make([]interface{}, len(create.Options))
+		for i, option := range create.Options {
+			options[i] = pdfTextString(option)
+		}
+		dict["Opt"] = options
+
+		if create.Value != "" {
+			dict["V"] = pdfTextString(create.Value)
+		}
+	case enums.FPDF_FORMFIELD_TYPE_SIGNATURE:
+		dict["FT"] = fdfName("Sig")
+	default:
+		return fmt.Errorf("unsupported field type %d", create.Type)
+	}
+
+	if flags != 0 {
+		dict["Ff"] = flags
+	}
+
+	if create.AlternateName != "" {
+		dict["TU"] = pdfTextString(create.AlternateName)
+	}
+
+	exportValues := make([]string, len(create.Widgets))
+	if isButton {
+		for i, widget := range create.Widgets {
+			exportValues[i] = widget.ExportValue
+			if exportValues[i] == "" {
+				if create.Type == enums.FPDF_FORMFIELD_TYPE_RADIOBUTTON {
+					return fmt.Errorf("widget %d of field %s has no export value", i, create.Name)
+				}
+				exportValues[i] = "Yes"
+			}
+
+			if exportValues[i] == "Off" {
+				return fmt.Errorf("export value of widget %d of field %s can't be Off", i, create.Name)
+			}
+		}
+
+		dict["V"] = fdfName("Off")
+		if create.Value != "" {
+			found := false
+			for _, exportValue := range exportValues {
+				if exportValue == create.Value {
+					found = true
+				}
+			}
+
+			if !found {
+				return fmt.Errorf("value %s is not an export value of field %s", create.Value, create.Name)
+			}
+			dict["V"] = fdfName(create.Value)
+		}
+	} else if create.Type != enums.FPDF_FORMFIELD_TYPE_SIGNATURE {
+		if _, err := e.formFont("Helv", "Helvetica"); err != nil {
+			return err
+		}
+
+		dict["DA"] = pdfTextString(fmt.Sprintf("/Helv %s Tf 0 g", formatFormFieldNumber(create.FontSize)))
+	}
+
+	parent, err := e.parentField(create.Name)
+	if err != nil {
+		return err
+	}
+
+	field := &formFieldEditorField{
+		dict:   dict,
+		name:   create.Name,
+		parent: parent,
+	}
+
+	// A single widget is merged into the field, radio buttons always have
+	// their widgets as kids.
+	mergeWidget := len(create.Widgets) == 1 && create.Type != enums.FPDF_FORMFIELD_TYPE_RADIOBUTTON
+	if !mergeWidget {
+		dict["Kids"] = []interface{}{}
+	}
+
+	if err := e.addField(field); err != nil {
+		return err
+	}
+
+	kids := []interface{}{}
+	for i, widget := range create.Widgets {
+		widgetDict := dict
+		widgetReference := field.reference
+		if !mergeWidget {
+			widgetDict = map[string]interface{}{
+				"Parent": field.reference,
+			}
+			widgetReference = e.update.add(widgetDict)
+			kids = append(kids, widgetReference)
+		}
+
+		widgetDict["Type"] = fdfName("Annot")
+		widgetDict["Subtype"] = fdfName("Widget")
+		widgetDict["Rect"] = getFormFieldRect(widget.Rect)
+		widgetDict["P"] = e.pages[widget.Page]
+		widgetDict["F"] = int(enums.FPDF_ANNOT_FLAG_PRINT)
+
+		// PDFium reads the export values of buttons from the states of their
+		// appearance, so these are built here.
+		if isButton {
+			if err := e.setButtonAppearance(widgetDict, create.Type, widget.Rect, exportValues[i], exportValues[i] == create.Value); err != nil {
+				return err
+			}
+		} else {
+			e.changedPages[widget.Page] = true
+		}
+
+		page := e.pages[widget.Page]
+		pageDict, err := e.update.dict(page)
+		if err != nil {
+			return err
+		}
+
+		annotations, err := e.update.array(pageDict["Annots"])
+		if err != nil {
+			return err
+		}
+
+		e.setArray(page, pageDict, "Annots", append(append([]interface{}{}, annotations...), widgetReference))
+	}
+
+	if !mergeWidget {
+		dict["Kids"] = kids
+	}
+
+	return nil
+}
+
+// setButtonAppearance sets the appearance of a checkbox or radio button
+// widget, a check or a dot in a square border.
+func (e *formFieldEditor) setButtonAppearance(widget map[string]interface{}, fieldType enums.FPDF_FORMFIELD_TYPE, rect structs.FPDF_FS_RECTF, exportValue string, checked bool) error {
+	font, err := e.formFont("ZaDb", "ZapfDingbats")
+	if err != nil {
+		return err
+	}
+
+	// The check and the dot of ZapfDingbats.
+	symbol := "4"
+	if fieldType == enums.FPDF_FORMFIELD_TYPE_RADIOBUTTON {
+		symbol = "l"
+	}
+
+	width := absFloat32(rect.Right - rect.Left)
+	height := absFloat32(rect.Top - rect.Bottom)
+
+	border := fmt.Sprintf("0 G 1 w 0.5 0.5 %s %s re S\n", formatFormFieldNumber(width-1), formatFormFieldNumber(height-1))
+
+	size := width
+	if height < size {
+		size = height
+	}
+	size *= 0.8
+	check := fmt.Sprintf("q BT 0 g /ZaDb %s Tf %s %s Td (%s) Tj ET Q\n", formatFormFieldNumber(size), formatFormFieldNumber((width-size*0.8)/2), formatFormFieldNumber((height-size*0.7)/2), symbol)
+
+	appearance := func(content string) fdfReference {
+		return e.update.add(&pdfStream{
+			dict: map[string]interface{}{
+				"Type":    fdfName("XObject"),
+				"Subtype": fdfName("Form"),
+				"BBox":    []interface{}{0, 0, width, height},
+				"Resources": map[string]interface{}{
+					"Font": map[string]interface{}{
+						"ZaDb": font,
+					},
+				},
+			},
+			data: []byte(content),
+		})
+	}
+
+	widget["MK"] = map[string]interface{}{
+		"CA": pdfTextString(symbol),
+		"BC": []interface{}{0},
+		"BG": []interface{}{1},
+	}
+	widget["AP"] = map[string]interface{}{
+		"N": map[string]interface{}{
+			exportValue: appearance(border + check),
+			"Off":       appearance(border),
+		},
+	}
+
+	widget["AS"] = fdfName("Off")
+	if checked {
+		widget["AS"] = fdfName(exportValue)
+	}
+
+	return nil
+}
+
+// splitFormFieldName splits a full field name into the full name of the
+// parent and the partial name.
+func splitFormFieldName(name string) (string, string) {
+	index := strings.LastIndex(name, ".")
+	if index == -1 {
+		return "", name
+	}
+
+	return name[:index], name[index+1:]
+}
+
+// getFormFieldRect returns a rectangle as PDF array of the lower left and the
+// upper right corner.
+func getFormFieldRect(rect structs.FPDF_FS_RECTF) []interface{} {
+	left, right := rect.Left, rect.Right
+	if right < left {
+		left, right = right, left
+	}
+
+	bottom, top := rect.Bottom, rect.Top
+	if top < bottom {
+		bottom, top = top, bottom
+	}
+
+	return []interface{}{left, bottom, right, top}
+}
+
+func formatFormFieldNumber(number float32) string {
+	return strconv.FormatFloat(float64(number), 'f', -1, 32)
+}
+
+func absFloat32(number float32) float32 {
+	if number < 0 {
+		return -number
+	}
+	return number
+}
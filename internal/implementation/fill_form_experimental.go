@@ -0,0 +1,408 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+// #include "fpdf_annot.h"
+// #include "fpdf_flatten.h"
+// #include "fpdf_formfill.h"
+import "C"
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"unsafe"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+const (
+	formFieldFlagChoiceEdit        = 1 << 18 // The combobox has an editable text field.
+	formFieldFlagChoiceMultiSelect = 1 << 21 // Multiple options of the listbox can be selected.
+)
+
+// formFillWidget is how one widget of a field is filled.
+type formFillWidget struct {
+	field     string
+	widget    responses.FormFieldWidget
+	text      *string // The text to replace the text of the widget with.
+	selection []int   // The option indexes to select, the other options are deselected.
+	toggle    bool    // Whether to click the widget to toggle a checkbox or radio button.
+}
+
+// FillForm fills the form fields of a document, regenerates their appearances,
+// optionally flattens them and saves the document.
+// Experimental API.
+func (p *PdfiumImplementation) FillForm(request *requests.FillForm) (*responses.FillForm, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	formHandle, closeFormHandle, err := p.getFormHandle(documentHandle)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFormHandle()
+
+	fields, err := p.getFormFields(documentHandle, formHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.fillForm(documentHandle, formHandle, fields, request.Values); err != nil {
+		return nil, err
+	}
+
+	// Read the values back, PDFium could have formatted or truncated them.
+	filledFields, err := p.getFormFields(documentHandle, formHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &responses.FillForm{
+		Fields: []responses.FillFormField{},
+	}
+
+	for _, field := range filledFields {
+		if _, ok := request.Values[field.Name]; ok {
+			resp.Fields = append(resp.Fields, responses.FillFormField{
+				Name:  field.Name,
+				Value: field.Value,
+			})
+		}
+	}
+
+	if request.Flatten {
+		pageCount := int(C.FPDF_GetPageCount(documentHandle.handle))
+		for pageIndex := 0; pageIndex < pageCount; pageIndex++ {
+			pageHandle, err := p.loadPage(requests.Page{
+				ByIndex: &requests.PageByIndex{
+					Document: documentHandle.nativeRef,
+					Index:    pageIndex,
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			if int(C.FPDFPage_Flatten(pageHandle.handle, C.int(request.FlattenUsage))) == C.FLATTEN_FAIL {
+				return nil, fmt.Errorf("could not flatten page %d", pageIndex)
+			}
+
+			// The page was changed without the page objects, close the page so
+			// that the objects are loaded again when the page is needed.
+			p.closeCurrentPage(documentHandle)
+		}
+	}
+
+	fileBytes, err := p.saveDocument(documentHandle.handle, request.Flags, request.FileVersion, request.FilePath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.FileBytes = fileBytes
+	resp.FilePath = request.FilePath
+
+	return resp, nil
+}
+
+// fillForm fills the fields of a document by their full name. All values are
+// checked before anything is filled.
+func (p *PdfiumImplementation) fillForm(documentHandle *DocumentHandle, formHandle C.FPDF_FORMHANDLE, fields []responses.FormField, values map[string]interface{}) error {
+	fieldsByName := map[string]responses.FormField{}
+	for _, field := range fields {
+		fieldsByName[field.Name] = field
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fillWidgets := []formFillWidget{}
+	for _, name := range names {
+		field, ok := fieldsByName[name]
+		if !ok {
+			return fmt.Errorf("field %s not found", name)
+		}
+
+		fieldWidgets, err := getFormFillWidgets(field, values[name])
+		if err != nil {
+			return err
+		}
+		fillWidgets = append(fillWidgets, fieldWidgets...)
+	}
+
+	sort.SliceStable(fillWidgets, func(i, j int) bool {
+		return fillWidgets[i].widget.Page < fillWidgets[j].widget.Page
+	})
+
+	for i := 0; i < len(fillWidgets); {
+		pageIndex := fillWidgets[i].widget.Page
+		end := i
+		for end < len(fillWidgets) && fillWidgets[end].widget.Page == pageIndex {
+			end++
+		}
+
+		if err := p.fillFormPage(documentHandle, formHandle, pageIndex, fillWidgets[i:end]); err != nil {
+			return err
+		}
+
+		i = end
+	}
+
+	return nil
+}
+
+// getFormFillWidgets returns how the widgets of a field should be filled for
+// a value.
+func getFormFillWidgets(field responses.FormField, value interface{}) ([]formFillWidget, error) {
+	if field.Flags&enums.FPDF_FORMFLAG_READONLY != 0 {
+		return nil, fmt.Errorf("field %s is read only", field.Name)
+	}
+
+	fillWidgets := []formFillWidget{}
+	switch field.Type {
+	case enums.FPDF_FORMFIELD_TYPE_TEXTFIELD:
+		text := ""
+		if value != nil {
+			var ok bool
+			text, ok = getFormFillText(value)
+			if !ok {
+				return nil, fmt.Errorf("unsupported value type %T for field %s", value, field.Name)
+			}
+		}
+
+		for _, widget := range field.Widgets {
+			fillWidgets = append(fillWidgets, formFillWidget{field: field.Name, widget: widget, text: &text})
+		}
+	case enums.FPDF_FORMFIELD_TYPE_CHECKBOX:
+		for _, widget := range field.Widgets {
+			checked := false
+			switch typedValue := value.(type) {
+			case nil:
+			case bool:
+				checked = typedValue
+			case string:
+				if typedValue != "" && typedValue != "Off" && typedValue != widget.ExportValue {
+					return nil, fmt.Errorf("value %s is not an export value of field %s", typedValue, field.Name)
+				}
+				checked = typedValue == widget.ExportValue
+			default:
+				return nil, fmt.Errorf("unsupported value type %T for field %s", value, field.Name)
+			}
+
+			if checked != widget.IsChecked {
+				fillWidgets = append(fillWidgets, formFillWidget{field: field.Name, widget: widget, toggle: true})
+			}
+		}
+	case enums.FPDF_FORMFIELD_TYPE_RADIOBUTTON:
+		exportValue, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("unsupported value type %T for field %s", value, field.Name)
+		}
+
+		found := false
+		for _, widget := range field.Widgets {
+			if widget.ExportValue != exportValue {
+				continue
+			}
+
+			found = true
+			if !widget.IsChecked {
+				fillWidgets = append(fillWidgets, formFillWidget{field: field.Name, widget: widget, toggle: true})
+			}
+			break
+		}
+
+		if !found {
+			return nil, fmt.Errorf("value %s is not an export value of field %s", exportValue, field.Name)
+		}
+	case enums.FPDF_FORMFIELD_TYPE_COMBOBOX, enums.FPDF_FORMFIELD_TYPE_LISTBOX:
+		var labels []string
+		switch typedValue := value.(type) {
+		case nil:
+			labels = []string{}
+		case string:
+			labels = []string{typedValue}
+		case []string:
+			labels = typedValue
+		default:
+			return nil, fmt.Errorf("unsupported value type %T for field %s", value, field.Name)
+		}
+
+		isCombobox := field.Type == enums.FPDF_FORMFIELD_TYPE_COMBOBOX
+		if len(labels) > 1 && (isCombobox || field.Flags&formFieldFlagChoiceMultiSelect == 0) {
+			return nil, fmt.Errorf("field %s only allows one option to be selected", field.Name)
+		}
+
+		selection := []int{}
+		for _, label := range labels {
+			optionIndex := -1
+			for i, option := range field.Options {
+				if option.Label == label {
+					optionIndex = i
+					break
+				}
+			}
+
+			if optionIndex == -1 {
+				// Editable comboboxes also accept text that is not an option.
+				if isCombobox && field.Flags&formFieldFlagChoiceEdit != 0 {
+					text := label
+					for _, widget := range field.Widgets {
+						fillWidgets = append(fillWidgets, formFillWidget{field: field.Name, widget: widget, text: &text})
+					}
+					return fillWidgets, nil
+				}
+
+				return nil, fmt.Errorf("value %s is not an option of field %s", label, field.Name)
+			}
+
+			selection = append(selection, optionIndex)
+		}
+
+		if len(selection) == 0 && isCombobox {
+			if field.Flags&formFieldFlagChoiceEdit == 0 {
+				return nil, fmt.Errorf("field %s can't be cleared", field.Name)
+			}
+
+			text := ""
+			for _, widget := range field.Widgets {
+				fillWidgets = append(fillWidgets, formFillWidget{field: field.Name, widget: widget, text: &text})
+			}
+			return fillWidgets, nil
+		}
+
+		for _, widget := range field.Widgets {
+			fillWidgets = append(fillWidgets, formFillWidget{field: field.Name, widget: widget, selection: selection})
+		}
+	default:
+		return nil, fmt.Errorf("field %s of type %d can't be filled", field.Name, field.Type)
+	}
+
+	return fillWidgets, nil
+}
+
+// getFormFillText returns the text of a value for a text field.
+func getFormFillText(value interface{}) (string, bool) {
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue, true
+	case bool:
+		return strconv.FormatBool(typedValue), true
+	case int:
+		return strconv.Itoa(typedValue), true
+	case int64:
+		return strconv.FormatInt(typedValue, 10), true
+	case float32:
+		return strconv.FormatFloat(float64(typedValue), 'f', -1, 32), true
+	case float64:
+		return strconv.FormatFloat(typedValue, 'f', -1, 64), true
+	default:
+		return "", false
+	}
+}
+
+// fillFormPage fills the widgets on one page. The page is loaded into the form
+// fill environment while filling, so that PDFium regenerates the appearances.
+func (p *PdfiumImplementation) fillFormPage(documentHandle *DocumentHandle, formHandle C.FPDF_FORMHANDLE, pageIndex int, fillWidgets []formFillWidget) error {
+	pageHandle, err := p.loadPage(requests.Page{
+		ByIndex: &requests.PageByIndex{
+			Document: documentHandle.nativeRef,
+			Index:    pageIndex,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	page := pageHandle.handle
+	if !p.isFormPageLoaded(formHandle, page) {
+		C.FORM_OnAfterLoadPage(page, formHandle)
+		defer C.FORM_OnBeforeClosePage(page, formHandle)
+	}
+
+	for _, fillWidget := range fillWidgets {
+		if err := p.fillFormWidget(formHandle, page, fillWidget); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fillFormWidget fills one widget through the form fill environment.
+func (p *PdfiumImplementation) fillFormWidget(formHandle C.FPDF_FORMHANDLE, page C.FPDF_PAGE, fillWidget formFillWidget) error {
+	annotation := C.FPDFPage_GetAnnot(page, C.int(fillWidget.widget.Index))
+	if annotation == nil {
+		return fmt.Errorf("could not get widget of field %s", fillWidget.field)
+	}
+	defer C.FPDFPage_CloseAnnot(annotation)
+
+	// Killing the focus commits the value and regenerates the appearance.
+	defer C.FORM_ForceToKillFocus(formHandle)
+
+	if fillWidget.toggle {
+		x := C.double((fillWidget.widget.Rect.Left + fillWidget.widget.Rect.Right) / 2)
+		y := C.double((fillWidget.widget.Rect.Top + fillWidget.widget.Rect.Bottom) / 2)
+		C.FORM_OnLButtonDown(formHandle, page, 0, x, y)
+		C.FORM_OnLButtonUp(formHandle, page, 0, x, y)
+		return nil
+	}
+
+	if int(C.FORM_SetFocusedAnnot(formHandle, annotation)) == 0 {
+		return fmt.Errorf("could not focus field %s", fillWidget.field)
+	}
+
+	if fillWidget.text != nil {
+		transformedText, err := p.transformUTF8ToUTF16LE(*fillWidget.text)
+		if err != nil {
+			return err
+		}
+
+		// Add the NULL terminator.
+		transformedText = append(transformedText, 0, 0)
+
+		C.FORM_SelectAllText(formHandle, page)
+		C.FORM_ReplaceSelection(formHandle, page, (C.FPDF_WIDESTRING)(unsafe.Pointer(&transformedText[0])))
+		return nil
+	}
+
+	optionCount := int(C.FPDFAnnot_GetOptionCount(formHandle, annotation))
+	selected := map[int]bool{}
+	for _, optionIndex := range fillWidget.selection {
+		selected[optionIndex] = true
+	}
+
+	// Select first, so that single selection fields deselect the other
+	// option themselves, and comboboxes never need a deselect.
+	for _, wantSelected := range []bool{true, false} {
+		for i := 0; i < optionCount; i++ {
+			if selected[i] != wantSelected || (int(C.FORM_IsIndexSelected(formHandle, page, C.int(i))) == 1) == wantSelected {
+				continue
+			}
+
+			cSelected := C.FPDF_BOOL(0)
+			if wantSelected {
+				cSelected = C.FPDF_BOOL(1)
+			}
+
+			if int(C.FORM_SetIndexSelected(formHandle, page, C.int(i), cSelected)) == 0 {
+				return fmt.Errorf("could not change the selection of field %s", fillWidget.field)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+//go:build !pdfium_experimental
+// +build !pdfium_experimental
+
+package implementation
+
+import (
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// FillForm fills the form fields of a document, regenerates their appearances,
+// optionally flattens them and saves the document.
+// Experimental API.
+func (p *PdfiumImplementation) FillForm(request *requests.FillForm) (*responses.FillForm, error) {
+	return nil, pdfium_errors.ErrExperimentalUnsupported
+}
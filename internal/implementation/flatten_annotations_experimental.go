@@ -0,0 +1,411 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+// #include "fpdf_annot.h"
+// #include "fpdf_edit.h"
+// #include "fpdf_flatten.h"
+// #include "fpdf_formfill.h"
+// #include "fpdf_ppo.h"
+// #include <stdlib.h>
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// FlattenAnnotations flattens the selected form fields and annotations into
+// the page content, the other annotations stay interactive. The fields of which
+// all widgets were flattened are removed from the form.
+// Experimental API.
+func (p *PdfiumImplementation) FlattenAnnotations(request *requests.FlattenAnnotations) (*responses.FlattenAnnotations, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(request.FieldNames) == 0 && len(request.FieldTypes) == 0 && len(request.Subtypes) == 0 {
+		return nil, errors.New("no field names, field types or subtypes given")
+	}
+
+	pageCount := int(C.FPDF_GetPageCount(documentHandle.handle))
+
+	pages := map[int]bool{}
+	for _, page := range request.Pages {
+		if page < 0 || page >= pageCount {
+			return nil, fmt.Errorf("page %d is out of bounds, document has %d pages", page, pageCount)
+		}
+		pages[page] = true
+	}
+
+	// PDFium can't remove fields from the form of a loaded document, so the
+	// form is changed in a saved copy that is loaded again. The strings of an
+	// encrypted copy can't be changed and a document in a form fill
+	// environment can't be loaded again, the flattened fields of those
+	// documents stay in the form without widgets, like FPDFPage_Flatten leaves
+	// them. This is decided before any page is changed.
+	removeFields := int(C.FPDF_GetSecurityHandlerRevision(documentHandle.handle)) == -1
+	for _, formHandleHandle := range p.formHandleRefs {
+		if formHandleHandle.documentRef == documentHandle.nativeRef {
+			removeFields = false
+		}
+	}
+
+	resp, removedFields, err := p.flattenDocumentAnnotations(documentHandle, pageCount, pages, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if !removeFields || len(removedFields) == 0 {
+		return resp, nil
+	}
+
+	data, err := p.saveDocument(documentHandle.handle, requests.SaveFlagNoIncremental, 0, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	fileBytes, err := removeFlattenedFormFields(*data, removedFields)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.reloadDocument(documentHandle, fileBytes); err != nil {
+		return nil, err
+	}
+
+	resp.RemovedFields = removedFields
+	return resp, nil
+}
+
+// flattenDocumentAnnotations flattens the selected annotations of the selected
+// pages, and returns the flattened fields that have no widgets left.
+func (p *PdfiumImplementation) flattenDocumentAnnotations(documentHandle *DocumentHandle, pageCount int, pages map[int]bool, request *requests.FlattenAnnotations) (*responses.FlattenAnnotations, []string, error) {
+	formHandle, closeFormHandle, err := p.getFormHandle(documentHandle)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeFormHandle()
+
+	resp := &responses.FlattenAnnotations{
+		Fields:        []string{},
+		RemovedFields: []string{},
+	}
+
+	flattenedFields := map[string]bool{}
+	for pageIndex := 0; pageIndex < pageCount; pageIndex++ {
+		if request.Pages != nil && !pages[pageIndex] {
+			continue
+		}
+
+		fields, annotations, err := p.flattenPageAnnotations(documentHandle, formHandle, pageIndex, request)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, field := range fields {
+			if !flattenedFields[field] {
+				flattenedFields[field] = true
+				resp.Fields = append(resp.Fields, field)
+			}
+		}
+		resp.Annotations += annotations
+	}
+
+	removedFields, err := p.getRemovedFlattenFields(documentHandle, formHandle, resp.Fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resp, removedFields, nil
+}
+
+// getRemovedFlattenFields returns the flattened fields that have no widgets
+// left on any page.
+func (p *PdfiumImplementation) getRemovedFlattenFields(documentHandle *DocumentHandle, formHandle C.FPDF_FORMHANDLE, flattenedFields []string) ([]string, error) {
+	if len(flattenedFields) == 0 {
+		return nil, nil
+	}
+
+	fields, err := p.getFormFields(documentHandle, formHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	remainingFields := map[string]bool{}
+	for _, field := range fields {
+		remainingFields[field.Name] = true
+	}
+
+	removedFields := []string{}
+	for _, field := range flattenedFields {
+		if !remainingFields[field] {
+			removedFields = append(removedFields, field)
+		}
+	}
+
+	return removedFields, nil
+}
+
+// removeFlattenedFormFields removes fields from the form of a document with
+// an incremental update. Parent fields without kids are removed as well.
+func removeFlattenedFormFields(data []byte, names []string) ([]byte, error) {
+	editor, err := newFormFieldEditor(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		field, ok := editor.fields[name]
+		if !ok {
+			continue
+		}
+
+		for field != nil {
+			parent := field.parent
+			if err := editor.removeField(field); err != nil {
+				return nil, err
+			}
+
+			if parent == nil {
+				break
+			}
+
+			kids, err := editor.update.array(parent.dict["Kids"])
+			if err != nil {
+				return nil, err
+			}
+
+			if len(kids) > 0 {
+				break
+			}
+			field = parent
+		}
+	}
+
+	return editor.update.write()
+}
+
+// flattenPageAnnotations flattens the selected annotations of one page. The
+// page is imported into a temporary document without its content and the
+// annotations that are not selected, so that FPDFPage_Flatten only flattens
+// the selected annotations. The result is placed on the page as a form object
+// and the selected annotations are removed.
+func (p *PdfiumImplementation) flattenPageAnnotations(documentHandle *DocumentHandle, formHandle C.FPDF_FORMHANDLE, pageIndex int, request *requests.FlattenAnnotations) ([]string, int, error) {
+	pageHandle, err := p.loadPage(requests.Page{
+		ByIndex: &requests.PageByIndex{
+			Document: documentHandle.nativeRef,
+			Index:    pageIndex,
+		},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	page := pageHandle.handle
+	selected, fields, err := p.getFlattenAnnotations(formHandle, page, request)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	annotations := 0
+	for _, isSelected := range selected {
+		if isSelected {
+			annotations++
+		}
+	}
+
+	if annotations == 0 {
+		return nil, 0, nil
+	}
+
+	// Popups of the selected annotations are removed with their annotation.
+	removed := make([]bool, len(selected))
+	copy(removed, selected)
+	for i := range selected {
+		annotation := C.FPDFPage_GetAnnot(page, C.int(i))
+		if annotation == nil {
+			continue
+		}
+
+		if C.FPDFAnnot_GetSubtype(annotation) == C.FPDF_ANNOT_POPUP {
+			parentKey := C.CString("Parent")
+			parent := C.FPDFAnnot_GetLinkedAnnot(annotation, parentKey)
+			C.free(unsafe.Pointer(parentKey))
+			if parent != nil {
+				parentIndex := int(C.FPDFPage_GetAnnotIndex(page, parent))
+				if parentIndex >= 0 && parentIndex < len(selected) && selected[parentIndex] {
+					removed[i] = true
+				}
+				C.FPDFPage_CloseAnnot(parent)
+			}
+		}
+		C.FPDFPage_CloseAnnot(annotation)
+	}
+
+	temporaryDocument := C.FPDF_CreateNewDocument()
+	if temporaryDocument == nil {
+		return nil, 0, errors.New("could not create temporary document")
+	}
+	defer C.FPDF_CloseDocument(temporaryDocument)
+
+	pageIndexes := []C.int{C.int(pageIndex)}
+	if int(C.FPDF_ImportPagesByIndex(temporaryDocument, documentHandle.handle, &pageIndexes[0], 1, 0)) == 0 {
+		return nil, 0, fmt.Errorf("could not import page %d", pageIndex)
+	}
+
+	if err := flattenTemporaryPage(temporaryDocument, selected, request.Usage); err != nil {
+		return nil, 0, fmt.Errorf("could not flatten page %d: %w", pageIndex, err)
+	}
+
+	xObject := C.FPDF_NewXObjectFromPage(documentHandle.handle, temporaryDocument, 0)
+	if xObject == nil {
+		return nil, 0, errors.New("creation of xobject failed")
+	}
+	defer C.FPDF_CloseXObject(xObject)
+
+	formObject := C.FPDF_NewFormObjectFromXObject(xObject)
+	if formObject == nil {
+		return nil, 0, errors.New("creation of form object failed")
+	}
+	C.FPDFPage_InsertObject(page, formObject)
+
+	// Remove the annotations from the back, so that the indexes stay valid.
+	for i := len(removed) - 1; i >= 0; i-- {
+		if !removed[i] {
+			continue
+		}
+
+		if int(C.FPDFPage_RemoveAnnot(page, C.int(i))) == 0 {
+			return nil, 0, fmt.Errorf("could not remove annotation %d of page %d", i, pageIndex)
+		}
+	}
+
+	if int(C.FPDFPage_GenerateContent(page)) == 0 {
+		return nil, 0, errors.New("could not generate page content")
+	}
+
+	// The annotations of the page changed, close the page so that the form
+	// fill environment and the annotations are loaded again when the page is
+	// needed.
+	p.closeCurrentPage(documentHandle)
+
+	return fields, annotations, nil
+}
+
+// getFlattenAnnotations returns which annotations of a page are selected to
+// flatten, and the full names of the selected form fields.
+func (p *PdfiumImplementation) getFlattenAnnotations(formHandle C.FPDF_FORMHANDLE, page C.FPDF_PAGE, request *requests.FlattenAnnotations) ([]bool, []string, error) {
+	fieldNames := map[string]bool{}
+	for _, fieldName := range request.FieldNames {
+		fieldNames[fieldName] = true
+	}
+
+	fieldTypes := map[enums.FPDF_FORMFIELD_TYPE]bool{}
+	for _, fieldType := range request.FieldTypes {
+		fieldTypes[fieldType] = true
+	}
+
+	subtypes := map[enums.FPDF_ANNOTATION_SUBTYPE]bool{}
+	for _, subtype := range request.Subtypes {
+		subtypes[subtype] = true
+	}
+
+	annotationCount := int(C.FPDFPage_GetAnnotCount(page))
+	selected := make([]bool, annotationCount)
+	fields := []string{}
+	for i := 0; i < annotationCount; i++ {
+		annotation := C.FPDFPage_GetAnnot(page, C.int(i))
+		if annotation == nil {
+			continue
+		}
+
+		subtype := enums.FPDF_ANNOTATION_SUBTYPE(C.FPDFAnnot_GetSubtype(annotation))
+
+		// Popups are never flattened.
+		if subtype == enums.FPDF_ANNOT_SUBTYPE_POPUP {
+			C.FPDFPage_CloseAnnot(annotation)
+			continue
+		}
+
+		selected[i] = subtypes[subtype]
+
+		if subtype == enums.FPDF_ANNOT_SUBTYPE_WIDGET {
+			fieldType := enums.FPDF_FORMFIELD_TYPE(C.FPDFAnnot_GetFormFieldType(formHandle, annotation))
+			name, err := p.getFormFieldString(func(buffer *C.FPDF_WCHAR, length C.ulong) C.ulong {
+				return C.FPDFAnnot_GetFormFieldName(formHandle, annotation, buffer, length)
+			})
+			if err != nil {
+				C.FPDFPage_CloseAnnot(annotation)
+				return nil, nil, err
+			}
+
+			if fieldType != -1 && (fieldNames[name] || fieldTypes[fieldType]) {
+				selected[i] = true
+			}
+
+			if selected[i] && name != "" {
+				fields = append(fields, name)
+			}
+		}
+
+		C.FPDFPage_CloseAnnot(annotation)
+	}
+
+	return selected, fields, nil
+}
+
+// flattenTemporaryPage removes the content and the annotations that are not
+// selected from the first page of a temporary document, and flattens the
+// selected annotations into the content.
+func flattenTemporaryPage(temporaryDocument C.FPDF_DOCUMENT, selected []bool, usage requests.FPDFPage_FlattenUsage) error {
+	page := C.FPDF_LoadPage(temporaryDocument, 0)
+	if page == nil {
+		return errors.New("could not load imported page")
+	}
+	defer C.FPDF_ClosePage(page)
+
+	if int(C.FPDFPage_GetAnnotCount(page)) != len(selected) {
+		return errors.New("annotations were not imported")
+	}
+
+	for i := int(C.FPDFPage_CountObjects(page)) - 1; i >= 0; i-- {
+		object := C.FPDFPage_GetObject(page, C.int(i))
+		if int(C.FPDFPage_RemoveObject(page, object)) == 0 {
+			return errors.New("could not remove page object")
+		}
+		C.FPDFPageObj_Destroy(object)
+	}
+
+	if int(C.FPDFPage_GenerateContent(page)) == 0 {
+		return errors.New("could not generate page content")
+	}
+
+	for i := len(selected) - 1; i >= 0; i-- {
+		if selected[i] {
+			continue
+		}
+
+		if int(C.FPDFPage_RemoveAnnot(page, C.int(i))) == 0 {
+			return fmt.Errorf("could not remove annotation %d", i)
+		}
+	}
+
+	if int(C.FPDFPage_Flatten(page, C.int(usage))) == C.FLATTEN_FAIL {
+		return errors.New("flattening failed")
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+//go:build !pdfium_experimental
+// +build !pdfium_experimental
+
+package implementation
+
+import (
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// FlattenAnnotations flattens the selected form fields and annotations into
+// the page content, the other annotations stay interactive.
+// Experimental API.
+func (p *PdfiumImplementation) FlattenAnnotations(request *requests.FlattenAnnotations) (*responses.FlattenAnnotations, error) {
+	return nil, pdfium_errors.ErrExperimentalUnsupported
+}
@@ -0,0 +1,347 @@
+package implementation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// formAccessibilityStructure is the part of a document that PDFium doesn't
+// expose for an accessibility audit of the form. PDFium only loads the
+// structure elements of the marked content of a page, not the elements of
+// the widgets, and doesn't return the tab order of a page.
+type formAccessibilityStructure struct {
+	tagged    bool
+	tabOrders []string                             // The tab order of every page.
+	widgets   [][]formAccessibilityStructureWidget // The widgets of every page.
+	labels    map[fdfReference]string              // The alternate text of the structure element of the annotations in the structure tree.
+	order     map[fdfReference]int                 // The position of the annotations in the structure tree.
+}
+
+// formAccessibilityStructureWidget is a widget annotation of a page.
+type formAccessibilityStructureWidget struct {
+	reference fdfReference // -1 for widgets that are not indirect objects.
+	name      string       // The full name of the field of the widget.
+	rect      structs.FPDF_FS_RECTF
+}
+
+// readFormAccessibilityStructure reads the tab order and widgets of the pages
+// and the structure elements of the annotations. The document has to be saved
+// without security, the strings of encrypted documents can't be read.
+func readFormAccessibilityStructure(data []byte) (*formAccessibilityStructure, error) {
+	update, err := newPDFUpdate(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := update.trailer["Encrypt"]; ok {
+		return nil, errors.New("encrypted documents are not supported, the alternate texts can't be read")
+	}
+
+	_, catalog, err := update.catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := update.pages()
+	if err != nil {
+		return nil, err
+	}
+
+	structure := &formAccessibilityStructure{
+		tabOrders: make([]string, len(pages)),
+		widgets:   make([][]formAccessibilityStructureWidget, len(pages)),
+		labels:    map[fdfReference]string{},
+		order:     map[fdfReference]int{},
+	}
+
+	for i, page := range pages {
+		pageDict, err := update.dict(page)
+		if err != nil {
+			return nil, err
+		}
+
+		if tabOrder, ok := pageDict["Tabs"].(fdfName); ok {
+			structure.tabOrders[i] = string(tabOrder)
+		}
+
+		annotations, err := update.array(pageDict["Annots"])
+		if err != nil {
+			return nil, err
+		}
+
+		structure.widgets[i] = []formAccessibilityStructureWidget{}
+		for _, annotation := range annotations {
+			annotationDict, err := update.dict(annotation)
+			if err != nil {
+				return nil, err
+			}
+
+			if annotationDict == nil || annotationDict["Subtype"] != fdfName("Widget") {
+				continue
+			}
+
+			widget := formAccessibilityStructureWidget{
+				reference: -1,
+			}
+
+			if reference, ok := annotation.(fdfReference); ok {
+				widget.reference = reference
+			}
+
+			widget.name, err = readFormAccessibilityFieldName(update, annotationDict)
+			if err != nil {
+				return nil, err
+			}
+
+			rect, err := update.array(annotationDict["Rect"])
+			if err != nil {
+				return nil, err
+			}
+
+			if len(rect) == 4 {
+				coordinates := make([]float32, 4)
+				for j, coordinate := range rect {
+					number, _ := strconv.ParseFloat(fmt.Sprint(coordinate), 32)
+					coordinates[j] = float32(number)
+				}
+				widget.rect = structs.FPDF_FS_RECTF{Left: coordinates[0], Bottom: coordinates[1], Right: coordinates[2], Top: coordinates[3]}
+			}
+
+			structure.widgets[i] = append(structure.widgets[i], widget)
+		}
+	}
+
+	structTreeRoot, err := update.dict(catalog["StructTreeRoot"])
+	if err != nil {
+		return nil, err
+	}
+
+	if structTreeRoot == nil {
+		return structure, nil
+	}
+	structure.tagged = true
+
+	visited := map[fdfReference]bool{}
+
+	// The annotations are object references (OBJR) in the kids of their
+	// structure element, the label is the alternate text of that element.
+	var walk func(node interface{}, alternateText string) error
+	walk = func(node interface{}, alternateText string) error {
+		if reference, ok := node.(fdfReference); ok {
+			if visited[reference] {
+				return nil
+			}
+			visited[reference] = true
+		}
+
+		resolved, err := update.resolve(node)
+		if err != nil {
+			return err
+		}
+
+		switch typedNode := resolved.(type) {
+		case []interface{}:
+			for _, kid := range typedNode {
+				if err := walk(kid, alternateText); err != nil {
+					return err
+				}
+			}
+		case map[string]interface{}:
+			switch typedNode["Type"] {
+			case fdfName("OBJR"):
+				if object, ok := typedNode["Obj"].(fdfReference); ok {
+					if _, ok := structure.order[object]; !ok {
+						structure.order[object] = len(structure.order)
+						structure.labels[object] = alternateText
+					}
+				}
+			case fdfName("MCR"):
+			default:
+				elementAlternateText := ""
+				if alt, ok := typedNode["Alt"].(string); ok {
+					elementAlternateText = decodePDFTextString(alt)
+				}
+
+				return walk(typedNode["K"], elementAlternateText)
+			}
+		}
+
+		return nil
+	}
+
+	if err := walk(structTreeRoot["K"], ""); err != nil {
+		return nil, err
+	}
+
+	return structure, nil
+}
+
+// readFormAccessibilityFieldName returns the full name of the field of a
+// widget, the partial names of the widget and its parents.
+func readFormAccessibilityFieldName(update *pdfUpdate, widget map[string]interface{}) (string, error) {
+	name := ""
+	visited := map[fdfReference]bool{}
+	for dict := widget; dict != nil; {
+		if partialName, ok := dict["T"].(string); ok {
+			if name == "" {
+				name = decodePDFTextString(partialName)
+			} else {
+				name = decodePDFTextString(partialName) + "." + name
+			}
+		}
+
+		parent, ok := dict["Parent"].(fdfReference)
+		if !ok || visited[parent] {
+			break
+		}
+		visited[parent] = true
+
+		var err error
+		dict, err = update.dict(parent)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return name, nil
+}
+
+// formAccessibilityWidget is a widget of a field on a page.
+type formAccessibilityWidget struct {
+	name      string
+	index     int          // The index of the annotation in PDFium.
+	reference fdfReference // The object of the widget, -1 when it's unknown.
+	rect      structs.FPDF_FS_RECTF
+}
+
+// matchFormAccessibilityWidgets sets the objects of the widgets of a page.
+// The annotation indexes of PDFium don't have to be the indexes in the
+// annotations of the page, PDFium can skip annotations, so the widgets are
+// matched on the name of their field and the closest rect.
+func matchFormAccessibilityWidgets(widgets []formAccessibilityWidget, structureWidgets []formAccessibilityStructureWidget) {
+	used := make([]bool, len(structureWidgets))
+	for i := range widgets {
+		widgets[i].reference = -1
+
+		match := -1
+		matchDistance := math.Inf(1)
+		left, bottom, right, top := normalizeFormWidgetRect(widgets[i].rect)
+		for j, structureWidget := range structureWidgets {
+			if used[j] || structureWidget.name != widgets[i].name {
+				continue
+			}
+
+			structureLeft, structureBottom, structureRight, structureTop := normalizeFormWidgetRect(structureWidget.rect)
+			distance := math.Abs(float64(left-structureLeft)) + math.Abs(float64(bottom-structureBottom)) + math.Abs(float64(right-structureRight)) + math.Abs(float64(top-structureTop))
+			if distance < matchDistance {
+				match = j
+				matchDistance = distance
+			}
+		}
+
+		if match != -1 {
+			used[match] = true
+			widgets[i].reference = structureWidgets[match].reference
+		}
+	}
+}
+
+// getFormTabOrder returns the widgets of a page in tab order. The structure
+// order puts the widgets that are not in the structure tree last, like
+// viewers do.
+func getFormTabOrder(widgets []formAccessibilityWidget, tabOrder string, structureOrder func(reference fdfReference) (int, bool)) []formAccessibilityWidget {
+	ordered := append([]formAccessibilityWidget{}, widgets...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].index < ordered[j].index
+	})
+
+	switch tabOrder {
+	case "S":
+		sort.SliceStable(ordered, func(i, j int) bool {
+			orderI, okI := structureOrder(ordered[i].reference)
+			orderJ, okJ := structureOrder(ordered[j].reference)
+			if okI != okJ {
+				return okI
+			}
+			return okI && orderI < orderJ
+		})
+	case "R":
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return isFormWidgetPlacedBefore(ordered[i].rect, ordered[j].rect)
+		})
+	case "C":
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return isFormWidgetPlacedBeforeInColumn(ordered[i].rect, ordered[j].rect)
+		})
+	}
+
+	return ordered
+}
+
+// isFormWidgetPlacedBefore returns whether widget a is placed before widget b
+// when the page is read in rows, from top to bottom and left to right.
+// Widgets are in the same row when they overlap vertically for at least half
+// of the smallest widget.
+func isFormWidgetPlacedBefore(a, b structs.FPDF_FS_RECTF) bool {
+	aLeft, aBottom, _, aTop := normalizeFormWidgetRect(a)
+	bLeft, bBottom, _, bTop := normalizeFormWidgetRect(b)
+
+	if isFormWidgetOverlapping(aBottom, aTop, bBottom, bTop) {
+		return aLeft < bLeft
+	}
+
+	return aBottom+aTop > bBottom+bTop
+}
+
+// isFormWidgetPlacedBeforeInColumn returns whether widget a is placed before
+// widget b when the page is read in columns, from left to right and top to
+// bottom.
+func isFormWidgetPlacedBeforeInColumn(a, b structs.FPDF_FS_RECTF) bool {
+	aLeft, aBottom, aRight, aTop := normalizeFormWidgetRect(a)
+	bLeft, bBottom, bRight, bTop := normalizeFormWidgetRect(b)
+
+	if isFormWidgetOverlapping(aLeft, aRight, bLeft, bRight) {
+		return aBottom+aTop > bBottom+bTop
+	}
+
+	return aLeft+aRight < bLeft+bRight
+}
+
+// isFormWidgetOverlapping returns whether two ranges overlap for at least
+// half of the smallest range.
+func isFormWidgetOverlapping(aStart, aEnd, bStart, bEnd float32) bool {
+	start, end := aStart, aEnd
+	if bStart > start {
+		start = bStart
+	}
+	if bEnd < end {
+		end = bEnd
+	}
+
+	size := aEnd - aStart
+	if bEnd-bStart < size {
+		size = bEnd - bStart
+	}
+
+	return end-start >= size/2
+}
+
+// normalizeFormWidgetRect returns the left, bottom, right and top of a rect.
+func normalizeFormWidgetRect(rect structs.FPDF_FS_RECTF) (float32, float32, float32, float32) {
+	left, right := rect.Left, rect.Right
+	if right < left {
+		left, right = right, left
+	}
+
+	bottom, top := rect.Bottom, rect.Top
+	if top < bottom {
+		bottom, top = top, bottom
+	}
+
+	return left, bottom, right, top
+}
@@ -0,0 +1,200 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+import "C"
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// AuditFormAccessibility returns the form fields without tooltip or label,
+// the pages with a wrong tab order and the widgets outside the crop box.
+// Experimental API.
+func (p *PdfiumImplementation) AuditFormAccessibility(request *requests.AuditFormAccessibility) (*responses.AuditFormAccessibility, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	formHandle, closeFormHandle, err := p.getFormHandle(documentHandle)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFormHandle()
+
+	fields, err := p.getFormFields(documentHandle, formHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save a copy without incremental updates, so that all objects are in a
+	// cross-reference table. The copy is saved without security, so that the
+	// strings in the structure tree are not encrypted.
+	data, err := p.saveDocument(documentHandle.handle, requests.SaveFlagRemoveSecurity, 0, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	structure, err := readFormAccessibilityStructure(*data)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &responses.AuditFormAccessibility{
+		Tagged: structure.tagged,
+		Pages:  []responses.FormAccessibilityPage{},
+		Issues: []responses.FormAccessibilityIssue{},
+	}
+
+	addIssue := func(issueType responses.FormAccessibilityIssueType, name string, page, widget int, message string) {
+		resp.Issues = append(resp.Issues, responses.FormAccessibilityIssue{
+			Type:    issueType,
+			Name:    name,
+			Page:    page,
+			Widget:  widget,
+			Message: message,
+		})
+	}
+
+	if !structure.tagged && len(fields) > 0 {
+		addIssue(responses.FormAccessibilityIssueTypeUntagged, "", -1, -1, "document has no structure tree, fields can't have labels")
+	}
+
+	pageWidgets := map[int][]formAccessibilityWidget{}
+	pageFields := map[int][]responses.FormField{}
+	for _, field := range fields {
+		pageFields[field.Page] = append(pageFields[field.Page], field)
+		for _, widget := range field.Widgets {
+			pageWidgets[widget.Page] = append(pageWidgets[widget.Page], formAccessibilityWidget{
+				name:  field.Name,
+				index: widget.Index,
+				rect:  widget.Rect,
+			})
+		}
+	}
+
+	pages := make([]int, 0, len(pageWidgets))
+	for page := range pageWidgets {
+		pages = append(pages, page)
+	}
+	sort.Ints(pages)
+
+	for _, page := range pages {
+		for _, field := range pageFields[page] {
+			if field.AlternateName == "" {
+				addIssue(responses.FormAccessibilityIssueTypeMissingTooltip, field.Name, page, -1, fmt.Sprintf("field %s has no tooltip", field.Name))
+			}
+		}
+
+		cropBox, err := p.getFormAccessibilityCropBox(documentHandle, page)
+		if err != nil {
+			return nil, err
+		}
+
+		widgets := pageWidgets[page]
+		sort.SliceStable(widgets, func(i, j int) bool {
+			return widgets[i].index < widgets[j].index
+		})
+
+		if page < len(structure.widgets) {
+			matchFormAccessibilityWidgets(widgets, structure.widgets[page])
+		}
+
+		for _, widget := range widgets {
+			if structure.tagged {
+				label, ok := structure.labels[widget.reference]
+				if !ok {
+					addIssue(responses.FormAccessibilityIssueTypeMissingLabel, widget.name, page, widget.index, fmt.Sprintf("widget of field %s is not in the structure tree", widget.name))
+				} else if label == "" {
+					addIssue(responses.FormAccessibilityIssueTypeMissingLabel, widget.name, page, widget.index, fmt.Sprintf("widget of field %s has no alternate text in the structure tree", widget.name))
+				}
+			}
+
+			if cropBox != nil {
+				left, bottom, right, top := normalizeFormWidgetRect(widget.rect)
+				cropLeft, cropBottom, cropRight, cropTop := normalizeFormWidgetRect(*cropBox)
+				if right <= cropLeft || left >= cropRight || top <= cropBottom || bottom >= cropTop {
+					addIssue(responses.FormAccessibilityIssueTypeOutsideCropBox, widget.name, page, widget.index, fmt.Sprintf("widget of field %s is outside the crop box", widget.name))
+				} else if left < cropLeft || right > cropRight || bottom < cropBottom || top > cropTop {
+					addIssue(responses.FormAccessibilityIssueTypeOutsideCropBox, widget.name, page, widget.index, fmt.Sprintf("widget of field %s is partly outside the crop box", widget.name))
+				}
+			}
+		}
+
+		tabOrder := ""
+		if page < len(structure.tabOrders) {
+			tabOrder = structure.tabOrders[page]
+		}
+
+		if structure.tagged && tabOrder != "S" {
+			addIssue(responses.FormAccessibilityIssueTypeTabOrder, "", page, -1, fmt.Sprintf("page %d doesn't use the structure tab order", page))
+		}
+
+		orderedWidgets := getFormTabOrder(widgets, tabOrder, func(reference fdfReference) (int, bool) {
+			order, ok := structure.order[reference]
+			return order, ok
+		})
+
+		pageResp := responses.FormAccessibilityPage{
+			Page:     page,
+			TabOrder: tabOrder,
+			Fields:   []string{},
+		}
+
+		for i, widget := range orderedWidgets {
+			pageResp.Fields = append(pageResp.Fields, widget.name)
+
+			// The row and column order follow the layout by definition.
+			if i == 0 || tabOrder == "R" || tabOrder == "C" {
+				continue
+			}
+
+			previousWidget := orderedWidgets[i-1]
+			if previousWidget.name != widget.name && isFormWidgetPlacedBefore(widget.rect, previousWidget.rect) {
+				addIssue(responses.FormAccessibilityIssueTypeTabOrder, widget.name, page, widget.index, fmt.Sprintf("field %s follows field %s in the tab order, but is placed before it on the page", widget.name, previousWidget.name))
+			}
+		}
+
+		resp.Pages = append(resp.Pages, pageResp)
+	}
+
+	return resp, nil
+}
+
+// getFormAccessibilityCropBox returns the crop box of a page, limited to the
+// media box. Nil when the page has no bounding box.
+func (p *PdfiumImplementation) getFormAccessibilityCropBox(documentHandle *DocumentHandle, page int) (*structs.FPDF_FS_RECTF, error) {
+	pageHandle, err := p.loadPage(requests.Page{
+		ByIndex: &requests.PageByIndex{
+			Document: documentHandle.nativeRef,
+			Index:    page,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rect := C.FS_RECTF{}
+	if int(C.FPDF_GetPageBoundingBox(pageHandle.handle, &rect)) == 0 {
+		return nil, nil
+	}
+
+	return &structs.FPDF_FS_RECTF{
+		Left:   float32(rect.left),
+		Top:    float32(rect.top),
+		Right:  float32(rect.right),
+		Bottom: float32(rect.bottom),
+	}, nil
+}
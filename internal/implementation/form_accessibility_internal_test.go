@@ -0,0 +1,79 @@
+package implementation
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/structs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("form accessibility", func() {
+	Context("readFormAccessibilityStructure", func() {
+		It("reads the widgets and their alternate texts", func() {
+			pdfData, err := ioutil.ReadFile("../../shared_tests/testdata/form_accessibility_popup.pdf")
+			Expect(err).To(BeNil())
+
+			structure, err := readFormAccessibilityStructure(pdfData)
+			Expect(err).To(BeNil())
+			Expect(structure.tagged).To(BeTrue())
+			Expect(structure.tabOrders).To(Equal([]string{"S"}))
+			Expect(structure.widgets).To(Equal([][]formAccessibilityStructureWidget{
+				{
+					{reference: 8, name: "name", rect: structs.FPDF_FS_RECTF{Left: 50, Top: 220, Right: 150, Bottom: 200}},
+					{reference: 9, name: "city", rect: structs.FPDF_FS_RECTF{Left: 50, Top: 170, Right: 150, Bottom: 150}},
+				},
+			}))
+			Expect(structure.labels).To(Equal(map[fdfReference]string{8: "Your name", 9: "Your city"}))
+			Expect(structure.order).To(Equal(map[fdfReference]int{8: 0, 9: 1}))
+		})
+
+		It("returns an error for an encrypted document", func() {
+			pdfData, err := ioutil.ReadFile("../../shared_tests/testdata/form_accessibility_popup.pdf")
+			Expect(err).To(BeNil())
+
+			encrypted := bytes.Replace(pdfData, []byte("trailer\n<< /Size 15 /Root 1 0 R >>"), []byte("trailer\n<< /Size 15 /Root 1 0 R /Encrypt 4 0 R >>"), 1)
+			Expect(encrypted).To(Not(Equal(pdfData)))
+
+			structure, err := readFormAccessibilityStructure(encrypted)
+			Expect(err).To(MatchError("encrypted documents are not supported, the alternate texts can't be read"))
+			Expect(structure).To(BeNil())
+		})
+	})
+
+	Context("matchFormAccessibilityWidgets", func() {
+		structureWidgets := []formAccessibilityStructureWidget{
+			{reference: 8, name: "name", rect: structs.FPDF_FS_RECTF{Left: 50, Top: 220, Right: 150, Bottom: 200}},
+			{reference: -1, name: "choice", rect: structs.FPDF_FS_RECTF{Left: 50, Top: 120, Right: 150, Bottom: 100}},
+			{reference: 9, name: "choice", rect: structs.FPDF_FS_RECTF{Left: 50, Top: 170, Right: 150, Bottom: 150}},
+		}
+
+		It("matches the widgets on their field name and rect instead of their index", func() {
+			widgets := []formAccessibilityWidget{
+				{name: "name", index: 3, rect: structs.FPDF_FS_RECTF{Left: 50, Top: 220, Right: 150, Bottom: 200}},
+				{name: "choice", index: 4, rect: structs.FPDF_FS_RECTF{Left: 50, Top: 170, Right: 150, Bottom: 150}},
+				{name: "choice", index: 5, rect: structs.FPDF_FS_RECTF{Left: 50, Top: 120, Right: 150, Bottom: 100}},
+				{name: "unknown", index: 6, rect: structs.FPDF_FS_RECTF{Left: 50, Top: 220, Right: 150, Bottom: 200}},
+			}
+
+			matchFormAccessibilityWidgets(widgets, structureWidgets)
+			Expect(widgets[0].reference).To(Equal(fdfReference(8)))
+			Expect(widgets[1].reference).To(Equal(fdfReference(9)))
+			Expect(widgets[2].reference).To(Equal(fdfReference(-1)))
+			Expect(widgets[3].reference).To(Equal(fdfReference(-1)))
+		})
+
+		It("matches every structure widget once", func() {
+			widgets := []formAccessibilityWidget{
+				{name: "name", index: 0, rect: structs.FPDF_FS_RECTF{Left: 50, Top: 220, Right: 150, Bottom: 200}},
+				{name: "name", index: 1, rect: structs.FPDF_FS_RECTF{Left: 50, Top: 220, Right: 150, Bottom: 200}},
+			}
+
+			matchFormAccessibilityWidgets(widgets, structureWidgets)
+			Expect(widgets[0].reference).To(Equal(fdfReference(8)))
+			Expect(widgets[1].reference).To(Equal(fdfReference(-1)))
+		})
+	})
+})
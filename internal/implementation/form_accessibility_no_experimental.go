@@ -0,0 +1,17 @@
+//go:build !pdfium_experimental
+// +build !pdfium_experimental
+
+package implementation
+
+import (
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// AuditFormAccessibility returns the form fields without tooltip or label,
+// the pages with a wrong tab order and the widgets outside the crop box.
+// Experimental API.
+func (p *PdfiumImplementation) AuditFormAccessibility(request *requests.AuditFormAccessibility) (*responses.AuditFormAccessibility, error) {
+	return nil, pdfium_errors.ErrExperimentalUnsupported
+}
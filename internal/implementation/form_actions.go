@@ -0,0 +1,1079 @@
+package implementation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// formActionCall is a JavaScript action that consists of one call to a
+// function with literal arguments, like the actions that Acrobat generates
+// for the format, keystroke, validate and calculate options of a field.
+type formActionCall struct {
+	function string
+	args     []interface{} // The arguments: float64, string, bool or []interface{}.
+}
+
+// parseFormActionScript parses the JavaScript of an action. Only scripts with
+// one function call with literal arguments are supported, since there is no
+// JavaScript engine.
+func parseFormActionScript(script string) (*formActionCall, error) {
+	parser := &formActionParser{script: stripFormActionComments(script)}
+	parser.skipSpace()
+
+	function := parser.readIdentifier()
+	if function == "" {
+		return nil, errors.New("script is not a function call")
+	}
+
+	parser.skipSpace()
+	if !parser.consume('(') {
+		return nil, errors.New("script is not a function call")
+	}
+
+	args, err := parser.readArgs(')')
+	if err != nil {
+		return nil, err
+	}
+
+	parser.skipSpace()
+	for parser.consume(';') {
+		parser.skipSpace()
+	}
+
+	if parser.pos < len(parser.script) {
+		return nil, errors.New("script contains more than one function call")
+	}
+
+	return &formActionCall{function: function, args: args}, nil
+}
+
+// stripFormActionComments removes the comments from a script. Strings are
+// kept as they are.
+func stripFormActionComments(script string) string {
+	var builder strings.Builder
+	var quote byte
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		switch {
+		case quote != 0:
+			builder.WriteByte(c)
+			if c == '\\' && i+1 < len(script) {
+				i++
+				builder.WriteByte(script[i])
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+			builder.WriteByte(c)
+		case strings.HasPrefix(script[i:], "//"):
+			for i < len(script) && script[i] != '\n' {
+				i++
+			}
+			builder.WriteByte('\n')
+		case strings.HasPrefix(script[i:], "/*"):
+			end := strings.Index(script[i+2:], "*/")
+			if end == -1 {
+				return builder.String()
+			}
+			i += end + 3
+			builder.WriteByte(' ')
+		default:
+			builder.WriteByte(c)
+		}
+	}
+
+	return builder.String()
+}
+
+// formActionParser reads the literals of a function call.
+type formActionParser struct {
+	script string
+	pos    int
+}
+
+func (p *formActionParser) skipSpace() {
+	for p.pos < len(p.script) && unicode.IsSpace(rune(p.script[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *formActionParser) consume(c byte) bool {
+	if p.pos < len(p.script) && p.script[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *formActionParser) readIdentifier() string {
+	start := p.pos
+	for p.pos < len(p.script) {
+		c := rune(p.script[p.pos])
+		if !unicode.IsLetter(c) && c != '_' && c != '$' && c != '.' && (p.pos == start || !unicode.IsDigit(c)) {
+			break
+		}
+		p.pos++
+	}
+	return p.script[start:p.pos]
+}
+
+// readArgs reads literals separated by commas until the end character.
+func (p *formActionParser) readArgs(end byte) ([]interface{}, error) {
+	args := []interface{}{}
+	p.skipSpace()
+	if p.consume(end) {
+		return args, nil
+	}
+
+	for {
+		arg, err := p.readValue()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		p.skipSpace()
+		if p.consume(end) {
+			return args, nil
+		}
+		if !p.consume(',') {
+			return nil, errors.New("script contains an unsupported expression")
+		}
+		p.skipSpace()
+	}
+}
+
+// readValue reads a number, string, boolean or array literal.
+func (p *formActionParser) readValue() (interface{}, error) {
+	p.skipSpace()
+	if p.pos >= len(p.script) {
+		return nil, errors.New("unexpected end of script")
+	}
+
+	c := p.script[p.pos]
+	switch {
+	case c == '"' || c == '\'':
+		return p.readString()
+	case c == '[':
+		p.pos++
+		return p.readArgs(']')
+	case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.script) && strings.ContainsRune("0123456789.eE+-", rune(p.script[p.pos])) {
+			p.pos++
+		}
+
+		number, err := strconv.ParseFloat(p.script[start:p.pos], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s in script", p.script[start:p.pos])
+		}
+		return number, nil
+	}
+
+	identifier := p.readIdentifier()
+	switch identifier {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "new":
+		p.skipSpace()
+		if p.readIdentifier() == "Array" {
+			p.skipSpace()
+			if p.consume('(') {
+				return p.readArgs(')')
+			}
+		}
+	}
+
+	return nil, errors.New("script contains an unsupported expression")
+}
+
+// readString reads a string literal with its escape sequences.
+func (p *formActionParser) readString() (string, error) {
+	quote := p.script[p.pos]
+	p.pos++
+
+	var builder strings.Builder
+	for p.pos < len(p.script) {
+		c := p.script[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			return builder.String(), nil
+		case c == '\\' && p.pos < len(p.script):
+			escaped := p.script[p.pos]
+			p.pos++
+			switch escaped {
+			case 'n':
+				builder.WriteByte('\n')
+			case 'r':
+				builder.WriteByte('\r')
+			case 't':
+				builder.WriteByte('\t')
+			case 'u':
+				if p.pos+4 > len(p.script) {
+					return "", errors.New("invalid escape sequence in script")
+				}
+				codePoint, err := strconv.ParseUint(p.script[p.pos:p.pos+4], 16, 16)
+				if err != nil {
+					return "", errors.New("invalid escape sequence in script")
+				}
+				builder.WriteRune(rune(codePoint))
+				p.pos += 4
+			default:
+				builder.WriteByte(escaped)
+			}
+		default:
+			builder.WriteByte(c)
+		}
+	}
+
+	return "", errors.New("unterminated string in script")
+}
+
+// number returns argument i as a number, like JavaScript converts it.
+func (c *formActionCall) number(i int) float64 {
+	if i >= len(c.args) {
+		return 0
+	}
+
+	switch arg := c.args[i].(type) {
+	case float64:
+		return arg
+	case bool:
+		if arg {
+			return 1
+		}
+	case string:
+		if number, err := strconv.ParseFloat(strings.TrimSpace(arg), 64); err == nil {
+			return number
+		}
+	}
+
+	return 0
+}
+
+// string returns argument i as a string.
+func (c *formActionCall) string(i int) string {
+	if i >= len(c.args) {
+		return ""
+	}
+
+	switch arg := c.args[i].(type) {
+	case string:
+		return arg
+	case float64:
+		return strconv.FormatFloat(arg, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(arg)
+	}
+
+	return ""
+}
+
+// bool returns argument i as a boolean, like JavaScript converts it.
+func (c *formActionCall) bool(i int) bool {
+	if i >= len(c.args) {
+		return false
+	}
+
+	switch arg := c.args[i].(type) {
+	case bool:
+		return arg
+	case float64:
+		return arg != 0
+	case string:
+		return arg != ""
+	}
+
+	return true
+}
+
+// formActionFormatMismatch is the message of Acrobat when a value is rejected
+// by a keystroke action.
+const formActionFormatMismatch = "The value entered does not match the format of the field [ %s ]"
+
+// runFormKeystrokeAction runs a keystroke action as when the value is
+// committed. The message is returned when the value is rejected.
+func runFormKeystrokeAction(call *formActionCall, fieldName, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	switch call.function {
+	case "AFNumber_Keystroke", "AFPercent_Keystroke":
+		if !isFormActionNumber(value, int(call.number(1))) {
+			return fmt.Sprintf(formActionFormatMismatch, fieldName), nil
+		}
+	case "AFDate_Keystroke", "AFDate_KeystrokeEx", "AFTime_Keystroke":
+		format, err := getFormActionDateFormat(call)
+		if err != nil {
+			return "", err
+		}
+
+		if _, ok := parseFormActionDate(value, format); !ok {
+			return fmt.Sprintf("Invalid date/time: please ensure that the date/time exists. Field [ %s ] should match format %s", fieldName, format), nil
+		}
+	case "AFSpecial_Keystroke":
+		mask, err := getFormActionSpecialMask(int(call.number(0)), value)
+		if err != nil {
+			return "", err
+		}
+
+		if !matchFormActionMask(value, mask) {
+			return fmt.Sprintf(formActionFormatMismatch, fieldName), nil
+		}
+	case "AFSpecial_KeystrokeEx":
+		if !matchFormActionMask(value, call.string(0)) {
+			return fmt.Sprintf(formActionFormatMismatch, fieldName), nil
+		}
+	default:
+		return "", fmt.Errorf("unsupported function %s", call.function)
+	}
+
+	return "", nil
+}
+
+// runFormValidateAction runs a validate action. The message is returned when
+// the value is rejected.
+func runFormValidateAction(call *formActionCall, value string) (string, error) {
+	if call.function != "AFRange_Validate" {
+		return "", fmt.Errorf("unsupported function %s", call.function)
+	}
+
+	if value == "" {
+		return "", nil
+	}
+
+	// Like JavaScript, values that are not a number are never out of range.
+	number, ok := parseFormActionNumber(value)
+	if !ok {
+		return "", nil
+	}
+
+	greaterThan, lessThan := call.bool(0), call.bool(2)
+	minimum, maximum := call.number(1), call.number(3)
+	minimumText, maximumText := strconv.FormatFloat(minimum, 'f', -1, 64), strconv.FormatFloat(maximum, 'f', -1, 64)
+	switch {
+	case greaterThan && lessThan && (number < minimum || number > maximum):
+		return fmt.Sprintf("Invalid value: must be greater than or equal to %s and less than or equal to %s.", minimumText, maximumText), nil
+	case greaterThan && !lessThan && number < minimum:
+		return fmt.Sprintf("Invalid value: must be greater than or equal to %s.", minimumText), nil
+	case lessThan && !greaterThan && number > maximum:
+		return fmt.Sprintf("Invalid value: must be less than or equal to %s.", maximumText), nil
+	}
+
+	return "", nil
+}
+
+// runFormFormatAction runs a format action and returns the value as a viewer
+// would display it.
+func runFormFormatAction(call *formActionCall, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	switch call.function {
+	case "AFNumber_Format", "AFPercent_Format":
+		number, ok := parseFormActionNumber(value)
+		if !ok {
+			return value, nil
+		}
+
+		if call.function == "AFPercent_Format" {
+			formatted := formatFormActionNumber(number*100, int(call.number(0)), int(call.number(1)))
+			if number < 0 {
+				formatted = "-" + formatted
+			}
+			return formatted + "%", nil
+		}
+
+		formatted := formatFormActionNumber(number, int(call.number(0)), int(call.number(1)))
+		if currency := call.string(4); currency != "" {
+			if call.bool(5) {
+				formatted = currency + formatted
+			} else {
+				formatted = formatted + currency
+			}
+		}
+
+		if number < 0 && math.Abs(number) >= 0.5*math.Pow(10, -call.number(0)) {
+			// Negative style 1 and 3 are displayed red, which is not part of
+			// the text.
+			switch int(call.number(2)) {
+			case 0:
+				formatted = "-" + formatted
+			case 2, 3:
+				formatted = "(" + formatted + ")"
+			}
+		}
+		return formatted, nil
+	case "AFDate_Format", "AFDate_FormatEx", "AFTime_Format", "AFTime_FormatEx":
+		format, err := getFormActionDateFormat(call)
+		if err != nil {
+			return "", err
+		}
+
+		date, ok := parseFormActionDate(value, format)
+		if !ok {
+			return value, nil
+		}
+		return formatFormActionDate(date, format), nil
+	case "AFSpecial_Format":
+		mask, err := getFormActionSpecialMask(int(call.number(0)), value)
+		if err != nil {
+			return "", err
+		}
+		return applyFormActionMask(value, mask), nil
+	case "AFSpecial_KeystrokeEx":
+		// Values are formatted while typing.
+		return value, nil
+	}
+
+	return "", fmt.Errorf("unsupported function %s", call.function)
+}
+
+// getFormCalculateFields returns the names of the fields that a calculate
+// action uses.
+func getFormCalculateFields(call *formActionCall) ([]string, error) {
+	if call.function != "AFSimple_Calculate" {
+		return nil, fmt.Errorf("unsupported function %s", call.function)
+	}
+
+	names := []string{}
+	if len(call.args) > 1 {
+		switch fields := call.args[1].(type) {
+		case string:
+			for _, name := range strings.Split(fields, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					names = append(names, name)
+				}
+			}
+		case []interface{}:
+			for _, name := range fields {
+				if name, ok := name.(string); ok {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+
+	return names, nil
+}
+
+// runFormCalculateAction runs a calculate action with the values of the
+// fields it uses.
+func runFormCalculateAction(call *formActionCall, values []string) (string, error) {
+	function := strings.ToUpper(call.string(0))
+	result := 0.0
+	if function == "PRD" {
+		result = 1
+	}
+
+	for i, value := range values {
+		// Empty values and values that are not a number, like the Off value of
+		// a checkbox, count as 0.
+		number, _ := parseFormActionNumber(value)
+		switch function {
+		case "SUM", "AVG":
+			result += number
+		case "PRD":
+			result *= number
+		case "MIN":
+			if i == 0 || number < result {
+				result = number
+			}
+		case "MAX":
+			if i == 0 || number > result {
+				result = number
+			}
+		default:
+			return "", fmt.Errorf("unsupported calculation %s", call.string(0))
+		}
+	}
+
+	if function == "AVG" && len(values) > 0 {
+		result /= float64(len(values))
+	}
+
+	return strconv.FormatFloat(result, 'f', -1, 64), nil
+}
+
+// getFormCalculationOrder returns the fields with a calculate action in the
+// order to calculate them. Fields are calculated after the calculated fields
+// they use, otherwise the given order is kept. Fields in a cycle are
+// calculated in the given order. Duplicate names are only calculated once.
+func getFormCalculationOrder(names []string, dependencies map[string][]string) []string {
+	order := []string{}
+	done := map[string]bool{}
+	pending := map[string]bool{}
+	uniqueNames := []string{}
+	for _, name := range names {
+		if !pending[name] {
+			uniqueNames = append(uniqueNames, name)
+		}
+		pending[name] = true
+	}
+	names = uniqueNames
+
+	for len(order) < len(names) {
+		next := ""
+		for _, name := range names {
+			if done[name] {
+				continue
+			}
+
+			ready := true
+			for _, dependency := range dependencies[name] {
+				if dependency != name && pending[dependency] && !done[dependency] {
+					ready = false
+					break
+				}
+			}
+
+			if ready {
+				next = name
+				break
+			}
+		}
+
+		// A cycle, continue with the first field that is left.
+		if next == "" {
+			for _, name := range names {
+				if !done[name] {
+					next = name
+					break
+				}
+			}
+		}
+
+		done[next] = true
+		order = append(order, next)
+	}
+
+	return order
+}
+
+// readFormCalculationOrder reads the full names of the fields in the
+// calculation order (/CO) of the AcroForm of a document that is saved without
+// security. The second return value is false when the form has no calculation
+// order.
+func readFormCalculationOrder(data []byte) ([]string, bool, error) {
+	update, err := newPDFUpdate(data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	_, catalog, err := update.catalog()
+	if err != nil {
+		return nil, false, err
+	}
+
+	acroForm, err := update.dict(catalog["AcroForm"])
+	if err != nil {
+		return nil, false, err
+	}
+
+	if acroForm == nil || acroForm["CO"] == nil {
+		return nil, false, nil
+	}
+
+	calculationOrder, err := update.array(acroForm["CO"])
+	if err != nil {
+		return nil, false, err
+	}
+
+	fields, err := update.array(acroForm["Fields"])
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The calculation order refers to the field objects, the full name of a
+	// field is the name of its parents and its partial name. Widgets without
+	// a partial name get the name of their field.
+	names := map[fdfReference]string{}
+	var walk func(kids []interface{}, parentName string) error
+	walk = func(kids []interface{}, parentName string) error {
+		for _, kid := range kids {
+			reference, ok := kid.(fdfReference)
+			if !ok {
+				continue
+			}
+
+			if _, ok := names[reference]; ok {
+				continue
+			}
+
+			dict, err := update.dict(reference)
+			if err != nil {
+				return err
+			}
+
+			if dict == nil {
+				continue
+			}
+
+			name := parentName
+			if partialName, ok := dict["T"].(string); ok {
+				name = decodePDFTextString(partialName)
+				if parentName != "" {
+					name = parentName + "." + name
+				}
+			}
+			names[reference] = name
+
+			childKids, err := update.array(dict["Kids"])
+			if err != nil {
+				return err
+			}
+
+			if err := walk(childKids, name); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := walk(fields, ""); err != nil {
+		return nil, false, err
+	}
+
+	order := []string{}
+	for _, item := range calculationOrder {
+		reference, ok := item.(fdfReference)
+		if !ok || names[reference] == "" {
+			continue
+		}
+		order = append(order, names[reference])
+	}
+
+	return order, true, nil
+}
+
+// matchFormFieldNames returns the full names of the fields that a name in a
+// calculation refers to: the field itself, or the fields below it.
+func matchFormFieldNames(fieldNames []string, name string) []string {
+	matches := []string{}
+	for _, fieldName := range fieldNames {
+		if fieldName == name || strings.HasPrefix(fieldName, name+".") {
+			matches = append(matches, fieldName)
+		}
+	}
+	return matches
+}
+
+// parseFormActionNumber converts a value to a number, like AFMakeNumber.
+func parseFormActionNumber(value string) (float64, bool) {
+	cleaned := strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '-' || r == '.' || r == ',' {
+			return r
+		}
+		return -1
+	}, value)
+
+	// The separator that comes last is the decimal separator.
+	lastDot, lastComma := strings.LastIndex(cleaned, "."), strings.LastIndex(cleaned, ",")
+	if lastComma > lastDot {
+		cleaned = strings.ReplaceAll(cleaned, ".", "")
+		cleaned = strings.ReplaceAll(cleaned, ",", ".")
+	} else {
+		cleaned = strings.ReplaceAll(cleaned, ",", "")
+	}
+
+	number, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, false
+	}
+	return number, true
+}
+
+var formActionNumberRegexp = regexp.MustCompile(`^[+-]?(\d+([.,]\d*)?|[.,]\d+)$`)
+
+// isFormActionNumber returns whether a committed value is a number in the
+// separator style of AFNumber_Keystroke.
+func isFormActionNumber(value string, separatorStyle int) bool {
+	value = strings.TrimSpace(value)
+	switch separatorStyle {
+	case 0:
+		value = strings.ReplaceAll(value, ",", "")
+	case 2:
+		value = strings.ReplaceAll(value, ".", "")
+	case 4:
+		value = strings.ReplaceAll(value, "'", "")
+	}
+	return formActionNumberRegexp.MatchString(value)
+}
+
+// formatFormActionNumber formats the absolute value of a number with the
+// separator style of AFNumber_Format: 0 is 1,234.56, 1 is 1234.56, 2 is
+// 1.234,56, 3 is 1234,56 and 4 is 1'234.56.
+func formatFormActionNumber(number float64, decimals, separatorStyle int) string {
+	if decimals < 0 {
+		decimals = 0
+	}
+
+	// Round half away from zero, like Acrobat.
+	scale := math.Pow(10, float64(decimals))
+	formatted := strconv.FormatFloat(math.Round(math.Abs(number)*scale)/scale, 'f', decimals, 64)
+	integer, fraction := formatted, ""
+	if index := strings.Index(formatted, "."); index != -1 {
+		integer, fraction = formatted[:index], formatted[index+1:]
+	}
+
+	groupSeparator, decimalSeparator := "", "."
+	switch separatorStyle {
+	case 0:
+		groupSeparator = ","
+	case 2:
+		groupSeparator, decimalSeparator = ".", ","
+	case 3:
+		decimalSeparator = ","
+	case 4:
+		groupSeparator = "'"
+	}
+
+	if groupSeparator != "" {
+		var builder strings.Builder
+		for i, digit := range integer {
+			if i > 0 && (len(integer)-i)%3 == 0 {
+				builder.WriteString(groupSeparator)
+			}
+			builder.WriteRune(digit)
+		}
+		integer = builder.String()
+	}
+
+	if fraction == "" {
+		return integer
+	}
+	return integer + decimalSeparator + fraction
+}
+
+// formActionDateFormats are the formats of AFDate_Format.
+var formActionDateFormats = []string{"m/d", "m/d/yy", "mm/dd/yy", "mm/yy", "d-mmm", "d-mmm-yy", "dd-mmm-yy", "yy-mm-dd", "mmm-yy", "mmmm-yy", "mmm d, yyyy", "mmmm d, yyyy", "m/d/yy h:MM tt", "m/d/yy HH:MM"}
+
+// formActionTimeFormats are the formats of AFTime_Format.
+var formActionTimeFormats = []string{"HH:MM", "h:MM tt", "HH:MM:ss", "h:MM:ss tt"}
+
+// getFormActionDateFormat returns the date format of a date or time action.
+func getFormActionDateFormat(call *formActionCall) (string, error) {
+	switch call.function {
+	case "AFDate_FormatEx", "AFDate_KeystrokeEx", "AFTime_FormatEx":
+		return call.string(0), nil
+	case "AFDate_Format", "AFDate_Keystroke":
+		index := int(call.number(0))
+		if index < 0 || index >= len(formActionDateFormats) {
+			return "", fmt.Errorf("unsupported date format %d", index)
+		}
+		return formActionDateFormats[index], nil
+	case "AFTime_Format", "AFTime_Keystroke":
+		index := int(call.number(0))
+		if index < 0 || index >= len(formActionTimeFormats) {
+			return "", fmt.Errorf("unsupported time format %d", index)
+		}
+		return formActionTimeFormats[index], nil
+	}
+
+	return "", fmt.Errorf("unsupported function %s", call.function)
+}
+
+// formActionDateTokens are the parts of a date format, longest first.
+var formActionDateTokens = []string{"yyyy", "yy", "mmmm", "mmm", "mm", "m", "dddd", "ddd", "dd", "d", "HH", "H", "hh", "h", "MM", "M", "ss", "s", "tt", "t"}
+
+// tokenizeFormActionDateFormat splits a date format into its tokens and the
+// literal text between them.
+func tokenizeFormActionDateFormat(format string) []string {
+	tokens := []string{}
+	for len(format) > 0 {
+		matched := false
+		for _, token := range formActionDateTokens {
+			if strings.HasPrefix(format, token) {
+				tokens = append(tokens, token)
+				format = format[len(token):]
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			tokens = append(tokens, format[:1])
+			format = format[1:]
+		}
+	}
+	return tokens
+}
+
+// formActionDateNumberTokens are the tokens of a date format that are
+// numbers.
+var formActionDateNumberTokens = map[string]bool{"yyyy": true, "yy": true, "mm": true, "m": true, "dd": true, "d": true, "HH": true, "H": true, "hh": true, "h": true, "MM": true, "M": true, "ss": true, "s": true}
+
+var formActionDatePartRegexp = regexp.MustCompile(`\d+|\pL+`)
+
+// parseFormActionDate parses a value with a date format. Like Acrobat, the
+// numbers of the value are assigned to the parts of the format in order, so
+// the separators don't have to match. Month names are accepted anywhere.
+func parseFormActionDate(value, format string) (time.Time, bool) {
+	numbers := []int{}
+	month, pm, am := 0, false, false
+	for _, part := range formActionDatePartRegexp.FindAllString(value, -1) {
+		if number, err := strconv.Atoi(part); err == nil {
+			numbers = append(numbers, number)
+			continue
+		}
+
+		lowerPart := strings.ToLower(part)
+		switch {
+		case lowerPart == "pm" || lowerPart == "p":
+			pm = true
+		case lowerPart == "am" || lowerPart == "a":
+			am = true
+		case len(lowerPart) >= 3:
+			for i := time.January; i <= time.December; i++ {
+				if strings.HasPrefix(strings.ToLower(i.String()), lowerPart[:3]) {
+					month = int(i)
+				}
+			}
+			if month == 0 {
+				return time.Time{}, false
+			}
+		default:
+			return time.Time{}, false
+		}
+	}
+
+	if len(numbers) == 0 {
+		return time.Time{}, false
+	}
+
+	hasMonthName := month != 0
+	year, day, hour, minute, second := time.Now().Year(), 1, 0, 0, 0
+	hasDate := false
+	for _, token := range tokenizeFormActionDateFormat(format) {
+		// Month names, day names, AM/PM and literals are not numbers. A month
+		// number is accepted for a month name when the value has no name.
+		isMonthName := token == "mmm" || token == "mmmm"
+		if !formActionDateNumberTokens[token] && !(isMonthName && !hasMonthName) {
+			continue
+		}
+
+		if len(numbers) == 0 {
+			break
+		}
+
+		number := numbers[0]
+		numbers = numbers[1:]
+		switch token[0] {
+		case 'y':
+			year = number
+			if len(token) == 2 || number < 100 {
+				year = 1900 + number
+				if number < 50 {
+					year = 2000 + number
+				}
+			}
+			hasDate = true
+		case 'm':
+			month = number
+			hasDate = true
+		case 'd':
+			day = number
+			hasDate = true
+		case 'H', 'h':
+			hour = number
+		case 'M':
+			minute = number
+		case 's':
+			second = number
+		}
+	}
+
+	// Values with more numbers than the format don't match.
+	if len(numbers) > 0 {
+		return time.Time{}, false
+	}
+
+	if pm && hour < 12 {
+		hour += 12
+	} else if am && hour == 12 {
+		hour = 0
+	}
+
+	if month == 0 {
+		month = 1
+		if hasDate && strings.Contains(format, "m") {
+			return time.Time{}, false
+		}
+	}
+
+	date := time.Date(year, time.Month(month), day, hour, minute, second, 0, time.UTC)
+	if date.Year() != year || int(date.Month()) != month || date.Day() != day || date.Hour() != hour || date.Minute() != minute || date.Second() != second {
+		return time.Time{}, false
+	}
+
+	return date, true
+}
+
+// formatFormActionDate formats a date with a date format.
+func formatFormActionDate(date time.Time, format string) string {
+	var builder strings.Builder
+	for _, token := range tokenizeFormActionDateFormat(format) {
+		hour12 := date.Hour() % 12
+		if hour12 == 0 {
+			hour12 = 12
+		}
+
+		switch token {
+		case "yyyy":
+			builder.WriteString(fmt.Sprintf("%04d", date.Year()))
+		case "yy":
+			builder.WriteString(fmt.Sprintf("%02d", date.Year()%100))
+		case "mmmm":
+			builder.WriteString(date.Month().String())
+		case "mmm":
+			builder.WriteString(date.Month().String()[:3])
+		case "mm":
+			builder.WriteString(fmt.Sprintf("%02d", int(date.Month())))
+		case "m":
+			builder.WriteString(strconv.Itoa(int(date.Month())))
+		case "dddd":
+			builder.WriteString(date.Weekday().String())
+		case "ddd":
+			builder.WriteString(date.Weekday().String()[:3])
+		case "dd":
+			builder.WriteString(fmt.Sprintf("%02d", date.Day()))
+		case "d":
+			builder.WriteString(strconv.Itoa(date.Day()))
+		case "HH":
+			builder.WriteString(fmt.Sprintf("%02d", date.Hour()))
+		case "H":
+			builder.WriteString(strconv.Itoa(date.Hour()))
+		case "hh":
+			builder.WriteString(fmt.Sprintf("%02d", hour12))
+		case "h":
+			builder.WriteString(strconv.Itoa(hour12))
+		case "MM":
+			builder.WriteString(fmt.Sprintf("%02d", date.Minute()))
+		case "M":
+			builder.WriteString(strconv.Itoa(date.Minute()))
+		case "ss":
+			builder.WriteString(fmt.Sprintf("%02d", date.Second()))
+		case "s":
+			builder.WriteString(strconv.Itoa(date.Second()))
+		case "tt", "t":
+			suffix := "am"
+			if date.Hour() >= 12 {
+				suffix = "pm"
+			}
+			builder.WriteString(suffix[:len(token)])
+		default:
+			builder.WriteString(token)
+		}
+	}
+	return builder.String()
+}
+
+// getFormActionSpecialMask returns the mask of a format of AFSpecial_Format:
+// 0 is a zip code, 1 is a zip+4 code, 2 is a phone number and 3 is a social
+// security number.
+func getFormActionSpecialMask(format int, value string) (string, error) {
+	switch format {
+	case 0:
+		return "99999", nil
+	case 1:
+		return "99999-9999", nil
+	case 2:
+		// Phone numbers can be without area code.
+		digits := 0
+		for _, r := range value {
+			if r >= '0' && r <= '9' {
+				digits++
+			}
+		}
+		if digits == 7 {
+			return "999-9999", nil
+		}
+		return "(999) 999-9999", nil
+	case 3:
+		return "999-99-9999", nil
+	}
+
+	return "", fmt.Errorf("unsupported special format %d", format)
+}
+
+// matchFormActionMask returns whether a value matches a mask of
+// AFSpecial_KeystrokeEx: 9 is a digit, A is a letter, O is a letter or digit,
+// X is any character and the other characters must match. Values without the
+// literal characters of the mask also match.
+func matchFormActionMask(value, mask string) bool {
+	matches := func(value, mask []rune) bool {
+		if len(value) != len(mask) {
+			return false
+		}
+
+		for i, r := range mask {
+			switch r {
+			case '9':
+				if !unicode.IsDigit(value[i]) {
+					return false
+				}
+			case 'A':
+				if !unicode.IsLetter(value[i]) {
+					return false
+				}
+			case 'O':
+				if !unicode.IsLetter(value[i]) && !unicode.IsDigit(value[i]) {
+					return false
+				}
+			case 'X':
+			default:
+				if value[i] != r {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	placeholders := []rune{}
+	for _, r := range mask {
+		if strings.ContainsRune("9AOX", r) {
+			placeholders = append(placeholders, r)
+		}
+	}
+
+	return matches([]rune(value), []rune(mask)) || matches([]rune(value), placeholders)
+}
+
+// applyFormActionMask puts the characters of a value into a mask, the value is
+// returned as is when it doesn't match the mask.
+func applyFormActionMask(value, mask string) string {
+	if !matchFormActionMask(value, mask) {
+		return value
+	}
+
+	valueRunes := []rune(value)
+	if len(valueRunes) == len([]rune(mask)) {
+		return value
+	}
+
+	var builder strings.Builder
+	for _, r := range mask {
+		if strings.ContainsRune("9AOX", r) {
+			builder.WriteRune(valueRunes[0])
+			valueRunes = valueRunes[1:]
+		} else {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,332 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+// #include "fpdf_annot.h"
+// #include "fpdf_formfill.h"
+import "C"
+
+import (
+	"fmt"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// formFieldActions are the JavaScript actions of a field, empty when the field
+// has no action for the event.
+type formFieldActions map[enums.FPDF_ANNOT_AACTION]string
+
+// RunFormActions fills the form fields of a document and runs the keystroke,
+// validate, calculate and format actions of the fields.
+// Experimental API.
+func (p *PdfiumImplementation) RunFormActions(request *requests.RunFormActions) (*responses.RunFormActions, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	formHandle, closeFormHandle, err := p.getFormHandle(documentHandle)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFormHandle()
+
+	fields, err := p.getFormFields(documentHandle, formHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(request.Values) > 0 {
+		if err := p.fillForm(documentHandle, formHandle, fields, request.Values); err != nil {
+			return nil, err
+		}
+
+		fields, err = p.getFormFields(documentHandle, formHandle)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &responses.RunFormActions{
+		Fields:           []responses.FormActionsField{},
+		ValidationErrors: []responses.FormActionsMessage{},
+		SkippedActions:   []responses.FormActionsMessage{},
+	}
+
+	skipAction := func(name string, event enums.FPDF_ANNOT_AACTION, message string) {
+		resp.SkippedActions = append(resp.SkippedActions, responses.FormActionsMessage{Name: name, Event: event, Message: message})
+	}
+
+	fieldNames := make([]string, len(fields))
+	fieldsByName := map[string]responses.FormField{}
+	values := map[string]string{}
+	actions := map[string]formFieldActions{}
+	for i, field := range fields {
+		fieldNames[i] = field.Name
+		fieldsByName[field.Name] = field
+		values[field.Name] = field.Value
+
+		fieldActions, err := p.getFormFieldActions(documentHandle, formHandle, field)
+		if err != nil {
+			return nil, err
+		}
+		actions[field.Name] = fieldActions
+	}
+
+	// validateField runs the actions that check a value, the keystroke action
+	// is only run for values that were entered.
+	validateField := func(name string, events ...enums.FPDF_ANNOT_AACTION) {
+		for _, event := range events {
+			script := actions[name][event]
+			if script == "" {
+				continue
+			}
+
+			call, err := parseFormActionScript(script)
+			if err != nil {
+				skipAction(name, event, err.Error())
+				continue
+			}
+
+			var message string
+			if event == enums.FPDF_ANNOT_AACTION_KEY_STROKE {
+				message, err = runFormKeystrokeAction(call, name, values[name])
+			} else {
+				message, err = runFormValidateAction(call, values[name])
+			}
+
+			if err != nil {
+				skipAction(name, event, err.Error())
+				continue
+			}
+
+			if message != "" {
+				resp.ValidationErrors = append(resp.ValidationErrors, responses.FormActionsMessage{Name: name, Event: event, Message: message})
+
+				// Like a viewer, the validate action is not run when the
+				// keystroke action rejects the value.
+				return
+			}
+		}
+	}
+
+	for _, name := range fieldNames {
+		validateField(name, enums.FPDF_ANNOT_AACTION_KEY_STROKE, enums.FPDF_ANNOT_AACTION_VALIDATE)
+	}
+
+	calculateNames := []string{}
+	calculations := map[string]*formActionCall{}
+	dependencies := map[string][]string{}
+	for _, name := range fieldNames {
+		script := actions[name][enums.FPDF_ANNOT_AACTION_CALCULATE]
+		if script == "" {
+			continue
+		}
+
+		call, err := parseFormActionScript(script)
+		if err != nil {
+			skipAction(name, enums.FPDF_ANNOT_AACTION_CALCULATE, err.Error())
+			continue
+		}
+
+		calculateFields, err := getFormCalculateFields(call)
+		if err != nil {
+			skipAction(name, enums.FPDF_ANNOT_AACTION_CALCULATE, err.Error())
+			continue
+		}
+
+		calculateNames = append(calculateNames, name)
+		calculations[name] = call
+		for _, calculateField := range calculateFields {
+			dependencies[name] = append(dependencies[name], matchFormFieldNames(fieldNames, calculateField)...)
+		}
+	}
+
+	// PDFium doesn't expose the calculation order of the form, so it is read
+	// from a copy of the document. The copy is saved without security, so
+	// that the names of the fields can be read. Like a viewer, only the fields
+	// in the calculation order are calculated. Without a calculation order,
+	// the fields are calculated in the order of their dependencies.
+	data, err := p.saveDocument(documentHandle.handle, requests.SaveFlagRemoveSecurity, 0, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	calculationOrder, hasCalculationOrder, err := readFormCalculationOrder(*data)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasCalculationOrder {
+		inCalculationOrder := map[string]bool{}
+		for _, name := range calculationOrder {
+			inCalculationOrder[name] = true
+		}
+
+		for _, name := range calculateNames {
+			if !inCalculationOrder[name] {
+				skipAction(name, enums.FPDF_ANNOT_AACTION_CALCULATE, "field is not in the calculation order of the form")
+			}
+		}
+	} else {
+		calculationOrder = getFormCalculationOrder(calculateNames, dependencies)
+	}
+
+	// Fields can be in the calculation order more than once, they are only
+	// calculated once.
+	calculated := map[string]bool{}
+	done := map[string]bool{}
+	for _, name := range calculationOrder {
+		call, ok := calculations[name]
+		if !ok || done[name] {
+			continue
+		}
+		done[name] = true
+
+		calculateFields, _ := getFormCalculateFields(call)
+
+		calculateValues := []string{}
+		missingField := ""
+		for _, calculateField := range calculateFields {
+			matches := matchFormFieldNames(fieldNames, calculateField)
+			if len(matches) == 0 {
+				missingField = calculateField
+				break
+			}
+
+			for _, match := range matches {
+				calculateValues = append(calculateValues, values[match])
+			}
+		}
+
+		if missingField != "" {
+			skipAction(name, enums.FPDF_ANNOT_AACTION_CALCULATE, fmt.Sprintf("field %s of the calculation not found", missingField))
+			continue
+		}
+
+		value, err := runFormCalculateAction(call, calculateValues)
+		if err != nil {
+			skipAction(name, enums.FPDF_ANNOT_AACTION_CALCULATE, err.Error())
+			continue
+		}
+
+		calculated[name] = true
+		if value == values[name] {
+			continue
+		}
+
+		field := fieldsByName[name]
+		if field.Type != enums.FPDF_FORMFIELD_TYPE_TEXTFIELD {
+			skipAction(name, enums.FPDF_ANNOT_AACTION_CALCULATE, "calculated values can only be set on text fields")
+			delete(calculated, name)
+			continue
+		}
+
+		// Calculated fields are often read only, so the value is set without
+		// the checks of fillForm.
+		for _, widget := range field.Widgets {
+			err = p.fillFormPage(documentHandle, formHandle, widget.Page, []formFillWidget{{field: name, widget: widget, text: &value}})
+			if err != nil {
+				break
+			}
+		}
+
+		if err != nil {
+			skipAction(name, enums.FPDF_ANNOT_AACTION_CALCULATE, fmt.Sprintf("could not set the calculated value: %s", err.Error()))
+			delete(calculated, name)
+			continue
+		}
+
+		values[name] = value
+		validateField(name, enums.FPDF_ANNOT_AACTION_VALIDATE)
+	}
+
+	// Read the values back, PDFium doesn't change the value of every field.
+	filledFields, err := p.getFormFields(documentHandle, formHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range filledFields {
+		fieldActions := actions[field.Name]
+		if len(fieldActions) == 0 {
+			continue
+		}
+
+		if calculated[field.Name] && field.Value != values[field.Name] {
+			skipAction(field.Name, enums.FPDF_ANNOT_AACTION_CALCULATE, fmt.Sprintf("PDFium did not accept the calculated value %s", values[field.Name]))
+			calculated[field.Name] = false
+		}
+
+		formattedValue := field.Value
+		if script := fieldActions[enums.FPDF_ANNOT_AACTION_FORMAT]; script != "" {
+			call, err := parseFormActionScript(script)
+			if err == nil {
+				formattedValue, err = runFormFormatAction(call, field.Value)
+			}
+
+			if err != nil {
+				skipAction(field.Name, enums.FPDF_ANNOT_AACTION_FORMAT, err.Error())
+				formattedValue = field.Value
+			}
+		}
+
+		resp.Fields = append(resp.Fields, responses.FormActionsField{
+			Name:           field.Name,
+			Value:          field.Value,
+			FormattedValue: formattedValue,
+			Calculated:     calculated[field.Name],
+		})
+	}
+
+	return resp, nil
+}
+
+// getFormFieldActions returns the JavaScript of the keystroke, format, validate
+// and calculate actions of a field. The actions are read from the first widget
+// of the field, PDFium returns the actions of the field itself.
+func (p *PdfiumImplementation) getFormFieldActions(documentHandle *DocumentHandle, formHandle C.FPDF_FORMHANDLE, field responses.FormField) (formFieldActions, error) {
+	fieldActions := formFieldActions{}
+	if len(field.Widgets) == 0 {
+		return fieldActions, nil
+	}
+
+	pageHandle, err := p.loadPage(requests.Page{
+		ByIndex: &requests.PageByIndex{
+			Document: documentHandle.nativeRef,
+			Index:    field.Widgets[0].Page,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	annotation := C.FPDFPage_GetAnnot(pageHandle.handle, C.int(field.Widgets[0].Index))
+	if annotation == nil {
+		return nil, fmt.Errorf("could not get widget of field %s", field.Name)
+	}
+	defer C.FPDFPage_CloseAnnot(annotation)
+
+	for _, event := range []enums.FPDF_ANNOT_AACTION{enums.FPDF_ANNOT_AACTION_KEY_STROKE, enums.FPDF_ANNOT_AACTION_FORMAT, enums.FPDF_ANNOT_AACTION_VALIDATE, enums.FPDF_ANNOT_AACTION_CALCULATE} {
+		script, err := p.getFormFieldString(func(buffer *C.FPDF_WCHAR, length C.ulong) C.ulong {
+			return C.FPDFAnnot_GetFormAdditionalActionJavaScript(formHandle, annotation, C.int(event), buffer, length)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if script != "" {
+			fieldActions[event] = script
+		}
+	}
+
+	return fieldActions, nil
+}
@@ -0,0 +1,193 @@
+package implementation
+
+import (
+	"bytes"
+	"io/ioutil"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("form actions", func() {
+	DescribeTable("parseFormActionScript",
+		func(script string, expected *formActionCall, expectedError string) {
+			call, err := parseFormActionScript(script)
+			if expectedError != "" {
+				Expect(err).To(MatchError(expectedError))
+				Expect(call).To(BeNil())
+				return
+			}
+
+			Expect(err).To(BeNil())
+			Expect(call).To(Equal(expected))
+		},
+		Entry("numbers, strings and booleans", `AFNumber_Format(2, 0, 0, 0, "$", true);`, &formActionCall{function: "AFNumber_Format", args: []interface{}{2.0, 0.0, 0.0, 0.0, "$", true}}, ""),
+		Entry("comments and arrays", "/* Sum */ AFSimple_Calculate(\"SUM\", new Array (\"a\", 'b')); // Total\n", &formActionCall{function: "AFSimple_Calculate", args: []interface{}{"SUM", []interface{}{"a", "b"}}}, ""),
+		Entry("array literals and negative numbers", `AFRange_Validate(true, -1.5, false, [1, 2])`, &formActionCall{function: "AFRange_Validate", args: []interface{}{true, -1.5, false, []interface{}{1.0, 2.0}}}, ""),
+		Entry("escape sequences", `AFSpecial_KeystrokeEx('99\'9A\n')`, &formActionCall{function: "AFSpecial_KeystrokeEx", args: []interface{}{"99'9A\n"}}, ""),
+		Entry("comment characters in strings", `AFDate_FormatEx("m//d")`, &formActionCall{function: "AFDate_FormatEx", args: []interface{}{"m//d"}}, ""),
+		Entry("no arguments", `AFTime_Keystroke()`, &formActionCall{function: "AFTime_Keystroke", args: []interface{}{}}, ""),
+		Entry("empty script", ``, nil, "script is not a function call"),
+		Entry("assignment", `event.value = 1`, nil, "script is not a function call"),
+		Entry("more than one call", `AFNumber_Format(2); AFNumber_Format(3)`, nil, "script contains more than one function call"),
+		Entry("variable argument", `AFNumber_Format(decimals)`, nil, "script contains an unsupported expression"),
+		Entry("unterminated string", `AFDate_FormatEx("mm/dd`, nil, "unterminated string in script"),
+		Entry("invalid number", `AFNumber_Format(1.2.3)`, nil, "invalid number 1.2.3 in script"),
+	)
+
+	DescribeTable("formatFormActionNumber",
+		func(number float64, decimals, separatorStyle int, expected string) {
+			Expect(formatFormActionNumber(number, decimals, separatorStyle)).To(Equal(expected))
+		},
+		Entry(nil, 1234.567, 2, 0, "1,234.57"),
+		Entry(nil, 1234.567, 2, 1, "1234.57"),
+		Entry(nil, 1234567.891, 2, 2, "1.234.567,89"),
+		Entry(nil, 1234.5, 1, 3, "1234,5"),
+		Entry(nil, 1234567.0, 0, 4, "1'234'567"),
+		Entry(nil, 999.999, 2, 0, "1,000.00"),
+		Entry(nil, 0.125, 2, 0, "0.13"),
+		Entry(nil, -2.5, 0, 0, "3"),
+		Entry(nil, 5.0, -1, 1, "5"),
+		Entry(nil, 123.0, 0, 0, "123"),
+	)
+
+	DescribeTable("parseFormActionDate",
+		func(value, format string, expected time.Time, expectedOk bool) {
+			date, ok := parseFormActionDate(value, format)
+			Expect(ok).To(Equal(expectedOk))
+			if expectedOk {
+				Expect(date).To(BeTemporally("==", expected))
+			}
+		},
+		Entry(nil, "12/25/2023", "mm/dd/yyyy", time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC), true),
+		Entry(nil, "1/2/23", "m/d/yy", time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), true),
+		Entry(nil, "1.2.75", "m/d/yy", time.Date(1975, 1, 2, 0, 0, 0, 0, time.UTC), true),
+		Entry(nil, "5/6/7", "m/d/yyyy", time.Date(2007, 5, 6, 0, 0, 0, 0, time.UTC), true),
+		Entry(nil, "25-Dec-99", "dd-mmm-yy", time.Date(1999, 12, 25, 0, 0, 0, 0, time.UTC), true),
+		Entry(nil, "25-12-99", "dd-mmm-yy", time.Date(1999, 12, 25, 0, 0, 0, 0, time.UTC), true),
+		Entry(nil, "December 5, 2023", "mmmm d, yyyy", time.Date(2023, 12, 5, 0, 0, 0, 0, time.UTC), true),
+		Entry(nil, "3:05 pm", "h:MM tt", time.Date(time.Now().Year(), 1, 1, 15, 5, 0, 0, time.UTC), true),
+		Entry(nil, "12:00 am", "h:MM tt", time.Date(time.Now().Year(), 1, 1, 0, 0, 0, 0, time.UTC), true),
+		Entry(nil, "23:59:30", "HH:MM:ss", time.Date(time.Now().Year(), 1, 1, 23, 59, 30, 0, time.UTC), true),
+		Entry(nil, "2/30/2023", "m/d/yyyy", time.Time{}, false),
+		Entry(nil, "1/2/3/4", "m/d/yy", time.Time{}, false),
+		Entry(nil, "hello", "mm/dd/yyyy", time.Time{}, false),
+		Entry(nil, "", "mm/dd/yyyy", time.Time{}, false),
+		Entry(nil, "25:00", "HH:MM", time.Time{}, false),
+	)
+
+	DescribeTable("formatFormActionDate",
+		func(date time.Time, format string, expected string) {
+			Expect(formatFormActionDate(date, format)).To(Equal(expected))
+		},
+		Entry(nil, time.Date(2023, 12, 5, 15, 4, 9, 0, time.UTC), "mm/dd/yyyy", "12/05/2023"),
+		Entry(nil, time.Date(2023, 12, 5, 15, 4, 9, 0, time.UTC), "m/d/yy", "12/5/23"),
+		Entry(nil, time.Date(2023, 12, 5, 15, 4, 9, 0, time.UTC), "mmmm d, yyyy", "December 5, 2023"),
+		Entry(nil, time.Date(2023, 12, 5, 15, 4, 9, 0, time.UTC), "ddd mmm dd yy", "Tue Dec 05 23"),
+		Entry(nil, time.Date(2023, 12, 5, 15, 4, 9, 0, time.UTC), "dddd", "Tuesday"),
+		Entry(nil, time.Date(2023, 12, 5, 15, 4, 9, 0, time.UTC), "h:MM tt", "3:04 pm"),
+		Entry(nil, time.Date(2023, 12, 5, 15, 4, 9, 0, time.UTC), "hh:MM t", "03:04 p"),
+		Entry(nil, time.Date(2023, 12, 5, 15, 4, 9, 0, time.UTC), "HH:MM:ss", "15:04:09"),
+		Entry(nil, time.Date(2023, 12, 5, 15, 4, 9, 0, time.UTC), "H:M:s", "15:4:9"),
+		Entry(nil, time.Date(2023, 1, 1, 0, 30, 0, 0, time.UTC), "h:MM tt", "12:30 am"),
+	)
+
+	DescribeTable("parses formatted dates back into the same date",
+		func(date time.Time, format string) {
+			parsed, ok := parseFormActionDate(formatFormActionDate(date, format), format)
+			Expect(ok).To(BeTrue())
+			Expect(parsed).To(BeTemporally("==", date))
+		},
+		Entry(nil, time.Date(2023, 12, 5, 0, 0, 0, 0, time.UTC), "mm/dd/yy"),
+		Entry(nil, time.Date(2023, 12, 5, 0, 0, 0, 0, time.UTC), "d-mmm-yy"),
+		Entry(nil, time.Date(2023, 12, 5, 0, 0, 0, 0, time.UTC), "yy-mm-dd"),
+		Entry(nil, time.Date(2023, 12, 5, 0, 0, 0, 0, time.UTC), "mmmm d, yyyy"),
+		Entry(nil, time.Date(2023, 12, 5, 15, 4, 0, 0, time.UTC), "m/d/yy h:MM tt"),
+		Entry(nil, time.Date(2023, 12, 5, 15, 4, 0, 0, time.UTC), "m/d/yy HH:MM"),
+	)
+
+	DescribeTable("getFormActionSpecialMask",
+		func(format int, value string, expected string, expectedError string) {
+			mask, err := getFormActionSpecialMask(format, value)
+			if expectedError != "" {
+				Expect(err).To(MatchError(expectedError))
+				return
+			}
+
+			Expect(err).To(BeNil())
+			Expect(mask).To(Equal(expected))
+		},
+		Entry(nil, 0, "", "99999", ""),
+		Entry(nil, 1, "", "99999-9999", ""),
+		Entry(nil, 2, "555-1234", "999-9999", ""),
+		Entry(nil, 2, "5551234567", "(999) 999-9999", ""),
+		Entry(nil, 3, "", "999-99-9999", ""),
+		Entry(nil, 4, "", "", "unsupported special format 4"),
+	)
+
+	DescribeTable("matchFormActionMask and applyFormActionMask",
+		func(value, mask string, expectedMatches bool, expectedApplied string) {
+			Expect(matchFormActionMask(value, mask)).To(Equal(expectedMatches))
+			Expect(applyFormActionMask(value, mask)).To(Equal(expectedApplied))
+		},
+		Entry(nil, "12345", "99999", true, "12345"),
+		Entry(nil, "1234", "99999", false, "1234"),
+		Entry(nil, "123-45-6789", "999-99-9999", true, "123-45-6789"),
+		Entry(nil, "123456789", "999-99-9999", true, "123-45-6789"),
+		Entry(nil, "12a456789", "999-99-9999", false, "12a456789"),
+		Entry(nil, "123-456789", "999-99-9999", false, "123-456789"),
+		Entry(nil, "5551234567", "(999) 999-9999", true, "(555) 123-4567"),
+		Entry(nil, "AB12", "AA99", true, "AB12"),
+		Entry(nil, "1B12", "AA99", false, "1B12"),
+		Entry(nil, "A1B2", "OOOO", true, "A1B2"),
+		Entry(nil, "A-B?", "XXXX", true, "A-B?"),
+	)
+
+	DescribeTable("getFormCalculationOrder",
+		func(names []string, dependencies map[string][]string, expected []string) {
+			Expect(getFormCalculationOrder(names, dependencies)).To(Equal(expected))
+		},
+		Entry("no dependencies", []string{"a", "b", "c"}, nil, []string{"a", "b", "c"}),
+		Entry("chain", []string{"a", "b", "c"}, map[string][]string{"a": {"b"}, "b": {"c"}}, []string{"c", "b", "a"}),
+		Entry("dependencies without calculate action", []string{"a", "b"}, map[string][]string{"a": {"x"}}, []string{"a", "b"}),
+		Entry("self dependency", []string{"a", "b"}, map[string][]string{"a": {"a"}}, []string{"a", "b"}),
+		Entry("cycle", []string{"a", "b", "c"}, map[string][]string{"a": {"b"}, "b": {"a"}}, []string{"c", "a", "b"}),
+		Entry("duplicates", []string{"a", "b", "a"}, nil, []string{"a", "b"}),
+		Entry("duplicates with dependencies", []string{"b", "a", "b"}, map[string][]string{"b": {"a"}}, []string{"a", "b"}),
+		Entry("no names", []string{}, nil, []string{}),
+	)
+
+	Context("readFormCalculationOrder", func() {
+		var pdfData []byte
+
+		BeforeEach(func() {
+			var err error
+			pdfData, err = ioutil.ReadFile("../../shared_tests/testdata/form_calculation_order.pdf")
+			Expect(err).To(BeNil())
+		})
+
+		It("returns the full names of the fields in the calculation order", func() {
+			calculationOrder, hasCalculationOrder, err := readFormCalculationOrder(pdfData)
+			Expect(err).To(BeNil())
+			Expect(hasCalculationOrder).To(BeTrue())
+			Expect(calculationOrder).To(Equal([]string{"totals.c", "totals.b"}))
+		})
+
+		It("returns an empty calculation order", func() {
+			emptyCalculationOrder := bytes.Replace(pdfData, []byte("/CO [8 0 R 7 0 R]"), []byte("/CO [           ]"), 1)
+			calculationOrder, hasCalculationOrder, err := readFormCalculationOrder(emptyCalculationOrder)
+			Expect(err).To(BeNil())
+			Expect(hasCalculationOrder).To(BeTrue())
+			Expect(calculationOrder).To(BeEmpty())
+		})
+
+		It("returns whether the form has no calculation order", func() {
+			withoutCalculationOrder := bytes.Replace(pdfData, []byte("/CO [8 0 R 7 0 R]"), bytes.Repeat([]byte(" "), len("/CO [8 0 R 7 0 R]")), 1)
+			calculationOrder, hasCalculationOrder, err := readFormCalculationOrder(withoutCalculationOrder)
+			Expect(err).To(BeNil())
+			Expect(hasCalculationOrder).To(BeFalse())
+			Expect(calculationOrder).To(BeNil())
+		})
+	})
+})
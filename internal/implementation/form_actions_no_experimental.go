@@ -0,0 +1,17 @@
+//go:build !pdfium_experimental
+// +build !pdfium_experimental
+
+package implementation
+
+import (
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// RunFormActions fills the form fields of a document and runs the keystroke,
+// validate, calculate and format actions of the fields.
+// Experimental API.
+func (p *PdfiumImplementation) RunFormActions(request *requests.RunFormActions) (*responses.RunFormActions, error) {
+	return nil, pdfium_errors.ErrExperimentalUnsupported
+}
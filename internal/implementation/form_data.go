@@ -0,0 +1,979 @@
+package implementation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// formDataField is the value of a field in FDF or XFDF data.
+type formDataField struct {
+	name   string   // The full name of the field.
+	values []string // The values of the field, more than 1 for multi select listboxes.
+	isName bool     // Whether the value is written as a name in FDF, for checkboxes and radio buttons.
+}
+
+// fillValue returns the value of the field as accepted by fillForm.
+func (f formDataField) fillValue() interface{} {
+	switch len(f.values) {
+	case 0:
+		return nil
+	case 1:
+		return f.values[0]
+	default:
+		return f.values
+	}
+}
+
+// formDataNode is a field in the field hierarchy of FDF and XFDF data.
+type formDataNode struct {
+	name  string
+	field *formDataField
+	kids  []*formDataNode
+}
+
+// buildFormDataTree turns full field names into a field hierarchy.
+func buildFormDataTree(fields []formDataField) []*formDataNode {
+	root := &formDataNode{}
+	for i := range fields {
+		node := root
+		for _, part := range strings.Split(fields[i].name, ".") {
+			var kid *formDataNode
+			for _, existingKid := range node.kids {
+				if existingKid.name == part {
+					kid = existingKid
+					break
+				}
+			}
+
+			if kid == nil {
+				kid = &formDataNode{name: part}
+				node.kids = append(node.kids, kid)
+			}
+			node = kid
+		}
+		node.field = &fields[i]
+	}
+
+	return root.kids
+}
+
+// formDataAnnotation is a markup annotation in XFDF data.
+type formDataAnnotation struct {
+	subtype       enums.FPDF_ANNOTATION_SUBTYPE
+	page          int
+	rect          structs.FPDF_FS_RECTF
+	color         *structs.FPDF_COLOR
+	interiorColor *structs.FPDF_COLOR
+	flags         enums.FPDF_ANNOT_FLAG
+	name          string
+	title         string
+	subject       string
+	date          string
+	contents      string
+	quadPoints    []structs.FPDF_FS_QUADPOINTSF
+	inkList       [][]structs.FPDF_FS_POINTF
+}
+
+// xfdfAnnotationSubtypes are the annotation subtypes that are supported in
+// XFDF data, by their element name.
+var xfdfAnnotationSubtypes = map[string]enums.FPDF_ANNOTATION_SUBTYPE{
+	"text":      enums.FPDF_ANNOT_SUBTYPE_TEXT,
+	"freetext":  enums.FPDF_ANNOT_SUBTYPE_FREETEXT,
+	"square":    enums.FPDF_ANNOT_SUBTYPE_SQUARE,
+	"circle":    enums.FPDF_ANNOT_SUBTYPE_CIRCLE,
+	"highlight": enums.FPDF_ANNOT_SUBTYPE_HIGHLIGHT,
+	"underline": enums.FPDF_ANNOT_SUBTYPE_UNDERLINE,
+	"squiggly":  enums.FPDF_ANNOT_SUBTYPE_SQUIGGLY,
+	"strikeout": enums.FPDF_ANNOT_SUBTYPE_STRIKEOUT,
+	"ink":       enums.FPDF_ANNOT_SUBTYPE_INK,
+}
+
+// xfdfAnnotationFlags are the names of the annotation flags in XFDF data.
+var xfdfAnnotationFlags = []struct {
+	name string
+	flag enums.FPDF_ANNOT_FLAG
+}{
+	{"invisible", enums.FPDF_ANNOT_FLAG_INVISIBLE},
+	{"hidden", enums.FPDF_ANNOT_FLAG_HIDDEN},
+	{"print", enums.FPDF_ANNOT_FLAG_PRINT},
+	{"nozoom", enums.FPDF_ANNOT_FLAG_NOZOOM},
+	{"norotate", enums.FPDF_ANNOT_FLAG_NOROTATE},
+	{"noview", enums.FPDF_ANNOT_FLAG_NOVIEW},
+	{"readonly", enums.FPDF_ANNOT_FLAG_READONLY},
+	{"locked", enums.FPDF_ANNOT_FLAG_LOCKED},
+	{"togglenoview", enums.FPDF_ANNOT_FLAG_TOGGLENOVIEW},
+}
+
+type xfdfDocument struct {
+	XMLName xml.Name    `xml:"xfdf"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	File    *xfdfFile   `xml:"f"`
+	Fields  *xfdfFields `xml:"fields"`
+	Annots  *xfdfAnnots `xml:"annots"`
+}
+
+type xfdfFile struct {
+	Href string `xml:"href,attr"`
+}
+
+type xfdfFields struct {
+	Fields []xfdfField `xml:"field"`
+}
+
+type xfdfField struct {
+	Name   string      `xml:"name,attr"`
+	Values []string    `xml:"value"`
+	Fields []xfdfField `xml:"field"`
+}
+
+type xfdfAnnots struct {
+	Annots []xfdfAnnot `xml:",any"`
+}
+
+type xfdfAnnot struct {
+	XMLName       xml.Name
+	Page          int          `xml:"page,attr"`
+	Rect          string       `xml:"rect,attr,omitempty"`
+	Color         string       `xml:"color,attr,omitempty"`
+	InteriorColor string       `xml:"interior-color,attr,omitempty"`
+	Opacity       string       `xml:"opacity,attr,omitempty"`
+	Flags         string       `xml:"flags,attr,omitempty"`
+	Name          string       `xml:"name,attr,omitempty"`
+	Title         string       `xml:"title,attr,omitempty"`
+	Subject       string       `xml:"subject,attr,omitempty"`
+	Date          string       `xml:"date,attr,omitempty"`
+	Coords        string       `xml:"coords,attr,omitempty"`
+	Contents      string       `xml:"contents,omitempty"`
+	InkList       *xfdfInkList `xml:"inklist"`
+}
+
+type xfdfInkList struct {
+	Gestures []string `xml:"gesture"`
+}
+
+// detectFormDataFormat detects whether data is FDF or XFDF.
+func detectFormDataFormat(data []byte) (requests.FormDataFormat, error) {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("%FDF-")) {
+		return requests.FormDataFormatFDF, nil
+	}
+
+	if bytes.HasPrefix(trimmed, []byte("<")) {
+		return requests.FormDataFormatXFDF, nil
+	}
+
+	return "", errors.New("could not detect the form data format")
+}
+
+// writeXFDF writes fields and annotations as XFDF.
+func writeXFDF(fields []formDataField, annotations []formDataAnnotation, fileName string) ([]byte, error) {
+	document := xfdfDocument{
+		Xmlns:  "http://ns.adobe.com/xfdf/",
+		Fields: &xfdfFields{Fields: getXFDFFields(buildFormDataTree(fields))},
+	}
+
+	if fileName != "" {
+		document.File = &xfdfFile{Href: fileName}
+	}
+
+	if len(annotations) > 0 {
+		document.Annots = &xfdfAnnots{}
+		for _, annotation := range annotations {
+			document.Annots.Annots = append(document.Annots.Annots, getXFDFAnnot(annotation))
+		}
+	}
+
+	data, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+// getXFDFFields returns the XFDF fields of a field hierarchy.
+func getXFDFFields(nodes []*formDataNode) []xfdfField {
+	xfdfFields := make([]xfdfField, len(nodes))
+	for i, node := range nodes {
+		xfdfFields[i] = xfdfField{
+			Name:   node.name,
+			Fields: getXFDFFields(node.kids),
+		}
+
+		if node.field != nil {
+			xfdfFields[i].Values = node.field.values
+		}
+	}
+
+	return xfdfFields
+}
+
+// getXFDFAnnot returns the XFDF element of an annotation.
+func getXFDFAnnot(annotation formDataAnnotation) xfdfAnnot {
+	xfdfAnnotation := xfdfAnnot{
+		Page:     annotation.page,
+		Rect:     formatFormDataNumbers(annotation.rect.Left, annotation.rect.Bottom, annotation.rect.Right, annotation.rect.Top),
+		Name:     annotation.name,
+		Title:    annotation.title,
+		Subject:  annotation.subject,
+		Date:     annotation.date,
+		Contents: annotation.contents,
+	}
+
+	for elementName, subtype := range xfdfAnnotationSubtypes {
+		if subtype == annotation.subtype {
+			xfdfAnnotation.XMLName = xml.Name{Local: elementName}
+		}
+	}
+
+	if annotation.color != nil {
+		xfdfAnnotation.Color = fmt.Sprintf("#%02X%02X%02X", annotation.color.R, annotation.color.G, annotation.color.B)
+		if annotation.color.A != 255 {
+			xfdfAnnotation.Opacity = strconv.FormatFloat(float64(annotation.color.A)/255, 'f', -1, 64)
+		}
+	}
+
+	if annotation.interiorColor != nil {
+		xfdfAnnotation.InteriorColor = fmt.Sprintf("#%02X%02X%02X", annotation.interiorColor.R, annotation.interiorColor.G, annotation.interiorColor.B)
+	}
+
+	flagNames := []string{}
+	for _, annotationFlag := range xfdfAnnotationFlags {
+		if annotation.flags&annotationFlag.flag != 0 {
+			flagNames = append(flagNames, annotationFlag.name)
+		}
+	}
+	xfdfAnnotation.Flags = strings.Join(flagNames, ",")
+
+	coords := []float32{}
+	for _, quadPoints := range annotation.quadPoints {
+		coords = append(coords, quadPoints.X1, quadPoints.Y1, quadPoints.X2, quadPoints.Y2, quadPoints.X3, quadPoints.Y3, quadPoints.X4, quadPoints.Y4)
+	}
+	xfdfAnnotation.Coords = formatFormDataNumbers(coords...)
+
+	if annotation.inkList != nil {
+		xfdfAnnotation.InkList = &xfdfInkList{}
+		for _, path := range annotation.inkList {
+			points := make([]string, len(path))
+			for i, point := range path {
+				points[i] = formatFormDataNumbers(point.X, point.Y)
+			}
+			xfdfAnnotation.InkList.Gestures = append(xfdfAnnotation.InkList.Gestures, strings.Join(points, ";"))
+		}
+	}
+
+	return xfdfAnnotation
+}
+
+// formatFormDataNumbers formats numbers as a comma separated list.
+func formatFormDataNumbers(numbers ...float32) string {
+	formattedNumbers := make([]string, len(numbers))
+	for i, number := range numbers {
+		formattedNumbers[i] = strconv.FormatFloat(float64(number), 'f', -1, 32)
+	}
+
+	return strings.Join(formattedNumbers, ",")
+}
+
+// parseFormDataNumbers parses a list of numbers separated by commas,
+// semicolons or whitespace.
+func parseFormDataNumbers(value string) ([]float32, error) {
+	parts := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+
+	numbers := make([]float32, len(parts))
+	for i, part := range parts {
+		number, err := strconv.ParseFloat(part, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s", part)
+		}
+		numbers[i] = float32(number)
+	}
+
+	return numbers, nil
+}
+
+// parseFormDataColor parses a color in the #RRGGBB format.
+func parseFormDataColor(value string) (*structs.FPDF_COLOR, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	decoded, err := hex.DecodeString(strings.TrimPrefix(value, "#"))
+	if err != nil || len(decoded) != 3 {
+		return nil, fmt.Errorf("invalid color %s", value)
+	}
+
+	return &structs.FPDF_COLOR{
+		R: uint(decoded[0]),
+		G: uint(decoded[1]),
+		B: uint(decoded[2]),
+		A: 255,
+	}, nil
+}
+
+// parseXFDF parses the fields and the supported annotations of XFDF data.
+func parseXFDF(data []byte) ([]formDataField, []formDataAnnotation, error) {
+	document := xfdfDocument{}
+	if err := xml.Unmarshal(data, &document); err != nil {
+		return nil, nil, fmt.Errorf("could not parse XFDF: %w", err)
+	}
+
+	fields := []formDataField{}
+	if document.Fields != nil {
+		var collectFields func(xfdfFields []xfdfField, prefix string)
+		collectFields = func(xfdfFields []xfdfField, prefix string) {
+			for _, xfdfField := range xfdfFields {
+				name := prefix + xfdfField.Name
+				if xfdfField.Values != nil {
+					fields = append(fields, formDataField{name: name, values: xfdfField.Values})
+				}
+				collectFields(xfdfField.Fields, name+".")
+			}
+		}
+		collectFields(document.Fields.Fields, "")
+	}
+
+	annotations := []formDataAnnotation{}
+	if document.Annots != nil {
+		for _, xfdfAnnotation := range document.Annots.Annots {
+			subtype, ok := xfdfAnnotationSubtypes[xfdfAnnotation.XMLName.Local]
+			if !ok {
+				continue
+			}
+
+			annotation, err := parseXFDFAnnot(xfdfAnnotation, subtype)
+			if err != nil {
+				return nil, nil, fmt.Errorf("could not parse XFDF %s annotation: %w", xfdfAnnotation.XMLName.Local, err)
+			}
+			annotations = append(annotations, *annotation)
+		}
+	}
+
+	return fields, annotations, nil
+}
+
+// parseXFDFAnnot parses one XFDF annotation element.
+func parseXFDFAnnot(xfdfAnnotation xfdfAnnot, subtype enums.FPDF_ANNOTATION_SUBTYPE) (*formDataAnnotation, error) {
+	annotation := &formDataAnnotation{
+		subtype:  subtype,
+		page:     xfdfAnnotation.Page,
+		name:     xfdfAnnotation.Name,
+		title:    xfdfAnnotation.Title,
+		subject:  xfdfAnnotation.Subject,
+		date:     xfdfAnnotation.Date,
+		contents: xfdfAnnotation.Contents,
+	}
+
+	rect, err := parseFormDataNumbers(xfdfAnnotation.Rect)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rect) != 4 {
+		return nil, errors.New("rect should have 4 numbers")
+	}
+
+	annotation.rect = structs.FPDF_FS_RECTF{Left: rect[0], Bottom: rect[1], Right: rect[2], Top: rect[3]}
+
+	if annotation.color, err = parseFormDataColor(xfdfAnnotation.Color); err != nil {
+		return nil, err
+	}
+
+	if annotation.interiorColor, err = parseFormDataColor(xfdfAnnotation.InteriorColor); err != nil {
+		return nil, err
+	}
+
+	if xfdfAnnotation.Opacity != "" && annotation.color != nil {
+		opacity, err := strconv.ParseFloat(xfdfAnnotation.Opacity, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid opacity %s", xfdfAnnotation.Opacity)
+		}
+		annotation.color.A = uint(opacity*255 + 0.5)
+	}
+
+	for _, flagName := range strings.Split(xfdfAnnotation.Flags, ",") {
+		for _, annotationFlag := range xfdfAnnotationFlags {
+			if strings.TrimSpace(flagName) == annotationFlag.name {
+				annotation.flags |= annotationFlag.flag
+			}
+		}
+	}
+
+	coords, err := parseFormDataNumbers(xfdfAnnotation.Coords)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(coords)%8 != 0 {
+		return nil, errors.New("coords should be a multiple of 8 numbers")
+	}
+
+	for i := 0; i < len(coords); i += 8 {
+		annotation.quadPoints = append(annotation.quadPoints, structs.FPDF_FS_QUADPOINTSF{
+			X1: coords[i], Y1: coords[i+1],
+			X2: coords[i+2], Y2: coords[i+3],
+			X3: coords[i+4], Y3: coords[i+5],
+			X4: coords[i+6], Y4: coords[i+7],
+		})
+	}
+
+	if xfdfAnnotation.InkList != nil {
+		for _, gesture := range xfdfAnnotation.InkList.Gestures {
+			numbers, err := parseFormDataNumbers(gesture)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(numbers)%2 != 0 {
+				return nil, errors.New("gesture should have an even amount of numbers")
+			}
+
+			path := make([]structs.FPDF_FS_POINTF, len(numbers)/2)
+			for i := range path {
+				path[i] = structs.FPDF_FS_POINTF{X: numbers[i*2], Y: numbers[i*2+1]}
+			}
+			annotation.inkList = append(annotation.inkList, path)
+		}
+	}
+
+	return annotation, nil
+}
+
+// writeFDF writes fields as FDF.
+func writeFDF(fields []formDataField, fileName string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%FDF-1.2\n%\xe2\xe3\xcf\xd3\n1 0 obj\n<< /FDF << ")
+	if fileName != "" {
+		buf.WriteString("/F ")
+		writeFDFString(&buf, fileName)
+		buf.WriteString(" ")
+	}
+
+	buf.WriteString("/Fields ")
+	writeFDFFields(&buf, buildFormDataTree(fields))
+	buf.WriteString(" >> >>\nendobj\ntrailer\n<< /Root 1 0 R >>\n%%EOF\n")
+
+	return buf.Bytes()
+}
+
+// writeFDFFields writes a field hierarchy as FDF field array.
+func writeFDFFields(buf *bytes.Buffer, nodes []*formDataNode) {
+	buf.WriteString("[")
+	for _, node := range nodes {
+		buf.WriteString("\n<< /T ")
+		writeFDFString(buf, node.name)
+
+		if node.field != nil {
+			buf.WriteString(" /V ")
+			switch {
+			case node.field.isName && len(node.field.values) == 1:
+				writeFDFName(buf, node.field.values[0])
+			case len(node.field.values) == 1:
+				writeFDFString(buf, node.field.values[0])
+			default:
+				buf.WriteString("[")
+				for i, value := range node.field.values {
+					if i > 0 {
+						buf.WriteString(" ")
+					}
+					writeFDFString(buf, value)
+				}
+				buf.WriteString("]")
+			}
+		}
+
+		if len(node.kids) > 0 {
+			buf.WriteString(" /Kids ")
+			writeFDFFields(buf, node.kids)
+		}
+
+		buf.WriteString(" >>")
+	}
+	buf.WriteString("\n]")
+}
+
+// writeFDFString writes a PDF text string, as literal string when it only
+// contains printable ASCII, and as UTF-16BE hex string otherwise.
+func writeFDFString(buf *bytes.Buffer, value string) {
+	isASCII := true
+	for _, r := range value {
+		if r < 0x20 || r > 0x7e {
+			isASCII = false
+			break
+		}
+	}
+
+	if isASCII {
+		buf.WriteString("(")
+		for _, r := range value {
+			if r == '(' || r == ')' || r == '\\' {
+				buf.WriteByte('\\')
+			}
+			buf.WriteRune(r)
+		}
+		buf.WriteString(")")
+		return
+	}
+
+	buf.WriteString("<FEFF")
+	for _, unit := range utf16.Encode([]rune(value)) {
+		fmt.Fprintf(buf, "%04X", unit)
+	}
+	buf.WriteString(">")
+}
+
+// writeFDFName writes a PDF name, escaping the characters that are not
+// regular characters.
+func writeFDFName(buf *bytes.Buffer, value string) {
+	buf.WriteString("/")
+	for _, b := range []byte(value) {
+		if b <= 0x20 || b >= 0x7f || strings.IndexByte("#()<>[]{}/%", b) != -1 {
+			fmt.Fprintf(buf, "#%02X", b)
+			continue
+		}
+		buf.WriteByte(b)
+	}
+}
+
+// fdfName is a name object in FDF data.
+type fdfName string
+
+// fdfReference is a reference to an indirect object in FDF data.
+type fdfReference int
+
+// fdfKeyword is a keyword or number in FDF data.
+type fdfKeyword string
+
+// fdfParser parses the objects of FDF data. Only the objects that are needed
+// to read field values are supported, streams are skipped.
+type fdfParser struct {
+	data []byte
+	pos  int
+}
+
+// parseFDF parses the field values of FDF data.
+func parseFDF(data []byte) ([]formDataField, error) {
+	parser := &fdfParser{data: data}
+	objects := map[int]interface{}{}
+	var trailer map[string]interface{}
+
+	// Collect the indirect objects and the trailer, the FDF dictionary is
+	// found through the root of the trailer.
+	stack := []interface{}{}
+	for {
+		object, err := parser.parseObject()
+		if err != nil {
+			return nil, fmt.Errorf("could not parse FDF: %w", err)
+		}
+
+		if object == nil {
+			break
+		}
+
+		switch typedObject := object.(type) {
+		case fdfKeyword:
+			switch typedObject {
+			case "obj":
+				if len(stack) >= 2 {
+					if objectNumber, err := strconv.Atoi(string(stack[len(stack)-2].(fdfKeyword))); err == nil {
+						value, err := parser.parseObject()
+						if err != nil {
+							return nil, fmt.Errorf("could not parse FDF: %w", err)
+						}
+						objects[objectNumber] = value
+					}
+				}
+				stack = stack[:0]
+				continue
+			case "stream":
+				parser.skipStream()
+			case "trailer":
+				value, err := parser.parseObject()
+				if err != nil {
+					return nil, fmt.Errorf("could not parse FDF: %w", err)
+				}
+				trailer, _ = value.(map[string]interface{})
+			}
+		}
+
+		stack = append(stack, object)
+		if len(stack) > 2 {
+			stack = stack[1:]
+		}
+	}
+
+	resolve := func(object interface{}) interface{} {
+		if reference, ok := object.(fdfReference); ok {
+			return objects[int(reference)]
+		}
+		return object
+	}
+
+	var root map[string]interface{}
+	if trailer != nil {
+		root, _ = resolve(trailer["Root"]).(map[string]interface{})
+	}
+
+	if root == nil {
+		for _, object := range objects {
+			if dict, ok := object.(map[string]interface{}); ok && dict["FDF"] != nil {
+				root = dict
+				break
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, errors.New("could not find the FDF dictionary")
+	}
+
+	fdf, ok := resolve(root["FDF"]).(map[string]interface{})
+	if !ok {
+		return nil, errors.New("could not find the FDF dictionary")
+	}
+
+	fields := []formDataField{}
+	var collectFields func(object interface{}, prefix string)
+	collectFields = func(object interface{}, prefix string) {
+		array, _ := resolve(object).([]interface{})
+		for _, item := range array {
+			dict, ok := resolve(item).(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			name := prefix
+			if partialName, ok := resolve(dict["T"]).(string); ok {
+				name = prefix + decodePDFTextString(partialName)
+			}
+
+			if value, ok := dict["V"]; ok {
+				field := formDataField{name: name}
+				switch typedValue := resolve(value).(type) {
+				case string:
+					field.values = []string{decodePDFTextString(typedValue)}
+				case fdfName:
+					field.values = []string{string(typedValue)}
+					field.isName = true
+				case []interface{}:
+					for _, arrayValue := range typedValue {
+						if stringValue, ok := resolve(arrayValue).(string); ok {
+							field.values = append(field.values, decodePDFTextString(stringValue))
+						}
+					}
+				}
+				fields = append(fields, field)
+			}
+
+			collectFields(dict["Kids"], name+".")
+		}
+	}
+	collectFields(fdf["Fields"], "")
+
+	return fields, nil
+}
+
+// decodePDFTextString decodes a PDF text string, which is UTF-16BE with a byte
+// order mark, UTF-8 with a byte order mark or PDFDocEncoding. PDFDocEncoding is
+// decoded as Latin-1, which only differs for a few rarely used characters.
+func decodePDFTextString(value string) string {
+	if strings.HasPrefix(value, "\xfe\xff") {
+		units := make([]uint16, (len(value)-2)/2)
+		for i := range units {
+			units[i] = binary.BigEndian.Uint16([]byte(value[2+i*2 : 4+i*2]))
+		}
+		return string(utf16.Decode(units))
+	}
+
+	if strings.HasPrefix(value, "\xef\xbb\xbf") {
+		return value[3:]
+	}
+
+	runes := make([]rune, len(value))
+	for i := 0; i < len(value); i++ {
+		runes[i] = rune(value[i])
+	}
+
+	return string(runes)
+}
+
+func isFDFWhitespace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f' || b == 0
+}
+
+func isFDFDelimiter(b byte) bool {
+	return strings.IndexByte("()<>[]{}/%", b) != -1
+}
+
+// skipWhitespace skips whitespace and comments.
+func (p *fdfParser) skipWhitespace() {
+	for p.pos < len(p.data) {
+		if isFDFWhitespace(p.data[p.pos]) {
+			p.pos++
+			continue
+		}
+
+		if p.data[p.pos] == '%' {
+			for p.pos < len(p.data) && p.data[p.pos] != '\n' && p.data[p.pos] != '\r' {
+				p.pos++
+			}
+			continue
+		}
+
+		return
+	}
+}
+
+// skipStream skips the data of a stream after the stream keyword.
+func (p *fdfParser) skipStream() {
+	end := bytes.Index(p.data[p.pos:], []byte("endstream"))
+	if end == -1 {
+		p.pos = len(p.data)
+		return
+	}
+
+	p.pos += end + len("endstream")
+}
+
+// parseObject parses the next object, nil is returned at the end of the data.
+func (p *fdfParser) parseObject() (interface{}, error) {
+	p.skipWhitespace()
+	if p.pos >= len(p.data) {
+		return nil, nil
+	}
+
+	switch {
+	case bytes.HasPrefix(p.data[p.pos:], []byte("<<")):
+		p.pos += 2
+		return p.parseDictionary()
+	case p.data[p.pos] == '[':
+		p.pos++
+		return p.parseArray()
+	case p.data[p.pos] == '(':
+		p.pos++
+		return p.parseLiteralString()
+	case p.data[p.pos] == '<':
+		p.pos++
+		return p.parseHexString()
+	case p.data[p.pos] == '/':
+		p.pos++
+		return p.parseName(), nil
+	case isFDFDelimiter(p.data[p.pos]):
+		return nil, fmt.Errorf("unexpected %c at offset %d", p.data[p.pos], p.pos)
+	}
+
+	start := p.pos
+	for p.pos < len(p.data) && !isFDFWhitespace(p.data[p.pos]) && !isFDFDelimiter(p.data[p.pos]) {
+		p.pos++
+	}
+
+	keyword := fdfKeyword(p.data[start:p.pos])
+	if keyword == "true" || keyword == "false" {
+		return keyword == "true", nil
+	}
+
+	return keyword, nil
+}
+
+func (p *fdfParser) parseDictionary() (interface{}, error) {
+	dict := map[string]interface{}{}
+	for {
+		p.skipWhitespace()
+		if p.pos >= len(p.data) {
+			return nil, errors.New("unterminated dictionary")
+		}
+
+		if bytes.HasPrefix(p.data[p.pos:], []byte(">>")) {
+			p.pos += 2
+			return dict, nil
+		}
+
+		key, err := p.parseObject()
+		if err != nil {
+			return nil, err
+		}
+
+		name, ok := key.(fdfName)
+		if !ok {
+			return nil, fmt.Errorf("dictionary key at offset %d is not a name", p.pos)
+		}
+
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		dict[string(name)] = value
+	}
+}
+
+func (p *fdfParser) parseArray() (interface{}, error) {
+	array := []interface{}{}
+	for {
+		p.skipWhitespace()
+		if p.pos >= len(p.data) {
+			return nil, errors.New("unterminated array")
+		}
+
+		if p.data[p.pos] == ']' {
+			p.pos++
+			return array, nil
+		}
+
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, value)
+	}
+}
+
+// parseValue parses an object in a dictionary or array, where an object
+// number followed by a generation number and R is a reference.
+func (p *fdfParser) parseValue() (interface{}, error) {
+	value, err := p.parseObject()
+	if err != nil {
+		return nil, err
+	}
+
+	keyword, ok := value.(fdfKeyword)
+	if !ok {
+		return value, nil
+	}
+
+	objectNumber, err := strconv.Atoi(string(keyword))
+	if err != nil {
+		return value, nil
+	}
+
+	// Look ahead for a reference.
+	start := p.pos
+	generation, err := p.parseObject()
+	if generationKeyword, ok := generation.(fdfKeyword); err == nil && ok {
+		if _, err := strconv.Atoi(string(generationKeyword)); err == nil {
+			if reference, err := p.parseObject(); err == nil && reference == fdfKeyword("R") {
+				return fdfReference(objectNumber), nil
+			}
+		}
+	}
+
+	p.pos = start
+	return value, nil
+}
+
+func (p *fdfParser) parseName() fdfName {
+	var name bytes.Buffer
+	for p.pos < len(p.data) && !isFDFWhitespace(p.data[p.pos]) && !isFDFDelimiter(p.data[p.pos]) {
+		if p.data[p.pos] == '#' && p.pos+2 < len(p.data) {
+			if decoded, err := hex.DecodeString(string(p.data[p.pos+1 : p.pos+3])); err == nil {
+				name.Write(decoded)
+				p.pos += 3
+				continue
+			}
+		}
+		name.WriteByte(p.data[p.pos])
+		p.pos++
+	}
+
+	return fdfName(name.String())
+}
+
+func (p *fdfParser) parseLiteralString() (interface{}, error) {
+	var value bytes.Buffer
+	depth := 1
+	for p.pos < len(p.data) {
+		b := p.data[p.pos]
+		p.pos++
+
+		switch b {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return value.String(), nil
+			}
+		case '\\':
+			if p.pos >= len(p.data) {
+				return nil, errors.New("unterminated string")
+			}
+
+			escaped := p.data[p.pos]
+			p.pos++
+			switch escaped {
+			case 'n':
+				value.WriteByte('\n')
+			case 'r':
+				value.WriteByte('\r')
+			case 't':
+				value.WriteByte('\t')
+			case 'b':
+				value.WriteByte('\b')
+			case 'f':
+				value.WriteByte('\f')
+			case '\r':
+				// A line continuation.
+				if p.pos < len(p.data) && p.data[p.pos] == '\n' {
+					p.pos++
+				}
+			case '\n':
+				// A line continuation.
+			default:
+				if escaped >= '0' && escaped <= '7' {
+					octal := int(escaped - '0')
+					for i := 0; i < 2 && p.pos < len(p.data) && p.data[p.pos] >= '0' && p.data[p.pos] <= '7'; i++ {
+						octal = octal*8 + int(p.data[p.pos]-'0')
+						p.pos++
+					}
+					value.WriteByte(byte(octal))
+				} else {
+					value.WriteByte(escaped)
+				}
+			}
+			continue
+		}
+
+		value.WriteByte(b)
+	}
+
+	return nil, errors.New("unterminated string")
+}
+
+func (p *fdfParser) parseHexString() (interface{}, error) {
+	end := bytes.IndexByte(p.data[p.pos:], '>')
+	if end == -1 {
+		return nil, errors.New("unterminated hex string")
+	}
+
+	hexData := bytes.Map(func(r rune) rune {
+		if isFDFWhitespace(byte(r)) {
+			return -1
+		}
+		return r
+	}, p.data[p.pos:p.pos+end])
+	p.pos += end + 1
+
+	// A missing last digit is 0.
+	if len(hexData)%2 == 1 {
+		hexData = append(hexData, '0')
+	}
+
+	decoded, err := hex.DecodeString(string(hexData))
+	if err != nil {
+		return nil, errors.New("invalid hex string")
+	}
+
+	return string(decoded), nil
+}
@@ -0,0 +1,484 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+// #include "fpdf_annot.h"
+// #include "fpdf_formfill.h"
+// #include <stdlib.h>
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// ExportFormData exports the values of the form fields of a document as FDF or
+// XFDF, optionally with the markup annotations of the document in XFDF.
+// Experimental API.
+func (p *PdfiumImplementation) ExportFormData(request *requests.ExportFormData) (*responses.ExportFormData, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Format != requests.FormDataFormatFDF && request.Format != requests.FormDataFormatXFDF {
+		return nil, fmt.Errorf("unsupported form data format %s", request.Format)
+	}
+
+	if request.IncludeAnnotations && request.Format != requests.FormDataFormatXFDF {
+		return nil, errors.New("annotations are only supported for XFDF")
+	}
+
+	formHandle, closeFormHandle, err := p.getFormHandle(documentHandle)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFormHandle()
+
+	fields, err := p.getFormFields(documentHandle, formHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	dataFields := []formDataField{}
+	for _, field := range fields {
+		if field.Type == enums.FPDF_FORMFIELD_TYPE_PUSHBUTTON || field.Type == enums.FPDF_FORMFIELD_TYPE_SIGNATURE || field.Flags&enums.FPDF_FORMFLAG_NOEXPORT != 0 {
+			continue
+		}
+
+		dataField := formDataField{name: field.Name, values: []string{field.Value}}
+		switch field.Type {
+		case enums.FPDF_FORMFIELD_TYPE_CHECKBOX, enums.FPDF_FORMFIELD_TYPE_RADIOBUTTON:
+			dataField.isName = true
+			if field.Value == "" {
+				dataField.values = []string{"Off"}
+			}
+		case enums.FPDF_FORMFIELD_TYPE_LISTBOX:
+			dataField.values = []string{}
+			for _, option := range field.Options {
+				if option.Selected {
+					dataField.values = append(dataField.values, option.Label)
+				}
+			}
+		}
+
+		dataFields = append(dataFields, dataField)
+	}
+
+	if request.Format == requests.FormDataFormatFDF {
+		return &responses.ExportFormData{
+			Data: writeFDF(dataFields, request.FileName),
+		}, nil
+	}
+
+	annotations := []formDataAnnotation{}
+	if request.IncludeAnnotations {
+		annotations, err = p.getFormDataAnnotations(documentHandle)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := writeXFDF(dataFields, annotations, request.FileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.ExportFormData{
+		Data: data,
+	}, nil
+}
+
+// ImportFormData imports FDF or XFDF data into the form fields of a document,
+// and optionally adds the markup annotations in XFDF data to the document.
+// Fields that don't exist or can't be filled are skipped.
+// Experimental API.
+func (p *PdfiumImplementation) ImportFormData(request *requests.ImportFormData) (*responses.ImportFormData, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	format := request.Format
+	if format == "" {
+		format, err = detectFormDataFormat(request.Data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var dataFields []formDataField
+	var annotations []formDataAnnotation
+	switch format {
+	case requests.FormDataFormatFDF:
+		dataFields, err = parseFDF(request.Data)
+	case requests.FormDataFormatXFDF:
+		dataFields, annotations, err = parseXFDF(request.Data)
+	default:
+		return nil, fmt.Errorf("unsupported form data format %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the annotations before anything is imported, so that the
+	// document isn't changed when they can't be imported.
+	if request.ImportAnnotations {
+		pageCount := int(C.FPDF_GetPageCount(documentHandle.handle))
+		for i, annotation := range annotations {
+			if annotation.page < 0 || annotation.page >= pageCount {
+				return nil, fmt.Errorf("page %d of annotation %d doesn't exist", annotation.page, i)
+			}
+		}
+	}
+
+	formHandle, closeFormHandle, err := p.getFormHandle(documentHandle)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFormHandle()
+
+	fields, err := p.getFormFields(documentHandle, formHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldsByName := map[string]responses.FormField{}
+	for _, field := range fields {
+		fieldsByName[field.Name] = field
+	}
+
+	resp := &responses.ImportFormData{
+		Fields:        []responses.FillFormField{},
+		SkippedFields: []string{},
+	}
+
+	values := map[string]interface{}{}
+	for _, dataField := range dataFields {
+		field, ok := fieldsByName[dataField.name]
+		if !ok {
+			resp.SkippedFields = append(resp.SkippedFields, dataField.name)
+			continue
+		}
+
+		// Only keep the values that can be filled, so that one field doesn't
+		// prevent the others from being imported.
+		value := dataField.fillValue()
+		if _, err := getFormFillWidgets(field, value); err != nil {
+			resp.SkippedFields = append(resp.SkippedFields, dataField.name)
+			continue
+		}
+
+		values[dataField.name] = value
+	}
+
+	if err := p.fillForm(documentHandle, formHandle, fields, values); err != nil {
+		return nil, err
+	}
+
+	// Read the values back, PDFium could have formatted or truncated them.
+	filledFields, err := p.getFormFields(documentHandle, formHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range filledFields {
+		if _, ok := values[field.Name]; ok {
+			resp.Fields = append(resp.Fields, responses.FillFormField{
+				Name:  field.Name,
+				Value: field.Value,
+			})
+		}
+	}
+
+	if request.ImportAnnotations {
+		for i, annotation := range annotations {
+			if err := p.addFormDataAnnotation(documentHandle, annotation); err != nil {
+				return nil, fmt.Errorf("could not add annotation %d: %w", i, err)
+			}
+			resp.Annotations++
+		}
+	}
+
+	return resp, nil
+}
+
+// getFormDataAnnotations returns the markup annotations of a document that
+// are supported in XFDF.
+func (p *PdfiumImplementation) getFormDataAnnotations(documentHandle *DocumentHandle) ([]formDataAnnotation, error) {
+	annotations := []formDataAnnotation{}
+
+	isSupportedSubtype := map[enums.FPDF_ANNOTATION_SUBTYPE]bool{}
+	for _, subtype := range xfdfAnnotationSubtypes {
+		isSupportedSubtype[subtype] = true
+	}
+
+	pageCount := int(C.FPDF_GetPageCount(documentHandle.handle))
+	for pageIndex := 0; pageIndex < pageCount; pageIndex++ {
+		pageHandle, err := p.loadPage(requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: documentHandle.nativeRef,
+				Index:    pageIndex,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		annotationCount := int(C.FPDFPage_GetAnnotCount(pageHandle.handle))
+		for i := 0; i < annotationCount; i++ {
+			annotation := C.FPDFPage_GetAnnot(pageHandle.handle, C.int(i))
+			if annotation == nil {
+				continue
+			}
+
+			subtype := enums.FPDF_ANNOTATION_SUBTYPE(C.FPDFAnnot_GetSubtype(annotation))
+			if !isSupportedSubtype[subtype] {
+				C.FPDFPage_CloseAnnot(annotation)
+				continue
+			}
+
+			dataAnnotation, err := p.getFormDataAnnotation(annotation, subtype, pageIndex)
+			C.FPDFPage_CloseAnnot(annotation)
+			if err != nil {
+				return nil, err
+			}
+
+			annotations = append(annotations, *dataAnnotation)
+		}
+	}
+
+	return annotations, nil
+}
+
+// getFormDataAnnotation reads the properties of an annotation that are
+// supported in XFDF.
+func (p *PdfiumImplementation) getFormDataAnnotation(annotation C.FPDF_ANNOTATION, subtype enums.FPDF_ANNOTATION_SUBTYPE, pageIndex int) (*formDataAnnotation, error) {
+	rect := C.FS_RECTF{}
+	C.FPDFAnnot_GetRect(annotation, &rect)
+
+	dataAnnotation := &formDataAnnotation{
+		subtype: subtype,
+		page:    pageIndex,
+		rect: structs.FPDF_FS_RECTF{
+			Left:   float32(rect.left),
+			Top:    float32(rect.top),
+			Right:  float32(rect.right),
+			Bottom: float32(rect.bottom),
+		},
+		flags: enums.FPDF_ANNOT_FLAG(C.FPDFAnnot_GetFlags(annotation)),
+	}
+
+	stringValues := []struct {
+		key   string
+		value *string
+	}{
+		{"NM", &dataAnnotation.name},
+		{"T", &dataAnnotation.title},
+		{"Subj", &dataAnnotation.subject},
+		{"M", &dataAnnotation.date},
+		{"Contents", &dataAnnotation.contents},
+	}
+
+	for _, stringValue := range stringValues {
+		cKey := C.CString(stringValue.key)
+		value, err := p.getFormFieldString(func(buffer *C.FPDF_WCHAR, length C.ulong) C.ulong {
+			return C.FPDFAnnot_GetStringValue(annotation, cKey, buffer, length)
+		})
+		C.free(unsafe.Pointer(cKey))
+		if err != nil {
+			return nil, err
+		}
+		*stringValue.value = value
+	}
+
+	// Only read the colors that are set, PDFium returns a default color for
+	// some subtypes otherwise.
+	colorKeys := []struct {
+		key       string
+		colorType enums.FPDFANNOT_COLORTYPE
+		color     **structs.FPDF_COLOR
+	}{
+		{"C", enums.FPDFANNOT_COLORTYPE_Color, &dataAnnotation.color},
+		{"IC", enums.FPDFANNOT_COLORTYPE_InteriorColor, &dataAnnotation.interiorColor},
+	}
+
+	for _, colorKey := range colorKeys {
+		cKey := C.CString(colorKey.key)
+		hasKey := int(C.FPDFAnnot_HasKey(annotation, cKey)) == 1
+		C.free(unsafe.Pointer(cKey))
+		if !hasKey {
+			continue
+		}
+
+		var r, g, b, a C.uint
+		if int(C.FPDFAnnot_GetColor(annotation, C.FPDFANNOT_COLORTYPE(colorKey.colorType), &r, &g, &b, &a)) == 1 {
+			*colorKey.color = &structs.FPDF_COLOR{R: uint(r), G: uint(g), B: uint(b), A: uint(a)}
+		}
+	}
+
+	quadPointsCount := uint64(C.FPDFAnnot_CountAttachmentPoints(annotation))
+	for i := uint64(0); i < quadPointsCount; i++ {
+		quadPoints := C.FS_QUADPOINTSF{}
+		if int(C.FPDFAnnot_GetAttachmentPoints(annotation, C.size_t(i), &quadPoints)) == 0 {
+			continue
+		}
+
+		dataAnnotation.quadPoints = append(dataAnnotation.quadPoints, structs.FPDF_FS_QUADPOINTSF{
+			X1: float32(quadPoints.x1), Y1: float32(quadPoints.y1),
+			X2: float32(quadPoints.x2), Y2: float32(quadPoints.y2),
+			X3: float32(quadPoints.x3), Y3: float32(quadPoints.y3),
+			X4: float32(quadPoints.x4), Y4: float32(quadPoints.y4),
+		})
+	}
+
+	if subtype == enums.FPDF_ANNOT_SUBTYPE_INK {
+		dataAnnotation.inkList = [][]structs.FPDF_FS_POINTF{}
+		pathCount := uint64(C.FPDFAnnot_GetInkListCount(annotation))
+		for i := uint64(0); i < pathCount; i++ {
+			length := C.FPDFAnnot_GetInkListPath(annotation, C.ulong(i), nil, 0)
+			if length == 0 {
+				continue
+			}
+
+			cPath := make([]C.FS_POINTF, uint64(length))
+			C.FPDFAnnot_GetInkListPath(annotation, C.ulong(i), &cPath[0], length)
+
+			path := make([]structs.FPDF_FS_POINTF, len(cPath))
+			for j := range cPath {
+				path[j] = structs.FPDF_FS_POINTF{
+					X: float32(cPath[j].x),
+					Y: float32(cPath[j].y),
+				}
+			}
+			dataAnnotation.inkList = append(dataAnnotation.inkList, path)
+		}
+	}
+
+	return dataAnnotation, nil
+}
+
+// addFormDataAnnotation adds an annotation from XFDF data to its page.
+func (p *PdfiumImplementation) addFormDataAnnotation(documentHandle *DocumentHandle, dataAnnotation formDataAnnotation) error {
+	pageHandle, err := p.loadPage(requests.Page{
+		ByIndex: &requests.PageByIndex{
+			Document: documentHandle.nativeRef,
+			Index:    dataAnnotation.page,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	annotation := C.FPDFPage_CreateAnnot(pageHandle.handle, C.FPDF_ANNOTATION_SUBTYPE(dataAnnotation.subtype))
+	if annotation == nil {
+		return errors.New("could not create annotation")
+	}
+	defer C.FPDFPage_CloseAnnot(annotation)
+
+	rect := C.FS_RECTF{
+		left:   C.float(dataAnnotation.rect.Left),
+		top:    C.float(dataAnnotation.rect.Top),
+		right:  C.float(dataAnnotation.rect.Right),
+		bottom: C.float(dataAnnotation.rect.Bottom),
+	}
+	if int(C.FPDFAnnot_SetRect(annotation, &rect)) == 0 {
+		return errors.New("could not set rect")
+	}
+
+	if dataAnnotation.color != nil {
+		color := dataAnnotation.color
+		if int(C.FPDFAnnot_SetColor(annotation, C.FPDFANNOT_COLORTYPE(enums.FPDFANNOT_COLORTYPE_Color), C.uint(color.R), C.uint(color.G), C.uint(color.B), C.uint(color.A))) == 0 {
+			return errors.New("could not set color")
+		}
+	}
+
+	if dataAnnotation.interiorColor != nil {
+		color := dataAnnotation.interiorColor
+		if int(C.FPDFAnnot_SetColor(annotation, C.FPDFANNOT_COLORTYPE(enums.FPDFANNOT_COLORTYPE_InteriorColor), C.uint(color.R), C.uint(color.G), C.uint(color.B), C.uint(color.A))) == 0 {
+			return errors.New("could not set interior color")
+		}
+	}
+
+	if dataAnnotation.flags != enums.FPDF_ANNOT_FLAG_NONE {
+		if int(C.FPDFAnnot_SetFlags(annotation, C.int(dataAnnotation.flags))) == 0 {
+			return errors.New("could not set flags")
+		}
+	}
+
+	stringValues := []struct {
+		key   string
+		value string
+	}{
+		{"NM", dataAnnotation.name},
+		{"T", dataAnnotation.title},
+		{"Subj", dataAnnotation.subject},
+		{"M", dataAnnotation.date},
+		{"Contents", dataAnnotation.contents},
+	}
+
+	for _, stringValue := range stringValues {
+		if stringValue.value == "" {
+			continue
+		}
+
+		transformedText, err := p.transformUTF8ToUTF16LE(stringValue.value)
+		if err != nil {
+			return err
+		}
+
+		// Add the NULL terminator.
+		transformedText = append(transformedText, 0, 0)
+
+		cKey := C.CString(stringValue.key)
+		success := C.FPDFAnnot_SetStringValue(annotation, cKey, (C.FPDF_WIDESTRING)(unsafe.Pointer(&transformedText[0])))
+		C.free(unsafe.Pointer(cKey))
+		if int(success) == 0 {
+			return fmt.Errorf("could not set %s", stringValue.key)
+		}
+	}
+
+	for _, quadPoints := range dataAnnotation.quadPoints {
+		cQuadPoints := C.FS_QUADPOINTSF{
+			x1: C.float(quadPoints.X1), y1: C.float(quadPoints.Y1),
+			x2: C.float(quadPoints.X2), y2: C.float(quadPoints.Y2),
+			x3: C.float(quadPoints.X3), y3: C.float(quadPoints.Y3),
+			x4: C.float(quadPoints.X4), y4: C.float(quadPoints.Y4),
+		}
+		if int(C.FPDFAnnot_AppendAttachmentPoints(annotation, &cQuadPoints)) == 0 {
+			return errors.New("could not append attachment points")
+		}
+	}
+
+	for _, path := range dataAnnotation.inkList {
+		if len(path) == 0 {
+			continue
+		}
+
+		cPath := make([]C.FS_POINTF, len(path))
+		for i, point := range path {
+			cPath[i] = C.FS_POINTF{x: C.float(point.X), y: C.float(point.Y)}
+		}
+
+		if int(C.FPDFAnnot_AddInkStroke(annotation, &cPath[0], C.size_t(len(cPath)))) == -1 {
+			return errors.New("could not add ink stroke")
+		}
+	}
+
+	return nil
+}
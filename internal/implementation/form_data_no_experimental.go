@@ -0,0 +1,25 @@
+//go:build !pdfium_experimental
+// +build !pdfium_experimental
+
+package implementation
+
+import (
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// ExportFormData exports the values of the form fields of a document as FDF or
+// XFDF, optionally with the markup annotations of the document in XFDF.
+// Experimental API.
+func (p *PdfiumImplementation) ExportFormData(request *requests.ExportFormData) (*responses.ExportFormData, error) {
+	return nil, pdfium_errors.ErrExperimentalUnsupported
+}
+
+// ImportFormData imports FDF or XFDF data into the form fields of a document,
+// and optionally adds the markup annotations in XFDF data to the document.
+// Fields that don't exist or can't be filled are skipped.
+// Experimental API.
+func (p *PdfiumImplementation) ImportFormData(request *requests.ImportFormData) (*responses.ImportFormData, error) {
+	return nil, pdfium_errors.ErrExperimentalUnsupported
+}
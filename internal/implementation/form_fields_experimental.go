@@ -0,0 +1,236 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+// #include "fpdf_annot.h"
+// #include "fpdf_formfill.h"
+// #include <stdlib.h>
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// GetFormFields returns all the form fields of a document with their widgets.
+// Experimental API.
+func (p *PdfiumImplementation) GetFormFields(request *requests.GetFormFields) (*responses.GetFormFields, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	formHandle, closeFormHandle, err := p.getFormHandle(documentHandle)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFormHandle()
+
+	fields, err := p.getFormFields(documentHandle, formHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.GetFormFields{
+		Fields: fields,
+	}, nil
+}
+
+// getFormFields returns the form fields of a document, in the order of their
+// first widget.
+func (p *PdfiumImplementation) getFormFields(documentHandle *DocumentHandle, formHandle C.FPDF_FORMHANDLE) ([]responses.FormField, error) {
+	fields := []responses.FormField{}
+
+	// Widgets of the same field share the full name of the field.
+	fieldIndexes := map[string]int{}
+
+	pageCount := int(C.FPDF_GetPageCount(documentHandle.handle))
+	for pageIndex := 0; pageIndex < pageCount; pageIndex++ {
+		pageHandle, err := p.loadPage(requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: documentHandle.nativeRef,
+				Index:    pageIndex,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		annotationCount := int(C.FPDFPage_GetAnnotCount(pageHandle.handle))
+		for i := 0; i < annotationCount; i++ {
+			annotation := C.FPDFPage_GetAnnot(pageHandle.handle, C.int(i))
+			if annotation == nil {
+				continue
+			}
+
+			field, widget, err := p.getFormFieldWidget(formHandle, annotation, pageIndex, i)
+			C.FPDFPage_CloseAnnot(annotation)
+			if err != nil {
+				return nil, err
+			}
+
+			if field == nil {
+				continue
+			}
+
+			if fieldIndex, ok := fieldIndexes[field.Name]; ok {
+				existingField := &fields[fieldIndex]
+				existingField.Widgets = append(existingField.Widgets, *widget)
+				if field.ExportValues != nil {
+					existingField.ExportValues = append(existingField.ExportValues, field.ExportValues...)
+				}
+				continue
+			}
+
+			field.Widgets = []responses.FormFieldWidget{*widget}
+			fieldIndexes[field.Name] = len(fields)
+			fields = append(fields, *field)
+		}
+	}
+
+	return fields, nil
+}
+
+// getFormFieldWidget returns the field and the widget of a widget annotation.
+// The field is nil when the annotation is not a form field widget.
+func (p *PdfiumImplementation) getFormFieldWidget(formHandle C.FPDF_FORMHANDLE, annotation C.FPDF_ANNOTATION, pageIndex, annotationIndex int) (*responses.FormField, *responses.FormFieldWidget, error) {
+	if enums.FPDF_ANNOTATION_SUBTYPE(C.FPDFAnnot_GetSubtype(annotation)) != enums.FPDF_ANNOT_SUBTYPE_WIDGET {
+		return nil, nil, nil
+	}
+
+	fieldType := int(C.FPDFAnnot_GetFormFieldType(formHandle, annotation))
+	if fieldType == -1 {
+		return nil, nil, nil
+	}
+
+	name, err := p.getFormFieldString(func(buffer *C.FPDF_WCHAR, length C.ulong) C.ulong {
+		return C.FPDFAnnot_GetFormFieldName(formHandle, annotation, buffer, length)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	alternateName, err := p.getFormFieldString(func(buffer *C.FPDF_WCHAR, length C.ulong) C.ulong {
+		return C.FPDFAnnot_GetFormFieldAlternateName(formHandle, annotation, buffer, length)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	value, err := p.getFormFieldString(func(buffer *C.FPDF_WCHAR, length C.ulong) C.ulong {
+		return C.FPDFAnnot_GetFormFieldValue(formHandle, annotation, buffer, length)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rect := C.FS_RECTF{}
+	C.FPDFAnnot_GetRect(annotation, &rect)
+
+	widget := &responses.FormFieldWidget{
+		Page:         pageIndex,
+		Index:        annotationIndex,
+		ControlIndex: int(C.FPDFAnnot_GetFormControlIndex(formHandle, annotation)),
+		Rect: structs.FPDF_FS_RECTF{
+			Left:   float32(rect.left),
+			Top:    float32(rect.top),
+			Right:  float32(rect.right),
+			Bottom: float32(rect.bottom),
+		},
+	}
+
+	field := &responses.FormField{
+		Name:          name,
+		AlternateName: alternateName,
+		Type:          enums.FPDF_FORMFIELD_TYPE(fieldType),
+		Flags:         enums.FPDF_FORMFLAG(C.FPDFAnnot_GetFormFieldFlags(formHandle, annotation)),
+		Value:         value,
+		Page:          pageIndex,
+		Rect:          widget.Rect,
+	}
+
+	switch field.Type {
+	case enums.FPDF_FORMFIELD_TYPE_CHECKBOX, enums.FPDF_FORMFIELD_TYPE_RADIOBUTTON:
+		exportValue, err := p.getFormFieldString(func(buffer *C.FPDF_WCHAR, length C.ulong) C.ulong {
+			return C.FPDFAnnot_GetFormFieldExportValue(formHandle, annotation, buffer, length)
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		widget.ExportValue = exportValue
+		widget.IsChecked = int(C.FPDFAnnot_IsChecked(formHandle, annotation)) == 1
+		field.ExportValues = []string{exportValue}
+	case enums.FPDF_FORMFIELD_TYPE_TEXTFIELD:
+		// PDFium only reads the key from the widget dictionary, which is
+		// merged with the field dictionary for fields with one widget.
+		cMaxLenKey := C.CString("MaxLen")
+		defer C.free(unsafe.Pointer(cMaxLenKey))
+
+		maxLength := C.float(0)
+		if int(C.FPDFAnnot_GetNumberValue(annotation, cMaxLenKey, &maxLength)) == 1 {
+			field.MaxLength = int(maxLength)
+		}
+	case enums.FPDF_FORMFIELD_TYPE_COMBOBOX, enums.FPDF_FORMFIELD_TYPE_LISTBOX:
+		optionCount := int(C.FPDFAnnot_GetOptionCount(formHandle, annotation))
+		field.Options = make([]responses.FormFieldOption, 0, optionCount)
+		for i := 0; i < optionCount; i++ {
+			label, err := p.getFormFieldString(func(buffer *C.FPDF_WCHAR, length C.ulong) C.ulong {
+				return C.FPDFAnnot_GetOptionLabel(formHandle, annotation, C.int(i), buffer, length)
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+
+			field.Options = append(field.Options, responses.FormFieldOption{
+				Label:    label,
+				Selected: int(C.FPDFAnnot_IsOptionSelected(formHandle, annotation, C.int(i))) == 1,
+			})
+		}
+	}
+
+	fontSize := C.float(0)
+	if int(C.FPDFAnnot_GetFontSize(formHandle, annotation, &fontSize)) == 1 {
+		field.FontSize = float32(fontSize)
+	}
+
+	cKey := C.CString("DV")
+	defer C.free(unsafe.Pointer(cKey))
+	if int(C.FPDFAnnot_HasKey(annotation, cKey)) == 1 {
+		defaultValue, err := p.getFormFieldString(func(buffer *C.FPDF_WCHAR, length C.ulong) C.ulong {
+			return C.FPDFAnnot_GetStringValue(annotation, cKey, buffer, length)
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		field.DefaultValue = &defaultValue
+	}
+
+	return field, widget, nil
+}
+
+// getFormFieldString reads a string from a PDFium function that writes UTF-16LE
+// into a buffer and returns the needed buffer length. An empty string is
+// returned when the value is missing.
+func (p *PdfiumImplementation) getFormFieldString(getString func(buffer *C.FPDF_WCHAR, length C.ulong) C.ulong) (string, error) {
+	// First get the value length.
+	length := getString(nil, 0)
+	if uint64(length) <= 2 {
+		return "", nil
+	}
+
+	charData := make([]byte, length)
+	getString((*C.FPDF_WCHAR)(unsafe.Pointer(&charData[0])), C.ulong(len(charData)))
+
+	return p.transformUTF16LEToUTF8(charData)
+}
@@ -0,0 +1,16 @@
+//go:build !pdfium_experimental
+// +build !pdfium_experimental
+
+package implementation
+
+import (
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// GetFormFields returns all the form fields of a document with their widgets.
+// Experimental API.
+func (p *PdfiumImplementation) GetFormFields(request *requests.GetFormFields) (*responses.GetFormFields, error) {
+	return nil, pdfium_errors.ErrExperimentalUnsupported
+}
@@ -2,18 +2,21 @@ package implementation
 
 // #cgo pkg-config: pdfium
 // #include "fpdf_formfill.h"
+// #include <stdlib.h>
 import "C"
 import (
+	"errors"
 	"unsafe"
 
 	"github.com/google/uuid"
 	"github.com/klippa-app/go-pdfium/references"
 )
 
-func (p *PdfiumImplementation) registerFormHandle(formHandle C.FPDF_FORMHANDLE, formInfo unsafe.Pointer) *FormHandleHandle {
+func (p *PdfiumImplementation) registerFormHandle(formHandle C.FPDF_FORMHANDLE, formInfo unsafe.Pointer, documentHandle *DocumentHandle) *FormHandleHandle {
 	ref := uuid.New()
 	handle := &FormHandleHandle{
 		handle:           formHandle,
+		documentRef:      documentHandle.nativeRef,
 		nativeRef:        references.FPDF_FORMHANDLE(ref.String()),
 		formInfo:         formInfo,
 		pagePointers:     map[unsafe.Pointer]references.FPDF_PAGE{},
@@ -24,3 +27,43 @@ func (p *PdfiumImplementation) registerFormHandle(formHandle C.FPDF_FORMHANDLE,
 
 	return handle
 }
+
+// getFormHandle returns the form handle of the form fill environment that was
+// initialized for the document. When there is none, a form fill environment
+// without callbacks is initialized, which is exited by the returned cleanup
+// function.
+func (p *PdfiumImplementation) getFormHandle(documentHandle *DocumentHandle) (C.FPDF_FORMHANDLE, func(), error) {
+	for _, formHandleHandle := range p.formHandleRefs {
+		if formHandleHandle.documentRef == documentHandle.nativeRef {
+			return formHandleHandle.handle, func() {}, nil
+		}
+	}
+
+	// PDFium keeps a pointer to the form fill info, so it has to be in C memory.
+	formInfo := (*C.FPDF_FORMFILLINFO)(C.calloc(1, C.sizeof_FPDF_FORMFILLINFO))
+	formInfo.version = 1
+
+	formHandle := C.FPDFDOC_InitFormFillEnvironment(documentHandle.handle, formInfo)
+	if formHandle == nil {
+		C.free(unsafe.Pointer(formInfo))
+		return nil, nil, errors.New("could not init form fill environment")
+	}
+
+	return formHandle, func() {
+		C.FPDFDOC_ExitFormFillEnvironment(formHandle)
+		C.free(unsafe.Pointer(formInfo))
+	}, nil
+}
+
+// isFormPageLoaded returns whether the page was loaded into the form fill
+// environment of the form handle with FORM_OnAfterLoadPage.
+func (p *PdfiumImplementation) isFormPageLoaded(formHandle C.FPDF_FORMHANDLE, page C.FPDF_PAGE) bool {
+	for _, formHandleHandle := range p.formHandleRefs {
+		if formHandleHandle.handle == formHandle {
+			_, ok := formHandleHandle.pagePointers[unsafe.Pointer(page)]
+			return ok
+		}
+	}
+
+	return false
+}
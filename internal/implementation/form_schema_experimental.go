@@ -0,0 +1,244 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package implementation
+
+import (
+	"encoding/json"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+const (
+	formFieldFlagTextMultiline = 1 << 12
+	formFieldFlagTextPassword  = 1 << 13
+	formFieldFlagTextComb      = 1 << 24
+)
+
+// formSchemaFieldTypes are the names of the field types in the x-pdf keyword
+// of the schema.
+var formSchemaFieldTypes = map[enums.FPDF_FORMFIELD_TYPE]string{
+	enums.FPDF_FORMFIELD_TYPE_TEXTFIELD:   "text",
+	enums.FPDF_FORMFIELD_TYPE_CHECKBOX:    "checkbox",
+	enums.FPDF_FORMFIELD_TYPE_RADIOBUTTON: "radiobutton",
+	enums.FPDF_FORMFIELD_TYPE_COMBOBOX:    "combobox",
+	enums.FPDF_FORMFIELD_TYPE_LISTBOX:     "listbox",
+}
+
+// GetFormSchema returns a JSON Schema of the form fields of a document, with
+// the layout of the fields.
+// Experimental API.
+func (p *PdfiumImplementation) GetFormSchema(request *requests.GetFormSchema) (*responses.GetFormSchema, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	formHandle, closeFormHandle, err := p.getFormHandle(documentHandle)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFormHandle()
+
+	fields, err := p.getFormFields(documentHandle, formHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, schemaFields := getFormSchema(fields, request.Title)
+	schemaData, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.GetFormSchema{
+		Schema: schemaData,
+		Fields: schemaFields,
+	}, nil
+}
+
+// getFormSchema returns the JSON Schema of fields and the layout of the fields
+// in the schema. The tab order is the order of the fields, which is the order
+// of the widgets in the annotations of the pages.
+func getFormSchema(fields []responses.FormField, title string) (map[string]interface{}, []responses.FormSchemaField) {
+	properties := map[string]interface{}{}
+	required := []string{}
+	schemaFields := []responses.FormSchemaField{}
+
+	for _, field := range fields {
+		property := getFormSchemaProperty(field)
+		if property == nil {
+			continue
+		}
+
+		schemaField := responses.FormSchemaField{
+			Name:     field.Name,
+			Type:     field.Type,
+			TabIndex: len(schemaFields),
+			Page:     field.Page,
+			Rect:     field.Rect,
+			Widgets:  field.Widgets,
+		}
+
+		property["x-pdf"] = getFormSchemaLayout(field, schemaField.TabIndex)
+		properties[field.Name] = property
+		schemaFields = append(schemaFields, schemaField)
+
+		if field.Flags&enums.FPDF_FORMFLAG_REQUIRED != 0 {
+			required = append(required, field.Name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+
+	if title != "" {
+		schema["title"] = title
+	}
+
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema, schemaFields
+}
+
+// getFormSchemaProperty returns the schema of the value of a field, nil is
+// returned for fields that can't be filled.
+func getFormSchemaProperty(field responses.FormField) map[string]interface{} {
+	property := map[string]interface{}{}
+
+	switch field.Type {
+	case enums.FPDF_FORMFIELD_TYPE_TEXTFIELD:
+		property["type"] = "string"
+		property["default"] = field.Value
+		if field.MaxLength > 0 {
+			property["maxLength"] = field.MaxLength
+		}
+	case enums.FPDF_FORMFIELD_TYPE_CHECKBOX:
+		property["type"] = "boolean"
+		property["default"] = field.Value != "" && field.Value != "Off"
+	case enums.FPDF_FORMFIELD_TYPE_RADIOBUTTON:
+		exportValues := []string{}
+		for _, exportValue := range field.ExportValues {
+			if !containsString(exportValues, exportValue) {
+				exportValues = append(exportValues, exportValue)
+			}
+		}
+
+		property["type"] = "string"
+		property["enum"] = exportValues
+		if containsString(exportValues, field.Value) {
+			property["default"] = field.Value
+		}
+	case enums.FPDF_FORMFIELD_TYPE_COMBOBOX, enums.FPDF_FORMFIELD_TYPE_LISTBOX:
+		labels := []string{}
+		selectedLabels := []string{}
+		for _, option := range field.Options {
+			labels = append(labels, option.Label)
+			if option.Selected {
+				selectedLabels = append(selectedLabels, option.Label)
+			}
+		}
+
+		switch {
+		case field.Type == enums.FPDF_FORMFIELD_TYPE_LISTBOX && field.Flags&formFieldFlagChoiceMultiSelect != 0:
+			property["type"] = "array"
+			property["items"] = map[string]interface{}{
+				"type": "string",
+				"enum": labels,
+			}
+			property["uniqueItems"] = true
+			property["default"] = selectedLabels
+		case field.Type == enums.FPDF_FORMFIELD_TYPE_COMBOBOX && field.Flags&formFieldFlagChoiceEdit != 0:
+			// Editable comboboxes also accept text that is not an option.
+			property["type"] = "string"
+			property["examples"] = labels
+			property["default"] = field.Value
+		default:
+			property["type"] = "string"
+			property["enum"] = labels
+			if containsString(labels, field.Value) {
+				property["default"] = field.Value
+			}
+		}
+	default:
+		return nil
+	}
+
+	property["title"] = field.Name
+	if field.AlternateName != "" {
+		property["title"] = field.AlternateName
+	}
+
+	if field.Flags&enums.FPDF_FORMFLAG_READONLY != 0 {
+		property["readOnly"] = true
+	}
+
+	return property
+}
+
+// getFormSchemaLayout returns the layout hints of a field, for the x-pdf
+// keyword of its property.
+func getFormSchemaLayout(field responses.FormField, tabIndex int) map[string]interface{} {
+	widgets := make([]map[string]interface{}, len(field.Widgets))
+	for i, widget := range field.Widgets {
+		widgets[i] = map[string]interface{}{
+			"page": widget.Page,
+			"rect": getFormSchemaRect(widget.Rect),
+		}
+
+		if widget.ExportValue != "" {
+			widgets[i]["exportValue"] = widget.ExportValue
+		}
+	}
+
+	layout := map[string]interface{}{
+		"type":     formSchemaFieldTypes[field.Type],
+		"tabIndex": tabIndex,
+		"page":     field.Page,
+		"rect":     getFormSchemaRect(field.Rect),
+		"widgets":  widgets,
+	}
+
+	if field.FontSize > 0 {
+		layout["fontSize"] = field.FontSize
+	}
+
+	if field.Type == enums.FPDF_FORMFIELD_TYPE_TEXTFIELD {
+		layout["multiline"] = field.Flags&formFieldFlagTextMultiline != 0
+		layout["password"] = field.Flags&formFieldFlagTextPassword != 0
+		layout["comb"] = field.Flags&formFieldFlagTextComb != 0
+	}
+
+	return layout
+}
+
+func getFormSchemaRect(rect structs.FPDF_FS_RECTF) map[string]float32 {
+	return map[string]float32{
+		"left":   rect.Left,
+		"top":    rect.Top,
+		"right":  rect.Right,
+		"bottom": rect.Bottom,
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, existingValue := range values {
+		if existingValue == value {
+			return true
+		}
+	}
+
+	return false
+}
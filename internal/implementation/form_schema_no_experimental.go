@@ -0,0 +1,17 @@
+//go:build !pdfium_experimental
+// +build !pdfium_experimental
+
+package implementation
+
+import (
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// GetFormSchema returns a JSON Schema of the form fields of a document, with
+// the layout of the fields.
+// Experimental API.
+func (p *PdfiumImplementation) GetFormSchema(request *requests.GetFormSchema) (*responses.GetFormSchema, error) {
+	return nil, pdfium_errors.ErrExperimentalUnsupported
+}
@@ -155,7 +155,18 @@ func go_formfill_FFI_SetTimer_cb(me *C.FPDF_FORMFILLINFO, uElapse C.int, lpTimer
 		return 0
 	}
 
+	// The timer function is called by the application outside of a PDFium
+	// call, so it has to take the lock like any other PDFium call. This means
+	// it can't be called from inside a callback.
 	timerFunc := func(idEvent int) {
+		Pdfium.mutex.Lock()
+		defer Pdfium.mutex.Unlock()
+
+		// The form fill environment could have been exited in the meantime.
+		if _, ok := formFillInfoHandles[pointer]; !ok {
+			return
+		}
+
 		C.FPDF_FORMFILLINFO_CALL_TIMER(lpTimerFunc, C.int(idEvent))
 	}
 
@@ -474,7 +485,7 @@ func (p *PdfiumImplementation) FPDFDOC_InitFormFillEnvironment(request *requests
 		return nil, errors.New("could not init form fill environment")
 	}
 
-	formHandleHandle := p.registerFormHandle(formHandle, unsafe.Pointer(formInfoStruct))
+	formHandleHandle := p.registerFormHandle(formHandle, unsafe.Pointer(formInfoStruct), documentHandle)
 
 	formFillInfo := &FormFillInfo{
 		Struct:           formInfoStruct,
@@ -80,6 +80,25 @@ func (p *PdfiumImplementation) FPDF_SaveWithVersion(request *requests.FPDF_SaveW
 		return nil, err
 	}
 
+	fileBytes, err := p.saveDocument(documentHandle.handle, request.Flags, request.FileVersion, request.FilePath, request.FileWriter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &responses.FPDF_SaveWithVersion{
+		FileBytes: fileBytes,
+	}
+	if request.FilePath != nil {
+		resp.FilePath = request.FilePath
+	}
+
+	return resp, nil
+}
+
+// saveDocument saves the document into the file writer or the file path,
+// when both are not given, the bytes of the document are returned.
+// When fileVersion is 0, the file version of the document is kept.
+func (p *PdfiumImplementation) saveDocument(document C.FPDF_DOCUMENT, flags requests.SaveFlags, fileVersion int, filePath *string, fileWriter io.Writer) (*[]byte, error) {
 	writer := C.FPDF_FILEWRITE{}
 	writer.version = 1
 
@@ -88,10 +107,10 @@ func (p *PdfiumImplementation) FPDF_SaveWithVersion(request *requests.FPDF_SaveW
 
 	var fileBuf *bytes.Buffer
 	var curFile *os.File
-	if request.FileWriter != nil {
-		currentWriter = request.FileWriter
-	} else if request.FilePath != nil {
-		newFile, err := os.Create(*request.FilePath)
+	if fileWriter != nil {
+		currentWriter = fileWriter
+	} else if filePath != nil {
+		newFile, err := os.Create(*filePath)
 		if err != nil {
 			return nil, err
 		}
@@ -112,25 +131,20 @@ func (p *PdfiumImplementation) FPDF_SaveWithVersion(request *requests.FPDF_SaveW
 	}()
 
 	var success C.int
-	if request.FileVersion == 0 {
-		success = C.FPDF_SaveAsCopy(documentHandle.handle, &writer, C.ulong(request.Flags))
+	if fileVersion == 0 {
+		success = C.FPDF_SaveAsCopy(document, &writer, C.ulong(flags))
 	} else {
-		success = C.FPDF_SaveWithVersion(documentHandle.handle, &writer, C.ulong(request.Flags), C.int(request.FileVersion))
+		success = C.FPDF_SaveWithVersion(document, &writer, C.ulong(flags), C.int(fileVersion))
 	}
 
 	if int(success) == 0 {
 		return nil, errors.New("save of document failed")
 	}
 
-	resp := &responses.FPDF_SaveWithVersion{}
-	if request.FilePath != nil {
-		resp.FilePath = request.FilePath
-	}
-
 	if fileBuf != nil {
 		pdfContent := fileBuf.Bytes()
-		resp.FileBytes = &pdfContent
+		return &pdfContent, nil
 	}
 
-	return resp, nil
+	return nil, nil
 }
@@ -0,0 +1,283 @@
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+// #include "fpdf_edit.h"
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+const headerFooterDefaultFontSize = 10
+
+var headerFooterTemplateVariable = regexp.MustCompile(`\{([a-z]+)(?::([0-9]+))?\}`)
+
+type headerFooterTemplateValues struct {
+	prefix   string
+	suffix   string
+	number   int
+	page     int
+	total    int
+	document int
+}
+
+// renderHeaderFooterTemplate replaces the variables in a header or footer template.
+func renderHeaderFooterTemplate(template string, values headerFooterTemplateValues) (string, error) {
+	var renderErr error
+	rendered := headerFooterTemplateVariable.ReplaceAllStringFunc(template, func(variable string) string {
+		match := headerFooterTemplateVariable.FindStringSubmatch(variable)
+		name, format := match[1], match[2]
+
+		var number int
+		switch name {
+		case "prefix", "suffix":
+			if format != "" {
+				renderErr = fmt.Errorf("template variable {%s} can't have a width", name)
+				return variable
+			}
+			if name == "prefix" {
+				return values.prefix
+			}
+			return values.suffix
+		case "number":
+			number = values.number
+		case "page":
+			number = values.page
+		case "total":
+			number = values.total
+		case "document":
+			number = values.document
+		default:
+			renderErr = fmt.Errorf("unknown template variable {%s}", name)
+			return variable
+		}
+
+		if format == "" {
+			return strconv.Itoa(number)
+		}
+
+		width, err := strconv.Atoi(format)
+		if err != nil {
+			renderErr = err
+			return variable
+		}
+
+		if strings.HasPrefix(format, "0") {
+			return fmt.Sprintf("%0*d", width, number)
+		}
+		return fmt.Sprintf("%*d", width, number)
+	})
+
+	if renderErr != nil {
+		return "", renderErr
+	}
+
+	return rendered, nil
+}
+
+// AddHeaderFooter adds headers and footers to every page of one or more documents.
+func (p *PdfiumImplementation) AddHeaderFooter(request *requests.AddHeaderFooter) (*responses.AddHeaderFooter, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	if len(request.Documents) == 0 {
+		return nil, errors.New("no documents given")
+	}
+
+	// The templates are in the order of the positions: header left, center,
+	// right, footer left, center, right.
+	templates := []string{request.HeaderLeft, request.HeaderCenter, request.HeaderRight, request.FooterLeft, request.FooterCenter, request.FooterRight}
+
+	// Validate the templates before changing anything.
+	for _, template := range templates {
+		if _, err := renderHeaderFooterTemplate(template, headerFooterTemplateValues{}); err != nil {
+			return nil, err
+		}
+	}
+
+	documentHandles := make([]*DocumentHandle, len(request.Documents))
+	for i := range request.Documents {
+		documentHandle, err := p.getDocumentHandle(request.Documents[i])
+		if err != nil {
+			return nil, err
+		}
+		documentHandles[i] = documentHandle
+	}
+
+	fontSize := float64(request.FontSize)
+	if fontSize <= 0 {
+		fontSize = headerFooterDefaultFontSize
+	}
+
+	resp := &responses.AddHeaderFooter{
+		Documents: make([]responses.AddHeaderFooterDocument, len(request.Documents)),
+	}
+
+	fontName := request.Font
+	if fontName == "" {
+		fontName = "Helvetica"
+	}
+
+	number := request.StartNumber
+	for documentIndex, documentHandle := range documentHandles {
+		font := loadFont(documentHandle.handle, fontName, request.FontData, request.FontType)
+		if font == nil {
+			return nil, errors.New("could not load font")
+		}
+
+		pageCount := int(C.FPDF_GetPageCount(documentHandle.handle))
+		resp.Documents[documentIndex].FirstNumber = number
+		for pageIndex := 0; pageIndex < pageCount; pageIndex++ {
+			values := headerFooterTemplateValues{
+				prefix:   request.Prefix,
+				suffix:   request.Suffix,
+				number:   number,
+				page:     pageIndex + 1,
+				total:    pageCount,
+				document: documentIndex + 1,
+			}
+
+			err := p.addHeaderFooterToPage(documentHandle, pageIndex, font, fontSize, templates, values, request)
+			if err != nil {
+				C.FPDFFont_Close(font)
+				return nil, err
+			}
+
+			number++
+		}
+
+		C.FPDFFont_Close(font)
+		resp.Documents[documentIndex].LastNumber = number - 1
+	}
+
+	resp.NextNumber = number
+
+	return resp, nil
+}
+
+// addHeaderFooterToPage adds the texts of the templates to a page.
+func (p *PdfiumImplementation) addHeaderFooterToPage(documentHandle *DocumentHandle, pageIndex int, font C.FPDF_FONT, fontSize float64, templates []string, values headerFooterTemplateValues, request *requests.AddHeaderFooter) error {
+	pageHandle, err := p.loadPage(requests.Page{
+		ByIndex: &requests.PageByIndex{
+			Document: documentHandle.nativeRef,
+			Index:    pageIndex,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	box := getPageDisplayBox(pageHandle.handle, false)
+	rotation := box.uprightRotation()
+	cos := math.Cos(rotation)
+	sin := math.Sin(rotation)
+
+	addedTexts := 0
+	for i, template := range templates {
+		if template == "" {
+			continue
+		}
+
+		text, err := renderHeaderFooterTemplate(template, values)
+		if err != nil {
+			return err
+		}
+
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		textObject, err := p.createHeaderFooterTextObject(documentHandle.handle, font, fontSize, text, request)
+		if err != nil {
+			return err
+		}
+
+		textLeft := C.float(0)
+		textBottom := C.float(0)
+		textRight := C.float(0)
+		textTop := C.float(0)
+		if int(C.FPDFPageObj_GetBounds(textObject, &textLeft, &textBottom, &textRight, &textTop)) == 0 {
+			C.FPDFPageObj_Destroy(textObject)
+			return errors.New("could not get bounds of text")
+		}
+
+		textWidth := float64(textRight - textLeft)
+		textHeight := float64(textTop - textBottom)
+
+		// The position of the bottom left corner of the text as displayed.
+		var displayX, displayY float64
+		switch i % 3 {
+		case 0:
+			displayX = float64(request.MarginX)
+		case 1:
+			displayX = (box.width - textWidth) / 2
+		case 2:
+			displayX = box.width - float64(request.MarginX) - textWidth
+		}
+
+		if i < 3 {
+			displayY = box.height - float64(request.MarginY) - textHeight
+		} else {
+			displayY = float64(request.MarginY)
+		}
+
+		x, y := box.toPage(displayX, displayY)
+
+		// Move the text to the origin, rotate it so that it's displayed
+		// upright and move it into position.
+		C.FPDFPageObj_Transform(textObject, 1, 0, 0, 1, C.double(-textLeft), C.double(-textBottom))
+		C.FPDFPageObj_Transform(textObject, C.double(cos), C.double(sin), C.double(-sin), C.double(cos), C.double(x), C.double(y))
+
+		C.FPDFPage_InsertObject(pageHandle.handle, textObject)
+		addedTexts++
+	}
+
+	if addedTexts > 0 {
+		if int(C.FPDFPage_GenerateContent(pageHandle.handle)) == 0 {
+			return errors.New("could not generate page content")
+		}
+	}
+
+	return nil
+}
+
+// createHeaderFooterTextObject creates a text object with the given text.
+func (p *PdfiumImplementation) createHeaderFooterTextObject(document C.FPDF_DOCUMENT, font C.FPDF_FONT, fontSize float64, text string, request *requests.AddHeaderFooter) (C.FPDF_PAGEOBJECT, error) {
+	textObject := C.FPDFPageObj_CreateTextObj(document, font, C.float(fontSize))
+	if textObject == nil {
+		return nil, errors.New("could not create text object")
+	}
+
+	transformedText, err := p.transformUTF8ToUTF16LE(text)
+	if err != nil {
+		C.FPDFPageObj_Destroy(textObject)
+		return nil, err
+	}
+
+	// Add the NULL terminator.
+	transformedText = append(transformedText, 0, 0)
+
+	if int(C.FPDFText_SetText(textObject, (C.FPDF_WIDESTRING)(unsafe.Pointer(&transformedText[0])))) == 0 {
+		C.FPDFPageObj_Destroy(textObject)
+		return nil, errors.New("could not set text")
+	}
+
+	if request.Color != nil {
+		if int(C.FPDFPageObj_SetFillColor(textObject, C.uint(request.Color.R), C.uint(request.Color.G), C.uint(request.Color.B), 255)) == 0 {
+			C.FPDFPageObj_Destroy(textObject)
+			return nil, errors.New("could not set text color")
+		}
+	}
+
+	return textObject, nil
+}
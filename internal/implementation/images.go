@@ -0,0 +1,369 @@
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+// #include "fpdf_edit.h"
+import "C"
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"unsafe"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// pageImagesOptions are the options of the image helpers.
+type pageImagesOptions struct {
+	includeRawData     bool
+	includeDecodedData bool
+	exportFormat       requests.ImageExportFormat
+}
+
+// GetPageImages returns the images of a page, including the images in form objects.
+func (p *PdfiumImplementation) GetPageImages(request *requests.GetPageImages) (*responses.GetPageImages, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	options := pageImagesOptions{
+		includeRawData:     request.IncludeRawData,
+		includeDecodedData: request.IncludeDecodedData,
+		exportFormat:       request.ExportFormat,
+	}
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+
+	pageHandle, err := p.loadPage(request.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.getPageImages(pageHandle, options)
+}
+
+// GetDocumentImages returns the images of the pages of a document.
+func (p *PdfiumImplementation) GetDocumentImages(request *requests.GetDocumentImages) (*responses.GetDocumentImages, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	options := pageImagesOptions{
+		includeRawData:     request.IncludeRawData,
+		includeDecodedData: request.IncludeDecodedData,
+		exportFormat:       request.ExportFormat,
+	}
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	pageCount := int(C.FPDF_GetPageCount(documentHandle.handle))
+	pages := request.Pages
+	if pages == nil {
+		pages = make([]int, pageCount)
+		for i := range pages {
+			pages[i] = i
+		}
+	}
+
+	for _, page := range pages {
+		if page < 0 || page >= pageCount {
+			return nil, fmt.Errorf("page %d is out of bounds, document has %d pages", page, pageCount)
+		}
+	}
+
+	resp := &responses.GetDocumentImages{
+		Pages: make([]responses.GetPageImages, len(pages)),
+	}
+
+	for i, page := range pages {
+		pageHandle, err := p.loadPage(requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: documentHandle.nativeRef,
+				Index:    page,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		pageImages, err := p.getPageImages(pageHandle, options)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Pages[i] = *pageImages
+	}
+
+	return resp, nil
+}
+
+func (o pageImagesOptions) validate() error {
+	switch o.exportFormat {
+	case requests.ImageExportFormatNone, requests.ImageExportFormatNative, requests.ImageExportFormatPNG:
+		return nil
+	default:
+		return fmt.Errorf("unsupported export format %s", o.exportFormat)
+	}
+}
+
+// getPageImages returns the images of a loaded page.
+func (p *PdfiumImplementation) getPageImages(pageHandle *PageHandle, options pageImagesOptions) (*responses.GetPageImages, error) {
+	resp := &responses.GetPageImages{
+		Page:   pageHandle.index,
+		Images: []responses.GetPageImagesImage{},
+	}
+
+	objectCount := int(C.FPDFPage_CountObjects(pageHandle.handle))
+	for i := 0; i < objectCount; i++ {
+		object := C.FPDFPage_GetObject(pageHandle.handle, C.int(i))
+		if object == nil {
+			continue
+		}
+
+		left, bottom, right, top, _ := getPageObjectBounds(object)
+		outerRect := structs.FPDF_FS_RECTF{
+			Left:   float32(left),
+			Top:    float32(top),
+			Right:  float32(right),
+			Bottom: float32(bottom),
+		}
+
+		err := p.collectPageImages(pageHandle.handle, object, []int{i}, pageMatrix{a: 1, d: 1}, true, outerRect, options, resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// collectPageImages adds the image of an image object to the response, or
+// the images in a form object. The parent matrix is the matrix of the form
+// objects the object is nested in, hasMatrix is false when the matrix is not
+// known.
+func (p *PdfiumImplementation) collectPageImages(page C.FPDF_PAGE, object C.FPDF_PAGEOBJECT, objectPath []int, parentMatrix pageMatrix, hasMatrix bool, outerRect structs.FPDF_FS_RECTF, options pageImagesOptions, resp *responses.GetPageImages) error {
+	objectMatrix, hasObjectMatrix := getPageObjectMatrix(object)
+	matrix := objectMatrix.then(parentMatrix)
+	hasMatrix = hasMatrix && hasObjectMatrix
+
+	switch C.FPDFPageObj_GetType(object) {
+	case C.FPDF_PAGEOBJ_FORM:
+		objectCount := int(C.FPDFFormObj_CountObjects(object))
+		for i := 0; i < objectCount; i++ {
+			childObject := C.FPDFFormObj_GetObject(object, C.ulong(i))
+			if childObject == nil {
+				continue
+			}
+
+			childPath := append(append([]int{}, objectPath...), i)
+			if err := p.collectPageImages(page, childObject, childPath, matrix, hasMatrix, outerRect, options, resp); err != nil {
+				return err
+			}
+		}
+	case C.FPDF_PAGEOBJ_IMAGE:
+		pageImage, err := p.getPageImage(page, object, options)
+		if err != nil {
+			return fmt.Errorf("could not get image %v: %w", objectPath, err)
+		}
+
+		pageImage.ObjectPath = objectPath
+		pageImage.Rect = outerRect
+		if hasMatrix {
+			pageImage.Matrix = &structs.FPDF_FS_MATRIX{
+				A: float32(matrix.a),
+				B: float32(matrix.b),
+				C: float32(matrix.c),
+				D: float32(matrix.d),
+				E: float32(matrix.e),
+				F: float32(matrix.f),
+			}
+
+			// Images are drawn in a 1x1 square that is transformed by the
+			// matrix.
+			left, bottom, right, top := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+			for _, corner := range [][2]float64{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+				x := matrix.a*corner[0] + matrix.c*corner[1] + matrix.e
+				y := matrix.b*corner[0] + matrix.d*corner[1] + matrix.f
+				left, bottom, right, top = math.Min(left, x), math.Min(bottom, y), math.Max(right, x), math.Max(top, y)
+			}
+
+			pageImage.Rect = structs.FPDF_FS_RECTF{
+				Left:   float32(left),
+				Top:    float32(top),
+				Right:  float32(right),
+				Bottom: float32(bottom),
+			}
+		}
+
+		resp.Images = append(resp.Images, *pageImage)
+	}
+
+	return nil
+}
+
+// getPageImage returns the information and the data of an image object.
+func (p *PdfiumImplementation) getPageImage(page C.FPDF_PAGE, object C.FPDF_PAGEOBJECT, options pageImagesOptions) (*responses.GetPageImagesImage, error) {
+	pageImage := &responses.GetPageImagesImage{
+		Filters: []string{},
+	}
+
+	filterCount := int(C.FPDFImageObj_GetImageFilterCount(object))
+	for i := 0; i < filterCount; i++ {
+		filterLength := C.FPDFImageObj_GetImageFilter(object, C.int(i), nil, 0)
+		if int(filterLength) == 0 {
+			continue
+		}
+
+		charData := make([]byte, uint64(filterLength))
+		C.FPDFImageObj_GetImageFilter(object, C.int(i), unsafe.Pointer(&charData[0]), C.ulong(len(charData)))
+		pageImage.Filters = append(pageImage.Filters, string(charData[:len(charData)-1])) // Remove NULL-terminator.
+	}
+
+	metadata := C.FPDF_IMAGEOBJ_METADATA{}
+	if int(C.FPDFImageObj_GetImageMetadata(object, page, &metadata)) == 1 {
+		pageImage.Metadata = structs.FPDF_IMAGEOBJ_METADATA{
+			Width:           uint(metadata.width),
+			Height:          uint(metadata.height),
+			HorizontalDPI:   float32(metadata.horizontal_dpi),
+			VerticalDPI:     float32(metadata.vertical_dpi),
+			BitsPerPixel:    uint(metadata.bits_per_pixel),
+			Colorspace:      enums.FPDF_COLORSPACE(metadata.colorspace),
+			MarkedContentID: int(metadata.marked_content_id),
+		}
+	}
+
+	var rawData []byte
+	if options.includeRawData || options.exportFormat == requests.ImageExportFormatNative {
+		rawData = getImageObjectRawData(object)
+	}
+
+	if options.includeRawData {
+		pageImage.RawData = rawData
+	}
+
+	if options.includeDecodedData {
+		dataLength := C.FPDFImageObj_GetImageDataDecoded(object, nil, 0)
+		if int(dataLength) > 0 {
+			pageImage.DecodedData = make([]byte, uint64(dataLength))
+			C.FPDFImageObj_GetImageDataDecoded(object, unsafe.Pointer(&pageImage.DecodedData[0]), C.ulong(len(pageImage.DecodedData)))
+		}
+	}
+
+	if options.exportFormat == requests.ImageExportFormatNone {
+		return pageImage, nil
+	}
+
+	// JPEG and JPEG 2000 images can be exported as they are when the last
+	// filter is the image filter, the other filters are applied.
+	if options.exportFormat == requests.ImageExportFormatNative && len(pageImage.Filters) > 0 && len(rawData) > 0 {
+		lastFilter := pageImage.Filters[len(pageImage.Filters)-1]
+		if lastFilter == "DCTDecode" || lastFilter == "JPXDecode" {
+			pageImage.ExportData = rawData
+			if len(pageImage.Filters) > 1 {
+				dataLength := C.FPDFImageObj_GetImageDataDecoded(object, nil, 0)
+				if int(dataLength) > 0 {
+					pageImage.ExportData = make([]byte, uint64(dataLength))
+					C.FPDFImageObj_GetImageDataDecoded(object, unsafe.Pointer(&pageImage.ExportData[0]), C.ulong(len(pageImage.ExportData)))
+				}
+			}
+
+			pageImage.ExportFormat = responses.ImageExportFormatJPEG
+			if lastFilter == "JPXDecode" {
+				pageImage.ExportFormat = responses.ImageExportFormatJPX
+			}
+
+			return pageImage, nil
+		}
+	}
+
+	exportImage, err := getImageObjectImage(object)
+	if err != nil {
+		return nil, err
+	}
+
+	var imgBuf bytes.Buffer
+	if err := png.Encode(&imgBuf, exportImage); err != nil {
+		return nil, err
+	}
+
+	pageImage.ExportData = imgBuf.Bytes()
+	pageImage.ExportFormat = responses.ImageExportFormatPNG
+
+	return pageImage, nil
+}
+
+// getImageObjectRawData returns the raw data of an image object.
+func getImageObjectRawData(object C.FPDF_PAGEOBJECT) []byte {
+	dataLength := C.FPDFImageObj_GetImageDataRaw(object, nil, 0)
+	if int(dataLength) == 0 {
+		return nil
+	}
+
+	data := make([]byte, uint64(dataLength))
+	C.FPDFImageObj_GetImageDataRaw(object, unsafe.Pointer(&data[0]), C.ulong(len(data)))
+	return data
+}
+
+// getImageObjectImage returns the bitmap of an image object as a Go image.
+// The mask of the image and the matrix are not applied.
+func getImageObjectImage(object C.FPDF_PAGEOBJECT) (image.Image, error) {
+	bitmap := C.FPDFImageObj_GetBitmap(object)
+	if bitmap == nil {
+		return nil, errors.New("could not get image bitmap")
+	}
+	defer C.FPDFBitmap_Destroy(bitmap)
+
+	width := int(C.FPDFBitmap_GetWidth(bitmap))
+	height := int(C.FPDFBitmap_GetHeight(bitmap))
+	stride := int(C.FPDFBitmap_GetStride(bitmap))
+	if width == 0 || height == 0 {
+		return nil, errors.New("image has no size")
+	}
+
+	size := stride * height
+	buffer := C.FPDFBitmap_GetBuffer(bitmap)
+	data := (*[1<<50 - 1]byte)(unsafe.Pointer(buffer))[:size:size]
+
+	switch C.FPDFBitmap_GetFormat(bitmap) {
+	case C.FPDFBitmap_Gray:
+		img := image.NewGray(image.Rect(0, 0, width, height))
+		for y := 0; y < height; y++ {
+			copy(img.Pix[y*img.Stride:y*img.Stride+width], data[y*stride:y*stride+width])
+		}
+		return img, nil
+	case C.FPDFBitmap_BGR, C.FPDFBitmap_BGRx, C.FPDFBitmap_BGRA:
+		bytesPerPixel := 4
+		if C.FPDFBitmap_GetFormat(bitmap) == C.FPDFBitmap_BGR {
+			bytesPerPixel = 3
+		}
+		hasAlpha := C.FPDFBitmap_GetFormat(bitmap) == C.FPDFBitmap_BGRA
+
+		img := image.NewNRGBA(image.Rect(0, 0, width, height))
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				offset := y*stride + x*bytesPerPixel
+				pixel := color.NRGBA{R: data[offset+2], G: data[offset+1], B: data[offset], A: 255}
+				if hasAlpha {
+					pixel.A = data[offset+3]
+				}
+				img.SetNRGBA(x, y, pixel)
+			}
+		}
+		return img, nil
+	default:
+		return nil, errors.New("unsupported image bitmap format")
+	}
+}
@@ -0,0 +1,26 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+// #include "fpdf_edit.h"
+import "C"
+
+// getPageObjectMatrix returns the matrix of a page object.
+func getPageObjectMatrix(object C.FPDF_PAGEOBJECT) (pageMatrix, bool) {
+	matrix := C.FS_MATRIX{}
+	if int(C.FPDFPageObj_GetMatrix(object, &matrix)) == 0 {
+		return pageMatrix{a: 1, d: 1}, false
+	}
+
+	return pageMatrix{
+		a: float64(matrix.a),
+		b: float64(matrix.b),
+		c: float64(matrix.c),
+		d: float64(matrix.d),
+		e: float64(matrix.e),
+		f: float64(matrix.f),
+	}, true
+}
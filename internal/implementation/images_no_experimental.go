@@ -0,0 +1,14 @@
+//go:build !pdfium_experimental
+// +build !pdfium_experimental
+
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+import "C"
+
+// getPageObjectMatrix always returns false, since getting the matrix of a
+// page object is only supported with experimental support.
+func getPageObjectMatrix(object C.FPDF_PAGEOBJECT) (pageMatrix, bool) {
+	return pageMatrix{a: 1, d: 1}, false
+}
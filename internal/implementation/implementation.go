@@ -337,27 +337,7 @@ func (p *PdfiumImplementation) OpenDocument(request *requests.OpenDocument) (*re
 	}
 
 	if doc == nil {
-		var pdfiumError error
-
-		errorCode := C.FPDF_GetLastError()
-		switch errorCode {
-		case C.FPDF_ERR_SUCCESS:
-			pdfiumError = pdfium_errors.ErrSuccess
-		case C.FPDF_ERR_UNKNOWN:
-			pdfiumError = pdfium_errors.ErrUnknown
-		case C.FPDF_ERR_FILE:
-			pdfiumError = pdfium_errors.ErrFile
-		case C.FPDF_ERR_FORMAT:
-			pdfiumError = pdfium_errors.ErrFormat
-		case C.FPDF_ERR_PASSWORD:
-			pdfiumError = pdfium_errors.ErrPassword
-		case C.FPDF_ERR_SECURITY:
-			pdfiumError = pdfium_errors.ErrSecurity
-		case C.FPDF_ERR_PAGE:
-			pdfiumError = pdfium_errors.ErrPage
-		default:
-			pdfiumError = pdfium_errors.ErrUnexpected
-		}
+		pdfiumError := getLastError()
 
 		// Cleanup when file loading didn't work.
 		if nativeDoc.fileHandleRef != nil {
@@ -380,6 +360,88 @@ func (p *PdfiumImplementation) OpenDocument(request *requests.OpenDocument) (*re
 	}, nil
 }
 
+// reloadDocument replaces the document of a document handle with the document
+// in data, for changes that PDFium can't make to a loaded document. The
+// reference of the document stays the same, its pages and other handles are
+// closed like FPDF_CloseDocument closes them. The document can't be reloaded
+// while a form fill environment is initialized for it, because the form handle
+// keeps a pointer to the document.
+func (p *PdfiumImplementation) reloadDocument(documentHandle *DocumentHandle, data []byte) error {
+	if len(data) == 0 {
+		return errors.New("no document data given")
+	}
+
+	for _, formHandleHandle := range p.formHandleRefs {
+		if formHandleHandle.documentRef == documentHandle.nativeRef {
+			return errors.New("document can't be reloaded while a form fill environment is initialized")
+		}
+	}
+
+	doc := C.FPDF_LoadMemDocument64(unsafe.Pointer(&(data[0])), C.size_t(len(data)), nil)
+	if doc == nil {
+		return getLastError()
+	}
+
+	for i := range documentHandle.pageRefs {
+		delete(p.pageRefs, i)
+	}
+
+	for i := range documentHandle.textPageRefs {
+		delete(p.textPageRefs, i)
+	}
+
+	for i := range documentHandle.pageLinkRefs {
+		delete(p.pageLinkRefs, i)
+	}
+
+	for i := range documentHandle.searchRefs {
+		delete(p.searchRefs, i)
+	}
+
+	for i := range documentHandle.structTreeRefs {
+		delete(p.structTreeRefs, i)
+	}
+
+	for i := range documentHandle.structElementRefs {
+		delete(p.structElementRefs, i)
+	}
+
+	if err := documentHandle.Close(); err != nil {
+		C.FPDF_CloseDocument(doc)
+		return err
+	}
+
+	documentHandle.handle = doc
+	documentHandle.data = &data
+	documentHandle.fileHandleRef = nil
+	Pdfium.documentRefs[documentHandle.nativeRef] = documentHandle
+
+	return nil
+}
+
+// getLastError returns the error of the last failed PDFium call.
+func getLastError() error {
+	errorCode := C.FPDF_GetLastError()
+	switch errorCode {
+	case C.FPDF_ERR_SUCCESS:
+		return pdfium_errors.ErrSuccess
+	case C.FPDF_ERR_UNKNOWN:
+		return pdfium_errors.ErrUnknown
+	case C.FPDF_ERR_FILE:
+		return pdfium_errors.ErrFile
+	case C.FPDF_ERR_FORMAT:
+		return pdfium_errors.ErrFormat
+	case C.FPDF_ERR_PASSWORD:
+		return pdfium_errors.ErrPassword
+	case C.FPDF_ERR_SECURITY:
+		return pdfium_errors.ErrSecurity
+	case C.FPDF_ERR_PAGE:
+		return pdfium_errors.ErrPage
+	default:
+		return pdfium_errors.ErrUnexpected
+	}
+}
+
 func (p *PdfiumImplementation) Close() error {
 	p.Lock()
 	defer p.Unlock()
@@ -0,0 +1,541 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+// #include "fpdf_edit.h"
+// #include "fpdf_ppo.h"
+// #include "fpdf_transformpage.h"
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+const (
+	imposeDefaultCropMarkLength = 10
+	imposeCropMarkOffset        = 3 // The space between the corner of a page and the crop marks.
+	imposeCropMarkWidth         = 0.25
+)
+
+type imposeSourcePage struct {
+	xObject  C.FPDF_XOBJECT
+	left     float64 // The visible box of the page, without rotation.
+	bottom   float64
+	width    float64
+	height   float64
+	rotation int // The rotation of the page in quarter turns clockwise.
+}
+
+// displaySize returns the size of the page when the rotation is applied.
+func (s *imposeSourcePage) displaySize() (float64, float64) {
+	if s.rotation%2 == 1 {
+		return s.height, s.width
+	}
+	return s.width, s.height
+}
+
+// imposeSource keeps track of the xobjects of the source pages that are
+// used in the destination document.
+type imposeSource struct {
+	source      C.FPDF_DOCUMENT
+	destination C.FPDF_DOCUMENT
+	pages       map[int]*imposeSourcePage
+}
+
+func newImposeSource(source, destination C.FPDF_DOCUMENT) *imposeSource {
+	return &imposeSource{
+		source:      source,
+		destination: destination,
+		pages:       map[int]*imposeSourcePage{},
+	}
+}
+
+func (s *imposeSource) getPage(index int) (*imposeSourcePage, error) {
+	if sourcePage, ok := s.pages[index]; ok {
+		return sourcePage, nil
+	}
+
+	page := C.FPDF_LoadPage(s.source, C.int(index))
+	if page == nil {
+		return nil, fmt.Errorf("could not load page %d", index)
+	}
+	defer C.FPDF_ClosePage(page)
+
+	box := getPageDisplayBox(page, false)
+
+	xObject := C.FPDF_NewXObjectFromPage(s.destination, s.source, C.int(index))
+	if xObject == nil {
+		return nil, errors.New("creation of xobject failed")
+	}
+
+	sourcePage := &imposeSourcePage{
+		xObject:  xObject,
+		left:     box.left,
+		bottom:   box.bottom,
+		width:    box.right - box.left,
+		height:   box.top - box.bottom,
+		rotation: box.rotation,
+	}
+	s.pages[index] = sourcePage
+
+	return sourcePage, nil
+}
+
+func (s *imposeSource) close() {
+	for i := range s.pages {
+		C.FPDF_CloseXObject(s.pages[i].xObject)
+	}
+	s.pages = map[int]*imposeSourcePage{}
+}
+
+// placePage places a source page on the destination page with its bottom
+// left corner at the given position, scaled with the given scale.
+func (s *imposeSource) placePage(destinationPage C.FPDF_PAGE, index int, x, y, scale float64) error {
+	formObject, err := s.newFormObject(index, x, y, scale)
+	if err != nil {
+		return err
+	}
+
+	C.FPDFPage_InsertObject(destinationPage, formObject)
+
+	return nil
+}
+
+// newFormObject creates a form object of a source page with its bottom left
+// corner at the given position, scaled with the given scale.
+func (s *imposeSource) newFormObject(index int, x, y, scale float64) (C.FPDF_PAGEOBJECT, error) {
+	sourcePage, err := s.getPage(index)
+	if err != nil {
+		return nil, err
+	}
+
+	formObject := C.FPDF_NewFormObjectFromXObject(sourcePage.xObject)
+	if formObject == nil {
+		return nil, errors.New("creation of form object failed")
+	}
+
+	// Move the visible box of the page to the origin while applying the
+	// rotation of the page.
+	right := sourcePage.left + sourcePage.width
+	top := sourcePage.bottom + sourcePage.height
+	var a, b, c, d, e, f float64
+	switch sourcePage.rotation {
+	case 1:
+		a, b, c, d, e, f = 0, -1, 1, 0, -sourcePage.bottom, right
+	case 2:
+		a, b, c, d, e, f = -1, 0, 0, -1, right, top
+	case 3:
+		a, b, c, d, e, f = 0, 1, -1, 0, top, -sourcePage.left
+	default:
+		a, b, c, d, e, f = 1, 0, 0, 1, -sourcePage.left, -sourcePage.bottom
+	}
+
+	C.FPDFPageObj_Transform(formObject, C.double(a*scale), C.double(b*scale), C.double(c*scale), C.double(d*scale), C.double(e*scale+x), C.double(f*scale+y))
+
+	return formObject, nil
+}
+
+// getImposeFitScale returns the scale to fit a page into a cell without
+// changing the aspect ratio.
+func getImposeFitScale(width, height, cellWidth, cellHeight float64) float64 {
+	if width <= 0 || height <= 0 {
+		return 1
+	}
+	return math.Min(cellWidth/width, cellHeight/height)
+}
+
+// addImposeCropMarks draws crop marks outside the corners of the given rect.
+func addImposeCropMarks(page C.FPDF_PAGE, left, bottom, right, top, length float64) {
+	if length <= 0 {
+		length = imposeDefaultCropMarkLength
+	}
+
+	addLine := func(x1, y1, x2, y2 float64) {
+		path := C.FPDFPageObj_CreateNewPath(C.float(x1), C.float(y1))
+		C.FPDFPath_LineTo(path, C.float(x2), C.float(y2))
+		C.FPDFPageObj_SetStrokeColor(path, 0, 0, 0, 255)
+		C.FPDFPageObj_SetStrokeWidth(path, imposeCropMarkWidth)
+		C.FPDFPath_SetDrawMode(path, C.FPDF_FILLMODE_NONE, 1)
+		C.FPDFPage_InsertObject(page, path)
+	}
+
+	for _, x := range []float64{left, right} {
+		direction := -1.0
+		if x == right {
+			direction = 1
+		}
+		for _, y := range []float64{bottom, top} {
+			verticalDirection := -1.0
+			if y == top {
+				verticalDirection = 1
+			}
+
+			// Horizontal mark next to the corner.
+			addLine(x+direction*imposeCropMarkOffset, y, x+direction*(imposeCropMarkOffset+length), y)
+
+			// Vertical mark below or above the corner.
+			addLine(x, y+verticalDirection*imposeCropMarkOffset, x, y+verticalDirection*(imposeCropMarkOffset+length))
+		}
+	}
+}
+
+// addImposeMask draws a white rect, used to hide content in the margins.
+func addImposeMask(page C.FPDF_PAGE, left, bottom, width, height float64) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	rect := C.FPDFPageObj_CreateNewRect(C.float(left), C.float(bottom), C.float(width), C.float(height))
+	C.FPDFPageObj_SetFillColor(rect, 255, 255, 255, 255)
+	C.FPDFPath_SetDrawMode(rect, C.FPDF_FILLMODE_WINDING, 0)
+	C.FPDFPage_InsertObject(page, rect)
+}
+
+// newImposeSheet adds a new sheet at the end of the destination document.
+func newImposeSheet(document C.FPDF_DOCUMENT, width, height float64) (C.FPDF_PAGE, error) {
+	page := C.FPDFPage_New(document, C.FPDF_GetPageCount(document), C.double(width), C.double(height))
+	if page == nil {
+		return nil, errors.New("could not create new sheet")
+	}
+	return page, nil
+}
+
+// finishImposeSheet writes the content of the sheet and closes it.
+func finishImposeSheet(page C.FPDF_PAGE) error {
+	defer C.FPDF_ClosePage(page)
+	if int(C.FPDFPage_GenerateContent(page)) == 0 {
+		return errors.New("could not generate content of sheet")
+	}
+	return nil
+}
+
+// ImposeNUp creates a new document with multiple pages of the source document on every sheet.
+// Experimental API.
+func (p *PdfiumImplementation) ImposeNUp(request *requests.ImposeNUp) (*responses.ImposeNUp, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.SheetWidth <= 0 || request.SheetHeight <= 0 {
+		return nil, errors.New("sheet width and height should be larger than 0")
+	}
+
+	if request.Columns < 1 || request.Rows < 1 {
+		return nil, errors.New("columns and rows should be at least 1")
+	}
+
+	sheetWidth := float64(request.SheetWidth)
+	sheetHeight := float64(request.SheetHeight)
+	margin := float64(request.Margin)
+	gutter := float64(request.Gutter)
+	cellWidth := (sheetWidth - 2*margin - float64(request.Columns-1)*gutter) / float64(request.Columns)
+	cellHeight := (sheetHeight - 2*margin - float64(request.Rows-1)*gutter) / float64(request.Rows)
+	if cellWidth <= 0 || cellHeight <= 0 {
+		return nil, errors.New("margin and gutter leave no space for the pages")
+	}
+
+	doc := C.FPDF_CreateNewDocument()
+	if doc == nil {
+		return nil, errors.New("could not create new document")
+	}
+
+	source := newImposeSource(documentHandle.handle, doc)
+	defer source.close()
+
+	pagesPerSheet := request.Columns * request.Rows
+	pageCount := int(C.FPDF_GetPageCount(documentHandle.handle))
+	for sheetStart := 0; sheetStart < pageCount; sheetStart += pagesPerSheet {
+		sheet, err := newImposeSheet(doc, sheetWidth, sheetHeight)
+		if err != nil {
+			C.FPDF_CloseDocument(doc)
+			return nil, err
+		}
+
+		for i := 0; i < pagesPerSheet && sheetStart+i < pageCount; i++ {
+			column := i % request.Columns
+			row := i / request.Columns
+			cellLeft := margin + float64(column)*(cellWidth+gutter)
+			cellBottom := sheetHeight - margin - float64(row+1)*cellHeight - float64(row)*gutter
+
+			err = p.placeImposeCell(source, sheet, sheetStart+i, cellLeft, cellBottom, cellWidth, cellHeight, request.CropMarks, float64(request.CropMarkLength))
+			if err != nil {
+				C.FPDF_ClosePage(sheet)
+				C.FPDF_CloseDocument(doc)
+				return nil, err
+			}
+		}
+
+		if err := finishImposeSheet(sheet); err != nil {
+			C.FPDF_CloseDocument(doc)
+			return nil, err
+		}
+	}
+
+	newDocumentHandle := p.registerDocument(doc)
+
+	return &responses.ImposeNUp{
+		Document:  newDocumentHandle.nativeRef,
+		PageCount: int(C.FPDF_GetPageCount(doc)),
+	}, nil
+}
+
+// placeImposeCell places a page centered in a cell, scaled to fit the cell.
+func (p *PdfiumImplementation) placeImposeCell(source *imposeSource, sheet C.FPDF_PAGE, index int, cellLeft, cellBottom, cellWidth, cellHeight float64, cropMarks bool, cropMarkLength float64) error {
+	sourcePage, err := source.getPage(index)
+	if err != nil {
+		return err
+	}
+
+	width, height := sourcePage.displaySize()
+	scale := getImposeFitScale(width, height, cellWidth, cellHeight)
+	left := cellLeft + (cellWidth-width*scale)/2
+	bottom := cellBottom + (cellHeight-height*scale)/2
+
+	err = source.placePage(sheet, index, left, bottom, scale)
+	if err != nil {
+		return err
+	}
+
+	if cropMarks {
+		addImposeCropMarks(sheet, left, bottom, left+width*scale, bottom+height*scale, cropMarkLength)
+	}
+
+	return nil
+}
+
+// ImposeBooklet creates a new document with the pages of the source document imposed for
+// saddle-stitch booklet printing.
+// Experimental API.
+func (p *PdfiumImplementation) ImposeBooklet(request *requests.ImposeBooklet) (*responses.ImposeBooklet, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.SheetWidth <= 0 || request.SheetHeight <= 0 {
+		return nil, errors.New("sheet width and height should be larger than 0")
+	}
+
+	sheetWidth := float64(request.SheetWidth)
+	sheetHeight := float64(request.SheetHeight)
+	margin := float64(request.Margin)
+	gutter := float64(request.Gutter)
+	cellWidth := (sheetWidth - 2*margin - gutter) / 2
+	cellHeight := sheetHeight - 2*margin
+	if cellWidth <= 0 || cellHeight <= 0 {
+		return nil, errors.New("margin and gutter leave no space for the pages")
+	}
+
+	pageCount := int(C.FPDF_GetPageCount(documentHandle.handle))
+	if pageCount == 0 {
+		return nil, errors.New("document has no pages")
+	}
+
+	// Every sheet contains 4 pages, pad with blank pages.
+	bookletPageCount := (pageCount + 3) / 4 * 4
+
+	doc := C.FPDF_CreateNewDocument()
+	if doc == nil {
+		return nil, errors.New("could not create new document")
+	}
+
+	source := newImposeSource(documentHandle.handle, doc)
+	defer source.close()
+
+	for sheetIndex := 0; sheetIndex < bookletPageCount/4; sheetIndex++ {
+		sides := [][2]int{
+			{bookletPageCount - 1 - 2*sheetIndex, 2 * sheetIndex},   // Front.
+			{2*sheetIndex + 1, bookletPageCount - 2 - 2*sheetIndex}, // Back.
+		}
+
+		for _, side := range sides {
+			sheet, err := newImposeSheet(doc, sheetWidth, sheetHeight)
+			if err != nil {
+				C.FPDF_CloseDocument(doc)
+				return nil, err
+			}
+
+			for position, index := range side {
+				// Blank page.
+				if index >= pageCount {
+					continue
+				}
+
+				cellLeft := margin + float64(position)*(cellWidth+gutter)
+				err = p.placeImposeCell(source, sheet, index, cellLeft, margin, cellWidth, cellHeight, request.CropMarks, float64(request.CropMarkLength))
+				if err != nil {
+					C.FPDF_ClosePage(sheet)
+					C.FPDF_CloseDocument(doc)
+					return nil, err
+				}
+			}
+
+			if err := finishImposeSheet(sheet); err != nil {
+				C.FPDF_CloseDocument(doc)
+				return nil, err
+			}
+		}
+	}
+
+	newDocumentHandle := p.registerDocument(doc)
+
+	return &responses.ImposeBooklet{
+		Document:  newDocumentHandle.nativeRef,
+		PageCount: int(C.FPDF_GetPageCount(doc)),
+	}, nil
+}
+
+// ImposeTiles creates a new document with the pages of the source document tiled across
+// multiple sheets.
+// Experimental API.
+func (p *PdfiumImplementation) ImposeTiles(request *requests.ImposeTiles) (*responses.ImposeTiles, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.SheetWidth <= 0 || request.SheetHeight <= 0 {
+		return nil, errors.New("sheet width and height should be larger than 0")
+	}
+
+	sheetWidth := float64(request.SheetWidth)
+	sheetHeight := float64(request.SheetHeight)
+	margin := float64(request.Margin)
+	overlap := float64(request.Overlap)
+	areaWidth := sheetWidth - 2*margin
+	areaHeight := sheetHeight - 2*margin
+	if areaWidth <= 0 || areaHeight <= 0 {
+		return nil, errors.New("margin leaves no space for the tiles")
+	}
+
+	if overlap < 0 || overlap >= areaWidth || overlap >= areaHeight {
+		return nil, errors.New("overlap should be smaller than the space for the tiles")
+	}
+
+	scale := float64(request.Scale)
+	if scale == 0 {
+		scale = 1
+	} else if scale < 0 {
+		return nil, errors.New("scale should be larger than 0")
+	}
+
+	pageCount := int(C.FPDF_GetPageCount(documentHandle.handle))
+	pages := request.Pages
+	if pages == nil {
+		pages = make([]int, pageCount)
+		for i := range pages {
+			pages[i] = i
+		}
+	}
+
+	for _, page := range pages {
+		if page < 0 || page >= pageCount {
+			return nil, fmt.Errorf("page %d does not exist", page)
+		}
+	}
+
+	doc := C.FPDF_CreateNewDocument()
+	if doc == nil {
+		return nil, errors.New("could not create new document")
+	}
+
+	source := newImposeSource(documentHandle.handle, doc)
+	defer source.close()
+
+	resp := &responses.ImposeTiles{
+		Tiles: []responses.ImposeTilesTile{},
+	}
+
+	for _, index := range pages {
+		sourcePage, err := source.getPage(index)
+		if err != nil {
+			C.FPDF_CloseDocument(doc)
+			return nil, err
+		}
+
+		width, height := sourcePage.displaySize()
+		width *= scale
+		height *= scale
+
+		columns := getImposeTileCount(width, areaWidth, overlap)
+		rows := getImposeTileCount(height, areaHeight, overlap)
+		for row := 0; row < rows; row++ {
+			for column := 0; column < columns; column++ {
+				sheet, err := newImposeSheet(doc, sheetWidth, sheetHeight)
+				if err != nil {
+					C.FPDF_CloseDocument(doc)
+					return nil, err
+				}
+
+				// Align the part of the page of this tile with the top left
+				// corner of the area within the margins.
+				left := margin - float64(column)*(areaWidth-overlap)
+				bottom := sheetHeight - margin - (height - float64(row)*(areaHeight-overlap))
+
+				err = source.placePage(sheet, index, left, bottom, scale)
+				if err != nil {
+					C.FPDF_ClosePage(sheet)
+					C.FPDF_CloseDocument(doc)
+					return nil, err
+				}
+
+				if margin > 0 {
+					addImposeMask(sheet, 0, 0, sheetWidth, margin)
+					addImposeMask(sheet, 0, sheetHeight-margin, sheetWidth, margin)
+					addImposeMask(sheet, 0, margin, margin, areaHeight)
+					addImposeMask(sheet, sheetWidth-margin, margin, margin, areaHeight)
+				}
+
+				if request.CropMarks {
+					addImposeCropMarks(sheet, margin, margin, sheetWidth-margin, sheetHeight-margin, float64(request.CropMarkLength))
+				}
+
+				if err := finishImposeSheet(sheet); err != nil {
+					C.FPDF_CloseDocument(doc)
+					return nil, err
+				}
+
+				resp.Tiles = append(resp.Tiles, responses.ImposeTilesTile{
+					Page:   index,
+					Column: column,
+					Row:    row,
+				})
+			}
+		}
+	}
+
+	newDocumentHandle := p.registerDocument(doc)
+	resp.Document = newDocumentHandle.nativeRef
+	resp.PageCount = int(C.FPDF_GetPageCount(doc))
+
+	return resp, nil
+}
+
+// getImposeTileCount returns the amount of tiles that are needed to cover
+// the given size.
+func getImposeTileCount(size, areaSize, overlap float64) int {
+	if size <= areaSize {
+		return 1
+	}
+
+	// Allow a small rounding error to prevent an almost empty tile.
+	return int(math.Ceil((size-overlap)/(areaSize-overlap) - 0.0001))
+}
@@ -0,0 +1,30 @@
+//go:build !pdfium_experimental
+// +build !pdfium_experimental
+
+package implementation
+
+import (
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// ImposeNUp creates a new document with multiple pages of the source document on every sheet.
+// Experimental API.
+func (p *PdfiumImplementation) ImposeNUp(request *requests.ImposeNUp) (*responses.ImposeNUp, error) {
+	return nil, pdfium_errors.ErrExperimentalUnsupported
+}
+
+// ImposeBooklet creates a new document with the pages of the source document imposed for
+// saddle-stitch booklet printing.
+// Experimental API.
+func (p *PdfiumImplementation) ImposeBooklet(request *requests.ImposeBooklet) (*responses.ImposeBooklet, error) {
+	return nil, pdfium_errors.ErrExperimentalUnsupported
+}
+
+// ImposeTiles creates a new document with the pages of the source document tiled across
+// multiple sheets.
+// Experimental API.
+func (p *PdfiumImplementation) ImposeTiles(request *requests.ImposeTiles) (*responses.ImposeTiles, error) {
+	return nil, pdfium_errors.ErrExperimentalUnsupported
+}
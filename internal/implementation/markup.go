@@ -0,0 +1,1018 @@
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdf_doc.h"
+// #include "fpdf_edit.h"
+// #include "fpdf_structtree.h"
+// #include "fpdf_text.h"
+import "C"
+import (
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unsafe"
+
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+type markupBlockKind int
+
+const (
+	markupBlockParagraph markupBlockKind = iota
+	markupBlockHeading
+	markupBlockListItem
+	markupBlockTable
+)
+
+// markupStructInfo is the information we get from the struct tree for a
+// marked content ID.
+type markupStructInfo struct {
+	kind   markupBlockKind
+	level  int
+	table  int
+	row    int
+	cell   int
+	header bool
+}
+
+type markupChar struct {
+	char   rune
+	left   float64
+	top    float64
+	right  float64
+	bottom float64
+	size   float64
+	bold   bool
+	link   string
+}
+
+type markupLine struct {
+	chars      []markupChar
+	text       string
+	left       float64
+	top        float64
+	right      float64
+	bottom     float64
+	size       float64
+	bold       bool
+	structInfo *markupStructInfo
+	heading    int // The heading level from the bookmarks, 0 when not a bookmark.
+}
+
+type markupSpan struct {
+	text string
+	link string
+}
+
+type markupBlock struct {
+	kind    markupBlockKind
+	level   int
+	ordered bool
+	spans   []markupSpan
+	rows    [][][]markupSpan
+	header  bool
+	table   int
+	lastRow int
+	last    *markupLine
+}
+
+type markupPage struct {
+	index int
+	lines []*markupLine
+}
+
+type markupRect struct {
+	left   float64
+	top    float64
+	right  float64
+	bottom float64
+	link   string
+}
+
+var markupUnorderedListRegex = regexp.MustCompile(`^[•◦▪▫■□●○‣⁃∙·\-\*–]\s+`)
+var markupOrderedListRegex = regexp.MustCompile(`^(\d{1,3}|[a-zA-Z]|[ivxlcdmIVXLCDM]{1,6})[\.\)]\s+`)
+
+// GetDocumentMarkup converts the text of a document into Markdown or simple HTML.
+func (p *PdfiumImplementation) GetDocumentMarkup(request *requests.GetDocumentMarkup) (*responses.GetDocumentMarkup, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Format != "" && request.Format != requests.GetDocumentMarkupFormatMarkdown && request.Format != requests.GetDocumentMarkupFormatHTML {
+		return nil, fmt.Errorf("unsupported markup format %s", request.Format)
+	}
+
+	pageCount := int(C.FPDF_GetPageCount(documentHandle.handle))
+	pageIndexes := request.Pages
+	if len(pageIndexes) == 0 {
+		pageIndexes = make([]int, pageCount)
+		for i := range pageIndexes {
+			pageIndexes[i] = i
+		}
+	}
+
+	bookmarkTitles := p.getMarkupBookmarkTitles(documentHandle)
+
+	pages := make([]markupPage, len(pageIndexes))
+	for i, pageIndex := range pageIndexes {
+		if pageIndex < 0 || pageIndex >= pageCount {
+			return nil, fmt.Errorf("page %d does not exist", pageIndex)
+		}
+
+		pageHandle, err := p.loadPage(requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: documentHandle.nativeRef,
+				Index:    pageIndex,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		lines, err := p.getMarkupLines(documentHandle, pageHandle)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, line := range lines {
+			for _, bookmarkTitle := range bookmarkTitles[pageIndex] {
+				if normalizeMarkupText(line.text) == bookmarkTitle.title {
+					line.heading = bookmarkTitle.level
+					break
+				}
+			}
+		}
+
+		pages[i] = markupPage{
+			index: pageIndex,
+			lines: lines,
+		}
+	}
+
+	bodySize, headingSizes := getMarkupFontSizes(pages)
+
+	resp := &responses.GetDocumentMarkup{
+		Pages: make([]responses.GetDocumentMarkupPage, len(pages)),
+	}
+
+	pageContents := make([]string, len(pages))
+	for i := range pages {
+		blocks := buildMarkupBlocks(pages[i].lines, bodySize, headingSizes)
+
+		content := ""
+		if request.Format == requests.GetDocumentMarkupFormatHTML {
+			content = renderMarkupHTML(blocks)
+		} else {
+			content = renderMarkupMarkdown(blocks)
+		}
+
+		pageContents[i] = content
+		resp.Pages[i] = responses.GetDocumentMarkupPage{
+			Page:    pages[i].index,
+			Content: content,
+		}
+	}
+
+	resp.Content = strings.Join(pageContents, "\n\n")
+
+	return resp, nil
+}
+
+type markupBookmarkTitle struct {
+	title string
+	level int
+}
+
+// getMarkupBookmarkTitles returns the bookmark titles per page index, with
+// the heading level that is derived from the depth of the bookmark.
+func (p *PdfiumImplementation) getMarkupBookmarkTitles(documentHandle *DocumentHandle) map[int][]markupBookmarkTitle {
+	titles := map[int][]markupBookmarkTitle{}
+
+	var walk func(bookmark C.FPDF_BOOKMARK, level int)
+	walk = func(bookmark C.FPDF_BOOKMARK, level int) {
+		// Guard against circular bookmarks.
+		visited := 0
+		for bookmark != nil && visited < 10000 {
+			visited++
+
+			titleSize := C.FPDFBookmark_GetTitle(bookmark, nil, 0)
+			if titleSize > 0 {
+				charData := make([]byte, titleSize)
+				C.FPDFBookmark_GetTitle(bookmark, unsafe.Pointer(&charData[0]), C.ulong(len(charData)))
+				title, err := p.transformUTF16LEToUTF8(charData)
+
+				pageIndex := -1
+				dest := C.FPDFBookmark_GetDest(documentHandle.handle, bookmark)
+				if dest == nil {
+					action := C.FPDFBookmark_GetAction(bookmark)
+					if action != nil {
+						dest = C.FPDFAction_GetDest(documentHandle.handle, action)
+					}
+				}
+				if dest != nil {
+					pageIndex = int(C.FPDFDest_GetDestPageIndex(documentHandle.handle, dest))
+				}
+
+				if err == nil && pageIndex >= 0 && strings.TrimSpace(title) != "" {
+					headingLevel := level
+					if headingLevel > 6 {
+						headingLevel = 6
+					}
+
+					titles[pageIndex] = append(titles[pageIndex], markupBookmarkTitle{
+						title: normalizeMarkupText(title),
+						level: headingLevel,
+					})
+				}
+			}
+
+			if level < 16 {
+				child := C.FPDFBookmark_GetFirstChild(documentHandle.handle, bookmark)
+				if child != nil {
+					walk(child, level+1)
+				}
+			}
+
+			bookmark = C.FPDFBookmark_GetNextSibling(documentHandle.handle, bookmark)
+		}
+	}
+
+	walk(C.FPDFBookmark_GetFirstChild(documentHandle.handle, nil), 1)
+
+	return titles
+}
+
+// getMarkupLines collects all the lines of a page with their position, font
+// and link information.
+func (p *PdfiumImplementation) getMarkupLines(documentHandle *DocumentHandle, pageHandle *PageHandle) ([]*markupLine, error) {
+	textPage := C.FPDFText_LoadPage(pageHandle.handle)
+	if textPage == nil {
+		return nil, errors.New("could not load text page")
+	}
+	defer C.FPDFText_ClosePage(textPage)
+
+	linkRects, err := p.getMarkupLinkRects(documentHandle, pageHandle, textPage)
+	if err != nil {
+		return nil, err
+	}
+
+	structInfo := p.getMarkupStructInfo(pageHandle)
+
+	lines := []*markupLine{}
+	currentLine := &markupLine{}
+
+	finishLine := func() {
+		if strings.TrimSpace(string(currentLineRunes(currentLine))) != "" {
+			finalizeMarkupLine(currentLine)
+			lines = append(lines, currentLine)
+		}
+		currentLine = &markupLine{}
+	}
+
+	charsInPage := int(C.FPDFText_CountChars(textPage))
+	for i := 0; i < charsInPage; i++ {
+		char := rune(C.FPDFText_GetUnicode(textPage, C.int(i)))
+		if char == '\r' || char == '\n' {
+			finishLine()
+			continue
+		}
+
+		// Skip soft hyphens and other invisible control characters.
+		if char == 0 || char == 0xAD || (unicode.IsControl(char) && char != '\t') {
+			continue
+		}
+
+		left := C.double(0)
+		right := C.double(0)
+		bottom := C.double(0)
+		top := C.double(0)
+		C.FPDFText_GetCharBox(textPage, C.int(i), &left, &right, &bottom, &top)
+
+		fontInformation := p.getFontInformation(textPage, i)
+
+		markupChar := markupChar{
+			char:   char,
+			left:   float64(left),
+			top:    float64(top),
+			right:  float64(right),
+			bottom: float64(bottom),
+			size:   fontInformation.Size,
+			bold:   fontInformation.Weight >= 600 || strings.Contains(strings.ToLower(fontInformation.Name), "bold"),
+		}
+
+		centerX := (markupChar.left + markupChar.right) / 2
+		centerY := (markupChar.top + markupChar.bottom) / 2
+		for _, linkRect := range linkRects {
+			if centerX >= linkRect.left && centerX <= linkRect.right && centerY >= linkRect.bottom && centerY <= linkRect.top {
+				markupChar.link = linkRect.link
+				break
+			}
+		}
+
+		currentLine.chars = append(currentLine.chars, markupChar)
+	}
+
+	finishLine()
+
+	if len(structInfo) > 0 {
+		lines = splitMarkupLines(lines, structInfo)
+	}
+
+	return lines, nil
+}
+
+// splitMarkupLines assigns the struct info to the characters of the lines and
+// splits the lines where the struct info changes. PDFium puts text on the same
+// baseline on a single line, like the cells of a table row. Whitespace belongs
+// to the struct info of the text before it.
+func splitMarkupLines(lines []*markupLine, structRects []markupStructRect) []*markupLine {
+	splitLines := []*markupLine{}
+	for _, line := range lines {
+		parts := []*markupLine{}
+		var current *markupLine
+		for _, char := range line.chars {
+			var info *markupStructInfo
+			if current != nil && unicode.IsSpace(char.char) {
+				info = current.structInfo
+			} else {
+				centerX := (char.left + char.right) / 2
+				centerY := (char.top + char.bottom) / 2
+				for _, structRect := range structRects {
+					if centerX >= structRect.left-1 && centerX <= structRect.right+1 && centerY >= structRect.bottom-1 && centerY <= structRect.top+1 {
+						info = structRect.structInfo
+						break
+					}
+				}
+			}
+
+			if current == nil || !equalMarkupStructInfo(current.structInfo, info) {
+				current = &markupLine{structInfo: info}
+				parts = append(parts, current)
+			}
+			current.chars = append(current.chars, char)
+		}
+
+		for _, part := range parts {
+			if strings.TrimSpace(string(currentLineRunes(part))) == "" {
+				continue
+			}
+
+			finalizeMarkupLine(part)
+			splitLines = append(splitLines, part)
+		}
+	}
+
+	return splitLines
+}
+
+func equalMarkupStructInfo(a, b *markupStructInfo) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// getMarkupLinkRects returns the rects of all web links and URI link
+// annotations on a page.
+func (p *PdfiumImplementation) getMarkupLinkRects(documentHandle *DocumentHandle, pageHandle *PageHandle, textPage C.FPDF_TEXTPAGE) ([]markupRect, error) {
+	linkRects := []markupRect{}
+
+	// Link annotations take precedence over detected web links.
+	startPos := C.int(0)
+	var link C.FPDF_LINK
+	for int(C.FPDFLink_Enumerate(pageHandle.handle, &startPos, &link)) != 0 {
+		action := C.FPDFLink_GetAction(link)
+		if action == nil || C.FPDFAction_GetType(action) != C.PDFACTION_URI {
+			continue
+		}
+
+		uriPathLength := C.FPDFAction_GetURIPath(documentHandle.handle, action, nil, 0)
+		if uriPathLength == 0 {
+			continue
+		}
+
+		charData := make([]byte, uriPathLength)
+		C.FPDFAction_GetURIPath(documentHandle.handle, action, unsafe.Pointer(&charData[0]), C.ulong(len(charData)))
+
+		rect := C.FS_RECTF{}
+		if int(C.FPDFLink_GetAnnotRect(link, &rect)) == 0 {
+			continue
+		}
+
+		linkRects = append(linkRects, markupRect{
+			left:   math.Min(float64(rect.left), float64(rect.right)),
+			top:    math.Max(float64(rect.top), float64(rect.bottom)),
+			right:  math.Max(float64(rect.left), float64(rect.right)),
+			bottom: math.Min(float64(rect.top), float64(rect.bottom)),
+			link:   string(charData[:uriPathLength-1]), // Take of NULL terminator
+		})
+	}
+
+	pageLink := C.FPDFLink_LoadWebLinks(textPage)
+	if pageLink == nil {
+		return linkRects, nil
+	}
+	defer C.FPDFLink_CloseWebLinks(pageLink)
+
+	webLinkCount := int(C.FPDFLink_CountWebLinks(pageLink))
+	for i := 0; i < webLinkCount; i++ {
+		urlLength := C.FPDFLink_GetURL(pageLink, C.int(i), nil, 0)
+		if urlLength <= 0 {
+			continue
+		}
+
+		charData := make([]byte, urlLength*2) // UTF16-LE 2 bytes per char, the terminator is included in the length.
+		C.FPDFLink_GetURL(pageLink, C.int(i), (*C.ushort)(unsafe.Pointer(&charData[0])), urlLength)
+
+		url, err := p.transformUTF16LEToUTF8(charData)
+		if err != nil {
+			return nil, err
+		}
+
+		rectCount := int(C.FPDFLink_CountRects(pageLink, C.int(i)))
+		for rectIndex := 0; rectIndex < rectCount; rectIndex++ {
+			left := C.double(0)
+			top := C.double(0)
+			right := C.double(0)
+			bottom := C.double(0)
+			if int(C.FPDFLink_GetRect(pageLink, C.int(i), C.int(rectIndex), &left, &top, &right, &bottom)) == 0 {
+				continue
+			}
+
+			linkRects = append(linkRects, markupRect{
+				left:   float64(left),
+				top:    float64(top),
+				right:  float64(right),
+				bottom: float64(bottom),
+				link:   url,
+			})
+		}
+	}
+
+	return linkRects, nil
+}
+
+type markupStructRect struct {
+	left       float64
+	top        float64
+	right      float64
+	bottom     float64
+	structInfo *markupStructInfo
+}
+
+// getMarkupStructInfo maps the struct tree of a page on the text objects of
+// the page through their marked content IDs. Only works when compiled with
+// experimental support, because we need the content marks of the objects.
+func (p *PdfiumImplementation) getMarkupStructInfo(pageHandle *PageHandle) []markupStructRect {
+	structTree := C.FPDF_StructTree_GetForPage(pageHandle.handle)
+	if structTree == nil {
+		return nil
+	}
+	defer C.FPDF_StructTree_Close(structTree)
+
+	markedContentInfo := map[int]*markupStructInfo{}
+	counter := 0
+
+	var walk func(structElement C.FPDF_STRUCTELEMENT, parent markupStructInfo, inStruct bool, depth int)
+	walk = func(structElement C.FPDF_STRUCTELEMENT, parent markupStructInfo, inStruct bool, depth int) {
+		if structElement == nil || depth > 64 {
+			return
+		}
+
+		info := parent
+		elementType := ""
+		typeLength := C.FPDF_StructElement_GetType(structElement, nil, 0)
+		if typeLength > 0 {
+			charData := make([]byte, typeLength)
+			C.FPDF_StructElement_GetType(structElement, unsafe.Pointer(&charData[0]), C.ulong(len(charData)))
+			elementType, _ = p.transformUTF16LEToUTF8(charData)
+		}
+
+		counter++
+		switch elementType {
+		case "H", "H1", "H2", "H3", "H4", "H5", "H6":
+			info.kind = markupBlockHeading
+			info.level = 1
+			if len(elementType) == 2 {
+				info.level = int(elementType[1] - '0')
+			}
+			inStruct = true
+		case "P", "Caption", "BlockQuote", "Note":
+			if info.kind != markupBlockListItem && info.kind != markupBlockTable {
+				info.kind = markupBlockParagraph
+			}
+			inStruct = true
+		case "LI":
+			info.kind = markupBlockListItem
+			inStruct = true
+		case "Table":
+			info.kind = markupBlockTable
+			info.table = counter
+			inStruct = true
+		case "TR":
+			info.kind = markupBlockTable
+			info.row = counter
+		case "TH", "TD":
+			info.kind = markupBlockTable
+			info.cell = counter
+			info.header = elementType == "TH"
+		}
+
+		if inStruct {
+			for _, markedContentID := range p.getMarkupStructElementMarkedContentIDs(structElement) {
+				newInfo := info
+				markedContentInfo[markedContentID] = &newInfo
+			}
+		}
+
+		childCount := int(C.FPDF_StructElement_CountChildren(structElement))
+		for i := 0; i < childCount; i++ {
+			walk(C.FPDF_StructElement_GetChildAtIndex(structElement, C.int(i)), info, inStruct, depth+1)
+		}
+	}
+
+	childCount := int(C.FPDF_StructTree_CountChildren(structTree))
+	for i := 0; i < childCount; i++ {
+		walk(C.FPDF_StructTree_GetChildAtIndex(structTree, C.int(i)), markupStructInfo{}, false, 0)
+	}
+
+	if len(markedContentInfo) == 0 {
+		return nil
+	}
+
+	structRects := []markupStructRect{}
+	objectCount := int(C.FPDFPage_CountObjects(pageHandle.handle))
+	for i := 0; i < objectCount; i++ {
+		pageObject := C.FPDFPage_GetObject(pageHandle.handle, C.int(i))
+		if pageObject == nil || C.FPDFPageObj_GetType(pageObject) != C.FPDF_PAGEOBJ_TEXT {
+			continue
+		}
+
+		markedContentID := p.getMarkupPageObjectMarkedContentID(pageObject)
+		info, ok := markedContentInfo[markedContentID]
+		if !ok {
+			continue
+		}
+
+		left := C.float(0)
+		bottom := C.float(0)
+		right := C.float(0)
+		top := C.float(0)
+		if int(C.FPDFPageObj_GetBounds(pageObject, &left, &bottom, &right, &top)) == 0 {
+			continue
+		}
+
+		structRects = append(structRects, markupStructRect{
+			left:       float64(left),
+			top:        float64(top),
+			right:      float64(right),
+			bottom:     float64(bottom),
+			structInfo: info,
+		})
+	}
+
+	return structRects
+}
+
+func currentLineRunes(line *markupLine) []rune {
+	runes := make([]rune, len(line.chars))
+	for i := range line.chars {
+		runes[i] = line.chars[i].char
+	}
+	return runes
+}
+
+// finalizeMarkupLine calculates the position, dominant font size and the
+// text of a line.
+func finalizeMarkupLine(line *markupLine) {
+	line.text = strings.TrimSpace(string(currentLineRunes(line)))
+
+	sizeCount := map[float64]int{}
+	boldCount := 0
+	textCount := 0
+	first := true
+	for _, char := range line.chars {
+		if unicode.IsSpace(char.char) {
+			continue
+		}
+
+		if first {
+			line.left, line.top, line.right, line.bottom = char.left, char.top, char.right, char.bottom
+			first = false
+		} else {
+			line.left = math.Min(line.left, char.left)
+			line.top = math.Max(line.top, char.top)
+			line.right = math.Max(line.right, char.right)
+			line.bottom = math.Min(line.bottom, char.bottom)
+		}
+
+		sizeCount[roundMarkupFontSize(char.size)]++
+		textCount++
+		if char.bold {
+			boldCount++
+		}
+	}
+
+	bestCount := 0
+	for size, count := range sizeCount {
+		if count > bestCount || (count == bestCount && size > line.size) {
+			line.size = size
+			bestCount = count
+		}
+	}
+
+	line.bold = textCount > 0 && boldCount == textCount
+}
+
+func roundMarkupFontSize(size float64) float64 {
+	return math.Round(size*2) / 2
+}
+
+// getMarkupFontSizes returns the most used font size in the given pages and
+// the font sizes that are big enough to be seen as a heading, biggest first.
+func getMarkupFontSizes(pages []markupPage) (float64, []float64) {
+	sizeCount := map[float64]int{}
+	for _, page := range pages {
+		for _, line := range page.lines {
+			sizeCount[line.size] += len([]rune(line.text))
+		}
+	}
+
+	bodySize := float64(0)
+	bestCount := 0
+	for size, count := range sizeCount {
+		if count > bestCount || (count == bestCount && size < bodySize) {
+			bodySize = size
+			bestCount = count
+		}
+	}
+
+	headingSizes := []float64{}
+	for size := range sizeCount {
+		if bodySize > 0 && size >= bodySize*1.15 {
+			headingSizes = append(headingSizes, size)
+		}
+	}
+
+	sort.Sort(sort.Reverse(sort.Float64Slice(headingSizes)))
+
+	return bodySize, headingSizes
+}
+
+func lastMarkupRune(text string) rune {
+	runes := []rune(text)
+	if len(runes) == 0 {
+		return 0
+	}
+	return runes[len(runes)-1]
+}
+
+func normalizeMarkupText(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// getMarkupLineSpans splits the text of a line into spans with the same link.
+func getMarkupLineSpans(line *markupLine, skip int) []markupSpan {
+	spans := []markupSpan{}
+	for i, char := range line.chars {
+		if i < skip {
+			continue
+		}
+		if len(spans) > 0 && spans[len(spans)-1].link == char.link {
+			spans[len(spans)-1].text += string(char.char)
+			continue
+		}
+		spans = append(spans, markupSpan{text: string(char.char), link: char.link})
+	}
+
+	// Trim the outer whitespace of the line.
+	for len(spans) > 0 {
+		spans[0].text = strings.TrimLeftFunc(spans[0].text, unicode.IsSpace)
+		if spans[0].text != "" {
+			break
+		}
+		spans = spans[1:]
+	}
+	for len(spans) > 0 {
+		last := len(spans) - 1
+		spans[last].text = strings.TrimRightFunc(spans[last].text, unicode.IsSpace)
+		if spans[last].text != "" {
+			break
+		}
+		spans = spans[:last]
+	}
+
+	return spans
+}
+
+func appendMarkupSpans(spans []markupSpan, newSpans []markupSpan) []markupSpan {
+	if len(newSpans) == 0 {
+		return spans
+	}
+	if len(spans) > 0 {
+		spans = append(spans, markupSpan{text: " "})
+	}
+	for _, span := range newSpans {
+		if len(spans) > 0 && spans[len(spans)-1].link == span.link {
+			spans[len(spans)-1].text += span.text
+			continue
+		}
+		spans = append(spans, span)
+	}
+	return spans
+}
+
+// buildMarkupBlocks classifies the lines of a page and groups them into
+// blocks like headings, paragraphs, list items and tables.
+func buildMarkupBlocks(lines []*markupLine, bodySize float64, headingSizes []float64) []*markupBlock {
+	blocks := []*markupBlock{}
+	var current *markupBlock
+
+	for _, line := range lines {
+		kind := markupBlockParagraph
+		level := 0
+		ordered := false
+		skip := 0
+
+		if line.structInfo != nil {
+			kind = line.structInfo.kind
+			level = line.structInfo.level
+		}
+
+		if line.heading > 0 {
+			kind = markupBlockHeading
+			level = line.heading
+		} else if line.structInfo == nil || kind == markupBlockParagraph || kind == markupBlockListItem {
+			if match := markupUnorderedListRegex.FindStringIndex(line.text); match != nil {
+				kind = markupBlockListItem
+				skip = len([]rune(line.text[:match[1]]))
+			} else if match := markupOrderedListRegex.FindStringIndex(line.text); match != nil && (kind == markupBlockListItem || line.structInfo == nil) {
+				kind = markupBlockListItem
+				ordered = true
+			} else if line.structInfo == nil {
+				for i, headingSize := range headingSizes {
+					if line.size == headingSize {
+						kind = markupBlockHeading
+						level = i + 1
+						break
+					}
+				}
+
+				if kind == markupBlockParagraph && line.bold && line.size >= bodySize && len([]rune(line.text)) <= 80 && !strings.ContainsRune(".,;:", lastMarkupRune(line.text)) {
+					kind = markupBlockHeading
+					level = len(headingSizes) + 1
+				}
+			}
+		}
+
+		if level > 6 {
+			level = 6
+		}
+
+		// Leading whitespace is trimmed from the line text, account for it
+		// when skipping the list bullet.
+		if skip > 0 {
+			for _, char := range line.chars {
+				if !unicode.IsSpace(char.char) {
+					break
+				}
+				skip++
+			}
+		}
+
+		spans := getMarkupLineSpans(line, skip)
+
+		switch kind {
+		case markupBlockTable:
+			info := line.structInfo
+			if current == nil || current.kind != markupBlockTable || current.table != info.table {
+				current = &markupBlock{kind: markupBlockTable, table: info.table, lastRow: -1}
+				blocks = append(blocks, current)
+			}
+			if current.lastRow != info.row || len(current.rows) == 0 {
+				current.rows = append(current.rows, [][]markupSpan{})
+				current.lastRow = info.row
+				if len(current.rows) == 1 {
+					current.header = info.header
+				}
+			}
+			row := len(current.rows) - 1
+			if current.last != nil && current.last.structInfo != nil && current.last.structInfo.cell == info.cell && current.last.structInfo.row == info.row && len(current.rows[row]) > 0 {
+				cell := len(current.rows[row]) - 1
+				current.rows[row][cell] = appendMarkupSpans(current.rows[row][cell], spans)
+			} else {
+				current.rows[row] = append(current.rows[row], appendMarkupSpans(nil, spans))
+			}
+		case markupBlockParagraph:
+			if current != nil && (current.kind == markupBlockParagraph || current.kind == markupBlockListItem) && continuesMarkupBlock(current, line) {
+				current.spans = appendMarkupSpans(current.spans, spans)
+			} else {
+				current = &markupBlock{kind: markupBlockParagraph, spans: appendMarkupSpans(nil, spans)}
+				blocks = append(blocks, current)
+			}
+		case markupBlockHeading:
+			if current != nil && current.kind == markupBlockHeading && current.level == level && continuesMarkupBlock(current, line) {
+				current.spans = appendMarkupSpans(current.spans, spans)
+			} else {
+				current = &markupBlock{kind: markupBlockHeading, level: level, spans: appendMarkupSpans(nil, spans)}
+				blocks = append(blocks, current)
+			}
+		case markupBlockListItem:
+			current = &markupBlock{kind: markupBlockListItem, ordered: ordered, spans: appendMarkupSpans(nil, spans)}
+			blocks = append(blocks, current)
+		}
+
+		current.last = line
+	}
+
+	return blocks
+}
+
+// continuesMarkupBlock returns whether the line is a continuation of the
+// previous line in the block, based on the font size and line spacing.
+func continuesMarkupBlock(block *markupBlock, line *markupLine) bool {
+	previous := block.last
+	if previous == nil || !equalMarkupStructInfo(previous.structInfo, line.structInfo) {
+		return false
+	}
+
+	if math.Abs(previous.size-line.size) > 1 {
+		return false
+	}
+
+	// The line should be below the previous line.
+	if line.top >= previous.top {
+		return false
+	}
+
+	lineHeight := math.Max(previous.top-previous.bottom, line.top-line.bottom)
+	gap := previous.bottom - line.top
+	if gap > lineHeight*0.8 {
+		return false
+	}
+
+	// List item continuation lines are indented.
+	if block.kind == markupBlockListItem && line.left < previous.left-1 {
+		return false
+	}
+
+	return true
+}
+
+var markupMarkdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+)
+
+var markupMarkdownURLEscaper = strings.NewReplacer(
+	" ", "%20",
+	"(", "%28",
+	")", "%29",
+)
+
+func renderMarkupMarkdownSpans(spans []markupSpan) string {
+	output := strings.Builder{}
+	for _, span := range spans {
+		text := markupMarkdownEscaper.Replace(span.text)
+		if span.link != "" {
+			output.WriteString("[" + strings.TrimSpace(text) + "](" + markupMarkdownURLEscaper.Replace(span.link) + ")")
+			if strings.HasSuffix(span.text, " ") {
+				output.WriteString(" ")
+			}
+		} else {
+			output.WriteString(text)
+		}
+	}
+	return output.String()
+}
+
+func renderMarkupMarkdown(blocks []*markupBlock) string {
+	output := strings.Builder{}
+	for i, block := range blocks {
+		if i > 0 {
+			if block.kind == markupBlockListItem && blocks[i-1].kind == markupBlockListItem {
+				output.WriteString("\n")
+			} else {
+				output.WriteString("\n\n")
+			}
+		}
+
+		switch block.kind {
+		case markupBlockHeading:
+			output.WriteString(strings.Repeat("#", block.level) + " " + renderMarkupMarkdownSpans(block.spans))
+		case markupBlockParagraph:
+			output.WriteString(renderMarkupMarkdownSpans(block.spans))
+		case markupBlockListItem:
+			if block.ordered {
+				// Keep the original label of ordered items, it's part of the text.
+				output.WriteString(renderMarkupMarkdownSpans(block.spans))
+			} else {
+				output.WriteString("- " + renderMarkupMarkdownSpans(block.spans))
+			}
+		case markupBlockTable:
+			columns := 0
+			for _, row := range block.rows {
+				if len(row) > columns {
+					columns = len(row)
+				}
+			}
+			for rowIndex, row := range block.rows {
+				if rowIndex > 0 {
+					output.WriteString("\n")
+				}
+				output.WriteString("|")
+				for column := 0; column < columns; column++ {
+					cell := ""
+					if column < len(row) {
+						cell = strings.ReplaceAll(renderMarkupMarkdownSpans(row[column]), "|", `\|`)
+					}
+					output.WriteString(" " + cell + " |")
+				}
+				if rowIndex == 0 {
+					output.WriteString("\n|" + strings.Repeat(" --- |", columns))
+				}
+			}
+		}
+	}
+
+	return output.String()
+}
+
+func renderMarkupHTMLSpans(spans []markupSpan) string {
+	output := strings.Builder{}
+	for _, span := range spans {
+		if span.link != "" {
+			output.WriteString(`<a href="` + html.EscapeString(span.link) + `">` + html.EscapeString(span.text) + `</a>`)
+		} else {
+			output.WriteString(html.EscapeString(span.text))
+		}
+	}
+	return output.String()
+}
+
+func renderMarkupHTML(blocks []*markupBlock) string {
+	output := strings.Builder{}
+	openList := ""
+	for _, block := range blocks {
+		listType := ""
+		if block.kind == markupBlockListItem {
+			listType = "ul"
+			if block.ordered {
+				listType = "ol"
+			}
+		}
+
+		if openList != "" && openList != listType {
+			output.WriteString("</" + openList + ">\n")
+			openList = ""
+		}
+
+		if listType != "" && openList == "" {
+			output.WriteString("<" + listType + ">\n")
+			openList = listType
+		}
+
+		switch block.kind {
+		case markupBlockHeading:
+			output.WriteString(fmt.Sprintf("<h%d>%s</h%d>\n", block.level, renderMarkupHTMLSpans(block.spans), block.level))
+		case markupBlockParagraph:
+			output.WriteString("<p>" + renderMarkupHTMLSpans(block.spans) + "</p>\n")
+		case markupBlockListItem:
+			output.WriteString("<li>" + renderMarkupHTMLSpans(block.spans) + "</li>\n")
+		case markupBlockTable:
+			output.WriteString("<table>\n")
+			for rowIndex, row := range block.rows {
+				cellTag := "td"
+				if rowIndex == 0 && block.header {
+					cellTag = "th"
+				}
+				output.WriteString("<tr>")
+				for _, cell := range row {
+					output.WriteString("<" + cellTag + ">" + renderMarkupHTMLSpans(cell) + "</" + cellTag + ">")
+				}
+				output.WriteString("</tr>\n")
+			}
+			output.WriteString("</table>\n")
+		}
+	}
+
+	if openList != "" {
+		output.WriteString("</" + openList + ">\n")
+	}
+
+	return output.String()
+}
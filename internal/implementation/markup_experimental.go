@@ -0,0 +1,47 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdf_edit.h"
+// #include "fpdf_structtree.h"
+// #include <stdlib.h>
+import "C"
+import "unsafe"
+
+// getMarkupStructElementMarkedContentIDs returns all the marked content IDs
+// of a struct element.
+func (p *PdfiumImplementation) getMarkupStructElementMarkedContentIDs(structElement C.FPDF_STRUCTELEMENT) []int {
+	markedContentIDs := []int{}
+	markedContentIDCount := int(C.FPDF_StructElement_GetMarkedContentIdCount(structElement))
+	for i := 0; i < markedContentIDCount; i++ {
+		markedContentID := int(C.FPDF_StructElement_GetMarkedContentIdAtIndex(structElement, C.int(i)))
+		if markedContentID >= 0 {
+			markedContentIDs = append(markedContentIDs, markedContentID)
+		}
+	}
+	return markedContentIDs
+}
+
+// getMarkupPageObjectMarkedContentID returns the marked content ID of a page
+// object, or -1 when the object has none.
+func (p *PdfiumImplementation) getMarkupPageObjectMarkedContentID(pageObject C.FPDF_PAGEOBJECT) int {
+	key := C.CString("MCID")
+	defer C.free(unsafe.Pointer(key))
+
+	markCount := int(C.FPDFPageObj_CountMarks(pageObject))
+	for i := 0; i < markCount; i++ {
+		mark := C.FPDFPageObj_GetMark(pageObject, C.ulong(i))
+		if mark == nil {
+			continue
+		}
+
+		markedContentID := C.int(0)
+		if int(C.FPDFPageObjMark_GetParamIntValue(mark, key, &markedContentID)) != 0 {
+			return int(markedContentID)
+		}
+	}
+
+	return -1
+}
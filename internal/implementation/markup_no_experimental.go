@@ -0,0 +1,25 @@
+//go:build !pdfium_experimental
+// +build !pdfium_experimental
+
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdf_edit.h"
+// #include "fpdf_structtree.h"
+import "C"
+
+// getMarkupStructElementMarkedContentIDs returns the marked content ID of a
+// struct element.
+func (p *PdfiumImplementation) getMarkupStructElementMarkedContentIDs(structElement C.FPDF_STRUCTELEMENT) []int {
+	markedContentID := int(C.FPDF_StructElement_GetMarkedContentID(structElement))
+	if markedContentID < 0 {
+		return nil
+	}
+	return []int{markedContentID}
+}
+
+// getMarkupPageObjectMarkedContentID returns -1 because the content marks of
+// page objects are only available with experimental support.
+func (p *PdfiumImplementation) getMarkupPageObjectMarkedContentID(pageObject C.FPDF_PAGEOBJECT) int {
+	return -1
+}
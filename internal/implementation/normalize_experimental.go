@@ -0,0 +1,263 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+// #include "fpdf_edit.h"
+// #include "fpdf_transformpage.h"
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// NormalizePages crops, scales and rotates pages of a document.
+// Experimental API.
+func (p *PdfiumImplementation) NormalizePages(request *requests.NormalizePages) (*responses.NormalizePages, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	switch request.Size {
+	case requests.NormalizePagesSizeKeep, requests.NormalizePagesSizeA4, requests.NormalizePagesSizeLetter:
+	case requests.NormalizePagesSizeCustom:
+		if request.Width <= 0 || request.Height <= 0 {
+			return nil, errors.New("width and height should be given for a custom size")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported page size %s", request.Size)
+	}
+
+	switch request.ScaleMode {
+	case "", requests.NormalizePagesScaleModeFit, requests.NormalizePagesScaleModeFill:
+	default:
+		return nil, fmt.Errorf("unsupported scale mode %s", request.ScaleMode)
+	}
+
+	if request.Margin < 0 {
+		return nil, errors.New("margin can't be negative")
+	}
+
+	pageCount := int(C.FPDF_GetPageCount(documentHandle.handle))
+	pages := request.Pages
+	if pages == nil {
+		pages = make([]int, pageCount)
+		for i := range pages {
+			pages[i] = i
+		}
+	}
+
+	for _, page := range pages {
+		if page < 0 || page >= pageCount {
+			return nil, fmt.Errorf("page %d is out of bounds, document has %d pages", page, pageCount)
+		}
+	}
+
+	resp := &responses.NormalizePages{
+		Pages: make([]responses.NormalizePagesPage, len(pages)),
+	}
+
+	for i, page := range pages {
+		normalizedPage, err := p.normalizePage(documentHandle, page, request)
+		if err != nil {
+			return nil, err
+		}
+		resp.Pages[i] = *normalizedPage
+	}
+
+	return resp, nil
+}
+
+// normalizePage crops, scales and rotates one page.
+func (p *PdfiumImplementation) normalizePage(documentHandle *DocumentHandle, pageIndex int, request *requests.NormalizePages) (*responses.NormalizePagesPage, error) {
+	pageHandle, err := p.loadPage(requests.Page{
+		ByIndex: &requests.PageByIndex{
+			Document: documentHandle.nativeRef,
+			Index:    pageIndex,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := pageHandle.handle
+	source := getPageDisplayBox(page, false)
+	if request.AutoCrop {
+		source = getNormalizeContentBox(page, source)
+	}
+
+	if source.width <= 0 || source.height <= 0 {
+		return nil, fmt.Errorf("page %d has no size", pageIndex)
+	}
+
+	margin := float64(request.Margin)
+
+	// The size of the page as displayed.
+	var width, height float64
+	switch request.Size {
+	case requests.NormalizePagesSizeA4:
+		width, height = 595.28, 841.89
+	case requests.NormalizePagesSizeLetter:
+		width, height = 612, 792
+	case requests.NormalizePagesSizeCustom:
+		width, height = float64(request.Width), float64(request.Height)
+	default:
+		width, height = source.width+2*margin, source.height+2*margin
+	}
+
+	if (request.Size == requests.NormalizePagesSizeA4 || request.Size == requests.NormalizePagesSizeLetter) && source.width > source.height {
+		width, height = height, width
+	}
+
+	availableWidth := width - 2*margin
+	availableHeight := height - 2*margin
+	if availableWidth <= 0 || availableHeight <= 0 {
+		return nil, errors.New("margin is too large for the page size")
+	}
+
+	scale := 1.0
+	if request.Size != requests.NormalizePagesSizeKeep {
+		scaleX := availableWidth / source.width
+		scaleY := availableHeight / source.height
+		if request.ScaleMode == requests.NormalizePagesScaleModeFill {
+			scale = math.Max(scaleX, scaleY)
+		} else {
+			scale = math.Min(scaleX, scaleY)
+		}
+	}
+
+	// Center the content in the space within the margins.
+	place := pageMatrix{
+		a: scale,
+		d: scale,
+		e: margin + (availableWidth-source.width*scale)/2,
+		f: margin + (availableHeight-source.height*scale)/2,
+	}
+
+	rotation := source.rotation
+	if request.NormalizeRotation {
+		rotation = 0
+	}
+
+	// The size of the page in page coordinates.
+	pageWidth, pageHeight := width, height
+	if rotation%2 == 1 {
+		pageWidth, pageHeight = height, width
+	}
+
+	destination := newPageDisplayBox(0, 0, pageWidth, pageHeight, rotation)
+
+	// Move the content from the source box on the page into the displayed
+	// page, and from there into the destination box on the page.
+	matrix := source.fromPageMatrix().then(place).then(destination.toPageMatrix())
+
+	cMatrix := C.FS_MATRIX{
+		a: C.float(matrix.a),
+		b: C.float(matrix.b),
+		c: C.float(matrix.c),
+		d: C.float(matrix.d),
+		e: C.float(matrix.e),
+		f: C.float(matrix.f),
+	}
+
+	clipRect := C.FS_RECTF{
+		left:   C.float(destination.left),
+		top:    C.float(destination.top),
+		right:  C.float(destination.right),
+		bottom: C.float(destination.bottom),
+	}
+
+	if int(C.FPDFPage_TransFormWithClip(page, &cMatrix, &clipRect)) == 0 {
+		return nil, errors.New("could not transform page")
+	}
+
+	left := C.float(destination.left)
+	bottom := C.float(destination.bottom)
+	right := C.float(destination.right)
+	top := C.float(destination.top)
+
+	C.FPDFPage_SetMediaBox(page, left, bottom, right, top)
+	C.FPDFPage_SetCropBox(page, left, bottom, right, top)
+
+	// The other boxes are only set when the page has them, they are in the
+	// same space as the media box.
+	boxLeft := C.float(0)
+	boxBottom := C.float(0)
+	boxRight := C.float(0)
+	boxTop := C.float(0)
+	if int(C.FPDFPage_GetBleedBox(page, &boxLeft, &boxBottom, &boxRight, &boxTop)) == 1 {
+		C.FPDFPage_SetBleedBox(page, left, bottom, right, top)
+	}
+	if int(C.FPDFPage_GetTrimBox(page, &boxLeft, &boxBottom, &boxRight, &boxTop)) == 1 {
+		C.FPDFPage_SetTrimBox(page, left, bottom, right, top)
+	}
+	if int(C.FPDFPage_GetArtBox(page, &boxLeft, &boxBottom, &boxRight, &boxTop)) == 1 {
+		C.FPDFPage_SetArtBox(page, left, bottom, right, top)
+	}
+
+	C.FPDFPage_SetRotation(page, C.int(rotation))
+
+	// The content of the page was changed without the page objects, close
+	// the page so that the objects are loaded again when the page is needed.
+	p.closeCurrentPage(documentHandle)
+
+	return &responses.NormalizePagesPage{
+		Page:   pageIndex,
+		Width:  width,
+		Height: height,
+	}, nil
+}
+
+// getNormalizeContentBox returns the bounds of the content of the page within
+// the bounding box of the page, with the rotation of the box. When the page
+// has no content, the box is returned.
+func getNormalizeContentBox(page C.FPDF_PAGE, box pageDisplayBox) pageDisplayBox {
+	pageBounds := C.FS_RECTF{}
+	if int(C.FPDF_GetPageBoundingBox(page, &pageBounds)) == 0 {
+		return box
+	}
+
+	contentLeft := math.Inf(1)
+	contentBottom := math.Inf(1)
+	contentRight := math.Inf(-1)
+	contentTop := math.Inf(-1)
+
+	objectCount := int(C.FPDFPage_CountObjects(page))
+	for i := 0; i < objectCount; i++ {
+		left := C.float(0)
+		bottom := C.float(0)
+		right := C.float(0)
+		top := C.float(0)
+		if int(C.FPDFPageObj_GetBounds(C.FPDFPage_GetObject(page, C.int(i)), &left, &bottom, &right, &top)) == 0 {
+			continue
+		}
+
+		contentLeft = math.Min(contentLeft, float64(left))
+		contentBottom = math.Min(contentBottom, float64(bottom))
+		contentRight = math.Max(contentRight, float64(right))
+		contentTop = math.Max(contentTop, float64(top))
+	}
+
+	// Only keep the content that's visible on the page.
+	contentLeft = math.Max(contentLeft, math.Min(float64(pageBounds.left), float64(pageBounds.right)))
+	contentBottom = math.Max(contentBottom, math.Min(float64(pageBounds.bottom), float64(pageBounds.top)))
+	contentRight = math.Min(contentRight, math.Max(float64(pageBounds.left), float64(pageBounds.right)))
+	contentTop = math.Min(contentTop, math.Max(float64(pageBounds.bottom), float64(pageBounds.top)))
+
+	if contentRight <= contentLeft || contentTop <= contentBottom {
+		return box
+	}
+
+	return newPageDisplayBox(contentLeft, contentBottom, contentRight, contentTop, box.rotation)
+}
@@ -0,0 +1,375 @@
+package implementation
+
+/*
+#cgo pkg-config: pdfium
+#include "fpdfview.h"
+#include "fpdf_edit.h"
+#include <stdlib.h>
+
+extern int go_read_seeker_cb(void *param, unsigned long position, unsigned char *pBuf, unsigned long size);
+
+static inline void FPDF_FILEACCESS_SET_GET_BLOCK(FPDF_FILEACCESS *fs, char *id) {
+	fs->m_GetBlock = &go_read_seeker_cb;
+	fs->m_Param = id;
+}
+
+*/
+import "C"
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"math"
+	"unsafe"
+
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	"github.com/google/uuid"
+)
+
+const optimizeImagesDefaultQuality = 75
+
+// optimizeImagesGrayTolerance is the maximum difference between the color
+// channels of a pixel for it to be considered gray.
+const optimizeImagesGrayTolerance = 2
+
+// OptimizeImages downsamples and recompresses the images of a document.
+func (p *PdfiumImplementation) OptimizeImages(request *requests.OptimizeImages) (*responses.OptimizeImages, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	quality := request.Quality
+	if quality == 0 {
+		quality = optimizeImagesDefaultQuality
+	}
+
+	if quality < 1 || quality > 100 {
+		return nil, errors.New("quality should be between 1 and 100")
+	}
+
+	if request.TargetDPI < 0 {
+		return nil, errors.New("target DPI can't be negative")
+	}
+
+	pageCount := int(C.FPDF_GetPageCount(documentHandle.handle))
+	pages := request.Pages
+	if pages == nil {
+		pages = make([]int, pageCount)
+		for i := range pages {
+			pages[i] = i
+		}
+	}
+
+	for _, page := range pages {
+		if page < 0 || page >= pageCount {
+			return nil, fmt.Errorf("page %d is out of bounds, document has %d pages", page, pageCount)
+		}
+	}
+
+	resp := &responses.OptimizeImages{
+		Images: []responses.OptimizeImagesImage{},
+	}
+
+	for _, page := range pages {
+		pageHandle, err := p.loadPage(requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: documentHandle.nativeRef,
+				Index:    page,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		firstImage := len(resp.Images)
+		objectCount := int(C.FPDFPage_CountObjects(pageHandle.handle))
+		for i := 0; i < objectCount; i++ {
+			object := C.FPDFPage_GetObject(pageHandle.handle, C.int(i))
+			if object == nil {
+				continue
+			}
+
+			err := p.optimizePageObjectImages(pageHandle, object, []int{i}, quality, request, resp)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		pageChanged := false
+		for _, optimizedImage := range resp.Images[firstImage:] {
+			if optimizedImage.Optimized {
+				pageChanged = true
+			}
+		}
+
+		if pageChanged {
+			if int(C.FPDFPage_GenerateContent(pageHandle.handle)) == 0 {
+				return nil, errors.New("could not generate page content")
+			}
+		}
+	}
+
+	for _, optimizedImage := range resp.Images {
+		resp.OriginalSize += int64(optimizedImage.OriginalSize)
+		resp.OptimizedSize += int64(optimizedImage.Size)
+	}
+	resp.Savings = resp.OriginalSize - resp.OptimizedSize
+
+	return resp, nil
+}
+
+// optimizePageObjectImages optimizes the image of an image object, or the
+// images in a form object.
+func (p *PdfiumImplementation) optimizePageObjectImages(pageHandle *PageHandle, object C.FPDF_PAGEOBJECT, objectPath []int, quality int, request *requests.OptimizeImages, resp *responses.OptimizeImages) error {
+	switch C.FPDFPageObj_GetType(object) {
+	case C.FPDF_PAGEOBJ_FORM:
+		objectCount := int(C.FPDFFormObj_CountObjects(object))
+		for i := 0; i < objectCount; i++ {
+			childObject := C.FPDFFormObj_GetObject(object, C.ulong(i))
+			if childObject == nil {
+				continue
+			}
+
+			childPath := append(append([]int{}, objectPath...), i)
+			if err := p.optimizePageObjectImages(pageHandle, childObject, childPath, quality, request, resp); err != nil {
+				return err
+			}
+		}
+	case C.FPDF_PAGEOBJ_IMAGE:
+		optimizedImage, err := p.optimizeImageObject(pageHandle.handle, object, quality, request)
+		if err != nil {
+			return fmt.Errorf("could not optimize image %v: %w", objectPath, err)
+		}
+
+		optimizedImage.Page = pageHandle.index
+		optimizedImage.ObjectPath = objectPath
+		resp.Images = append(resp.Images, *optimizedImage)
+	}
+
+	return nil
+}
+
+// optimizeImageObject downsamples and recompresses the image of an image
+// object, the image is only replaced when the result is smaller.
+func (p *PdfiumImplementation) optimizeImageObject(page C.FPDF_PAGE, object C.FPDF_PAGEOBJECT, quality int, request *requests.OptimizeImages) (*responses.OptimizeImagesImage, error) {
+	originalSize := len(getImageObjectRawData(object))
+	optimizedImage := &responses.OptimizeImagesImage{
+		OriginalSize: originalSize,
+		Size:         originalSize,
+	}
+
+	metadata := C.FPDF_IMAGEOBJ_METADATA{}
+	if int(C.FPDFImageObj_GetImageMetadata(object, page, &metadata)) == 0 {
+		optimizedImage.SkipReason = responses.OptimizeImagesSkipReasonUnsupported
+		return optimizedImage, nil
+	}
+
+	optimizedImage.OriginalWidth = int(metadata.width)
+	optimizedImage.OriginalHeight = int(metadata.height)
+	optimizedImage.Width = optimizedImage.OriginalWidth
+	optimizedImage.Height = optimizedImage.OriginalHeight
+
+	if int(metadata.bits_per_pixel) <= 1 {
+		optimizedImage.SkipReason = responses.OptimizeImagesSkipReasonBilevel
+		return optimizedImage, nil
+	}
+
+	if int(C.FPDFPageObj_HasTransparency(object)) == 1 {
+		optimizedImage.SkipReason = responses.OptimizeImagesSkipReasonTransparency
+		return optimizedImage, nil
+	}
+
+	sourceImage, err := getImageObjectImage(object)
+	if err != nil {
+		optimizedImage.SkipReason = responses.OptimizeImagesSkipReasonUnsupported
+		return optimizedImage, nil
+	}
+
+	pixels, stride, channels := getOptimizeImagePixels(sourceImage)
+	if channels == 0 {
+		optimizedImage.SkipReason = responses.OptimizeImagesSkipReasonUnsupported
+		return optimizedImage, nil
+	}
+
+	if channels == 4 {
+		for i := 3; i < len(pixels); i += 4 {
+			if pixels[i] != 255 {
+				optimizedImage.SkipReason = responses.OptimizeImagesSkipReasonTransparency
+				return optimizedImage, nil
+			}
+		}
+	}
+
+	bounds := sourceImage.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	newWidth, newHeight := width, height
+
+	// The DPI is 0 when the image is not visible.
+	dpi := math.Max(float64(metadata.horizontal_dpi), float64(metadata.vertical_dpi))
+	if request.TargetDPI > 0 && dpi > float64(request.TargetDPI) {
+		scale := float64(request.TargetDPI) / dpi
+		newWidth = int(math.Max(1, math.Round(float64(width)*scale)))
+		newHeight = int(math.Max(1, math.Round(float64(height)*scale)))
+	}
+
+	if newWidth != width || newHeight != height {
+		pixels = downsampleOptimizeImagePixels(pixels, stride, width, height, channels, newWidth, newHeight)
+		stride = newWidth * channels
+	}
+
+	var resultImage image.Image
+	if channels == 1 {
+		resultImage = &image.Gray{Pix: pixels, Stride: stride, Rect: image.Rect(0, 0, newWidth, newHeight)}
+	} else if request.ConvertToGrayscale && isOptimizeImageGray(pixels) {
+		grayImage := image.NewGray(image.Rect(0, 0, newWidth, newHeight))
+		for i := range grayImage.Pix {
+			grayImage.Pix[i] = pixels[i*4+1]
+		}
+		resultImage = grayImage
+		optimizedImage.Grayscale = true
+	} else {
+		resultImage = &image.NRGBA{Pix: pixels, Stride: stride, Rect: image.Rect(0, 0, newWidth, newHeight)}
+	}
+
+	var imgBuf bytes.Buffer
+	if err := jpeg.Encode(&imgBuf, resultImage, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+
+	if originalSize > 0 && imgBuf.Len() >= originalSize {
+		optimizedImage.Grayscale = false
+		optimizedImage.SkipReason = responses.OptimizeImagesSkipReasonNoSavings
+		return optimizedImage, nil
+	}
+
+	if err := p.loadImageObjectJpegInline(page, object, imgBuf.Bytes()); err != nil {
+		return nil, err
+	}
+
+	optimizedImage.Width = newWidth
+	optimizedImage.Height = newHeight
+	optimizedImage.Size = imgBuf.Len()
+	optimizedImage.Optimized = true
+
+	return optimizedImage, nil
+}
+
+// getOptimizeImagePixels returns the pixels of an image as returned by
+// getImageObjectImage, with the stride and the amount of channels. The amount
+// of channels is 0 when the image type is not supported.
+func getOptimizeImagePixels(img image.Image) ([]byte, int, int) {
+	switch typedImage := img.(type) {
+	case *image.Gray:
+		return typedImage.Pix, typedImage.Stride, 1
+	case *image.NRGBA:
+		return typedImage.Pix, typedImage.Stride, 4
+	default:
+		return nil, 0, 0
+	}
+}
+
+// isOptimizeImageGray returns whether all the pixels of an NRGBA image are gray.
+func isOptimizeImageGray(pixels []byte) bool {
+	diff := func(a, b byte) int {
+		if a > b {
+			return int(a - b)
+		}
+		return int(b - a)
+	}
+
+	for i := 0; i+2 < len(pixels); i += 4 {
+		if diff(pixels[i], pixels[i+1]) > optimizeImagesGrayTolerance || diff(pixels[i+1], pixels[i+2]) > optimizeImagesGrayTolerance {
+			return false
+		}
+	}
+
+	return true
+}
+
+// downsampleOptimizeImagePixels downsamples pixels by averaging the source
+// pixels that fall in every target pixel.
+func downsampleOptimizeImagePixels(pixels []byte, stride, width, height, channels, newWidth, newHeight int) []byte {
+	result := make([]byte, newWidth*newHeight*channels)
+	sums := make([]int, channels)
+
+	for y := 0; y < newHeight; y++ {
+		sourceTop := y * height / newHeight
+		sourceBottom := (y + 1) * height / newHeight
+		if sourceBottom <= sourceTop {
+			sourceBottom = sourceTop + 1
+		}
+
+		for x := 0; x < newWidth; x++ {
+			sourceLeft := x * width / newWidth
+			sourceRight := (x + 1) * width / newWidth
+			if sourceRight <= sourceLeft {
+				sourceRight = sourceLeft + 1
+			}
+
+			for i := range sums {
+				sums[i] = 0
+			}
+
+			for sourceY := sourceTop; sourceY < sourceBottom; sourceY++ {
+				offset := sourceY*stride + sourceLeft*channels
+				for sourceX := sourceLeft; sourceX < sourceRight; sourceX++ {
+					for i := 0; i < channels; i++ {
+						sums[i] += int(pixels[offset+i])
+					}
+					offset += channels
+				}
+			}
+
+			count := (sourceBottom - sourceTop) * (sourceRight - sourceLeft)
+			offset := (y*newWidth + x) * channels
+			for i := 0; i < channels; i++ {
+				result[offset+i] = byte((sums[i] + count/2) / count)
+			}
+		}
+	}
+
+	return result
+}
+
+// loadImageObjectJpegInline replaces the image of an image object by a JPEG
+// image. The image is loaded inline, so the data can be dropped afterwards.
+func (p *PdfiumImplementation) loadImageObjectJpegInline(page C.FPDF_PAGE, object C.FPDF_PAGEOBJECT, jpegData []byte) error {
+	// Create a PDFium file access struct.
+	readerStruct := C.FPDF_FILEACCESS{}
+	readerStruct.m_FileLen = C.ulong(len(jpegData))
+
+	readerRef := uuid.New()
+	readerRefString := readerRef.String()
+	cReaderRef := C.CString(readerRefString)
+
+	// Set the Go callback through cgo.
+	C.FPDF_FILEACCESS_SET_GET_BLOCK(&readerStruct, cReaderRef)
+
+	Pdfium.fileReaders[readerRefString] = &fileReaderRef{
+		stringRef:  unsafe.Pointer(cReaderRef),
+		reader:     bytes.NewReader(jpegData),
+		fileAccess: &readerStruct,
+	}
+
+	// The image is copied into the document, so we can cleanup right away.
+	defer func() {
+		delete(Pdfium.fileReaders, readerRefString)
+		C.free(unsafe.Pointer(cReaderRef))
+	}()
+
+	// Pass the page so that its image cache is cleared.
+	if int(C.FPDFImageObj_LoadJpegFileInline(&page, 1, object, &readerStruct)) == 0 {
+		return errors.New("could not load jpeg image")
+	}
+
+	return nil
+}
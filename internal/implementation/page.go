@@ -35,15 +35,7 @@ func (p *PdfiumImplementation) loadPage(page requests.Page) (*PageHandle, error)
 		return documentHandle.currentPage, nil
 	}
 
-	if documentHandle.currentPage != nil {
-		documentHandle.currentPage.Close()
-
-		// Cleanup refs.
-		delete(documentHandle.pageRefs, documentHandle.currentPage.nativeRef)
-		delete(p.pageRefs, documentHandle.currentPage.nativeRef)
-
-		documentHandle.currentPage = nil
-	}
+	p.closeCurrentPage(documentHandle)
 
 	pageObject := C.FPDF_LoadPage(documentHandle.handle, C.int(page.ByIndex.Index))
 	if pageObject == nil {
@@ -57,6 +49,22 @@ func (p *PdfiumImplementation) loadPage(page requests.Page) (*PageHandle, error)
 	return nativePage, nil
 }
 
+// closeCurrentPage closes the page that's currently open in the document if
+// any is open, so that it will be loaded again when it's needed.
+func (p *PdfiumImplementation) closeCurrentPage(documentHandle *DocumentHandle) {
+	if documentHandle.currentPage == nil {
+		return
+	}
+
+	documentHandle.currentPage.Close()
+
+	// Cleanup refs.
+	delete(documentHandle.pageRefs, documentHandle.currentPage.nativeRef)
+	delete(p.pageRefs, documentHandle.currentPage.nativeRef)
+
+	documentHandle.currentPage = nil
+}
+
 func (p *PdfiumImplementation) registerPage(page C.FPDF_PAGE, index int, documentHandle *DocumentHandle) *PageHandle {
 	pageRef := uuid.New()
 	pageHandle := &PageHandle{
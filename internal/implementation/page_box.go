@@ -0,0 +1,149 @@
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+// #include "fpdf_edit.h"
+// #include "fpdf_transformpage.h"
+import "C"
+
+import (
+	"math"
+)
+
+// pageDisplayBox is a box of a page as it is displayed, so with the rotation
+// of the page applied. Positions within the displayed box have their origin
+// in the bottom left corner of the box as it is displayed.
+type pageDisplayBox struct {
+	left     float64 // The box in page coordinates.
+	bottom   float64
+	right    float64
+	top      float64
+	rotation int     // The rotation of the page in quarter turns clockwise.
+	width    float64 // The displayed width of the box.
+	height   float64 // The displayed height of the box.
+}
+
+// getPageDisplayBox returns the crop box of the page, or the media box when
+// useMediaBox is true. When the page doesn't have the box, the page size is
+// used.
+func getPageDisplayBox(page C.FPDF_PAGE, useMediaBox bool) pageDisplayBox {
+	left := C.float(0)
+	bottom := C.float(0)
+	right := C.float(0)
+	top := C.float(0)
+
+	hasBox := 0
+	if !useMediaBox {
+		hasBox = int(C.FPDFPage_GetCropBox(page, &left, &bottom, &right, &top))
+	}
+	if hasBox == 0 {
+		hasBox = int(C.FPDFPage_GetMediaBox(page, &left, &bottom, &right, &top))
+	}
+	if hasBox == 0 {
+		left = 0
+		bottom = 0
+		right = C.float(C.FPDF_GetPageWidthF(page))
+		top = C.float(C.FPDF_GetPageHeightF(page))
+	}
+
+	return newPageDisplayBox(float64(left), float64(bottom), float64(right), float64(top), int(C.FPDFPage_GetRotation(page)))
+}
+
+// newPageDisplayBox creates a display box from a box in page coordinates and
+// the rotation of the page.
+func newPageDisplayBox(left, bottom, right, top float64, rotation int) pageDisplayBox {
+	box := pageDisplayBox{
+		left:     math.Min(left, right),
+		bottom:   math.Min(bottom, top),
+		right:    math.Max(left, right),
+		top:      math.Max(bottom, top),
+		rotation: rotation,
+	}
+
+	box.width = box.right - box.left
+	box.height = box.top - box.bottom
+	if box.rotation%2 == 1 {
+		box.width, box.height = box.height, box.width
+	}
+
+	return box
+}
+
+// toPage converts a position in the displayed box into a position on the page.
+func (b pageDisplayBox) toPage(x, y float64) (float64, float64) {
+	switch b.rotation {
+	case 1:
+		return b.right - y, b.bottom + x
+	case 2:
+		return b.right - x, b.top - y
+	case 3:
+		return b.left + y, b.top - x
+	default:
+		return b.left + x, b.bottom + y
+	}
+}
+
+// uprightRotation returns the rotation in radians counter-clockwise that
+// content needs to be displayed upright on the page.
+func (b pageDisplayBox) uprightRotation() float64 {
+	return float64(b.rotation) * math.Pi / 2
+}
+
+// getPageObjectBounds returns the bounds of a page object.
+func getPageObjectBounds(object C.FPDF_PAGEOBJECT) (float64, float64, float64, float64, bool) {
+	left := C.float(0)
+	bottom := C.float(0)
+	right := C.float(0)
+	top := C.float(0)
+	if int(C.FPDFPageObj_GetBounds(object, &left, &bottom, &right, &top)) == 0 {
+		return 0, 0, 0, 0, false
+	}
+	return float64(left), float64(bottom), float64(right), float64(top), true
+}
+
+// pageMatrix is a transformation matrix, it transforms x and y into
+// a*x + c*y + e and b*x + d*y + f.
+type pageMatrix struct {
+	a, b, c, d, e, f float64
+}
+
+// then returns the matrix that applies m and then n.
+func (m pageMatrix) then(n pageMatrix) pageMatrix {
+	return pageMatrix{
+		a: m.a*n.a + m.b*n.c,
+		b: m.a*n.b + m.b*n.d,
+		c: m.c*n.a + m.d*n.c,
+		d: m.c*n.b + m.d*n.d,
+		e: m.e*n.a + m.f*n.c + n.e,
+		f: m.e*n.b + m.f*n.d + n.f,
+	}
+}
+
+// toPageMatrix returns the matrix of toPage.
+func (b pageDisplayBox) toPageMatrix() pageMatrix {
+	switch b.rotation {
+	case 1:
+		return pageMatrix{a: 0, b: 1, c: -1, d: 0, e: b.right, f: b.bottom}
+	case 2:
+		return pageMatrix{a: -1, b: 0, c: 0, d: -1, e: b.right, f: b.top}
+	case 3:
+		return pageMatrix{a: 0, b: -1, c: 1, d: 0, e: b.left, f: b.top}
+	default:
+		return pageMatrix{a: 1, b: 0, c: 0, d: 1, e: b.left, f: b.bottom}
+	}
+}
+
+// fromPageMatrix returns the matrix that converts a position on the page into
+// a position in the displayed box, the inverse of toPageMatrix.
+func (b pageDisplayBox) fromPageMatrix() pageMatrix {
+	switch b.rotation {
+	case 1:
+		return pageMatrix{a: 0, b: -1, c: 1, d: 0, e: -b.bottom, f: b.right}
+	case 2:
+		return pageMatrix{a: -1, b: 0, c: 0, d: -1, e: b.right, f: b.top}
+	case 3:
+		return pageMatrix{a: 0, b: 1, c: -1, d: 0, e: b.top, f: -b.left}
+	default:
+		return pageMatrix{a: 1, b: 0, c: 0, d: 1, e: -b.left, f: -b.bottom}
+	}
+}
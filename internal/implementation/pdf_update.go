@@ -0,0 +1,526 @@
+package implementation
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// pdfTextString is a text string that is written as PDF text string, strings
+// that are read from a document are written as they are.
+type pdfTextString string
+
+// pdfStream is a stream object that is added in an update.
+type pdfStream struct {
+	dict map[string]interface{}
+	data []byte
+}
+
+// errPDFUpdateXRefStream is returned for documents with a cross-reference
+// stream, the objects in object streams can't be read.
+var errPDFUpdateXRefStream = errors.New("documents with a cross-reference stream are not supported, save the document without the incremental flag first")
+
+// pdfUpdate reads the objects of a document with a cross-reference table and
+// writes the objects that changed as an incremental update. Objects are read
+// with the FDF parser, references are fdfReference and names are fdfName.
+// Cross-reference streams are not supported, so the document has to be saved
+// by PDFium without the incremental flag first: PDFium then writes all objects,
+// including the ones in object streams, with a cross-reference table.
+type pdfUpdate struct {
+	data        []byte
+	startXRef   int
+	offsets     map[int]int
+	generations map[int]int
+	trailer     map[string]interface{}
+	objects     map[int]interface{}
+	streams     map[int]bool
+	changed     map[int]bool
+	size        int
+}
+
+// newPDFUpdate reads the cross-reference table and trailer of a document.
+func newPDFUpdate(data []byte) (*pdfUpdate, error) {
+	startXRefIndex := bytes.LastIndex(data, []byte("startxref"))
+	if startXRefIndex == -1 {
+		return nil, errors.New("could not find the cross-reference table")
+	}
+
+	parser := &fdfParser{data: data, pos: startXRefIndex + len("startxref")}
+	startXRefKeyword, err := parser.parseObject()
+	if err != nil {
+		return nil, err
+	}
+
+	startXRef, err := strconv.Atoi(fmt.Sprint(startXRefKeyword))
+	if err != nil {
+		return nil, errors.New("could not find the cross-reference table")
+	}
+
+	update := &pdfUpdate{
+		data:        data,
+		startXRef:   startXRef,
+		offsets:     map[int]int{},
+		generations: map[int]int{},
+		objects:     map[int]interface{}{},
+		streams:     map[int]bool{},
+		changed:     map[int]bool{},
+	}
+
+	// Follow the previous tables, the entries of later tables take
+	// precedence.
+	visited := map[int]bool{}
+	for offset := startXRef; offset >= 0 && !visited[offset]; {
+		visited[offset] = true
+
+		trailer, err := update.readXRefTable(offset)
+		if err != nil {
+			return nil, err
+		}
+
+		if update.trailer == nil {
+			update.trailer = trailer
+		}
+
+		offset = -1
+		if previous, ok := trailer["Prev"].(fdfKeyword); ok {
+			if previousOffset, err := strconv.Atoi(string(previous)); err == nil {
+				offset = previousOffset
+			}
+		}
+	}
+
+	if size, ok := update.trailer["Size"].(fdfKeyword); ok {
+		update.size, _ = strconv.Atoi(string(size))
+	}
+
+	for objectNumber := range update.offsets {
+		if objectNumber >= update.size {
+			update.size = objectNumber + 1
+		}
+	}
+
+	return update, nil
+}
+
+// readXRefTable reads a cross-reference table and returns its trailer.
+// Cross-reference streams are not supported.
+func (u *pdfUpdate) readXRefTable(offset int) (map[string]interface{}, error) {
+	if offset < 0 || offset >= len(u.data) {
+		return nil, errors.New("cross-reference table is out of bounds")
+	}
+
+	parser := &fdfParser{data: u.data, pos: offset}
+	keyword, err := parser.parseObject()
+	if err != nil || keyword != fdfKeyword("xref") {
+		if u.isXRefStream(offset) {
+			return nil, errPDFUpdateXRefStream
+		}
+		return nil, errors.New("document has no cross-reference table")
+	}
+
+	readNumber := func() (int, error) {
+		object, err := parser.parseObject()
+		if err != nil {
+			return 0, err
+		}
+
+		keyword, _ := object.(fdfKeyword)
+		return strconv.Atoi(string(keyword))
+	}
+
+	for {
+		start := parser.pos
+		object, err := parser.parseObject()
+		if err != nil {
+			return nil, err
+		}
+
+		if object == fdfKeyword("trailer") {
+			break
+		}
+
+		parser.pos = start
+		firstObject, err := readNumber()
+		if err != nil {
+			return nil, errors.New("invalid cross-reference table")
+		}
+
+		count, err := readNumber()
+		if err != nil {
+			return nil, errors.New("invalid cross-reference table")
+		}
+
+		for i := 0; i < count; i++ {
+			objectOffset, err := readNumber()
+			if err != nil {
+				return nil, errors.New("invalid cross-reference table")
+			}
+
+			generation, err := readNumber()
+			if err != nil {
+				return nil, errors.New("invalid cross-reference table")
+			}
+
+			entryType, err := parser.parseObject()
+			if err != nil {
+				return nil, errors.New("invalid cross-reference table")
+			}
+
+			objectNumber := firstObject + i
+			if _, ok := u.offsets[objectNumber]; ok {
+				continue
+			}
+
+			if entryType == fdfKeyword("n") {
+				u.offsets[objectNumber] = objectOffset
+				u.generations[objectNumber] = generation
+			} else {
+				// Keep the free entry, so that older tables don't override it.
+				u.offsets[objectNumber] = -1
+			}
+		}
+	}
+
+	trailer, err := parser.parseObject()
+	if err != nil {
+		return nil, err
+	}
+
+	trailerDict, ok := trailer.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid trailer")
+	}
+
+	// Hybrid documents have the objects in object streams in a
+	// cross-reference stream.
+	if _, ok := trailerDict["XRefStm"]; ok {
+		return nil, errPDFUpdateXRefStream
+	}
+
+	return trailerDict, nil
+}
+
+// isXRefStream returns whether there is a cross-reference stream at an
+// offset.
+func (u *pdfUpdate) isXRefStream(offset int) bool {
+	parser := &fdfParser{data: u.data, pos: offset}
+	for _, expected := range []string{"", "", "obj"} {
+		keyword, err := parser.parseObject()
+		if err != nil {
+			return false
+		}
+
+		if expected != "" && keyword != fdfKeyword(expected) {
+			return false
+		}
+	}
+
+	object, err := parser.parseValue()
+	if err != nil {
+		return false
+	}
+
+	dict, ok := object.(map[string]interface{})
+	return ok && dict["Type"] == fdfName("XRef")
+}
+
+// object returns an indirect object, nil when it doesn't exist.
+func (u *pdfUpdate) object(objectNumber int) (interface{}, error) {
+	if object, ok := u.objects[objectNumber]; ok {
+		return object, nil
+	}
+
+	offset, ok := u.offsets[objectNumber]
+	if !ok || offset < 0 || offset >= len(u.data) {
+		return nil, nil
+	}
+
+	parser := &fdfParser{data: u.data, pos: offset}
+	for _, expected := range []string{strconv.Itoa(objectNumber), "", "obj"} {
+		keyword, err := parser.parseObject()
+		if err != nil {
+			return nil, err
+		}
+
+		if expected != "" && keyword != fdfKeyword(expected) {
+			return nil, fmt.Errorf("object %d not found at offset %d", objectNumber, offset)
+		}
+	}
+
+	object, err := parser.parseValue()
+	if err != nil {
+		return nil, fmt.Errorf("could not read object %d: %w", objectNumber, err)
+	}
+
+	// Only the dictionary of a stream is read.
+	if keyword, err := parser.parseObject(); err == nil && keyword == fdfKeyword("stream") {
+		u.streams[objectNumber] = true
+	}
+
+	u.objects[objectNumber] = object
+	return object, nil
+}
+
+// resolve returns the object of a reference, other objects are returned as
+// they are.
+func (u *pdfUpdate) resolve(object interface{}) (interface{}, error) {
+	if reference, ok := object.(fdfReference); ok {
+		return u.object(int(reference))
+	}
+	return object, nil
+}
+
+// dict returns the dictionary of a reference or a direct dictionary, nil when
+// the object is not a dictionary.
+func (u *pdfUpdate) dict(object interface{}) (map[string]interface{}, error) {
+	resolved, err := u.resolve(object)
+	if err != nil {
+		return nil, err
+	}
+
+	dict, _ := resolved.(map[string]interface{})
+	return dict, nil
+}
+
+// array returns the array of a reference or a direct array, nil when the
+// object is not an array.
+func (u *pdfUpdate) array(object interface{}) ([]interface{}, error) {
+	resolved, err := u.resolve(object)
+	if err != nil {
+		return nil, err
+	}
+
+	array, _ := resolved.([]interface{})
+	return array, nil
+}
+
+// catalog returns the reference and the dictionary of the document catalog.
+func (u *pdfUpdate) catalog() (fdfReference, map[string]interface{}, error) {
+	catalogReference, ok := u.trailer["Root"].(fdfReference)
+	if !ok {
+		return 0, nil, errors.New("could not find the document catalog")
+	}
+
+	catalog, err := u.dict(catalogReference)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if catalog == nil {
+		return 0, nil, errors.New("could not find the document catalog")
+	}
+
+	return catalogReference, catalog, nil
+}
+
+// pages returns the references of the pages, in the order of the page tree.
+func (u *pdfUpdate) pages() ([]fdfReference, error) {
+	_, catalog, err := u.catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	pages := []fdfReference{}
+	visited := map[fdfReference]bool{}
+
+	var loadPages func(node interface{}) error
+	loadPages = func(node interface{}) error {
+		reference, ok := node.(fdfReference)
+		if !ok || visited[reference] {
+			return nil
+		}
+		visited[reference] = true
+
+		dict, err := u.dict(reference)
+		if err != nil {
+			return err
+		}
+
+		if dict == nil {
+			return nil
+		}
+
+		if dict["Type"] == fdfName("Page") {
+			pages = append(pages, reference)
+			return nil
+		}
+
+		kids, err := u.array(dict["Kids"])
+		if err != nil {
+			return err
+		}
+
+		for _, kid := range kids {
+			if err := loadPages(kid); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := loadPages(catalog["Pages"]); err != nil {
+		return nil, err
+	}
+
+	return pages, nil
+}
+
+// add adds a new indirect object and returns its reference.
+func (u *pdfUpdate) add(object interface{}) fdfReference {
+	objectNumber := u.size
+	u.size++
+	u.objects[objectNumber] = object
+	u.changed[objectNumber] = true
+	return fdfReference(objectNumber)
+}
+
+// set replaces an indirect object, it is written in the update.
+func (u *pdfUpdate) set(objectNumber int, object interface{}) {
+	u.objects[objectNumber] = object
+	u.changed[objectNumber] = true
+}
+
+// touch marks an object that was changed in place, so that it is written in
+// the update.
+func (u *pdfUpdate) touch(reference fdfReference) {
+	u.changed[int(reference)] = true
+}
+
+// write returns the document with the changed objects appended as an
+// incremental update.
+func (u *pdfUpdate) write() ([]byte, error) {
+	objectNumbers := make([]int, 0, len(u.changed))
+	for objectNumber := range u.changed {
+		objectNumbers = append(objectNumbers, objectNumber)
+	}
+	sort.Ints(objectNumbers)
+
+	buf := bytes.NewBuffer(append([]byte{}, u.data...))
+	if !bytes.HasSuffix(u.data, []byte("\n")) {
+		buf.WriteString("\n")
+	}
+
+	offsets := map[int]int{}
+	for _, objectNumber := range objectNumbers {
+		if u.streams[objectNumber] {
+			return nil, fmt.Errorf("object %d is a stream and can't be changed", objectNumber)
+		}
+
+		offsets[objectNumber] = buf.Len()
+		fmt.Fprintf(buf, "%d %d obj\n", objectNumber, u.generations[objectNumber])
+		if err := u.writeObject(buf, u.objects[objectNumber]); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\nendobj\n")
+	}
+
+	xRefOffset := buf.Len()
+	buf.WriteString("xref\n")
+	for i := 0; i < len(objectNumbers); {
+		end := i + 1
+		for end < len(objectNumbers) && objectNumbers[end] == objectNumbers[end-1]+1 {
+			end++
+		}
+
+		fmt.Fprintf(buf, "%d %d\n", objectNumbers[i], end-i)
+		for _, objectNumber := range objectNumbers[i:end] {
+			fmt.Fprintf(buf, "%010d %05d n\r\n", offsets[objectNumber], u.generations[objectNumber])
+		}
+		i = end
+	}
+
+	trailer := map[string]interface{}{}
+	for key, value := range u.trailer {
+		if key != "Prev" && key != "XRefStm" {
+			trailer[key] = value
+		}
+	}
+	trailer["Size"] = fdfKeyword(strconv.Itoa(u.size))
+	trailer["Prev"] = fdfKeyword(strconv.Itoa(u.startXRef))
+
+	buf.WriteString("trailer\n")
+	if err := u.writeObject(buf, trailer); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(buf, "\nstartxref\n%d\n%%%%EOF\n", xRefOffset)
+
+	return buf.Bytes(), nil
+}
+
+// writeObject writes an object in PDF syntax.
+func (u *pdfUpdate) writeObject(buf *bytes.Buffer, object interface{}) error {
+	switch typedObject := object.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(typedObject))
+	case int:
+		buf.WriteString(strconv.Itoa(typedObject))
+	case float64:
+		buf.WriteString(strconv.FormatFloat(typedObject, 'f', -1, 64))
+	case float32:
+		buf.WriteString(strconv.FormatFloat(float64(typedObject), 'f', -1, 32))
+	case fdfKeyword:
+		buf.WriteString(string(typedObject))
+	case fdfName:
+		writeFDFName(buf, string(typedObject))
+	case fdfReference:
+		fmt.Fprintf(buf, "%d %d R", int(typedObject), u.generations[int(typedObject)])
+	case pdfTextString:
+		writeFDFString(buf, string(typedObject))
+	case string:
+		// Strings of the document can contain any byte.
+		buf.WriteString("<")
+		buf.WriteString(hex.EncodeToString([]byte(typedObject)))
+		buf.WriteString(">")
+	case []interface{}:
+		buf.WriteString("[")
+		for i, item := range typedObject {
+			if i > 0 {
+				buf.WriteString(" ")
+			}
+			if err := u.writeObject(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("]")
+	case map[string]interface{}:
+		keys := make([]string, 0, len(typedObject))
+		for key := range typedObject {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		buf.WriteString("<<")
+		for _, key := range keys {
+			buf.WriteString(" ")
+			writeFDFName(buf, key)
+			buf.WriteString(" ")
+			if err := u.writeObject(buf, typedObject[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteString(" >>")
+	case *pdfStream:
+		dict := map[string]interface{}{}
+		for key, value := range typedObject.dict {
+			dict[key] = value
+		}
+		dict["Length"] = len(typedObject.data)
+
+		if err := u.writeObject(buf, dict); err != nil {
+			return err
+		}
+		buf.WriteString("\nstream\n")
+		buf.Write(typedObject.data)
+		buf.WriteString("\nendstream")
+	default:
+		return fmt.Errorf("unsupported object type %T", object)
+	}
+
+	return nil
+}
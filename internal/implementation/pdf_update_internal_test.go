@@ -0,0 +1,89 @@
+package implementation
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pdf update", func() {
+	var xrefTable []byte
+
+	BeforeEach(func() {
+		var err error
+		xrefTable, err = ioutil.ReadFile("../../shared_tests/testdata/form_actions.pdf")
+		Expect(err).To(BeNil())
+	})
+
+	Context("newPDFUpdate", func() {
+		It("reads a cross-reference table", func() {
+			update, err := newPDFUpdate(xrefTable)
+			Expect(err).To(BeNil())
+			Expect(update.size).To(Equal(9))
+		})
+
+		It("returns an error for a cross-reference stream", func() {
+			xrefStream, err := ioutil.ReadFile("../../shared_tests/testdata/form_xref_stream.pdf")
+			Expect(err).To(BeNil())
+
+			update, err := newPDFUpdate(xrefStream)
+			Expect(err).To(MatchError(errPDFUpdateXRefStream))
+			Expect(update).To(BeNil())
+		})
+
+		It("returns an error for a cross-reference table with a cross-reference stream", func() {
+			hybrid := bytes.Replace(xrefTable, []byte("<< /Size 9 /Root 1 0 R >>"), []byte("<< /Size 9 /Root 1 0 R /XRefStm 0 >>"), 1)
+			Expect(hybrid).To(Not(Equal(xrefTable)))
+
+			update, err := newPDFUpdate(hybrid)
+			Expect(err).To(MatchError(errPDFUpdateXRefStream))
+			Expect(update).To(BeNil())
+		})
+
+		DescribeTable("returns an error for an invalid document",
+			func(data string, expectedError string) {
+				update, err := newPDFUpdate([]byte(data))
+				Expect(err).To(MatchError(expectedError))
+				Expect(update).To(BeNil())
+			},
+			Entry("no cross-reference table", "%PDF-1.7\n", "could not find the cross-reference table"),
+			Entry("invalid cross-reference offset", "%PDF-1.7\nstartxref\n3\n%%EOF\n", "document has no cross-reference table"),
+		)
+	})
+
+	Context("write", func() {
+		It("appends the changed and added objects to the document", func() {
+			update, err := newPDFUpdate(xrefTable)
+			Expect(err).To(BeNil())
+
+			field, err := update.dict(fdfReference(7))
+			Expect(err).To(BeNil())
+
+			field["T"] = pdfTextString("sum")
+			update.touch(fdfReference(7))
+			added := update.add(map[string]interface{}{"Type": fdfName("Test")})
+
+			written, err := update.write()
+			Expect(err).To(BeNil())
+			Expect(bytes.HasPrefix(written, xrefTable)).To(BeTrue())
+
+			writtenUpdate, err := newPDFUpdate(written)
+			Expect(err).To(BeNil())
+			Expect(writtenUpdate.trailer["Prev"]).To(Equal(fdfKeyword("1618")))
+
+			price, err := writtenUpdate.dict(fdfReference(5))
+			Expect(err).To(BeNil())
+			Expect(decodePDFTextString(price["T"].(string))).To(Equal("price"))
+
+			sum, err := writtenUpdate.dict(fdfReference(7))
+			Expect(err).To(BeNil())
+			Expect(decodePDFTextString(sum["T"].(string))).To(Equal("sum"))
+
+			addedDict, err := writtenUpdate.dict(added)
+			Expect(err).To(BeNil())
+			Expect(addedDict["Type"]).To(Equal(fdfName("Test")))
+		})
+	})
+})
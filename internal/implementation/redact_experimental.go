@@ -0,0 +1,856 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+// #include "fpdf_annot.h"
+// #include "fpdf_edit.h"
+// #include "fpdf_text.h"
+// #include <stdlib.h>
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	gounicode "unicode"
+	"unsafe"
+
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// redactEpsilon is the minimal overlap in points before something is
+// considered to be in a redaction area, so that content that only touches
+// an area is kept.
+const redactEpsilon = 0.01
+
+// redactArea is an area to redact in page coordinates.
+type redactArea struct {
+	left   float64
+	bottom float64
+	right  float64
+	top    float64
+}
+
+func newRedactArea(left, bottom, right, top float64) redactArea {
+	return redactArea{
+		left:   math.Min(left, right),
+		bottom: math.Min(bottom, top),
+		right:  math.Max(left, right),
+		top:    math.Max(bottom, top),
+	}
+}
+
+type redactAreas []redactArea
+
+// intersects returns whether the given box overlaps any of the areas.
+func (a redactAreas) intersects(left, bottom, right, top float64) bool {
+	for _, area := range a {
+		if math.Min(right, area.right)-math.Max(left, area.left) > redactEpsilon && math.Min(top, area.top)-math.Max(bottom, area.bottom) > redactEpsilon {
+			return true
+		}
+	}
+	return false
+}
+
+// contains returns whether the given point is in any of the areas.
+func (a redactAreas) contains(x, y float64) bool {
+	for _, area := range a {
+		if x >= area.left && x <= area.right && y >= area.bottom && y <= area.top {
+			return true
+		}
+	}
+	return false
+}
+
+// redactTextRun is a run of chars of a text object that is kept.
+type redactTextRun struct {
+	text string
+	x    float64 // The origin of the first char of the run in page coordinates.
+	y    float64
+}
+
+// redactTextObject describes how to redact a text object.
+type redactTextObject struct {
+	runs         []redactTextRun // The runs to keep, when nil the object is removed entirely.
+	removedChars int
+}
+
+// RedactDocument removes the content under areas of pages of a document, the
+// areas can be given directly, be the matches of searches, or the areas of
+// redact annotations.
+// Experimental API.
+func (p *PdfiumImplementation) RedactDocument(request *requests.RedactDocument) (*responses.RedactDocument, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	pageCount := int(C.FPDF_GetPageCount(documentHandle.handle))
+
+	pageAreas := map[int]redactAreas{}
+	for i, area := range request.Areas {
+		if area.Page < 0 || area.Page >= pageCount {
+			return nil, fmt.Errorf("page %d of area %d is out of bounds, document has %d pages", area.Page, i, pageCount)
+		}
+		pageAreas[area.Page] = append(pageAreas[area.Page], newRedactArea(float64(area.Rect.Left), float64(area.Rect.Bottom), float64(area.Rect.Right), float64(area.Rect.Top)))
+	}
+
+	searchPages := map[int]bool{}
+	for _, page := range request.Pages {
+		if page < 0 || page >= pageCount {
+			return nil, fmt.Errorf("page %d is out of bounds, document has %d pages", page, pageCount)
+		}
+		searchPages[page] = true
+	}
+
+	for _, search := range request.Searches {
+		if search.Query == "" {
+			return nil, errors.New("no query given")
+		}
+	}
+
+	boxColor := structs.FPDF_COLOR{}
+	if request.BoxColor != nil {
+		boxColor = *request.BoxColor
+	}
+
+	resp := &responses.RedactDocument{
+		Pages:    []responses.RedactDocumentPage{},
+		Verified: true,
+	}
+
+	for pageIndex := 0; pageIndex < pageCount; pageIndex++ {
+		search := request.Pages == nil || searchPages[pageIndex]
+		if !search && len(pageAreas[pageIndex]) == 0 {
+			continue
+		}
+
+		redactedPage, err := p.redactPage(documentHandle, pageIndex, pageAreas[pageIndex], search, boxColor, request)
+		if err != nil {
+			return nil, err
+		}
+
+		if redactedPage == nil {
+			continue
+		}
+
+		if !redactedPage.Verified {
+			resp.Verified = false
+		}
+
+		resp.Pages = append(resp.Pages, *redactedPage)
+	}
+
+	return resp, nil
+}
+
+// redactPage redacts one page, it returns nil when there was nothing to redact.
+func (p *PdfiumImplementation) redactPage(documentHandle *DocumentHandle, pageIndex int, areas redactAreas, search bool, boxColor structs.FPDF_COLOR, request *requests.RedactDocument) (*responses.RedactDocumentPage, error) {
+	pageHandle, err := p.loadPage(requests.Page{
+		ByIndex: &requests.PageByIndex{
+			Document: documentHandle.nativeRef,
+			Index:    pageIndex,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := pageHandle.handle
+
+	redactAnnotations := []int{}
+	if search {
+		for _, searchRequest := range request.Searches {
+			searchResult, err := p.searchPageText(pageHandle, &requests.SearchPageText{
+				Query:          searchRequest.Query,
+				MatchCase:      searchRequest.MatchCase,
+				MatchWholeWord: searchRequest.MatchWholeWord,
+				TextOptions:    searchRequest.TextOptions,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			for _, match := range searchResult.Matches {
+				for _, rect := range match.Rects {
+					areas = append(areas, newRedactArea(rect.Left, rect.Bottom, rect.Right, rect.Top))
+				}
+			}
+		}
+
+		if request.ApplyRedactAnnotations {
+			var annotationAreas redactAreas
+			annotationAreas, redactAnnotations = getRedactAnnotationAreas(page)
+			areas = append(areas, annotationAreas...)
+		}
+	}
+
+	if len(areas) == 0 {
+		return nil, nil
+	}
+
+	resp := &responses.RedactDocumentPage{
+		Page:          pageIndex,
+		Areas:         make([]structs.FPDF_FS_RECTF, len(areas)),
+		RemainingText: []string{},
+	}
+
+	for i, area := range areas {
+		resp.Areas[i] = structs.FPDF_FS_RECTF{
+			Left:   float32(area.left),
+			Top:    float32(area.top),
+			Right:  float32(area.right),
+			Bottom: float32(area.bottom),
+		}
+	}
+
+	// Collect the objects first, since we are going to remove objects.
+	objects := []C.FPDF_PAGEOBJECT{}
+	textObjects := []C.FPDF_PAGEOBJECT{}
+	objectCount := int(C.FPDFPage_CountObjects(page))
+	for i := 0; i < objectCount; i++ {
+		object := C.FPDFPage_GetObject(page, C.int(i))
+		if object == nil {
+			continue
+		}
+
+		if C.FPDFPageObj_GetType(object) == C.FPDF_PAGEOBJ_TEXT {
+			textObjects = append(textObjects, object)
+		}
+
+		left, bottom, right, top, ok := getPageObjectBounds(object)
+		if ok && areas.intersects(left, bottom, right, top) {
+			objects = append(objects, object)
+		}
+	}
+
+	textRedactions, err := p.getRedactTextObjects(page, textObjects, objects, areas)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, object := range objects {
+		switch C.FPDFPageObj_GetType(object) {
+		case C.FPDF_PAGEOBJ_TEXT:
+			textRedaction, ok := textRedactions[object]
+			if !ok {
+				// None of the chars are in the areas.
+				continue
+			}
+
+			if err := p.redactTextObject(documentHandle.handle, page, object, textRedaction); err != nil {
+				return nil, err
+			}
+
+			resp.RemovedChars += textRedaction.removedChars
+			if textRedaction.runs == nil {
+				resp.RemovedObjects++
+			}
+		case C.FPDF_PAGEOBJ_IMAGE:
+			redacted, err := redactImageObject(object, areas)
+			if err != nil {
+				return nil, err
+			}
+
+			if redacted {
+				resp.RedactedImages++
+				continue
+			}
+
+			// The pixels of the image could not be edited, remove the
+			// image entirely.
+			if err := removeRedactObject(page, object); err != nil {
+				return nil, err
+			}
+			resp.RemovedObjects++
+		case C.FPDF_PAGEOBJ_FORM:
+			// PDFium can't remove the objects in a form object, so the form
+			// object is removed entirely, including the content outside the
+			// areas.
+			if err := removeRedactObject(page, object); err != nil {
+				return nil, err
+			}
+			resp.RemovedFormObjects++
+		default:
+			// Paths and shadings are removed entirely.
+			if err := removeRedactObject(page, object); err != nil {
+				return nil, err
+			}
+			resp.RemovedObjects++
+		}
+	}
+
+	removeAnnotations, remainingWidgets := getRedactAreaAnnotations(page, areas, redactAnnotations)
+	resp.RemainingWidgets = remainingWidgets
+	for _, index := range redactAnnotations {
+		removeAnnotations[index] = true
+	}
+	resp.RemovedAnnotations = len(removeAnnotations) - len(redactAnnotations)
+
+	// Remove the annotations from the back, so that the indexes stay valid.
+	annotationCount := int(C.FPDFPage_GetAnnotCount(page))
+	for i := annotationCount - 1; i >= 0; i-- {
+		if !removeAnnotations[i] {
+			continue
+		}
+
+		if int(C.FPDFPage_RemoveAnnot(page, C.int(i))) == 0 {
+			return nil, errors.New("could not remove annotation")
+		}
+	}
+
+	if request.DrawBoxes {
+		for _, area := range areas {
+			box := C.FPDFPageObj_CreateNewRect(C.float(area.left), C.float(area.bottom), C.float(area.right-area.left), C.float(area.top-area.bottom))
+			if box == nil {
+				return nil, errors.New("could not create box")
+			}
+			C.FPDFPageObj_SetFillColor(box, C.uint(boxColor.R), C.uint(boxColor.G), C.uint(boxColor.B), 255)
+			C.FPDFPath_SetDrawMode(box, C.FPDF_FILLMODE_WINDING, 0)
+			C.FPDFPage_InsertObject(page, box)
+		}
+	}
+
+	if int(C.FPDFPage_GenerateContent(page)) == 0 {
+		return nil, errors.New("could not generate page content")
+	}
+
+	remainingText, err := p.getRedactRemainingText(pageHandle, areas, search, request)
+	if err != nil {
+		return nil, err
+	}
+	resp.RemainingText = remainingText
+	resp.Verified = len(resp.RemainingText) == 0 && resp.RemovedFormObjects == 0 && resp.RemainingWidgets == 0
+
+	return resp, nil
+}
+
+// getRedactAreaAnnotations returns the indexes of the annotations that
+// intersect the areas, they are removed with their popups, and the amount of
+// widgets that intersect the areas. Widgets are not removed, since the value
+// of a form field is stored in the field and not in the widget. The given
+// redact annotations are skipped.
+func getRedactAreaAnnotations(page C.FPDF_PAGE, areas redactAreas, redactAnnotations []int) (map[int]bool, int) {
+	skip := map[int]bool{}
+	for _, index := range redactAnnotations {
+		skip[index] = true
+	}
+
+	removeAnnotations := map[int]bool{}
+	remainingWidgets := 0
+
+	annotationCount := int(C.FPDFPage_GetAnnotCount(page))
+	for i := 0; i < annotationCount; i++ {
+		if skip[i] {
+			continue
+		}
+
+		annotation := C.FPDFPage_GetAnnot(page, C.int(i))
+		if annotation == nil {
+			continue
+		}
+
+		rect := C.FS_RECTF{}
+		area := newRedactArea(0, 0, 0, 0)
+		if int(C.FPDFAnnot_GetRect(annotation, &rect)) == 1 {
+			area = newRedactArea(float64(rect.left), float64(rect.bottom), float64(rect.right), float64(rect.top))
+		}
+
+		if !areas.intersects(area.left, area.bottom, area.right, area.top) {
+			C.FPDFPage_CloseAnnot(annotation)
+			continue
+		}
+
+		subtype := C.FPDFAnnot_GetSubtype(annotation)
+		if subtype == C.FPDF_ANNOT_WIDGET || subtype == C.FPDF_ANNOT_XFAWIDGET {
+			remainingWidgets++
+			C.FPDFPage_CloseAnnot(annotation)
+			continue
+		}
+
+		removeAnnotations[i] = true
+
+		popupKey := C.CString("Popup")
+		popup := C.FPDFAnnot_GetLinkedAnnot(annotation, popupKey)
+		C.free(unsafe.Pointer(popupKey))
+		if popup != nil {
+			if popupIndex := int(C.FPDFPage_GetAnnotIndex(page, popup)); popupIndex >= 0 && !skip[popupIndex] {
+				removeAnnotations[popupIndex] = true
+			}
+			C.FPDFPage_CloseAnnot(popup)
+		}
+
+		C.FPDFPage_CloseAnnot(annotation)
+	}
+
+	return removeAnnotations, remainingWidgets
+}
+
+// getRedactAnnotationAreas returns the areas of the redact annotations of a
+// page and the indexes of the redact annotations.
+func getRedactAnnotationAreas(page C.FPDF_PAGE) (redactAreas, []int) {
+	areas := redactAreas{}
+	indexes := []int{}
+
+	annotationCount := int(C.FPDFPage_GetAnnotCount(page))
+	for i := 0; i < annotationCount; i++ {
+		annotation := C.FPDFPage_GetAnnot(page, C.int(i))
+		if annotation == nil {
+			continue
+		}
+
+		if C.FPDFAnnot_GetSubtype(annotation) != C.FPDF_ANNOT_REDACT {
+			C.FPDFPage_CloseAnnot(annotation)
+			continue
+		}
+
+		indexes = append(indexes, i)
+
+		// The quad points mark the areas to redact, when there are none the
+		// rect of the annotation is used.
+		quadPointsCount := int(C.FPDFAnnot_CountAttachmentPoints(annotation))
+		for quadIndex := 0; quadIndex < quadPointsCount; quadIndex++ {
+			quadPoints := C.FS_QUADPOINTSF{}
+			if int(C.FPDFAnnot_GetAttachmentPoints(annotation, C.size_t(quadIndex), &quadPoints)) == 0 {
+				continue
+			}
+
+			areas = append(areas, newRedactArea(
+				math.Min(math.Min(float64(quadPoints.x1), float64(quadPoints.x2)), math.Min(float64(quadPoints.x3), float64(quadPoints.x4))),
+				math.Min(math.Min(float64(quadPoints.y1), float64(quadPoints.y2)), math.Min(float64(quadPoints.y3), float64(quadPoints.y4))),
+				math.Max(math.Max(float64(quadPoints.x1), float64(quadPoints.x2)), math.Max(float64(quadPoints.x3), float64(quadPoints.x4))),
+				math.Max(math.Max(float64(quadPoints.y1), float64(quadPoints.y2)), math.Max(float64(quadPoints.y3), float64(quadPoints.y4))),
+			))
+		}
+
+		if quadPointsCount == 0 {
+			rect := C.FS_RECTF{}
+			if int(C.FPDFAnnot_GetRect(annotation, &rect)) == 1 {
+				areas = append(areas, newRedactArea(float64(rect.left), float64(rect.bottom), float64(rect.right), float64(rect.top)))
+			}
+		}
+
+		C.FPDFPage_CloseAnnot(annotation)
+	}
+
+	return areas, indexes
+}
+
+// getRedactTextObjects determines which chars of the given text objects are
+// in the areas. PDFium doesn't tell which char belongs to which text object,
+// so the chars are matched to the smallest text object that contains the
+// origin of the char. When the matched chars don't form the text of the
+// object, the object is removed entirely.
+func (p *PdfiumImplementation) getRedactTextObjects(page C.FPDF_PAGE, textObjects []C.FPDF_PAGEOBJECT, objects []C.FPDF_PAGEOBJECT, areas redactAreas) (map[C.FPDF_PAGEOBJECT]*redactTextObject, error) {
+	redactions := map[C.FPDF_PAGEOBJECT]*redactTextObject{}
+
+	redactObjects := map[C.FPDF_PAGEOBJECT]bool{}
+	for _, object := range objects {
+		if C.FPDFPageObj_GetType(object) == C.FPDF_PAGEOBJ_TEXT {
+			redactObjects[object] = true
+		}
+	}
+
+	if len(redactObjects) == 0 {
+		return redactions, nil
+	}
+
+	textPage := C.FPDFText_LoadPage(page)
+	if textPage == nil {
+		return nil, errors.New("could not load text page")
+	}
+	defer C.FPDFText_ClosePage(textPage)
+
+	type textObjectBounds struct {
+		object                   C.FPDF_PAGEOBJECT
+		left, bottom, right, top float64
+	}
+
+	bounds := []textObjectBounds{}
+	for _, object := range textObjects {
+		left, bottom, right, top, ok := getPageObjectBounds(object)
+		if ok {
+			bounds = append(bounds, textObjectBounds{object, left, bottom, right, top})
+		}
+	}
+
+	// The chars of every text object that we redact, in page order.
+	objectChars := map[C.FPDF_PAGEOBJECT][]int{}
+	charCount := int(C.FPDFText_CountChars(textPage))
+	for i := 0; i < charCount; i++ {
+		isGenerated, err := p.getTextCharIsGenerated(textPage, i)
+		if err != nil {
+			return nil, err
+		}
+
+		if isGenerated {
+			continue
+		}
+
+		x := C.double(0)
+		y := C.double(0)
+		if int(C.FPDFText_GetCharOrigin(textPage, C.int(i), &x, &y)) == 0 {
+			continue
+		}
+
+		var charObject C.FPDF_PAGEOBJECT
+		smallestSize := math.Inf(1)
+		for _, objectBounds := range bounds {
+			// Allow a point of tolerance for rounding of the bounds.
+			if float64(x) < objectBounds.left-1 || float64(x) > objectBounds.right+1 || float64(y) < objectBounds.bottom-1 || float64(y) > objectBounds.top+1 {
+				continue
+			}
+
+			size := (objectBounds.right - objectBounds.left) * (objectBounds.top - objectBounds.bottom)
+			if size < smallestSize {
+				smallestSize = size
+				charObject = objectBounds.object
+			}
+		}
+
+		if charObject != nil && redactObjects[charObject] {
+			objectChars[charObject] = append(objectChars[charObject], i)
+		}
+	}
+
+	for object := range redactObjects {
+		text, err := p.getRedactTextObjectText(object, textPage)
+		if err != nil {
+			return nil, err
+		}
+
+		chars := objectChars[object]
+		charRunes := make([]rune, len(chars))
+		for i, char := range chars {
+			charRunes[i] = rune(C.FPDFText_GetUnicode(textPage, C.int(char)))
+		}
+
+		// Whitespace is ignored in the comparison, since PDFium can add and
+		// drop spaces in the text of an object.
+		if len(chars) == 0 || removeRedactWhitespace(string(charRunes)) != removeRedactWhitespace(text) {
+			redactions[object] = &redactTextObject{
+				removedChars: len([]rune(removeRedactWhitespace(text))),
+			}
+			continue
+		}
+
+		redaction := &redactTextObject{
+			runs: []redactTextRun{},
+		}
+
+		redactedChars := 0
+		var currentRun *redactTextRun
+		for i, char := range chars {
+			if p.isRedactChar(textPage, char, areas) {
+				redactedChars++
+				if !gounicode.IsSpace(charRunes[i]) {
+					redaction.removedChars++
+				}
+				currentRun = nil
+				continue
+			}
+
+			if currentRun == nil {
+				x := C.double(0)
+				y := C.double(0)
+				C.FPDFText_GetCharOrigin(textPage, C.int(char), &x, &y)
+				redaction.runs = append(redaction.runs, redactTextRun{
+					x: float64(x),
+					y: float64(y),
+				})
+				currentRun = &redaction.runs[len(redaction.runs)-1]
+			}
+
+			currentRun.text += string(charRunes[i])
+		}
+
+		if redactedChars == 0 {
+			// Nothing of this object is in the areas.
+			continue
+		}
+
+		// Drop runs that only contain whitespace.
+		runs := []redactTextRun{}
+		for _, run := range redaction.runs {
+			if strings.TrimSpace(run.text) != "" {
+				runs = append(runs, run)
+			}
+		}
+
+		redaction.runs = runs
+		if len(redaction.runs) == 0 {
+			redaction.runs = nil
+		}
+
+		redactions[object] = redaction
+	}
+
+	return redactions, nil
+}
+
+// isRedactChar returns whether a char is in the areas.
+func (p *PdfiumImplementation) isRedactChar(textPage C.FPDF_TEXTPAGE, char int, areas redactAreas) bool {
+	left := C.double(0)
+	right := C.double(0)
+	bottom := C.double(0)
+	top := C.double(0)
+	if int(C.FPDFText_GetCharBox(textPage, C.int(char), &left, &right, &bottom, &top)) == 1 && float64(right-left) > redactEpsilon && float64(top-bottom) > redactEpsilon {
+		return areas.intersects(float64(left), float64(bottom), float64(right), float64(top))
+	}
+
+	// Chars without a box, like spaces, are matched on their origin.
+	x := C.double(0)
+	y := C.double(0)
+	if int(C.FPDFText_GetCharOrigin(textPage, C.int(char), &x, &y)) == 0 {
+		return false
+	}
+	return areas.contains(float64(x), float64(y))
+}
+
+// getRedactTextObjectText returns the text of a text object.
+func (p *PdfiumImplementation) getRedactTextObjectText(object C.FPDF_PAGEOBJECT, textPage C.FPDF_TEXTPAGE) (string, error) {
+	textSize := C.FPDFTextObj_GetText(object, textPage, nil, 0)
+	if textSize == 0 {
+		return "", nil
+	}
+
+	charData := make([]byte, textSize)
+	C.FPDFTextObj_GetText(object, textPage, (*C.ushort)(unsafe.Pointer(&charData[0])), C.ulong(len(charData)))
+
+	return p.transformUTF16LEToUTF8(charData)
+}
+
+func removeRedactWhitespace(text string) string {
+	return strings.Map(func(char rune) rune {
+		if gounicode.IsSpace(char) {
+			return -1
+		}
+		return char
+	}, text)
+}
+
+// redactTextObject replaces a text object by new text objects that only
+// contain the runs of chars to keep. The new objects get the font, the font
+// size, the matrix, the colors and the render mode of the original object,
+// other text state like char spacing is not kept.
+func (p *PdfiumImplementation) redactTextObject(document C.FPDF_DOCUMENT, page C.FPDF_PAGE, object C.FPDF_PAGEOBJECT, redaction *redactTextObject) error {
+	if len(redaction.runs) > 0 {
+		font := C.FPDFTextObj_GetFont(object)
+		fontSize := C.float(0)
+		matrix := C.FS_MATRIX{}
+		if font == nil || int(C.FPDFTextObj_GetFontSize(object, &fontSize)) == 0 || int(C.FPDFPageObj_GetMatrix(object, &matrix)) == 0 {
+			// We can't recreate the text, remove it entirely.
+			redaction.runs = nil
+			return removeRedactObject(page, object)
+		}
+
+		renderMode := C.FPDFTextObj_GetTextRenderMode(object)
+
+		fillR, fillG, fillB, fillA := C.uint(0), C.uint(0), C.uint(0), C.uint(0)
+		hasFillColor := int(C.FPDFPageObj_GetFillColor(object, &fillR, &fillG, &fillB, &fillA)) == 1
+
+		strokeR, strokeG, strokeB, strokeA := C.uint(0), C.uint(0), C.uint(0), C.uint(0)
+		hasStrokeColor := int(C.FPDFPageObj_GetStrokeColor(object, &strokeR, &strokeG, &strokeB, &strokeA)) == 1
+
+		for _, run := range redaction.runs {
+			runObject := C.FPDFPageObj_CreateTextObj(document, font, fontSize)
+			if runObject == nil {
+				return errors.New("could not create text object")
+			}
+
+			transformedText, err := p.transformUTF8ToUTF16LE(run.text)
+			if err != nil {
+				C.FPDFPageObj_Destroy(runObject)
+				return err
+			}
+
+			// Add the NULL terminator.
+			transformedText = append(transformedText, 0, 0)
+
+			if int(C.FPDFText_SetText(runObject, (C.FPDF_WIDESTRING)(unsafe.Pointer(&transformedText[0])))) == 0 {
+				C.FPDFPageObj_Destroy(runObject)
+				return errors.New("could not set text")
+			}
+
+			runMatrix := matrix
+			runMatrix.e = C.float(run.x)
+			runMatrix.f = C.float(run.y)
+			C.FPDFPageObj_SetMatrix(runObject, &runMatrix)
+			C.FPDFTextObj_SetTextRenderMode(runObject, renderMode)
+
+			if hasFillColor {
+				C.FPDFPageObj_SetFillColor(runObject, fillR, fillG, fillB, fillA)
+			}
+
+			if hasStrokeColor {
+				C.FPDFPageObj_SetStrokeColor(runObject, strokeR, strokeG, strokeB, strokeA)
+			}
+
+			C.FPDFPage_InsertObject(page, runObject)
+		}
+	}
+
+	return removeRedactObject(page, object)
+}
+
+// redactImageObject blacks out the pixels of an image that are in the areas.
+// It returns false when the pixels of the image could not be edited.
+func redactImageObject(object C.FPDF_PAGEOBJECT, areas redactAreas) (bool, error) {
+	matrix := C.FS_MATRIX{}
+	if int(C.FPDFPageObj_GetMatrix(object, &matrix)) == 0 {
+		return false, nil
+	}
+
+	bitmap := C.FPDFImageObj_GetBitmap(object)
+	if bitmap == nil {
+		return false, nil
+	}
+	defer C.FPDFBitmap_Destroy(bitmap)
+
+	bytesPerPixel := 0
+	hasAlpha := false
+	switch C.FPDFBitmap_GetFormat(bitmap) {
+	case C.FPDFBitmap_Gray:
+		bytesPerPixel = 1
+	case C.FPDFBitmap_BGR:
+		bytesPerPixel = 3
+	case C.FPDFBitmap_BGRx:
+		bytesPerPixel = 4
+	case C.FPDFBitmap_BGRA:
+		bytesPerPixel = 4
+		hasAlpha = true
+	default:
+		return false, nil
+	}
+
+	width := int(C.FPDFBitmap_GetWidth(bitmap))
+	height := int(C.FPDFBitmap_GetHeight(bitmap))
+	stride := int(C.FPDFBitmap_GetStride(bitmap))
+	if width == 0 || height == 0 {
+		return false, nil
+	}
+
+	size := stride * height
+	buffer := C.FPDFBitmap_GetBuffer(bitmap)
+	data := (*[1<<50 - 1]byte)(unsafe.Pointer(buffer))[:size:size]
+
+	// Images are drawn in a 1x1 square that is transformed by the matrix,
+	// the first row of the bitmap is the top of the image.
+	toPage := func(u, v float64) (float64, float64) {
+		return float64(matrix.a)*u + float64(matrix.c)*v + float64(matrix.e), float64(matrix.b)*u + float64(matrix.d)*v + float64(matrix.f)
+	}
+
+	for y := 0; y < height; y++ {
+		v0 := 1 - float64(y+1)/float64(height)
+		v1 := 1 - float64(y)/float64(height)
+		for x := 0; x < width; x++ {
+			u0 := float64(x) / float64(width)
+			u1 := float64(x+1) / float64(width)
+
+			x1, y1 := toPage(u0, v0)
+			x2, y2 := toPage(u1, v0)
+			x3, y3 := toPage(u0, v1)
+			x4, y4 := toPage(u1, v1)
+
+			left := math.Min(math.Min(x1, x2), math.Min(x3, x4))
+			bottom := math.Min(math.Min(y1, y2), math.Min(y3, y4))
+			right := math.Max(math.Max(x1, x2), math.Max(x3, x4))
+			top := math.Max(math.Max(y1, y2), math.Max(y3, y4))
+			if !areas.intersects(left, bottom, right, top) {
+				continue
+			}
+
+			offset := y*stride + x*bytesPerPixel
+			for i := 0; i < bytesPerPixel; i++ {
+				data[offset+i] = 0
+			}
+
+			if hasAlpha {
+				data[offset+3] = 255
+			}
+		}
+	}
+
+	if int(C.FPDFImageObj_SetBitmap(nil, 0, object, bitmap)) == 0 {
+		return false, errors.New("could not set redacted image")
+	}
+
+	return true, nil
+}
+
+// removeRedactObject removes an object from the page and destroys it.
+func removeRedactObject(page C.FPDF_PAGE, object C.FPDF_PAGEOBJECT) error {
+	if int(C.FPDFPage_RemoveObject(page, object)) == 0 {
+		return errors.New("could not remove page object")
+	}
+	C.FPDFPageObj_Destroy(object)
+	return nil
+}
+
+// getRedactRemainingText extracts the text in the areas and searches the page
+// again, to verify that nothing remained.
+func (p *PdfiumImplementation) getRedactRemainingText(pageHandle *PageHandle, areas redactAreas, search bool, request *requests.RedactDocument) ([]string, error) {
+	remainingText := []string{}
+
+	textPage := C.FPDFText_LoadPage(pageHandle.handle)
+	if textPage == nil {
+		return nil, errors.New("could not load text page")
+	}
+
+	for _, area := range areas {
+		charCount := int(C.FPDFText_GetBoundedText(textPage, C.double(area.left), C.double(area.top), C.double(area.right), C.double(area.bottom), nil, C.int(0)))
+		if charCount <= 0 {
+			continue
+		}
+
+		// The buffer length is given in UTF16 chars, add 1 char for the terminator.
+		charData := make([]byte, (charCount+1)*2)
+		charsWritten := C.FPDFText_GetBoundedText(textPage, C.double(area.left), C.double(area.top), C.double(area.right), C.double(area.bottom), (*C.ushort)(unsafe.Pointer(&charData[0])), C.int(charCount+1))
+		if charsWritten <= 0 {
+			continue
+		}
+
+		text, err := p.transformUTF16LEToUTF8(charData[0 : charsWritten*2])
+		if err != nil {
+			C.FPDFText_ClosePage(textPage)
+			return nil, err
+		}
+
+		if strings.TrimSpace(text) != "" {
+			remainingText = append(remainingText, strings.TrimSpace(text))
+		}
+	}
+
+	C.FPDFText_ClosePage(textPage)
+
+	if !search {
+		return remainingText, nil
+	}
+
+	for _, searchRequest := range request.Searches {
+		searchResult, err := p.searchPageText(pageHandle, &requests.SearchPageText{
+			Query:          searchRequest.Query,
+			MatchCase:      searchRequest.MatchCase,
+			MatchWholeWord: searchRequest.MatchWholeWord,
+			TextOptions:    searchRequest.TextOptions,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, match := range searchResult.Matches {
+			remainingText = append(remainingText, match.Text)
+		}
+	}
+
+	return remainingText, nil
+}
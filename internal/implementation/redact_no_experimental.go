@@ -0,0 +1,16 @@
+//go:build !pdfium_experimental
+// +build !pdfium_experimental
+
+package implementation
+
+import (
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// RedactDocument removes the content under areas of pages of a document.
+// Experimental API.
+func (p *PdfiumImplementation) RedactDocument(request *requests.RedactDocument) (*responses.RedactDocument, error) {
+	return nil, pdfium_errors.ErrExperimentalUnsupported
+}
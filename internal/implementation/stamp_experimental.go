@@ -0,0 +1,541 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+// #include "fpdf_annot.h"
+// #include "fpdf_edit.h"
+// #include "fpdf_ppo.h"
+// #include "fpdf_transformpage.h"
+// #include <stdlib.h>
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"unsafe"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+const stampDefaultFontSize = 48
+
+// stampWatermarkName is the name of the temporary stamp annotations that are
+// converted into watermark annotations.
+const stampWatermarkName = "go-pdfium-stamp-watermark"
+
+type stampAnnotation struct {
+	page  int
+	index int
+}
+
+// StampDocument adds a text, an image or a page of another PDF to pages of a document.
+// Experimental API.
+func (p *PdfiumImplementation) StampDocument(request *requests.StampDocument) (*responses.StampDocument, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Opacity != nil && (*request.Opacity < 0 || *request.Opacity > 1) {
+		return nil, errors.New("opacity should be between 0 and 1")
+	}
+
+	switch request.Placement {
+	case "", requests.StampDocumentPlacementContent, requests.StampDocumentPlacementAnnotation:
+	default:
+		return nil, fmt.Errorf("unsupported stamp placement %s", request.Placement)
+	}
+
+	// The stamp annotations are converted into watermark annotations in a
+	// copy of the document, the strings and streams of an encrypted copy
+	// can't be read or written.
+	if request.Placement == requests.StampDocumentPlacementAnnotation && int(C.FPDF_GetSecurityHandlerRevision(documentHandle.handle)) != -1 {
+		return nil, errors.New("watermark annotations are not supported for encrypted documents")
+	}
+
+	pageCount := int(C.FPDF_GetPageCount(documentHandle.handle))
+	pages := request.Pages
+	if pages == nil {
+		pages = make([]int, pageCount)
+		for i := range pages {
+			pages[i] = i
+		}
+	}
+
+	for _, page := range pages {
+		if page < 0 || page >= pageCount {
+			return nil, fmt.Errorf("page %d does not exist", page)
+		}
+	}
+
+	// The stamp is created as a page in a (temporary) source document, so
+	// that it can be added to every page as the same form xobject.
+	var stampDocument C.FPDF_DOCUMENT
+	stampPageIndex := 0
+	scale := 1.0
+	switch request.Type {
+	case requests.StampDocumentTypeText:
+		stampDocument, err = p.createStampTextDocument(request.Text, request.Opacity)
+		if err != nil {
+			return nil, err
+		}
+		defer C.FPDF_CloseDocument(stampDocument)
+	case requests.StampDocumentTypeImage:
+		stampDocument, err = p.createStampImageDocument(request.Image, request.Opacity)
+		if err != nil {
+			return nil, err
+		}
+		defer C.FPDF_CloseDocument(stampDocument)
+	case requests.StampDocumentTypePage:
+		if request.Opacity != nil {
+			return nil, errors.New("opacity is not supported for page stamps")
+		}
+
+		sourceDocument, err := p.openAssembleDocument(request.SourcePage.Document)
+		if err != nil {
+			return nil, err
+		}
+		defer sourceDocument.close()
+
+		if request.SourcePage.Index < 0 || request.SourcePage.Index >= int(C.FPDF_GetPageCount(sourceDocument.handle)) {
+			return nil, fmt.Errorf("source page %d does not exist", request.SourcePage.Index)
+		}
+
+		stampDocument = sourceDocument.handle
+		stampPageIndex = request.SourcePage.Index
+		if request.SourcePage.Scale < 0 {
+			return nil, errors.New("scale should be larger than 0")
+		} else if request.SourcePage.Scale > 0 {
+			scale = float64(request.SourcePage.Scale)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported stamp type %s", request.Type)
+	}
+
+	source := newImposeSource(stampDocument, documentHandle.handle)
+	defer source.close()
+
+	stampPage, err := source.getPage(stampPageIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	stampWidth, stampHeight := stampPage.displaySize()
+	stampWidth *= scale
+	stampHeight *= scale
+
+	resp := &responses.StampDocument{
+		Pages: []int{},
+	}
+
+	// PDFium can't create watermark annotations, so the stamps are added as
+	// stamp annotations that are converted into watermark annotations in a
+	// copy of the document. The loaded document is restored afterwards.
+	stampAnnotations := []stampAnnotation{}
+	defer func() {
+		for i := len(stampAnnotations) - 1; i >= 0; i-- {
+			pageHandle, err := p.loadPage(requests.Page{
+				ByIndex: &requests.PageByIndex{
+					Document: request.Document,
+					Index:    stampAnnotations[i].page,
+				},
+			})
+			if err != nil {
+				continue
+			}
+
+			C.FPDFPage_RemoveAnnot(pageHandle.handle, C.int(stampAnnotations[i].index))
+		}
+	}()
+
+	for _, index := range pages {
+		pageHandle, err := p.loadPage(requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: request.Document,
+				Index:    index,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		centerX, centerY, rotation, err := p.getStampCenter(pageHandle.handle, request, stampWidth, stampHeight)
+		if err != nil {
+			return nil, err
+		}
+
+		// Create the stamp around the origin so that we can rotate it
+		// around its center.
+		formObject, err := source.newFormObject(stampPageIndex, -stampWidth/2, -stampHeight/2, scale)
+		if err != nil {
+			return nil, err
+		}
+
+		cos := math.Cos(rotation)
+		sin := math.Sin(rotation)
+		C.FPDFPageObj_Transform(formObject, C.double(cos), C.double(sin), C.double(-sin), C.double(cos), C.double(centerX), C.double(centerY))
+
+		if request.Placement == requests.StampDocumentPlacementAnnotation {
+			annotationIndex, err := p.addStampAnnotation(pageHandle.handle, formObject)
+			if err != nil {
+				return nil, err
+			}
+
+			stampAnnotations = append(stampAnnotations, stampAnnotation{
+				page:  index,
+				index: annotationIndex,
+			})
+		} else {
+			C.FPDFPage_InsertObject(pageHandle.handle, formObject)
+			if int(C.FPDFPage_GenerateContent(pageHandle.handle)) == 0 {
+				return nil, errors.New("could not generate page content")
+			}
+		}
+
+		resp.Pages = append(resp.Pages, index)
+	}
+
+	if request.Placement == requests.StampDocumentPlacementAnnotation {
+		// Save a copy without incremental updates, so that all objects are in
+		// a cross-reference table.
+		data, err := p.saveDocument(documentHandle.handle, requests.SaveFlagNoIncremental, request.FileVersion, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		fileBytes, err := setStampWatermarkAnnotations(*data, len(stampAnnotations))
+		if err != nil {
+			return nil, err
+		}
+
+		if request.FilePath != nil {
+			if err := ioutil.WriteFile(*request.FilePath, fileBytes, 0666); err != nil {
+				return nil, err
+			}
+
+			resp.FilePath = request.FilePath
+		} else {
+			resp.FileBytes = &fileBytes
+		}
+	}
+
+	return resp, nil
+}
+
+// getStampCenter returns the position of the center of the stamp on the
+// page and the rotation of the stamp in radians. The stamp is positioned in
+// the page box as the page is displayed, so the rotation of the page is taken
+// into account.
+func (p *PdfiumImplementation) getStampCenter(page C.FPDF_PAGE, request *requests.StampDocument, stampWidth, stampHeight float64) (float64, float64, float64, error) {
+	var box pageDisplayBox
+	switch request.Box {
+	case "", requests.StampDocumentPageBoxCrop:
+		box = getPageDisplayBox(page, false)
+	case requests.StampDocumentPageBoxMedia:
+		box = getPageDisplayBox(page, true)
+	default:
+		return 0, 0, 0, fmt.Errorf("unsupported page box %s", request.Box)
+	}
+
+	// The size of the rotated stamp.
+	rotation := float64(request.Rotation) * math.Pi / 180
+	boundsWidth := math.Abs(stampWidth*math.Cos(rotation)) + math.Abs(stampHeight*math.Sin(rotation))
+	boundsHeight := math.Abs(stampWidth*math.Sin(rotation)) + math.Abs(stampHeight*math.Cos(rotation))
+
+	displayX := box.width / 2
+	displayY := box.height / 2
+	switch request.Position {
+	case "", requests.StampDocumentPositionCenter:
+	case requests.StampDocumentPositionTopLeft:
+		displayX, displayY = boundsWidth/2, box.height-boundsHeight/2
+	case requests.StampDocumentPositionTop:
+		displayY = box.height - boundsHeight/2
+	case requests.StampDocumentPositionTopRight:
+		displayX, displayY = box.width-boundsWidth/2, box.height-boundsHeight/2
+	case requests.StampDocumentPositionLeft:
+		displayX = boundsWidth / 2
+	case requests.StampDocumentPositionRight:
+		displayX = box.width - boundsWidth/2
+	case requests.StampDocumentPositionBottomLeft:
+		displayX, displayY = boundsWidth/2, boundsHeight/2
+	case requests.StampDocumentPositionBottom:
+		displayY = boundsHeight / 2
+	case requests.StampDocumentPositionBottomRight:
+		displayX, displayY = box.width-boundsWidth/2, boundsHeight/2
+	default:
+		return 0, 0, 0, fmt.Errorf("unsupported stamp position %s", request.Position)
+	}
+
+	// Convert the displayed position into the position on the page, and
+	// counter the rotation of the page so that the stamp is displayed upright.
+	centerX, centerY := box.toPage(displayX+float64(request.OffsetX), displayY+float64(request.OffsetY))
+	return centerX, centerY, rotation + box.uprightRotation(), nil
+}
+
+// addStampAnnotation adds the stamp as a printable, locked stamp annotation
+// and returns the index of the annotation. PDFium does not support creating
+// watermark annotations, so a stamp annotation with the watermark name is
+// added, see setStampWatermarkAnnotations.
+func (p *PdfiumImplementation) addStampAnnotation(page C.FPDF_PAGE, formObject C.FPDF_PAGEOBJECT) (int, error) {
+	left := C.float(0)
+	bottom := C.float(0)
+	right := C.float(0)
+	top := C.float(0)
+	if int(C.FPDFPageObj_GetBounds(formObject, &left, &bottom, &right, &top)) == 0 {
+		C.FPDFPageObj_Destroy(formObject)
+		return 0, errors.New("could not get bounds of stamp")
+	}
+
+	annotation := C.FPDFPage_CreateAnnot(page, C.FPDF_ANNOT_STAMP)
+	if annotation == nil {
+		C.FPDFPageObj_Destroy(formObject)
+		return 0, errors.New("could not create stamp annotation")
+	}
+	defer C.FPDFPage_CloseAnnot(annotation)
+
+	annotationIndex := int(C.FPDFPage_GetAnnotIndex(page, annotation))
+
+	rect := C.FS_RECTF{
+		left:   left,
+		top:    top,
+		right:  right,
+		bottom: bottom,
+	}
+	if int(C.FPDFAnnot_SetRect(annotation, &rect)) == 0 {
+		C.FPDFPageObj_Destroy(formObject)
+		return annotationIndex, errors.New("could not set rect of stamp annotation")
+	}
+
+	if int(C.FPDFAnnot_SetFlags(annotation, C.int(enums.FPDF_ANNOT_FLAG_PRINT|enums.FPDF_ANNOT_FLAG_LOCKED))) == 0 {
+		C.FPDFPageObj_Destroy(formObject)
+		return annotationIndex, errors.New("could not set flags of stamp annotation")
+	}
+
+	name, err := p.transformUTF8ToUTF16LE(stampWatermarkName)
+	if err != nil {
+		C.FPDFPageObj_Destroy(formObject)
+		return annotationIndex, err
+	}
+
+	// Add the NULL terminator.
+	name = append(name, 0, 0)
+
+	cKey := C.CString("NM")
+	defer C.free(unsafe.Pointer(cKey))
+	if int(C.FPDFAnnot_SetStringValue(annotation, cKey, (C.FPDF_WIDESTRING)(unsafe.Pointer(&name[0])))) == 0 {
+		C.FPDFPageObj_Destroy(formObject)
+		return annotationIndex, errors.New("could not set name of stamp annotation")
+	}
+
+	if int(C.FPDFAnnot_AppendObject(annotation, formObject)) == 0 {
+		C.FPDFPageObj_Destroy(formObject)
+		return annotationIndex, errors.New("could not add stamp to annotation")
+	}
+
+	return annotationIndex, nil
+}
+
+// setStampWatermarkAnnotations converts the stamp annotations that were added
+// by StampDocument into watermark annotations, as an incremental update of
+// the document. It returns an error when not all of the given amount of
+// stamp annotations were found.
+func setStampWatermarkAnnotations(data []byte, count int) ([]byte, error) {
+	update, err := newPDFUpdate(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := update.trailer["Encrypt"]; ok {
+		return nil, errors.New("watermark annotations are not supported for encrypted documents")
+	}
+
+	converted := 0
+
+	pages, err := update.pages()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, page := range pages {
+		pageDict, err := update.dict(page)
+		if err != nil {
+			return nil, err
+		}
+
+		annotations, err := update.array(pageDict["Annots"])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, annotation := range annotations {
+			annotationDict, err := update.dict(annotation)
+			if err != nil {
+				return nil, err
+			}
+
+			if annotationDict == nil || annotationDict["Subtype"] != fdfName("Stamp") {
+				continue
+			}
+
+			if name, ok := annotationDict["NM"].(string); !ok || decodePDFTextString(name) != stampWatermarkName {
+				continue
+			}
+
+			annotationDict["Subtype"] = fdfName("Watermark")
+			delete(annotationDict, "NM")
+			converted++
+
+			// Direct annotations are changed in the array or the page.
+			if reference, ok := annotation.(fdfReference); ok {
+				update.touch(reference)
+			} else if reference, ok := pageDict["Annots"].(fdfReference); ok {
+				update.touch(reference)
+			} else {
+				update.touch(page)
+			}
+		}
+	}
+
+	if converted != count {
+		return nil, fmt.Errorf("could only convert %d of %d stamp annotations into watermark annotations", converted, count)
+	}
+
+	return update.write()
+}
+
+// newStampDocument creates a document with a page that contains the given
+// object, the page is exactly as big as the object.
+func (p *PdfiumImplementation) newStampDocument(createObject func(document C.FPDF_DOCUMENT) (C.FPDF_PAGEOBJECT, error)) (C.FPDF_DOCUMENT, error) {
+	document := C.FPDF_CreateNewDocument()
+	if document == nil {
+		return nil, errors.New("could not create new document")
+	}
+
+	object, err := createObject(document)
+	if err != nil {
+		C.FPDF_CloseDocument(document)
+		return nil, err
+	}
+
+	left := C.float(0)
+	bottom := C.float(0)
+	right := C.float(0)
+	top := C.float(0)
+	if int(C.FPDFPageObj_GetBounds(object, &left, &bottom, &right, &top)) == 0 || right-left <= 0 || top-bottom <= 0 {
+		C.FPDFPageObj_Destroy(object)
+		C.FPDF_CloseDocument(document)
+		return nil, errors.New("stamp has no size")
+	}
+
+	C.FPDFPageObj_Transform(object, 1, 0, 0, 1, C.double(-left), C.double(-bottom))
+
+	page := C.FPDFPage_New(document, 0, C.double(right-left), C.double(top-bottom))
+	if page == nil {
+		C.FPDFPageObj_Destroy(object)
+		C.FPDF_CloseDocument(document)
+		return nil, errors.New("could not create stamp page")
+	}
+
+	// The page has to be closed before the document.
+	C.FPDFPage_InsertObject(page, object)
+	success := int(C.FPDFPage_GenerateContent(page))
+	C.FPDF_ClosePage(page)
+	if success == 0 {
+		C.FPDF_CloseDocument(document)
+		return nil, errors.New("could not generate stamp content")
+	}
+
+	return document, nil
+}
+
+// createStampTextDocument creates the stamp document of a text stamp.
+func (p *PdfiumImplementation) createStampTextDocument(text requests.StampDocumentText, opacity *float32) (C.FPDF_DOCUMENT, error) {
+	if text.Text == "" {
+		return nil, errors.New("no text given")
+	}
+
+	return p.newStampDocument(func(document C.FPDF_DOCUMENT) (C.FPDF_PAGEOBJECT, error) {
+		fontName := text.Font
+		if fontName == "" {
+			fontName = "Helvetica"
+		}
+
+		font := loadFont(document, fontName, text.FontData, text.FontType)
+		if font == nil {
+			return nil, errors.New("could not load font")
+		}
+		defer C.FPDFFont_Close(font)
+
+		fontSize := text.FontSize
+		if fontSize <= 0 {
+			fontSize = stampDefaultFontSize
+		}
+
+		textObject := C.FPDFPageObj_CreateTextObj(document, font, C.float(fontSize))
+		if textObject == nil {
+			return nil, errors.New("could not create text object")
+		}
+
+		transformedText, err := p.transformUTF8ToUTF16LE(text.Text)
+		if err != nil {
+			C.FPDFPageObj_Destroy(textObject)
+			return nil, err
+		}
+
+		// Add the NULL terminator.
+		transformedText = append(transformedText, 0, 0)
+
+		if int(C.FPDFText_SetText(textObject, (C.FPDF_WIDESTRING)(unsafe.Pointer(&transformedText[0])))) == 0 {
+			C.FPDFPageObj_Destroy(textObject)
+			return nil, errors.New("could not set text")
+		}
+
+		alpha := uint(255)
+		if opacity != nil {
+			alpha = uint(math.Round(float64(*opacity) * 255))
+		}
+
+		if int(C.FPDFPageObj_SetFillColor(textObject, C.uint(text.Color.R), C.uint(text.Color.G), C.uint(text.Color.B), C.uint(alpha))) == 0 {
+			C.FPDFPageObj_Destroy(textObject)
+			return nil, errors.New("could not set text color")
+		}
+
+		return textObject, nil
+	})
+}
+
+// createStampImageDocument creates the stamp document of an image stamp.
+func (p *PdfiumImplementation) createStampImageDocument(stampImage requests.StampDocumentImage, opacity *float32) (C.FPDF_DOCUMENT, error) {
+	if len(stampImage.Data) == 0 {
+		return nil, errors.New("no image given")
+	}
+
+	bgraImage, err := decodeBGRAImage(stampImage.Data, opacity)
+	if err != nil {
+		return nil, err
+	}
+
+	width, height := getImageSizeInPoints(bgraImage.Bounds(), stampImage.Width, stampImage.Height)
+
+	return p.newStampDocument(func(document C.FPDF_DOCUMENT) (C.FPDF_PAGEOBJECT, error) {
+		imageObject, err := newBGRAImageObject(document, bgraImage)
+		if err != nil {
+			return nil, err
+		}
+
+		// Images are drawn in a 1x1 square, scale it to the requested size.
+		C.FPDFPageObj_Transform(imageObject, C.double(width), 0, 0, C.double(height), 0, 0)
+
+		return imageObject, nil
+	})
+}
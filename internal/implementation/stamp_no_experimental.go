@@ -0,0 +1,16 @@
+//go:build !pdfium_experimental
+// +build !pdfium_experimental
+
+package implementation
+
+import (
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// StampDocument adds a text, an image or a page of another PDF to pages of a document.
+// Experimental API.
+func (p *PdfiumImplementation) StampDocument(request *requests.StampDocument) (*responses.StampDocument, error) {
+	return nil, pdfium_errors.ErrExperimentalUnsupported
+}
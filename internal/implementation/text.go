@@ -12,6 +12,8 @@ import (
 	"github.com/klippa-app/go-pdfium/references"
 	"io/ioutil"
 	"math"
+	"strings"
+	gounicode "unicode"
 	"unsafe"
 
 	"github.com/klippa-app/go-pdfium/requests"
@@ -60,6 +62,20 @@ func (p *PdfiumImplementation) GetPageText(request *requests.GetPageText) (*resp
 	}
 
 	textPage := C.FPDFText_LoadPage(pageHandle.handle)
+
+	if request.TextOptions != (requests.TextOptions{}) {
+		textParts, err := p.getTextParts(textPage, request.TextOptions)
+		C.FPDFText_ClosePage(textPage)
+		if err != nil {
+			return nil, err
+		}
+
+		return &responses.GetPageText{
+			Page: pageHandle.index,
+			Text: strings.Join(textParts, ""),
+		}, nil
+	}
+
 	charsInPage := int(C.FPDFText_CountChars(textPage))
 	charData := make([]byte, (charsInPage+1)*2) // UTF16-LE max 2 bytes per char, add 1 char for terminator.
 	charsWritten := C.FPDFText_GetText(textPage, C.int(0), C.int(charsInPage), (*C.ushort)(unsafe.Pointer(&charData[0])))
@@ -112,10 +128,42 @@ func (p *PdfiumImplementation) GetPageTextStructured(request *requests.GetPageTe
 	}
 
 	textPage := C.FPDFText_LoadPage(pageHandle.handle)
+	defer C.FPDFText_ClosePage(textPage)
+
 	charsInPage := C.FPDFText_CountChars(textPage)
+	hasTextOptions := request.TextOptions != (requests.TextOptions{})
+	collectChars := request.Mode == "" || request.Mode == requests.GetPageTextStructuredModeChars || request.Mode == requests.GetPageTextStructuredModeBoth
+
+	var textParts []string
+	if hasTextOptions {
+		textParts, err = p.getTextParts(textPage, request.TextOptions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var lines []*textDirectionLine
+	if (collectChars && request.CollectDirectionInformation) || request.Mode == requests.GetPageTextStructuredModeWords || request.Mode == requests.GetPageTextStructuredModeLines {
+		lines = p.getTextDirectionLines(textPage, textParts)
+	}
+
+	if collectChars {
+		charDirections := map[int]responses.TextDirection{}
+		for _, line := range lines {
+			for _, char := range line.chars {
+				if char.index != -1 {
+					charDirections[char.index] = getTextDirectionCharDirection(line, char)
+				}
+			}
+		}
 
-	if request.Mode == "" || request.Mode == requests.GetPageTextStructuredModeChars || request.Mode == requests.GetPageTextStructuredModeBoth {
+		previousDirection := responses.TextDirectionLeftToRight
 		for i := 0; i < int(charsInPage); i++ {
+			// Skip the chars that were removed by the text options.
+			if hasTextOptions && textParts[i] == "" {
+				continue
+			}
+
 			angle := C.FPDFText_GetCharAngle(textPage, C.int(i))
 			left := C.double(0)
 			top := C.double(0)
@@ -130,6 +178,10 @@ func (p *PdfiumImplementation) GetPageTextStructured(request *requests.GetPageTe
 				return nil, err
 			}
 
+			if hasTextOptions {
+				transformedText = textParts[i]
+			}
+
 			char := &responses.GetPageTextStructuredChar{
 				Text:  transformedText,
 				Angle: float64(angle),
@@ -145,6 +197,15 @@ func (p *PdfiumImplementation) GetPageTextStructured(request *requests.GetPageTe
 				char.FontInformation = p.getFontInformation(textPage, i)
 			}
 
+			if request.CollectDirectionInformation {
+				// Chars that are not part of a line, like spaces and line
+				// breaks, get the direction of the char before them.
+				if direction, ok := charDirections[i]; ok {
+					previousDirection = direction
+				}
+				char.Direction = previousDirection
+			}
+
 			if request.PixelPositions.Calculate {
 				char.PixelPosition = convertPointPositions(char.PointPosition, pointToPixelRatio)
 
@@ -179,6 +240,13 @@ func (p *PdfiumImplementation) GetPageTextStructured(request *requests.GetPageTe
 				return nil, err
 			}
 
+			if hasTextOptions {
+				transformedText, err = applyTextOptionsToString(transformedText, request.TextOptions)
+				if err != nil {
+					return nil, err
+				}
+			}
+
 			char := &responses.GetPageTextStructuredRect{
 				Text: transformedText,
 				PointPosition: responses.CharPosition{
@@ -189,6 +257,13 @@ func (p *PdfiumImplementation) GetPageTextStructured(request *requests.GetPageTe
 				},
 			}
 
+			if request.CollectDirectionInformation {
+				char.Direction = getTextStringDirection(transformedText)
+				if char.PointPosition.Top-char.PointPosition.Bottom > (char.PointPosition.Right-char.PointPosition.Left)*2 && isTextDirectionVerticalScript(transformedText) && len([]rune(strings.TrimSpace(transformedText))) > 1 {
+					char.Direction = responses.TextDirectionTopToBottom
+				}
+			}
+
 			if request.CollectFontInformation {
 				// Find index of the first letter of the rect.
 				// @todo: is 5 a "valid" tolerance?
@@ -209,7 +284,162 @@ func (p *PdfiumImplementation) GetPageTextStructured(request *requests.GetPageTe
 		}
 	}
 
-	C.FPDFText_ClosePage(textPage)
+	if request.Mode == requests.GetPageTextStructuredModeWords || request.Mode == requests.GetPageTextStructuredModeLines {
+		if request.Mode == requests.GetPageTextStructuredModeWords {
+			resp.Words = []*responses.GetPageTextStructuredWord{}
+		} else {
+			resp.Lines = []*responses.GetPageTextStructuredLine{}
+		}
+
+		for _, line := range lines {
+			words := getTextDirectionWords(line)
+			if request.PixelPositions.Calculate {
+				for _, word := range words {
+					word.PixelPosition = convertPointPositions(word.PointPosition, pointToPixelRatio)
+				}
+			}
+
+			if request.Mode == requests.GetPageTextStructuredModeWords {
+				resp.Words = append(resp.Words, words...)
+				continue
+			}
+
+			structuredLine := &responses.GetPageTextStructuredLine{
+				Text:          getTextDirectionLineText(line),
+				Direction:     line.direction,
+				Angle:         line.angle,
+				PointPosition: getTextDirectionBounds(line.chars),
+				Words:         words,
+			}
+
+			if request.PixelPositions.Calculate {
+				structuredLine.PixelPosition = convertPointPositions(structuredLine.PointPosition, pointToPixelRatio)
+			}
+
+			resp.Lines = append(resp.Lines, structuredLine)
+		}
+	}
+
+	return resp, nil
+}
+
+// SearchPageText searches the text of a page after applying the text options.
+func (p *PdfiumImplementation) SearchPageText(request *requests.SearchPageText) (*responses.SearchPageText, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	pageHandle, err := p.loadPage(request.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.searchPageText(pageHandle, request)
+}
+
+// searchPageText searches the text of a loaded page, the page in the request
+// is ignored.
+func (p *PdfiumImplementation) searchPageText(pageHandle *PageHandle, request *requests.SearchPageText) (*responses.SearchPageText, error) {
+	query, err := applyTextOptionsToString(request.Query, request.TextOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	if query == "" {
+		return nil, errors.New("no query given")
+	}
+
+	textPage := C.FPDFText_LoadPage(pageHandle.handle)
+	defer C.FPDFText_ClosePage(textPage)
+
+	textParts, err := p.getTextParts(textPage, request.TextOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create one slice of runes of the text, with for every rune the index
+	// of the char in the page it came from.
+	textRunes := []rune{}
+	textIndexes := []int{}
+	for i := range textParts {
+		for _, textRune := range textParts[i] {
+			textRunes = append(textRunes, textRune)
+			textIndexes = append(textIndexes, i)
+		}
+	}
+
+	queryRunes := []rune(query)
+	compareRune := func(a, b rune) bool {
+		if request.MatchCase {
+			return a == b
+		}
+		return gounicode.ToLower(a) == gounicode.ToLower(b)
+	}
+
+	isWordRune := func(char rune) bool {
+		return gounicode.IsLetter(char) || gounicode.IsDigit(char) || gounicode.IsMark(char)
+	}
+
+	resp := &responses.SearchPageText{
+		Page:    pageHandle.index,
+		Matches: []responses.SearchPageTextMatch{},
+	}
+
+	for start := 0; start+len(queryRunes) <= len(textRunes); start++ {
+		matches := true
+		for i := range queryRunes {
+			if !compareRune(textRunes[start+i], queryRunes[i]) {
+				matches = false
+				break
+			}
+		}
+
+		if !matches {
+			continue
+		}
+
+		end := start + len(queryRunes)
+		if request.MatchWholeWord {
+			if start > 0 && isWordRune(textRunes[start-1]) && isWordRune(textRunes[start]) {
+				continue
+			}
+			if end < len(textRunes) && isWordRune(textRunes[end]) && isWordRune(textRunes[end-1]) {
+				continue
+			}
+		}
+
+		startIndex := textIndexes[start]
+		count := textIndexes[end-1] - startIndex + 1
+
+		match := responses.SearchPageTextMatch{
+			Text:       string(textRunes[start:end]),
+			StartIndex: startIndex,
+			Count:      count,
+			Rects:      []responses.CharPosition{},
+		}
+
+		rectsCount := int(C.FPDFText_CountRects(textPage, C.int(startIndex), C.int(count)))
+		for i := 0; i < rectsCount; i++ {
+			left := C.double(0)
+			top := C.double(0)
+			right := C.double(0)
+			bottom := C.double(0)
+			if int(C.FPDFText_GetRect(textPage, C.int(i), &left, &top, &right, &bottom)) == 0 {
+				continue
+			}
+
+			match.Rects = append(match.Rects, responses.CharPosition{
+				Left:   float64(left),
+				Top:    float64(top),
+				Right:  float64(right),
+				Bottom: float64(bottom),
+			})
+		}
+
+		resp.Matches = append(resp.Matches, match)
+
+		// Don't return overlapping matches.
+		start = end - 1
+	}
 
 	return resp, nil
 }
@@ -0,0 +1,577 @@
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+// #include "fpdf_text.h"
+import "C"
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/klippa-app/go-pdfium/responses"
+
+	"golang.org/x/text/unicode/bidi"
+)
+
+var textMirroredBrackets = map[rune]rune{
+	'(': ')',
+	')': '(',
+	'[': ']',
+	']': '[',
+	'{': '}',
+	'}': '{',
+	'<': '>',
+	'>': '<',
+	'«': '»',
+	'»': '«',
+	'‹': '›',
+	'›': '‹',
+}
+
+// textFullWidthForms contains the CJK punctuation and full width forms.
+var textFullWidthForms = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x3000, Hi: 0x303F, Stride: 1},
+		{Lo: 0xFF00, Hi: 0xFFEF, Stride: 1},
+	},
+}
+
+type textDirectionClass int
+
+const (
+	textDirectionClassNeutral textDirectionClass = iota
+	textDirectionClassLeftToRight
+	textDirectionClassRightToLeft
+	textDirectionClassNumber
+)
+
+// textDirectionChar is a char of the text page with the information that is
+// needed to put it in logical order.
+type textDirectionChar struct {
+	index   int
+	text    string
+	left    float64
+	top     float64
+	right   float64
+	bottom  float64
+	angle   float64
+	isSpace bool
+	class   textDirectionClass
+	level   int
+
+	// The position of the char on the baseline (u) and perpendicular to the
+	// baseline (v), in a coordinate system that is rotated by the angle.
+	uMin float64
+	uMax float64
+	vMin float64
+	vMax float64
+}
+
+type textDirectionLine struct {
+	chars     []*textDirectionChar // The chars in logical order, spaces are included.
+	direction responses.TextDirection
+	angle     float64
+}
+
+// getTextDirectionClass returns the bidi class of the text, based on the
+// first char with a direction.
+func getTextDirectionClass(text string) textDirectionClass {
+	for _, char := range text {
+		properties, _ := bidi.LookupRune(char)
+		switch properties.Class() {
+		case bidi.L:
+			return textDirectionClassLeftToRight
+		case bidi.R, bidi.AL:
+			return textDirectionClassRightToLeft
+		case bidi.EN, bidi.AN:
+			return textDirectionClassNumber
+		}
+	}
+	return textDirectionClassNeutral
+}
+
+// getTextStringDirection returns the direction of a piece of text, based on
+// the majority of the chars with a strong direction.
+func getTextStringDirection(text string) responses.TextDirection {
+	leftToRight := 0
+	rightToLeft := 0
+	for _, char := range text {
+		switch getTextDirectionClass(string(char)) {
+		case textDirectionClassLeftToRight:
+			leftToRight++
+		case textDirectionClassRightToLeft:
+			rightToLeft++
+		}
+	}
+
+	if rightToLeft > leftToRight {
+		return responses.TextDirectionRightToLeft
+	}
+
+	return responses.TextDirectionLeftToRight
+}
+
+// getTextDirectionLines collects the lines of a text page in logical order.
+// When textParts is given, the text of every char is taken from textParts
+// and chars with an empty text part are skipped.
+func (p *PdfiumImplementation) getTextDirectionLines(textPage C.FPDF_TEXTPAGE, textParts []string) []*textDirectionLine {
+	charsInPage := int(C.FPDFText_CountChars(textPage))
+
+	segments := [][]*textDirectionChar{}
+	currentSegment := []*textDirectionChar{}
+	segmentAngle := math.NaN()
+
+	finishSegment := func() {
+		if len(currentSegment) > 0 {
+			segments = append(segments, currentSegment)
+		}
+		currentSegment = []*textDirectionChar{}
+		segmentAngle = math.NaN()
+	}
+
+	for i := 0; i < charsInPage; i++ {
+		char := rune(C.FPDFText_GetUnicode(textPage, C.int(i)))
+		if char == '\r' || char == '\n' {
+			finishSegment()
+			continue
+		}
+
+		text := string(char)
+		if textParts != nil {
+			text = textParts[i]
+		}
+
+		if text == "" || char == 0 {
+			continue
+		}
+
+		left := C.double(0)
+		right := C.double(0)
+		bottom := C.double(0)
+		top := C.double(0)
+		C.FPDFText_GetCharBox(textPage, C.int(i), &left, &right, &bottom, &top)
+
+		directionChar := &textDirectionChar{
+			index:   i,
+			text:    text,
+			left:    float64(left),
+			top:     float64(top),
+			right:   float64(right),
+			bottom:  float64(bottom),
+			angle:   p.getCharAngle(textPage, i),
+			isSpace: strings.TrimSpace(text) == "",
+		}
+
+		if !directionChar.isSpace {
+			// Text in another angle is never part of the same line.
+			if !math.IsNaN(segmentAngle) && math.Abs(directionChar.angle-segmentAngle) > 0.1 {
+				finishSegment()
+			}
+			segmentAngle = directionChar.angle
+		}
+
+		currentSegment = append(currentSegment, directionChar)
+	}
+
+	finishSegment()
+
+	// PDFium can split vertical text into a line per char, merge the chars
+	// that are directly below each other.
+	mergedSegments := [][]*textDirectionChar{}
+	for _, segment := range segments {
+		if len(mergedSegments) > 0 {
+			previous := mergedSegments[len(mergedSegments)-1]
+			if isTextDirectionVerticalContinuation(previous, segment) {
+				mergedSegments[len(mergedSegments)-1] = append(previous, segment...)
+				continue
+			}
+		}
+		mergedSegments = append(mergedSegments, segment)
+	}
+
+	lines := []*textDirectionLine{}
+	for _, segment := range mergedSegments {
+		line := buildTextDirectionLine(segment)
+		if line != nil {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+func getTextDirectionVisibleChars(chars []*textDirectionChar) []*textDirectionChar {
+	visibleChars := []*textDirectionChar{}
+	for _, char := range chars {
+		if !char.isSpace {
+			visibleChars = append(visibleChars, char)
+		}
+	}
+	return visibleChars
+}
+
+// isTextDirectionVertical returns whether the chars are stacked on top of
+// each other instead of next to each other.
+func isTextDirectionVertical(chars []*textDirectionChar) bool {
+	if len(chars) < 2 {
+		return false
+	}
+
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	charSize := float64(0)
+	for _, char := range chars {
+		centerX := (char.left + char.right) / 2
+		centerY := (char.top + char.bottom) / 2
+		minX = math.Min(minX, centerX)
+		maxX = math.Max(maxX, centerX)
+		minY = math.Min(minY, centerY)
+		maxY = math.Max(maxY, centerY)
+		charSize += math.Max(char.right-char.left, char.top-char.bottom)
+	}
+	charSize /= float64(len(chars))
+
+	return (maxY-minY) > (maxX-minX)*2 && (maxX-minX) < charSize
+}
+
+func isTextDirectionVerticalContinuation(previous []*textDirectionChar, next []*textDirectionChar) bool {
+	previousChars := getTextDirectionVisibleChars(previous)
+	nextChars := getTextDirectionVisibleChars(next)
+	if len(previousChars) == 0 || len(nextChars) == 0 {
+		return false
+	}
+
+	if len(previousChars) > 1 && !isTextDirectionVertical(previousChars) {
+		return false
+	}
+
+	if len(nextChars) > 1 && !isTextDirectionVertical(nextChars) {
+		return false
+	}
+
+	last := previousChars[len(previousChars)-1]
+	first := nextChars[0]
+	if math.Abs(last.angle-first.angle) > 0.1 {
+		return false
+	}
+
+	// Only merge scripts that are written vertically, to prevent merging
+	// columns of short lines, like numbers in a table.
+	if !isTextDirectionVerticalScript(last.text) || !isTextDirectionVerticalScript(first.text) {
+		return false
+	}
+
+	width := math.Max(last.right-last.left, first.right-first.left)
+	height := math.Max(last.top-last.bottom, first.top-first.bottom)
+	centerDistance := math.Abs((last.left+last.right)/2 - (first.left+first.right)/2)
+
+	return centerDistance < width/2 && first.top <= last.bottom+height/2 && first.top >= last.bottom-height
+}
+
+func isTextDirectionVerticalScript(text string) bool {
+	for _, char := range text {
+		if unicode.In(char, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul, unicode.Bopomofo) || unicode.Is(textFullWidthForms, char) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildTextDirectionLine puts the chars of a line in logical order.
+func buildTextDirectionLine(chars []*textDirectionChar) *textDirectionLine {
+	visibleChars := getTextDirectionVisibleChars(chars)
+	if len(visibleChars) == 0 {
+		return nil
+	}
+
+	line := &textDirectionLine{
+		angle:     visibleChars[0].angle,
+		direction: responses.TextDirectionLeftToRight,
+	}
+
+	vertical := isTextDirectionVertical(visibleChars)
+	cos := math.Cos(line.angle)
+	sin := math.Sin(line.angle)
+	for _, char := range visibleChars {
+		char.class = getTextDirectionClass(char.text)
+		char.uMin, char.uMax = math.Inf(1), math.Inf(-1)
+		char.vMin, char.vMax = math.Inf(1), math.Inf(-1)
+		for _, x := range []float64{char.left, char.right} {
+			for _, y := range []float64{char.bottom, char.top} {
+				u := x*cos + y*sin
+				v := -x*sin + y*cos
+				if vertical {
+					// Read from top to bottom.
+					u, v = -y, x
+				}
+				char.uMin = math.Min(char.uMin, u)
+				char.uMax = math.Max(char.uMax, u)
+				char.vMin = math.Min(char.vMin, v)
+				char.vMax = math.Max(char.vMax, v)
+			}
+		}
+	}
+
+	// Sort the chars in visual order.
+	sort.SliceStable(visibleChars, func(i, j int) bool {
+		return (visibleChars[i].uMin + visibleChars[i].uMax) < (visibleChars[j].uMin + visibleChars[j].uMax)
+	})
+
+	// Add spaces between chars that are far apart or that had a space
+	// between them in the original text.
+	spaceIndexes := map[int]bool{}
+	for _, char := range chars {
+		if char.isSpace {
+			spaceIndexes[char.index] = true
+		}
+	}
+
+	visualChars := []*textDirectionChar{}
+	for i, char := range visibleChars {
+		if i > 0 {
+			previous := visibleChars[i-1]
+			size := math.Max(previous.vMax-previous.vMin, char.vMax-char.vMin)
+			gap := char.uMin - previous.uMax
+			hasSpace := gap > size*0.25
+			if !hasSpace && (previous.index+2 == char.index && spaceIndexes[previous.index+1] || char.index+2 == previous.index && spaceIndexes[char.index+1]) {
+				hasSpace = true
+			}
+			if hasSpace {
+				visualChars = append(visualChars, &textDirectionChar{
+					index:   -1,
+					text:    " ",
+					isSpace: true,
+					angle:   line.angle,
+				})
+			}
+		}
+		visualChars = append(visualChars, char)
+	}
+
+	if vertical {
+		line.direction = responses.TextDirectionTopToBottom
+		line.chars = visualChars
+		return line
+	}
+
+	leftToRight := 0
+	rightToLeft := 0
+	for _, char := range visualChars {
+		switch char.class {
+		case textDirectionClassLeftToRight:
+			leftToRight++
+		case textDirectionClassRightToLeft:
+			rightToLeft++
+		}
+	}
+
+	if rightToLeft == 0 {
+		line.chars = visualChars
+		return line
+	}
+
+	paragraphLevel := 0
+	if rightToLeft > leftToRight {
+		paragraphLevel = 1
+		line.direction = responses.TextDirectionRightToLeft
+	}
+
+	// Resolve the embedding levels of the chars, a simplified version of the
+	// Unicode bidi algorithm that supports a single level of embedding.
+	for i, char := range visualChars {
+		switch char.class {
+		case textDirectionClassRightToLeft:
+			char.level = 1
+		case textDirectionClassLeftToRight:
+			char.level = paragraphLevel * 2
+		case textDirectionClassNumber:
+			if paragraphLevel == 1 || getTextDirectionNeighbourClass(visualChars, i, -1) == textDirectionClassRightToLeft || getTextDirectionNeighbourClass(visualChars, i, 1) == textDirectionClassRightToLeft {
+				char.level = 2
+			} else {
+				char.level = 0
+			}
+		}
+	}
+
+	for i, char := range visualChars {
+		if char.class != textDirectionClassNeutral {
+			continue
+		}
+
+		char.level = paragraphLevel
+		previous := getTextDirectionNeighbourLevel(visualChars, i, -1)
+		next := getTextDirectionNeighbourLevel(visualChars, i, 1)
+		if previous != -1 && previous == next {
+			char.level = previous
+		} else if previous != -1 && next != -1 && previous%2 == 1 && next%2 == 1 {
+			char.level = 1
+		}
+	}
+
+	// Reverse the visual reordering, from the lowest odd level to the
+	// highest level.
+	maxLevel := 0
+	for _, char := range visualChars {
+		if char.level > maxLevel {
+			maxLevel = char.level
+		}
+	}
+
+	logicalChars := append([]*textDirectionChar{}, visualChars...)
+	for level := 1; level <= maxLevel; level++ {
+		for start := 0; start < len(logicalChars); start++ {
+			if logicalChars[start].level < level {
+				continue
+			}
+			end := start
+			for end < len(logicalChars) && logicalChars[end].level >= level {
+				end++
+			}
+			for i, j := start, end-1; i < j; i, j = i+1, j-1 {
+				logicalChars[i], logicalChars[j] = logicalChars[j], logicalChars[i]
+			}
+			start = end
+		}
+	}
+
+	// Mirror the brackets that are displayed in right-to-left text.
+	for _, char := range logicalChars {
+		if char.level%2 == 1 {
+			runes := []rune(char.text)
+			if len(runes) == 1 {
+				if mirrored, ok := textMirroredBrackets[runes[0]]; ok {
+					char.text = string(mirrored)
+				}
+			}
+		}
+	}
+
+	line.chars = logicalChars
+
+	return line
+}
+
+func getTextDirectionNeighbourClass(chars []*textDirectionChar, index int, step int) textDirectionClass {
+	for i := index + step; i >= 0 && i < len(chars); i += step {
+		if chars[i].class == textDirectionClassLeftToRight || chars[i].class == textDirectionClassRightToLeft {
+			return chars[i].class
+		}
+	}
+	return textDirectionClassNeutral
+}
+
+func getTextDirectionNeighbourLevel(chars []*textDirectionChar, index int, step int) int {
+	for i := index + step; i >= 0 && i < len(chars); i += step {
+		if chars[i].class != textDirectionClassNeutral {
+			return chars[i].level
+		}
+	}
+	return -1
+}
+
+// getTextDirectionCharDirection returns the direction of a char in a line.
+func getTextDirectionCharDirection(line *textDirectionLine, char *textDirectionChar) responses.TextDirection {
+	if line.direction == responses.TextDirectionTopToBottom {
+		return responses.TextDirectionTopToBottom
+	}
+
+	switch char.class {
+	case textDirectionClassRightToLeft:
+		return responses.TextDirectionRightToLeft
+	case textDirectionClassLeftToRight, textDirectionClassNumber:
+		return responses.TextDirectionLeftToRight
+	}
+
+	return line.direction
+}
+
+func getTextDirectionBounds(chars []*textDirectionChar) responses.CharPosition {
+	position := responses.CharPosition{
+		Left:   math.Inf(1),
+		Top:    math.Inf(-1),
+		Right:  math.Inf(-1),
+		Bottom: math.Inf(1),
+	}
+
+	for _, char := range chars {
+		if char.index == -1 {
+			continue
+		}
+		position.Left = math.Min(position.Left, char.left)
+		position.Top = math.Max(position.Top, char.top)
+		position.Right = math.Max(position.Right, char.right)
+		position.Bottom = math.Min(position.Bottom, char.bottom)
+	}
+
+	return position
+}
+
+// getTextDirectionWords splits a line into words.
+func getTextDirectionWords(line *textDirectionLine) []*responses.GetPageTextStructuredWord {
+	words := []*responses.GetPageTextStructuredWord{}
+	wordChars := []*textDirectionChar{}
+
+	finishWord := func() {
+		if len(wordChars) == 0 {
+			return
+		}
+
+		text := strings.Builder{}
+		leftToRight := false
+		rightToLeft := false
+		for _, char := range wordChars {
+			text.WriteString(char.text)
+			switch char.class {
+			case textDirectionClassLeftToRight:
+				leftToRight = true
+			case textDirectionClassRightToLeft:
+				rightToLeft = true
+			}
+		}
+
+		direction := line.direction
+		if direction != responses.TextDirectionTopToBottom {
+			if rightToLeft && !leftToRight {
+				direction = responses.TextDirectionRightToLeft
+			} else if leftToRight && !rightToLeft {
+				direction = responses.TextDirectionLeftToRight
+			}
+		}
+
+		words = append(words, &responses.GetPageTextStructuredWord{
+			Text:          text.String(),
+			Direction:     direction,
+			Angle:         line.angle,
+			PointPosition: getTextDirectionBounds(wordChars),
+		})
+		wordChars = []*textDirectionChar{}
+	}
+
+	for _, char := range line.chars {
+		if char.isSpace {
+			finishWord()
+			continue
+		}
+		wordChars = append(wordChars, char)
+	}
+
+	finishWord()
+
+	return words
+}
+
+// getTextDirectionLineText returns the text of a line in logical order.
+func getTextDirectionLineText(line *textDirectionLine) string {
+	text := strings.Builder{}
+	for _, char := range line.chars {
+		if char.isSpace {
+			text.WriteString(" ")
+			continue
+		}
+		text.WriteString(char.text)
+	}
+	return text.String()
+}
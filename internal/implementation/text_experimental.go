@@ -10,6 +10,8 @@ import "C"
 
 import (
 	"bytes"
+	"errors"
+	"math"
 	"unsafe"
 
 	"github.com/klippa-app/go-pdfium/responses"
@@ -42,3 +44,35 @@ func (p *PdfiumImplementation) getFontInformation(textPage C.FPDF_TEXTPAGE, char
 		Flags:  int(fontFlags),
 	}
 }
+
+func (p *PdfiumImplementation) getTextCharIsGenerated(textPage C.FPDF_TEXTPAGE, charIndex int) (bool, error) {
+	isGenerated := C.FPDFText_IsGenerated(textPage, C.int(charIndex))
+	if int(isGenerated) == -1 {
+		return false, errors.New("could not get whether text is generated")
+	}
+	return int(isGenerated) == 1, nil
+}
+
+// getCharAngle returns the angle of a char in radians, calculated from the
+// matrix of the char, so that mirrored and skewed text is handled correctly.
+func (p *PdfiumImplementation) getCharAngle(textPage C.FPDF_TEXTPAGE, charIndex int) float64 {
+	matrix := C.FS_MATRIX{}
+	if int(C.FPDFText_GetMatrix(textPage, C.int(charIndex), &matrix)) == 0 {
+		angle := float64(C.FPDFText_GetCharAngle(textPage, C.int(charIndex)))
+		if angle < 0 {
+			return 0
+		}
+		return angle
+	}
+
+	angle := math.Atan2(float64(matrix.b), float64(matrix.a))
+	if angle < 0 {
+		angle += 2 * math.Pi
+	}
+
+	return angle
+}
+
+func (p *PdfiumImplementation) getCharHasUnicodeMapError(textPage C.FPDF_TEXTPAGE, charIndex int) (bool, error) {
+	return int(C.FPDFText_HasUnicodeMapError(textPage, C.int(charIndex))) == 1, nil
+}
@@ -0,0 +1,162 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+// #include "fpdf_edit.h"
+import "C"
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"unsafe"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// AddPageTextLayer adds an invisible text layer to a page.
+// Experimental API.
+func (p *PdfiumImplementation) AddPageTextLayer(request *requests.AddPageTextLayer) (*responses.AddPageTextLayer, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	pageHandle, err := p.loadPage(request.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	documentHandle, err := p.getDocumentHandle(pageHandle.documentRef)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.PixelPositions.Calculate && (request.PixelPositions.Width <= 0 || request.PixelPositions.Height <= 0) {
+		return nil, errors.New("no resolution given to calculate point positions")
+	}
+
+	fontName := request.Font
+	if fontName == "" {
+		fontName = "Helvetica"
+	}
+
+	font := loadFont(documentHandle.handle, fontName, request.FontData, request.FontType)
+	if font == nil {
+		return nil, errors.New("could not load font")
+	}
+	defer C.FPDFFont_Close(font)
+
+	resp := &responses.AddPageTextLayer{
+		Page: pageHandle.index,
+	}
+
+	// Create the text objects of all words before inserting them, so that
+	// an error doesn't leave the page with a partial text layer.
+	textObjects := []C.FPDF_PAGEOBJECT{}
+	destroyTextObjects := func() {
+		for _, textObject := range textObjects {
+			C.FPDFPageObj_Destroy(textObject)
+		}
+	}
+
+	for _, word := range request.Words {
+		text := strings.TrimSpace(word.Text)
+		if text == "" {
+			continue
+		}
+
+		left, top, right, bottom := word.Left, word.Top, word.Right, word.Bottom
+		if request.PixelPositions.Calculate {
+			left, top, right, bottom = p.getTextLayerPointPosition(pageHandle, request.PixelPositions, word)
+		}
+
+		if right-left <= 0 || top-bottom <= 0 {
+			continue
+		}
+
+		transformedText, err := p.transformUTF8ToUTF16LE(text)
+		if err != nil {
+			destroyTextObjects()
+			return nil, err
+		}
+
+		// Add the NULL terminator.
+		transformedText = append(transformedText, 0, 0)
+
+		textObject := C.FPDFPageObj_CreateTextObj(documentHandle.handle, font, C.float(top-bottom))
+		if textObject == nil {
+			destroyTextObjects()
+			return nil, errors.New("could not create text object")
+		}
+
+		if int(C.FPDFText_SetText(textObject, (C.FPDF_WIDESTRING)(unsafe.Pointer(&transformedText[0])))) == 0 {
+			C.FPDFPageObj_Destroy(textObject)
+			destroyTextObjects()
+			return nil, errors.New("could not set text")
+		}
+
+		if int(C.FPDFTextObj_SetTextRenderMode(textObject, C.FPDF_TEXT_RENDERMODE(enums.FPDF_TEXTRENDERMODE_INVISIBLE))) == 0 {
+			C.FPDFPageObj_Destroy(textObject)
+			destroyTextObjects()
+			return nil, errors.New("could not set text render mode")
+		}
+
+		// Scale and move the text so that it exactly covers the given box.
+		textLeft := C.float(0)
+		textBottom := C.float(0)
+		textRight := C.float(0)
+		textTop := C.float(0)
+		if int(C.FPDFPageObj_GetBounds(textObject, &textLeft, &textBottom, &textRight, &textTop)) == 0 || textRight-textLeft <= 0 || textTop-textBottom <= 0 {
+			C.FPDFPageObj_Destroy(textObject)
+			continue
+		}
+
+		scaleX := (right - left) / float64(textRight-textLeft)
+		scaleY := (top - bottom) / float64(textTop-textBottom)
+		C.FPDFPageObj_Transform(textObject, C.double(scaleX), 0, 0, C.double(scaleY), C.double(left-scaleX*float64(textLeft)), C.double(bottom-scaleY*float64(textBottom)))
+
+		textObjects = append(textObjects, textObject)
+	}
+
+	if len(textObjects) == 0 {
+		return resp, nil
+	}
+
+	// The page owns the objects after inserting them.
+	for _, textObject := range textObjects {
+		C.FPDFPage_InsertObject(pageHandle.handle, textObject)
+	}
+	resp.WordsAdded = len(textObjects)
+
+	if int(C.FPDFPage_GenerateContent(pageHandle.handle)) == 0 {
+		return nil, errors.New("could not generate page content")
+	}
+
+	return resp, nil
+}
+
+// getTextLayerPointPosition converts the pixel position of a word into a
+// position in points.
+func (p *PdfiumImplementation) getTextLayerPointPosition(pageHandle *PageHandle, pixelPositions requests.AddPageTextLayerPixelPositions, word requests.AddPageTextLayerWord) (float64, float64, float64, float64) {
+	corners := [][2]float64{
+		{word.Left, word.Top},
+		{word.Right, word.Bottom},
+	}
+
+	left, top, right, bottom := math.Inf(1), math.Inf(-1), math.Inf(-1), math.Inf(1)
+	for _, corner := range corners {
+		pageX := C.double(0)
+		pageY := C.double(0)
+		C.FPDF_DeviceToPage(pageHandle.handle, 0, 0, C.int(pixelPositions.Width), C.int(pixelPositions.Height), 0, C.int(math.Round(corner[0])), C.int(math.Round(corner[1])), &pageX, &pageY)
+		left = math.Min(left, float64(pageX))
+		right = math.Max(right, float64(pageX))
+		top = math.Max(top, float64(pageY))
+		bottom = math.Min(bottom, float64(pageY))
+	}
+
+	return left, top, right, bottom
+}
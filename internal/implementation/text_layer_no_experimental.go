@@ -0,0 +1,16 @@
+//go:build !pdfium_experimental
+// +build !pdfium_experimental
+
+package implementation
+
+import (
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// AddPageTextLayer adds an invisible text layer to a page.
+// Experimental API.
+func (p *PdfiumImplementation) AddPageTextLayer(request *requests.AddPageTextLayer) (*responses.AddPageTextLayer, error) {
+	return nil, pdfium_errors.ErrExperimentalUnsupported
+}
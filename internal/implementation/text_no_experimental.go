@@ -9,6 +9,7 @@ package implementation
 import "C"
 
 import (
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
 	"github.com/klippa-app/go-pdfium/responses"
 )
 
@@ -19,3 +20,22 @@ func (p *PdfiumImplementation) getFontInformation(textPage C.FPDF_TEXTPAGE, char
 		Size: float64(fontSize),
 	}
 }
+
+func (p *PdfiumImplementation) getTextCharIsGenerated(textPage C.FPDF_TEXTPAGE, charIndex int) (bool, error) {
+	return false, pdfium_errors.ErrExperimentalUnsupported
+}
+
+// getCharAngle returns the angle of a char in radians.
+func (p *PdfiumImplementation) getCharAngle(textPage C.FPDF_TEXTPAGE, charIndex int) float64 {
+	angle := float64(C.FPDFText_GetCharAngle(textPage, C.int(charIndex)))
+	if angle < 0 {
+		return 0
+	}
+	return angle
+}
+
+// getCharHasUnicodeMapError returns an unsupported error, since detecting
+// unicode map errors is only supported with experimental support.
+func (p *PdfiumImplementation) getCharHasUnicodeMapError(textPage C.FPDF_TEXTPAGE, charIndex int) (bool, error) {
+	return false, pdfium_errors.ErrExperimentalUnsupported
+}
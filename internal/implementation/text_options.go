@@ -0,0 +1,260 @@
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+// #include "fpdf_text.h"
+import "C"
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/klippa-app/go-pdfium/requests"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var textLigatures = map[rune]string{
+	0xFB00: "ff",
+	0xFB01: "fi",
+	0xFB02: "fl",
+	0xFB03: "ffi",
+	0xFB04: "ffl",
+	0xFB05: "st",
+	0xFB06: "st",
+	0x0132: "IJ",
+	0x0133: "ij",
+	0x0152: "OE",
+	0x0153: "oe",
+}
+
+const (
+	textSoftHyphen     = 0x00AD
+	textPDFiumHyphen   = 0x0002 // PDFium marks hyphens at the end of a line with this char.
+	textUnicodeHyphen  = 0x2010
+	textHyphenMinus    = '-'
+	textCarriageReturn = '\r'
+	textLineFeed       = '\n'
+)
+
+func getTextNormalizationForm(normalization requests.TextNormalizationForm) (*norm.Form, error) {
+	var form norm.Form
+	switch normalization {
+	case requests.TextNormalizationFormNone:
+		return nil, nil
+	case requests.TextNormalizationFormNFC:
+		form = norm.NFC
+	case requests.TextNormalizationFormNFD:
+		form = norm.NFD
+	case requests.TextNormalizationFormNFKC:
+		form = norm.NFKC
+	case requests.TextNormalizationFormNFKD:
+		form = norm.NFKD
+	default:
+		return nil, fmt.Errorf("unsupported text normalization form %s", normalization)
+	}
+
+	return &form, nil
+}
+
+// getTextParts returns the text of every char in the text page after
+// applying the text options. The result has an entry for every char index,
+// chars that were removed or merged into a previous char have an empty text.
+func (p *PdfiumImplementation) getTextParts(textPage C.FPDF_TEXTPAGE, options requests.TextOptions) ([]string, error) {
+	normalizationForm, err := getTextNormalizationForm(options.Normalization)
+	if err != nil {
+		return nil, err
+	}
+
+	charsInPage := int(C.FPDFText_CountChars(textPage))
+	chars := make([]rune, charsInPage)
+	for i := 0; i < charsInPage; i++ {
+		chars[i] = rune(C.FPDFText_GetUnicode(textPage, C.int(i)))
+	}
+
+	removed := make([]bool, charsInPage)
+	if options.RemoveGeneratedChars {
+		for i := 0; i < charsInPage; i++ {
+			isGenerated, err := p.getTextCharIsGenerated(textPage, i)
+			if err != nil {
+				return nil, err
+			}
+			removed[i] = isGenerated
+		}
+	}
+
+	return p.applyTextOptionsToChars(chars, removed, normalizationForm, options), nil
+}
+
+// applyTextOptionsToChars applies the text options to the chars of a text
+// page, chars that are marked as removed are skipped. The result has an
+// entry for every char, chars that were removed or merged into a previous
+// char have an empty text.
+func (p *PdfiumImplementation) applyTextOptionsToChars(chars []rune, removed []bool, normalizationForm *norm.Form, options requests.TextOptions) []string {
+	charsInPage := len(chars)
+	parts := make([]string, charsInPage)
+	for i := 0; i < charsInPage; i++ {
+		if removed[i] {
+			continue
+		}
+
+		char := chars[i]
+
+		// Combine surrogate pairs into the first char.
+		if utf16.IsSurrogate(char) {
+			if i+1 < charsInPage && !removed[i+1] {
+				if decoded := utf16.DecodeRune(char, chars[i+1]); decoded != unicode.ReplacementChar {
+					parts[i] = string(decoded)
+					removed[i+1] = true
+					continue
+				}
+			}
+			parts[i] = string(unicode.ReplacementChar)
+			continue
+		}
+
+		if char == textPDFiumHyphen {
+			char = textHyphenMinus
+		}
+
+		if options.JoinHyphenatedWords {
+			if char == textSoftHyphen {
+				p.removeTextLineBreakAt(chars, removed, i+1)
+				continue
+			}
+
+			if char == textHyphenMinus || char == textUnicodeHyphen {
+				// Only join when the hyphen is between letters and directly
+				// followed by a line break.
+				nextIndex := p.getTextIndexAfterLineBreak(chars, removed, i+1)
+				if nextIndex > i+1 && nextIndex < charsInPage && i > 0 && unicode.IsLetter(chars[i-1]) && unicode.IsLower(chars[nextIndex]) {
+					p.removeTextLineBreakAt(chars, removed, i+1)
+					continue
+				}
+			}
+		}
+
+		if options.ExpandLigatures {
+			if ligature, ok := textLigatures[char]; ok {
+				parts[i] = ligature
+				continue
+			}
+		}
+
+		parts[i] = string(char)
+	}
+
+	if normalizationForm != nil {
+		// Normalize per segment of chars that can be influenced by each other,
+		// like a letter followed by combining marks, the result is stored in
+		// the first char of the segment.
+		segmentStart := -1
+		normalizeSegment := func(end int) {
+			if segmentStart == -1 {
+				return
+			}
+			segment := strings.Builder{}
+			for i := segmentStart; i < end; i++ {
+				segment.WriteString(parts[i])
+				parts[i] = ""
+			}
+			parts[segmentStart] = normalizationForm.String(segment.String())
+		}
+
+		for i := 0; i < charsInPage; i++ {
+			if parts[i] == "" {
+				continue
+			}
+			if segmentStart == -1 || normalizationForm.FirstBoundaryInString(parts[i]) == 0 {
+				normalizeSegment(i)
+				segmentStart = i
+			}
+		}
+		normalizeSegment(charsInPage)
+	}
+
+	return parts
+}
+
+// getTextIndexAfterLineBreak returns the index of the first char after the
+// line break at the given index, or the given index when there is no line
+// break at that position.
+func (p *PdfiumImplementation) getTextIndexAfterLineBreak(chars []rune, removed []bool, index int) int {
+	next := index
+	hasLineBreak := false
+	for next < len(chars) && (removed[next] || chars[next] == textCarriageReturn || chars[next] == textLineFeed) {
+		if chars[next] == textCarriageReturn || chars[next] == textLineFeed {
+			hasLineBreak = true
+		}
+		next++
+	}
+
+	if !hasLineBreak {
+		return index
+	}
+
+	return next
+}
+
+// removeTextLineBreakAt marks the line break at the given index as removed.
+func (p *PdfiumImplementation) removeTextLineBreakAt(chars []rune, removed []bool, index int) {
+	end := p.getTextIndexAfterLineBreak(chars, removed, index)
+	for i := index; i < end; i++ {
+		removed[i] = true
+	}
+}
+
+// applyTextOptionsToString applies the text options that don't need the
+// information of the text page to a string.
+func applyTextOptionsToString(text string, options requests.TextOptions) (string, error) {
+	normalizationForm, err := getTextNormalizationForm(options.Normalization)
+	if err != nil {
+		return "", err
+	}
+
+	runes := []rune(text)
+	output := strings.Builder{}
+	for i := 0; i < len(runes); i++ {
+		char := runes[i]
+		if char == textPDFiumHyphen {
+			char = textHyphenMinus
+		}
+
+		if options.JoinHyphenatedWords {
+			if char == textSoftHyphen {
+				continue
+			}
+
+			if (char == textHyphenMinus || char == textUnicodeHyphen) && i > 0 && unicode.IsLetter(runes[i-1]) {
+				next := i + 1
+				if next < len(runes) && runes[next] == textCarriageReturn {
+					next++
+				}
+				if next < len(runes) && runes[next] == textLineFeed {
+					next++
+				}
+				if next > i+1 && next < len(runes) && unicode.IsLower(runes[next]) {
+					i = next - 1
+					continue
+				}
+			}
+		}
+
+		if options.ExpandLigatures {
+			if ligature, ok := textLigatures[char]; ok {
+				output.WriteString(ligature)
+				continue
+			}
+		}
+
+		output.WriteRune(char)
+	}
+
+	if normalizationForm != nil {
+		return normalizationForm.String(output.String()), nil
+	}
+
+	return output.String(), nil
+}
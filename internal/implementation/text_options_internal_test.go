@@ -0,0 +1,85 @@
+package implementation
+
+import (
+	"strings"
+
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("text options", func() {
+	DescribeTable("applyTextOptionsToString",
+		func(text string, options requests.TextOptions, expected string) {
+			output, err := applyTextOptionsToString(text, options)
+			Expect(err).To(BeNil())
+			Expect(output).To(Equal(expected))
+		},
+		Entry("keeps the text without options", "eﬃcient ①", requests.TextOptions{}, "eﬃcient ①"),
+		Entry("replaces the PDFium hyphen marker", "exam\x02\r\nple", requests.TextOptions{}, "exam-\r\nple"),
+		Entry("expands ligatures", "ﬁnal ﬂow eﬃcient Œuvre", requests.TextOptions{ExpandLigatures: true}, "final flow efficient OEuvre"),
+		Entry("joins words hyphenated with the PDFium hyphen marker", "exam\x02\r\nple", requests.TextOptions{JoinHyphenatedWords: true}, "example"),
+		Entry("joins words hyphenated with a hyphen-minus", "exam-\nple", requests.TextOptions{JoinHyphenatedWords: true}, "example"),
+		Entry("joins words hyphenated with a Unicode hyphen", "exam‐\r\nple", requests.TextOptions{JoinHyphenatedWords: true}, "example"),
+		Entry("removes soft hyphens", "exam\u00adple", requests.TextOptions{JoinHyphenatedWords: true}, "example"),
+		Entry("keeps hyphens before uppercase letters", "Jean-\nPaul", requests.TextOptions{JoinHyphenatedWords: true}, "Jean-\nPaul"),
+		Entry("keeps hyphens that are not at the end of a line", "well-known", requests.TextOptions{JoinHyphenatedWords: true}, "well-known"),
+		Entry("keeps hyphens after numbers", "1-\nabc", requests.TextOptions{JoinHyphenatedWords: true}, "1-\nabc"),
+		Entry("composes with NFC", "e\u0301", requests.TextOptions{Normalization: requests.TextNormalizationFormNFC}, "\u00e9"),
+		Entry("decomposes with NFD", "\u00e9", requests.TextOptions{Normalization: requests.TextNormalizationFormNFD}, "e\u0301"),
+		Entry("replaces compatibility chars with NFKC", "① ＡＢ ﬁ x²", requests.TextOptions{Normalization: requests.TextNormalizationFormNFKC}, "1 AB fi x2"),
+		Entry("replaces compatibility chars with NFKD", "\u00e9①", requests.TextOptions{Normalization: requests.TextNormalizationFormNFKD}, "e\u03011"),
+		Entry("combines all options", "ﬁ\x02\nnal ①", requests.TextOptions{ExpandLigatures: true, JoinHyphenatedWords: true, Normalization: requests.TextNormalizationFormNFKC}, "final 1"),
+	)
+
+	It("returns an error for an unsupported normalization form", func() {
+		output, err := applyTextOptionsToString("text", requests.TextOptions{Normalization: "NFX"})
+		Expect(err).To(MatchError("unsupported text normalization form NFX"))
+		Expect(output).To(Equal(""))
+	})
+
+	DescribeTable("applyTextOptionsToChars",
+		func(chars []rune, removedIndexes []int, options requests.TextOptions, expected []string) {
+			removed := make([]bool, len(chars))
+			for _, index := range removedIndexes {
+				removed[index] = true
+			}
+
+			normalizationForm, err := getTextNormalizationForm(options.Normalization)
+			Expect(err).To(BeNil())
+
+			implementation := &PdfiumImplementation{}
+			parts := implementation.applyTextOptionsToChars(chars, removed, normalizationForm, options)
+			Expect(parts).To(HaveLen(len(chars)))
+			Expect(parts).To(Equal(expected))
+		},
+		Entry("replaces the PDFium hyphen marker", []rune("ab\x02\r\ncd"), nil, requests.TextOptions{}, []string{"a", "b", "-", "\r", "\n", "c", "d"}),
+		Entry("joins words hyphenated with the PDFium hyphen marker", []rune("ab\x02\r\ncd"), nil, requests.TextOptions{JoinHyphenatedWords: true}, []string{"a", "b", "", "", "", "c", "d"}),
+		Entry("removes soft hyphens and the line break after them", []rune("ab\u00ad\ncd"), nil, requests.TextOptions{JoinHyphenatedWords: true}, []string{"a", "b", "", "", "c", "d"}),
+		Entry("keeps hyphens before uppercase letters", []rune("ab-\nCd"), nil, requests.TextOptions{JoinHyphenatedWords: true}, []string{"a", "b", "-", "\n", "C", "d"}),
+		Entry("skips removed chars", []rune("a b"), []int{1}, requests.TextOptions{}, []string{"a", "", "b"}),
+		Entry("expands ligatures", []rune("\ufb03x"), nil, requests.TextOptions{ExpandLigatures: true}, []string{"ffi", "x"}),
+		Entry("combines surrogate pairs into the first char", []rune{'a', 0xD835, 0xDC00}, nil, requests.TextOptions{}, []string{"a", "\U0001D400", ""}),
+		Entry("replaces a lone surrogate", []rune{'a', 0xD835}, nil, requests.TextOptions{}, []string{"a", "\ufffd"}),
+		Entry("normalizes into the first char of a segment", []rune("e\u0301x"), nil, requests.TextOptions{Normalization: requests.TextNormalizationFormNFC}, []string{"\u00e9", "", "x"}),
+		Entry("replaces compatibility chars with NFKC", []rune("①Ａ"), nil, requests.TextOptions{Normalization: requests.TextNormalizationFormNFKC}, []string{"1", "A"}),
+	)
+
+	It("joins the parts into the same text as applyTextOptionsToString", func() {
+		text := "e\ufb03-\r\ncient e\u0301①"
+		options := requests.TextOptions{ExpandLigatures: true, JoinHyphenatedWords: true, Normalization: requests.TextNormalizationFormNFKC}
+
+		normalizationForm, err := getTextNormalizationForm(options.Normalization)
+		Expect(err).To(BeNil())
+
+		chars := []rune(text)
+		implementation := &PdfiumImplementation{}
+		parts := implementation.applyTextOptionsToChars(chars, make([]bool, len(chars)), normalizationForm, options)
+
+		output, err := applyTextOptionsToString(text, options)
+		Expect(err).To(BeNil())
+		Expect(strings.Join(parts, "")).To(Equal(output))
+		Expect(output).To(Equal("efficient \u00e91"))
+	})
+})
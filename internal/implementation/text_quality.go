@@ -0,0 +1,283 @@
+package implementation
+
+// #cgo pkg-config: pdfium
+// #include "fpdfview.h"
+// #include "fpdf_edit.h"
+// #include "fpdf_text.h"
+// #include "fpdf_transformpage.h"
+import "C"
+
+import (
+	"math"
+	"unicode"
+	"unsafe"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// textQualityImageCoverageGridSize is the size of the grid that is used to
+// calculate the part of the page that is covered by images, so that
+// overlapping images are not counted twice.
+const textQualityImageCoverageGridSize = 100
+
+type textQualityRect struct {
+	left   float64
+	top    float64
+	right  float64
+	bottom float64
+}
+
+type textQualityPageObjects struct {
+	invisibleCharCount int
+	imageCount         int
+	imageRects         []textQualityRect
+}
+
+// GetPageTextQuality analyzes the text layer of a page.
+func (p *PdfiumImplementation) GetPageTextQuality(request *requests.GetPageTextQuality) (*responses.GetPageTextQuality, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	pageHandle, err := p.loadPage(request.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	textPage := C.FPDFText_LoadPage(pageHandle.handle)
+	defer C.FPDFText_ClosePage(textPage)
+
+	resp := &responses.GetPageTextQuality{
+		Page:                    pageHandle.index,
+		UnicodeMapErrorsChecked: true,
+	}
+
+	charsInPage := int(C.FPDFText_CountChars(textPage))
+	for i := 0; i < charsInPage; i++ {
+		char := rune(C.FPDFText_GetUnicode(textPage, C.int(i)))
+		if char == '\r' || char == '\n' || (char != 0 && unicode.IsSpace(char)) {
+			continue
+		}
+
+		resp.CharCount++
+
+		if isTextQualityUnmappableChar(char) {
+			resp.UnmappableCharCount++
+			continue
+		}
+
+		if resp.UnicodeMapErrorsChecked {
+			hasUnicodeMapError, err := p.getCharHasUnicodeMapError(textPage, i)
+			if err != nil {
+				if err != pdfium_errors.ErrExperimentalUnsupported {
+					return nil, err
+				}
+				resp.UnicodeMapErrorsChecked = false
+			} else if hasUnicodeMapError {
+				resp.UnmappableCharCount++
+			}
+		}
+	}
+
+	pageObjects := &textQualityPageObjects{}
+	objectCount := int(C.FPDFPage_CountObjects(pageHandle.handle))
+	for i := 0; i < objectCount; i++ {
+		p.analyzeTextQualityPageObject(C.FPDFPage_GetObject(pageHandle.handle, C.int(i)), textPage, pageObjects, 0)
+	}
+
+	resp.ImageCount = pageObjects.imageCount
+	resp.InvisibleCharCount = pageObjects.invisibleCharCount
+	if resp.InvisibleCharCount > resp.CharCount {
+		resp.InvisibleCharCount = resp.CharCount
+	}
+
+	if len(pageObjects.imageRects) > 0 {
+		left := C.float(0)
+		bottom := C.float(0)
+		right := C.float(0)
+		top := C.float(0)
+		if int(C.FPDFPage_GetMediaBox(pageHandle.handle, &left, &bottom, &right, &top)) == 0 {
+			left = 0
+			bottom = 0
+			right = C.float(C.FPDF_GetPageWidthF(pageHandle.handle))
+			top = C.float(C.FPDF_GetPageHeightF(pageHandle.handle))
+		}
+		resp.ImageCoverage = getTextQualityCoverage(pageObjects.imageRects, float64(left), float64(bottom), float64(right), float64(top))
+	}
+
+	resp.Status, resp.NeedsOCR, resp.Confidence = getTextQualityStatus(resp, objectCount)
+
+	return resp, nil
+}
+
+// analyzeTextQualityPageObject collects the invisible text and the images of
+// a page object, form objects are analyzed recursively.
+func (p *PdfiumImplementation) analyzeTextQualityPageObject(pageObject C.FPDF_PAGEOBJECT, textPage C.FPDF_TEXTPAGE, pageObjects *textQualityPageObjects, depth int) {
+	if pageObject == nil {
+		return
+	}
+
+	switch C.FPDFPageObj_GetType(pageObject) {
+	case C.FPDF_PAGEOBJ_TEXT:
+		if enums.FPDF_TEXT_RENDERMODE(C.FPDFTextObj_GetTextRenderMode(pageObject)) != enums.FPDF_TEXTRENDERMODE_INVISIBLE {
+			return
+		}
+
+		textSize := C.FPDFTextObj_GetText(pageObject, textPage, nil, 0)
+		if textSize == 0 {
+			return
+		}
+
+		charData := make([]byte, textSize)
+		C.FPDFTextObj_GetText(pageObject, textPage, (*C.ushort)(unsafe.Pointer(&charData[0])), C.ulong(len(charData)))
+
+		text, err := p.transformUTF16LEToUTF8(charData)
+		if err != nil {
+			return
+		}
+
+		for _, char := range text {
+			if !unicode.IsSpace(char) {
+				pageObjects.invisibleCharCount++
+			}
+		}
+	case C.FPDF_PAGEOBJ_IMAGE:
+		pageObjects.imageCount++
+
+		// Images in form objects are positioned relative to the form, so
+		// we can only use the bounds of images that are directly on the page.
+		if depth > 0 {
+			return
+		}
+
+		left := C.float(0)
+		bottom := C.float(0)
+		right := C.float(0)
+		top := C.float(0)
+		if int(C.FPDFPageObj_GetBounds(pageObject, &left, &bottom, &right, &top)) == 0 {
+			return
+		}
+
+		pageObjects.imageRects = append(pageObjects.imageRects, textQualityRect{
+			left:   float64(left),
+			top:    float64(top),
+			right:  float64(right),
+			bottom: float64(bottom),
+		})
+	case C.FPDF_PAGEOBJ_FORM:
+		// Prevent endless recursion in broken documents.
+		if depth > 32 {
+			return
+		}
+
+		imageCount := pageObjects.imageCount
+		objectCount := int(C.FPDFFormObj_CountObjects(pageObject))
+		for i := 0; i < objectCount; i++ {
+			p.analyzeTextQualityPageObject(C.FPDFFormObj_GetObject(pageObject, C.ulong(i)), textPage, pageObjects, depth+1)
+		}
+
+		// When the form contains images, use the bounds of the form as
+		// the position of the images.
+		if depth == 0 && pageObjects.imageCount > imageCount {
+			left := C.float(0)
+			bottom := C.float(0)
+			right := C.float(0)
+			top := C.float(0)
+			if int(C.FPDFPageObj_GetBounds(pageObject, &left, &bottom, &right, &top)) == 0 {
+				return
+			}
+
+			pageObjects.imageRects = append(pageObjects.imageRects, textQualityRect{
+				left:   float64(left),
+				top:    float64(top),
+				right:  float64(right),
+				bottom: float64(bottom),
+			})
+		}
+	}
+}
+
+// isTextQualityUnmappableChar returns whether a char is most likely the
+// result of a missing or broken ToUnicode map.
+func isTextQualityUnmappableChar(char rune) bool {
+	if char == 0 || char == unicode.ReplacementChar {
+		return true
+	}
+
+	// Private use area, often used by fonts without a proper unicode mapping.
+	if char >= 0xE000 && char <= 0xF8FF {
+		return true
+	}
+
+	// Control chars, PDFium uses 0x02 for hyphens at the end of a line.
+	if char != textPDFiumHyphen && char != '\t' && unicode.IsControl(char) {
+		return true
+	}
+
+	return false
+}
+
+// getTextQualityCoverage returns the part of the page that is covered by the
+// given rects, by sampling the page in a grid.
+func getTextQualityCoverage(rects []textQualityRect, left, bottom, right, top float64) float64 {
+	width := right - left
+	height := top - bottom
+	if width <= 0 || height <= 0 {
+		return 0
+	}
+
+	covered := 0
+	for x := 0; x < textQualityImageCoverageGridSize; x++ {
+		pointX := left + (float64(x)+0.5)*width/textQualityImageCoverageGridSize
+		for y := 0; y < textQualityImageCoverageGridSize; y++ {
+			pointY := bottom + (float64(y)+0.5)*height/textQualityImageCoverageGridSize
+			for _, rect := range rects {
+				if pointX >= rect.left && pointX <= rect.right && pointY >= rect.bottom && pointY <= rect.top {
+					covered++
+					break
+				}
+			}
+		}
+	}
+
+	return float64(covered) / (textQualityImageCoverageGridSize * textQualityImageCoverageGridSize)
+}
+
+// getTextQualityStatus determines the status of the text layer based on the
+// collected statistics.
+func getTextQualityStatus(quality *responses.GetPageTextQuality, objectCount int) (responses.GetPageTextQualityStatus, bool, float64) {
+	if quality.CharCount == 0 {
+		if quality.ImageCount > 0 {
+			return responses.GetPageTextQualityStatusImageOnly, true, math.Min(1, 0.5+quality.ImageCoverage/2)
+		}
+
+		// The page could contain text that is drawn with paths, we can't
+		// be completely sure that the page is empty.
+		if objectCount > 0 {
+			return responses.GetPageTextQualityStatusEmpty, false, 0.7
+		}
+
+		return responses.GetPageTextQualityStatusEmpty, false, 1
+	}
+
+	invisibleRatio := float64(quality.InvisibleCharCount) / float64(quality.CharCount)
+	unmappableRatio := float64(quality.UnmappableCharCount) / float64(quality.CharCount)
+
+	if unmappableRatio > 0.1 {
+		return responses.GetPageTextQualityStatusBrokenText, true, math.Min(1, 0.5+unmappableRatio)
+	}
+
+	if invisibleRatio > 0.5 {
+		return responses.GetPageTextQualityStatusInvisibleText, false, invisibleRatio * (1 - unmappableRatio)
+	}
+
+	// A page that is mostly an image with only a little bit of text is most
+	// likely a scan with a stamp or a page number.
+	if quality.ImageCoverage > 0.8 && quality.CharCount-quality.InvisibleCharCount < 20 {
+		return responses.GetPageTextQualityStatusText, true, 0.5
+	}
+
+	return responses.GetPageTextQualityStatusText, false, 1 - unmappableRatio
+}
@@ -11,6 +11,54 @@ import (
 	"github.com/klippa-app/go-pdfium/responses"
 )
 
+func (i *pdfiumInstance) AddHeaderFooter(request *requests.AddHeaderFooter) (*responses.AddHeaderFooter, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.AddHeaderFooter(request)
+}
+
+func (i *pdfiumInstance) AddPageTextLayer(request *requests.AddPageTextLayer) (*responses.AddPageTextLayer, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.AddPageTextLayer(request)
+}
+
+func (i *pdfiumInstance) AuditFormAccessibility(request *requests.AuditFormAccessibility) (*responses.AuditFormAccessibility, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.AuditFormAccessibility(request)
+}
+
+func (i *pdfiumInstance) DrawPage(request *requests.DrawPage) (*responses.DrawPage, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.DrawPage(request)
+}
+
+func (i *pdfiumInstance) EditFormFields(request *requests.EditFormFields) (*responses.EditFormFields, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.EditFormFields(request)
+}
+
+func (i *pdfiumInstance) ExportFormData(request *requests.ExportFormData) (*responses.ExportFormData, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.ExportFormData(request)
+}
+
 func (i *pdfiumInstance) FORM_CanRedo(request *requests.FORM_CanRedo) (*responses.FORM_CanRedo, error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -976,7 +1024,11 @@ func (i *pdfiumInstance) FPDFDOC_ExitFormFillEnvironment(request *requests.FPDFD
 }
 
 func (i *pdfiumInstance) FPDFDOC_InitFormFillEnvironment(request *requests.FPDFDOC_InitFormFillEnvironment) (*responses.FPDFDOC_InitFormFillEnvironment, error) {
-	return nil, errors.New("unsupported method on multi-threaded usage")
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.FPDFDOC_InitFormFillEnvironment(request)
 }
 
 func (i *pdfiumInstance) FPDFDest_GetDestPageIndex(request *requests.FPDFDest_GetDestPageIndex) (*responses.FPDFDest_GetDestPageIndex, error) {
@@ -3299,6 +3351,22 @@ func (i *pdfiumInstance) FSDK_SetUnSpObjProcessHandler(request *requests.FSDK_Se
 	return nil, errors.New("unsupported method on multi-threaded usage")
 }
 
+func (i *pdfiumInstance) FillForm(request *requests.FillForm) (*responses.FillForm, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.FillForm(request)
+}
+
+func (i *pdfiumInstance) FlattenAnnotations(request *requests.FlattenAnnotations) (*responses.FlattenAnnotations, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.FlattenAnnotations(request)
+}
+
 func (i *pdfiumInstance) GetActionInfo(request *requests.GetActionInfo) (*responses.GetActionInfo, error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -3331,6 +3399,38 @@ func (i *pdfiumInstance) GetDestInfo(request *requests.GetDestInfo) (*responses.
 	return i.worker.plugin.GetDestInfo(request)
 }
 
+func (i *pdfiumInstance) GetDocumentImages(request *requests.GetDocumentImages) (*responses.GetDocumentImages, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.GetDocumentImages(request)
+}
+
+func (i *pdfiumInstance) GetDocumentMarkup(request *requests.GetDocumentMarkup) (*responses.GetDocumentMarkup, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.GetDocumentMarkup(request)
+}
+
+func (i *pdfiumInstance) GetFormFields(request *requests.GetFormFields) (*responses.GetFormFields, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.GetFormFields(request)
+}
+
+func (i *pdfiumInstance) GetFormSchema(request *requests.GetFormSchema) (*responses.GetFormSchema, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.GetFormSchema(request)
+}
+
 func (i *pdfiumInstance) GetJavaScriptActions(request *requests.GetJavaScriptActions) (*responses.GetJavaScriptActions, error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -3347,6 +3447,14 @@ func (i *pdfiumInstance) GetMetaData(request *requests.GetMetaData) (*responses.
 	return i.worker.plugin.GetMetaData(request)
 }
 
+func (i *pdfiumInstance) GetPageImages(request *requests.GetPageImages) (*responses.GetPageImages, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.GetPageImages(request)
+}
+
 func (i *pdfiumInstance) GetPageSize(request *requests.GetPageSize) (*responses.GetPageSize, error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -3371,6 +3479,14 @@ func (i *pdfiumInstance) GetPageText(request *requests.GetPageText) (*responses.
 	return i.worker.plugin.GetPageText(request)
 }
 
+func (i *pdfiumInstance) GetPageTextQuality(request *requests.GetPageTextQuality) (*responses.GetPageTextQuality, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.GetPageTextQuality(request)
+}
+
 func (i *pdfiumInstance) GetPageTextStructured(request *requests.GetPageTextStructured) (*responses.GetPageTextStructured, error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -3379,6 +3495,60 @@ func (i *pdfiumInstance) GetPageTextStructured(request *requests.GetPageTextStru
 	return i.worker.plugin.GetPageTextStructured(request)
 }
 
+func (i *pdfiumInstance) ImportFormData(request *requests.ImportFormData) (*responses.ImportFormData, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.ImportFormData(request)
+}
+
+func (i *pdfiumInstance) ImposeBooklet(request *requests.ImposeBooklet) (*responses.ImposeBooklet, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.ImposeBooklet(request)
+}
+
+func (i *pdfiumInstance) ImposeNUp(request *requests.ImposeNUp) (*responses.ImposeNUp, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.ImposeNUp(request)
+}
+
+func (i *pdfiumInstance) ImposeTiles(request *requests.ImposeTiles) (*responses.ImposeTiles, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.ImposeTiles(request)
+}
+
+func (i *pdfiumInstance) MergeDocuments(request *requests.MergeDocuments) (*responses.MergeDocuments, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	// Since multi-threaded usage implements gRPC, it can't serialize the readers onto that.
+	for documentIndex := range request.Documents {
+		if err := readDocumentInput(&request.Documents[documentIndex]); err != nil {
+			return nil, err
+		}
+	}
+	return i.worker.plugin.MergeDocuments(request)
+}
+
+func (i *pdfiumInstance) NormalizePages(request *requests.NormalizePages) (*responses.NormalizePages, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.NormalizePages(request)
+}
+
 func (i *pdfiumInstance) OpenDocument(request *requests.OpenDocument) (*responses.OpenDocument, error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -3399,6 +3569,22 @@ func (i *pdfiumInstance) OpenDocument(request *requests.OpenDocument) (*response
 	return i.worker.plugin.OpenDocument(request)
 }
 
+func (i *pdfiumInstance) OptimizeImages(request *requests.OptimizeImages) (*responses.OptimizeImages, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.OptimizeImages(request)
+}
+
+func (i *pdfiumInstance) RedactDocument(request *requests.RedactDocument) (*responses.RedactDocument, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.RedactDocument(request)
+}
+
 func (i *pdfiumInstance) RenderPageInDPI(request *requests.RenderPageInDPI) (*responses.RenderPageInDPI, error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -3438,3 +3624,55 @@ func (i *pdfiumInstance) RenderToFile(request *requests.RenderToFile) (*response
 
 	return i.worker.plugin.RenderToFile(request)
 }
+
+func (i *pdfiumInstance) ReorderPages(request *requests.ReorderPages) (*responses.ReorderPages, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	// Since multi-threaded usage implements gRPC, it can't serialize the reader onto that.
+	if err := readDocumentInput(&request.Document); err != nil {
+		return nil, err
+	}
+	return i.worker.plugin.ReorderPages(request)
+}
+
+func (i *pdfiumInstance) RunFormActions(request *requests.RunFormActions) (*responses.RunFormActions, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.RunFormActions(request)
+}
+
+func (i *pdfiumInstance) SearchPageText(request *requests.SearchPageText) (*responses.SearchPageText, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.SearchPageText(request)
+}
+
+func (i *pdfiumInstance) SplitDocument(request *requests.SplitDocument) (*responses.SplitDocument, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	// Since multi-threaded usage implements gRPC, it can't serialize the reader onto that.
+	if err := readDocumentInput(&request.Document); err != nil {
+		return nil, err
+	}
+	return i.worker.plugin.SplitDocument(request)
+}
+
+func (i *pdfiumInstance) StampDocument(request *requests.StampDocument) (*responses.StampDocument, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	// Since multi-threaded usage implements gRPC, it can't serialize the reader onto that.
+	if err := readDocumentInput(&request.SourcePage.Document); err != nil {
+		return nil, err
+	}
+	return i.worker.plugin.StampDocument(request)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"sync"
@@ -15,6 +16,7 @@ import (
 	pool "github.com/jolestar/go-commons-pool/v2"
 	"github.com/klippa-app/go-pdfium"
 	"github.com/klippa-app/go-pdfium/internal/commons"
+	"github.com/klippa-app/go-pdfium/requests"
 )
 
 type worker struct {
@@ -295,3 +297,21 @@ func (i *pdfiumInstance) Kill() (err error) {
 	i.worker.pluginClient.Kill()
 	return
 }
+
+// readDocumentInput fully reads the io.ReadSeeker of a document input into
+// a byte array, since a reader can't be serialized to the worker.
+func readDocumentInput(input *requests.DocumentInput) error {
+	if input.FileReader == nil {
+		return nil
+	}
+
+	fileData, err := ioutil.ReadAll(input.FileReader)
+	if err != nil {
+		return err
+	}
+
+	input.FileReader = nil
+	input.FileReaderSize = 0
+	input.File = &fileData
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/klippa-app/go-pdfium"
 	"github.com/klippa-app/go-pdfium/multi_threaded"
 	"github.com/klippa-app/go-pdfium/shared_tests"
 
@@ -24,7 +25,7 @@ var _ = BeforeSuite(func() {
 
 	args = append(args, "../examples/multi_threaded/worker/main.go")
 
-	pool := multi_threaded.Init(multi_threaded.Config{
+	config := multi_threaded.Config{
 		MinIdle:  1, // Makes sure that at least x workers are always available
 		MaxIdle:  1, // Makes sure that at most x workers are ever available
 		MaxTotal: 1, // Maxium amount of workers in total, allows the amount of workers to grow when needed, items between total max and idle max are automatically cleaned up, while idle workers are kept alive so they can be used directly.
@@ -33,9 +34,13 @@ var _ = BeforeSuite(func() {
 			Args:         args,             // This is a reference to the worker package, this can be left empty when using a direct binary path.
 			StartTimeout: time.Minute * 15, // Some test environments are real slow.
 		},
-	})
+	}
 
+	pool := multi_threaded.Init(config)
 	shared_tests.PdfiumPool = pool
+	shared_tests.NewPdfiumPool = func() pdfium.Pool {
+		return multi_threaded.Init(config)
+	}
 
 	instance, err := pool.GetInstance(time.Second * 30)
 	Expect(err).To(BeNil())
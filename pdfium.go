@@ -65,8 +65,241 @@ type Pdfium interface {
 	// with coordinates and font information.
 	GetPageTextStructured(request *requests.GetPageTextStructured) (*responses.GetPageTextStructured, error)
 
+	// SearchPageText searches the text of a given page, after applying the same
+	// text options as GetPageText and GetPageTextStructured.
+	SearchPageText(request *requests.SearchPageText) (*responses.SearchPageText, error)
+
+	// GetPageTextQuality analyzes the text layer of a given page, it reports whether
+	// the page has a real text layer, is image-only, has invisible (OCR) text or has
+	// text that can't be mapped to unicode. Use this to decide whether a page needs OCR.
+	GetPageTextQuality(request *requests.GetPageTextQuality) (*responses.GetPageTextQuality, error)
+
+	// AddPageTextLayer adds an invisible text layer to a page, positioned and scaled
+	// over the content of the page. This can be used to make scanned pages searchable
+	// with the results of an OCR engine.
+	// Experimental API.
+	AddPageTextLayer(request *requests.AddPageTextLayer) (*responses.AddPageTextLayer, error)
+
 	// End text: text helpers
 
+	// Start markup: markup helpers
+
+	// GetDocumentMarkup converts the text of a document into Markdown or simple HTML.
+	// It detects headings, paragraphs, lists, tables and hyperlinks by combining the
+	// struct tree, font sizes and weights, web links, link annotations and bookmark titles.
+	// Using the struct tree is only supported when compiled with experimental support.
+	GetDocumentMarkup(request *requests.GetDocumentMarkup) (*responses.GetDocumentMarkup, error)
+
+	// End markup
+
+	// Start assemble: document assembly helpers
+
+	// MergeDocuments merges the pages of multiple documents into a new document.
+	// The documents can be given as bytes, paths, readers or opened documents.
+	MergeDocuments(request *requests.MergeDocuments) (*responses.MergeDocuments, error)
+
+	// SplitDocument splits a document into multiple documents, by page ranges,
+	// by page count, by bookmark level or by file size.
+	SplitDocument(request *requests.SplitDocument) (*responses.SplitDocument, error)
+
+	// ReorderPages creates a new document with the pages of a document in the given order.
+	ReorderPages(request *requests.ReorderPages) (*responses.ReorderPages, error)
+
+	// End assemble
+
+	// Start imposition: imposition helpers
+
+	// ImposeNUp creates a new document with multiple pages of the source document on every sheet,
+	// with optional margins, gutters and crop marks.
+	// Experimental API.
+	ImposeNUp(request *requests.ImposeNUp) (*responses.ImposeNUp, error)
+
+	// ImposeBooklet creates a new document with the pages of the source document imposed for
+	// saddle-stitch booklet printing. Every sheet has a front and a back side.
+	// Experimental API.
+	ImposeBooklet(request *requests.ImposeBooklet) (*responses.ImposeBooklet, error)
+
+	// ImposeTiles creates a new document with the pages of the source document tiled across
+	// multiple sheets, to print a page as a poster.
+	// Experimental API.
+	ImposeTiles(request *requests.ImposeTiles) (*responses.ImposeTiles, error)
+
+	// End imposition
+
+	// Start stamp: stamp helpers
+
+	// StampDocument adds a text, an image or a page of another PDF to pages of a document,
+	// positioned relative to the crop box or the media box of the pages. The stamp can be
+	// added to the page content or as a watermark annotation in a copy of the document.
+	// Watermark annotations are not supported for encrypted documents.
+	// Experimental API.
+	StampDocument(request *requests.StampDocument) (*responses.StampDocument, error)
+
+	// End stamp
+
+	// Start header_footer: header and footer helpers
+
+	// AddHeaderFooter adds headers and footers to every page of one or more documents,
+	// the texts are templates that support page numbers and running (Bates) numbers.
+	AddHeaderFooter(request *requests.AddHeaderFooter) (*responses.AddHeaderFooter, error)
+
+	// End header_footer
+
+	// Start redact: redaction helpers
+
+	// RedactDocument removes the content under areas of pages of a document, instead of only
+	// covering it. The areas can be given directly, be the matches of searches or the areas of
+	// redact annotations. Chars in the areas are removed from text objects, the pixels of images
+	// in the areas are blacked out and other objects that intersect the areas are removed. Form
+	// objects are removed entirely and reported separately, since PDFium can't remove the objects
+	// in them. Annotations in the areas are removed, widgets of form fields are only reported,
+	// flatten the form fields first to redact their values. Boxes can be drawn over the areas.
+	// Afterwards the text of the page is extracted again to verify that nothing remained, pages
+	// with removed form objects or widgets in the areas are not verified.
+	// Experimental API.
+	RedactDocument(request *requests.RedactDocument) (*responses.RedactDocument, error)
+
+	// End redact
+
+	// Start normalize: page normalization helpers
+
+	// NormalizePages crops, scales and rotates pages of a document in place. It can crop the
+	// pages to their content, apply the rotation of the pages to their content, scale the pages
+	// to A4, Letter or a custom size with fit or fill modes and add margins. Annotations are not
+	// moved, flatten them first when the document has annotations.
+	// Experimental API.
+	NormalizePages(request *requests.NormalizePages) (*responses.NormalizePages, error)
+
+	// End normalize
+
+	// Start images: image helpers
+
+	// GetPageImages returns the images of a page, including the images in form objects, with
+	// their filters, metadata and position. Optionally the raw and the decoded data of the images
+	// is returned, and the images are exported as JPEG, JPEG 2000 or PNG.
+	GetPageImages(request *requests.GetPageImages) (*responses.GetPageImages, error)
+
+	// GetDocumentImages returns the images of the pages of a document, like GetPageImages.
+	GetDocumentImages(request *requests.GetDocumentImages) (*responses.GetDocumentImages, error)
+
+	// End images
+
+	// Start optimize_images: image optimization helpers
+
+	// OptimizeImages downsamples the images of a document that have a higher resolution than
+	// the target DPI, and recompresses them as JPEG with the given quality. Images that only
+	// contain gray pixels can be stored as grayscale images. Bilevel images and images with
+	// transparency are skipped, and images are only replaced when the result is smaller.
+	// Images that are used multiple times are reported for every use.
+	OptimizeImages(request *requests.OptimizeImages) (*responses.OptimizeImages, error)
+
+	// End optimize_images
+
+	// Start draw: drawing helpers
+
+	// DrawPage adds page objects for a list of drawing operations to a page in one call: paths,
+	// rectangles, text runs and images, each with an optional transformation matrix. When one
+	// of the operations is invalid, nothing is drawn. The content of the page is generated
+	// afterwards, and the references of the added page objects are returned.
+	DrawPage(request *requests.DrawPage) (*responses.DrawPage, error)
+
+	// End draw
+
+	// Start form_fields: form field helpers
+
+	// GetFormFields returns all the form fields of a document with their full name, type, flags,
+	// value, default value, export values, options and widgets. The form fill environment of the
+	// document is used when one was initialized, otherwise a temporary one is initialized.
+	// Experimental API.
+	GetFormFields(request *requests.GetFormFields) (*responses.GetFormFields, error)
+
+	// FillForm fills form fields by their full name: text fields, checkboxes, radio buttons,
+	// comboboxes and listboxes. The values are set through the form fill environment, so PDFium
+	// regenerates the appearances of the widgets and the result renders in any viewer. The form
+	// fields can be flattened afterwards, and the document is saved.
+	// Experimental API.
+	FillForm(request *requests.FillForm) (*responses.FillForm, error)
+
+	// GetFormSchema returns a JSON Schema of the form fields of a document, to generate forms
+	// from. The properties are keyed by the full field name and their values are accepted by
+	// FillForm. The field types, required and read only flags, options, max length and current
+	// values are in the schema, the layout (type, tab order, page and rects of the widgets) is
+	// in the x-pdf keyword of each property and in the response. Push buttons and signatures
+	// are left out.
+	// Experimental API.
+	GetFormSchema(request *requests.GetFormSchema) (*responses.GetFormSchema, error)
+
+	// RunFormActions fills form fields like FillForm does and runs the keystroke, validate,
+	// calculate and format actions of the form fields. PDFium is built without a JavaScript
+	// engine, so only actions that call one of the built-in Acrobat functions (AFNumber_*,
+	// AFPercent_*, AFDate_*, AFTime_*, AFSpecial_*, AFRange_Validate and AFSimple_Calculate)
+	// are run, other actions are skipped and returned. The keystroke and validate actions
+	// check the values and return the rejected values per field, the values are kept. The
+	// calculated values are set in the calculation order (/CO) of the form, fields that are
+	// not in it are skipped like viewers do. Forms without a calculation order are calculated
+	// in the order of the dependencies between the calculations. Formatted values are
+	// returned, but not written into the document.
+	// Experimental API.
+	RunFormActions(request *requests.RunFormActions) (*responses.RunFormActions, error)
+
+	// FlattenAnnotations flattens only the selected form fields (by full name or type) and
+	// annotations (by subtype) into the page content, where FPDFPage_Flatten flattens all of
+	// them. The other annotations and form fields stay interactive. The appearance streams
+	// of the selected annotations are placed on the page as a form object and the annotations
+	// are removed, together with their popups. Annotations without an appearance stream are
+	// removed without being drawn. The fields of which all widgets were flattened are removed
+	// from the form. PDFium can't change the form of a loaded document, so the document is
+	// saved and loaded again for that: pages and other handles of the document are closed,
+	// the document reference stays valid. The flattened fields of encrypted documents and of
+	// documents with an initialized form fill environment stay in the form without widgets,
+	// like FPDFPage_Flatten leaves them. Pages of the document that are loaded in a form fill
+	// environment should be closed first.
+	// Experimental API.
+	FlattenAnnotations(request *requests.FlattenAnnotations) (*responses.FlattenAnnotations, error)
+
+	// EditFormFields creates text fields, checkboxes, radio groups, comboboxes, listboxes and
+	// signature fields, and renames, moves, resizes or deletes existing fields. PDFium can't
+	// create widget annotations through FPDFPage_CreateAnnot, and its annotation setters can't
+	// set the field type, the field flags or the AcroForm fields. So the document is saved
+	// without the incremental flag, the changes are appended to that copy as an incremental
+	// update, and the copy is loaded into the document: pages and other handles of the
+	// document are closed, the document reference stays valid. Documents with a
+	// cross-reference stream get a cross-reference table. The security of an encrypted
+	// document is removed, because the strings of the new fields can't be encrypted. The
+	// appearances of checkboxes and radio buttons are generated, PDFium builds the
+	// appearances of the other fields. Fields can't be edited while a form fill environment
+	// is initialized for the document.
+	EditFormFields(request *requests.EditFormFields) (*responses.EditFormFields, error)
+
+	// AuditFormAccessibility checks the form fields of a document for accessibility. It reports
+	// fields without tooltip (alternate name), widgets without label (alternate text of their
+	// structure element), pages that don't use the structure tab order or with a tab order that
+	// doesn't follow the rows of the page, and widgets outside the crop box. PDFium only loads
+	// the structure elements of the marked content of a page, so the structure elements of the
+	// widgets and the tab order of the pages are read from a copy of the document.
+	// Experimental API.
+	AuditFormAccessibility(request *requests.AuditFormAccessibility) (*responses.AuditFormAccessibility, error)
+
+	// End form_fields
+
+	// Start form_data: form data helpers
+
+	// ExportFormData exports the values of the form fields of a document as FDF or XFDF. Push
+	// buttons, signatures and fields with the NoExport flag are left out. The markup annotations
+	// of the document (text, free text, square, circle, text markup and ink) can be included
+	// in XFDF.
+	// Experimental API.
+	ExportFormData(request *requests.ExportFormData) (*responses.ExportFormData, error)
+
+	// ImportFormData imports FDF or XFDF data into the form fields of a document, the format is
+	// detected when not given. The fields are filled like FillForm does, fields that don't exist
+	// in the document or can't be filled are skipped and returned. The markup annotations in
+	// XFDF data can be added to the document.
+	// Experimental API.
+	ImportFormData(request *requests.ImportFormData) (*responses.ImportFormData, error)
+
+	// End form_data
+
 	// Start text: metadata helpers
 
 	// GetMetaData returns the metadata values of the document.
@@ -0,0 +1,53 @@
+package requests
+
+import (
+	"io"
+
+	"github.com/klippa-app/go-pdfium/references"
+)
+
+type DocumentInput struct {
+	Document       references.FPDF_DOCUMENT // A document that is already opened in this instance.
+	File           *[]byte                  // A reference to the file data.
+	FilePath       *string                  // A path to a PDF file.
+	FileReader     io.ReadSeeker            // A reader of a PDF file, PDFium reads from it while the document is used. In multi-threaded usage, it is fully read into memory.
+	FileReaderSize int64                    // The size of the file in FileReader, required when FileReader is set.
+	Password       *string                  // The password of the document.
+	Pages          *string                  // The pages to use from this document, in the format "1,3,5-7" (1-index based). All pages when nil.
+}
+
+type MergeDocuments struct {
+	Documents   []DocumentInput // The documents to merge, in the order they should appear in the output.
+	Flags       SaveFlags       // The save flags of the output document.
+	FileVersion int             // The PDF file version of the output document. File version: 14 for 1.4, 15 for 1.5, ... When 0, PDFium decides.
+	FilePath    *string         // A path to save the output document to. When not given, the bytes are returned.
+}
+
+type SplitDocumentMode string
+
+const (
+	SplitDocumentModeRanges        SplitDocumentMode = "ranges"         // Split the document into the given page ranges.
+	SplitDocumentModePageCount     SplitDocumentMode = "page_count"     // Split the document into parts of the given page count.
+	SplitDocumentModeBookmarkLevel SplitDocumentMode = "bookmark_level" // Split the document on the bookmarks of the given level.
+	SplitDocumentModeFileSize      SplitDocumentMode = "file_size"      // Split the document into parts with a maximum file size.
+)
+
+type SplitDocument struct {
+	Document       DocumentInput     // The document to split.
+	Mode           SplitDocumentMode // How to split the document.
+	Ranges         []string          // The page ranges for every part in the format "1,3,5-7" (1-index based), can't be combined with Document.Pages. When Mode is SplitDocumentModeRanges.
+	PageCount      int               // The maximum amount of pages per part. When Mode is SplitDocumentModePageCount.
+	BookmarkLevel  int               // The level of the bookmarks to split on, 1 is the top level. Every bookmark starts a new part. When Mode is SplitDocumentModeBookmarkLevel.
+	MaxFileSize    int64             // The maximum file size of every part in bytes, a single page that is bigger than this will still be its own part. When Mode is SplitDocumentModeFileSize.
+	Flags          SaveFlags         // The save flags of the output documents.
+	FileVersion    int               // The PDF file version of the output documents. File version: 14 for 1.4, 15 for 1.5, ... When 0, PDFium decides.
+	FilePathFormat *string           // A path format to save the output documents to, the part number (1-index based) is given as argument, e.g. "/tmp/part-%d.pdf". When not given, the bytes are returned.
+}
+
+type ReorderPages struct {
+	Document    DocumentInput // The document to reorder.
+	Order       []int         // The new order of the pages (0-index based), pages can be repeated or left out.
+	Flags       SaveFlags     // The save flags of the output document.
+	FileVersion int           // The PDF file version of the output document. File version: 14 for 1.4, 15 for 1.5, ... When 0, PDFium decides.
+	FilePath    *string       // A path to save the output document to. When not given, the bytes are returned.
+}
@@ -0,0 +1,72 @@
+package requests
+
+import (
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+type DrawPageOperationType string
+
+const (
+	DrawPageOperationTypePath  DrawPageOperationType = "path"  // Draw a path from segments.
+	DrawPageOperationTypeRect  DrawPageOperationType = "rect"  // Draw a rectangle.
+	DrawPageOperationTypeText  DrawPageOperationType = "text"  // Draw a text run.
+	DrawPageOperationTypeImage DrawPageOperationType = "image" // Draw an image.
+)
+
+type DrawPagePathSegmentType string
+
+const (
+	DrawPagePathSegmentTypeMoveTo   DrawPagePathSegmentType = "move_to"   // Start a new sub path at X, Y.
+	DrawPagePathSegmentTypeLineTo   DrawPagePathSegmentType = "line_to"   // Draw a line to X, Y.
+	DrawPagePathSegmentTypeBezierTo DrawPagePathSegmentType = "bezier_to" // Draw a cubic bezier curve to X, Y with the control points X1, Y1 and X2, Y2.
+	DrawPagePathSegmentTypeClose    DrawPagePathSegmentType = "close"     // Close the current sub path.
+)
+
+type DrawPage struct {
+	Page       Page                // The page to draw on.
+	Operations []DrawPageOperation // The operations to draw, in order. Every operation adds one page object on top of the content of the page.
+}
+
+type DrawPageOperation struct {
+	Type        DrawPageOperationType   // The type of the operation.
+	Matrix      *structs.FPDF_FS_MATRIX // The transformation to apply to the object after it has been created, in page coordinates.
+	FillColor   *structs.FPDF_COLOR     // The fill color of the object. Paths and rectangles are not filled when nil, text is black when nil.
+	StrokeColor *structs.FPDF_COLOR     // The stroke color of paths and rectangles. Not stroked when nil.
+	StrokeWidth float32                 // The stroke width of paths and rectangles in points, defaults to 1.
+	FillMode    enums.FPDF_FILLMODE     // The fill mode of paths and rectangles, defaults to FPDF_FILLMODE_WINDING when FillColor is given.
+	LineJoin    enums.FPDF_LINEJOIN     // The line join of paths and rectangles.
+	LineCap     enums.FPDF_LINECAP      // The line cap of paths and rectangles.
+	Path        []DrawPagePathSegment   // The segments of the path. When Type is DrawPageOperationTypePath, the first segment should be a move.
+	Rect        structs.FPDF_FS_RECTF   // The rectangle to draw in page coordinates. When Type is DrawPageOperationTypeRect.
+	Text        DrawPageText            // The text to draw. When Type is DrawPageOperationTypeText.
+	Image       DrawPageImage           // The image to draw. When Type is DrawPageOperationTypeImage.
+}
+
+type DrawPagePathSegment struct {
+	Type DrawPagePathSegmentType // The type of the segment.
+	X    float32                 // The x coordinate of the end point.
+	Y    float32                 // The y coordinate of the end point.
+	X1   float32                 // The x coordinate of the first control point of a bezier curve.
+	Y1   float32                 // The y coordinate of the first control point of a bezier curve.
+	X2   float32                 // The x coordinate of the second control point of a bezier curve.
+	Y2   float32                 // The y coordinate of the second control point of a bezier curve.
+}
+
+type DrawPageText struct {
+	Text     string          // The text to draw.
+	X        float32         // The x coordinate of the baseline origin of the text.
+	Y        float32         // The y coordinate of the baseline origin of the text.
+	Font     string          // The name of the standard font to use, defaults to Helvetica. Ignored when FontData is given.
+	FontData []byte          // The data of a font to use, needed when the text contains chars that are not supported by the standard fonts.
+	FontType enums.FPDF_FONT // The type of the font in FontData, defaults to TrueType.
+	FontSize float32         // The font size in points, defaults to 12.
+}
+
+type DrawPageImage struct {
+	Data   []byte  // The data of the image, JPEG and PNG images are supported.
+	X      float32 // The x coordinate of the bottom left corner of the image.
+	Y      float32 // The y coordinate of the bottom left corner of the image.
+	Width  float32 // The width of the image in points. When 0, it is calculated from the height, or the size in pixels when both are 0.
+	Height float32 // The height of the image in points. When 0, it is calculated from the width, or the size in pixels when both are 0.
+}
@@ -0,0 +1,45 @@
+package requests
+
+import (
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+type CreateFormFieldWidget struct {
+	Page        int                   // The page to place the widget on (0-index based).
+	Rect        structs.FPDF_FS_RECTF // The rectangle of the widget in page coordinates.
+	ExportValue string                // The export value of a checkbox or radio button widget. Defaults to "Yes" for checkboxes, required for radio buttons.
+}
+
+type CreateFormField struct {
+	Name          string                    // The full name of the field, parent fields that don't exist are created, e.g. "address.street".
+	Type          enums.FPDF_FORMFIELD_TYPE // The type of the field: text field, checkbox, radio button, combobox, listbox or signature.
+	Widgets       []CreateFormFieldWidget   // The widgets of the field, radio buttons have a widget per option.
+	Value         string                    // The initial value. For checkboxes and radio buttons the export value of the checked widget.
+	Options       []string                  // The options of a combobox or listbox.
+	Flags         enums.FPDF_FORMFLAG       // The field flags, the flags that are specific to a field type can be given by their bit value.
+	AlternateName string                    // The alternate name of the field, shown as tooltip.
+	FontSize      float32                   // The font size of a text field, combobox or listbox. When 0, the text is sized automatically.
+	MaxLength     int                       // The maximum length of the value of a text field. When 0, there is no maximum.
+}
+
+type EditFormField struct {
+	Name    string                 // The full name of the field to edit.
+	NewName string                 // The new full name of the field, fields can only be renamed within their parent. Not renamed when empty.
+	Rect    *structs.FPDF_FS_RECTF // The new rectangle of the widget in page coordinates, to move or resize the widget. Not changed when nil.
+	Widget  int                    // The index of the widget of the field to change the rectangle of.
+	Delete  bool                   // Whether to delete the field with its kids and widgets.
+}
+
+// EditFormFields creates and edits the form fields of the loaded document.
+// PDFium can't create the fields through FPDFPage_CreateAnnot: it doesn't
+// create widget annotations, and the annotation setters only write strings,
+// while /FT and /Ff are a name and a number, and parent fields and the
+// AcroForm /Fields aren't annotations. The fields are written to a saved copy
+// of the document instead, which is loaded again.
+type EditFormFields struct {
+	Document references.FPDF_DOCUMENT
+	Create   []CreateFormField // The fields to create, after the edits are applied.
+	Edit     []EditFormField   // The fields to edit, in the given order.
+}
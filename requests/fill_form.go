@@ -0,0 +1,13 @@
+package requests
+
+import "github.com/klippa-app/go-pdfium/references"
+
+type FillForm struct {
+	Document     references.FPDF_DOCUMENT
+	Values       map[string]interface{} // The values to fill by the full name of the field. Text fields accept strings, numbers and bools, checkboxes accept bools or an export value, radio buttons accept an export value, comboboxes accept an option label and listboxes accept an option label or a []string of option labels. Nil clears a text or choice field and unchecks a checkbox.
+	Flatten      bool                   // Whether to flatten the form fields into the page content after filling.
+	FlattenUsage FPDFPage_FlattenUsage  // The usage flag for the flattening.
+	Flags        SaveFlags              // The save flags of the output document.
+	FileVersion  int                    // The PDF file version of the output document. File version: 14 for 1.4, 15 for 1.5, ... When 0, the file version of the document is kept.
+	FilePath     *string                // A path to save the output document to. When not given, the bytes are returned.
+}
@@ -0,0 +1,15 @@
+package requests
+
+import (
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+)
+
+type FlattenAnnotations struct {
+	Document   references.FPDF_DOCUMENT
+	Pages      []int                           // The pages to flatten the annotations of (0-index based). All pages when nil.
+	FieldNames []string                        // The full names of the form fields to flatten.
+	FieldTypes []enums.FPDF_FORMFIELD_TYPE     // The types of the form fields to flatten.
+	Subtypes   []enums.FPDF_ANNOTATION_SUBTYPE // The subtypes of the annotations to flatten. FPDF_ANNOT_SUBTYPE_WIDGET flattens all form fields.
+	Usage      FPDFPage_FlattenUsage           // The usage flag for the flattening.
+}
@@ -0,0 +1,9 @@
+package requests
+
+import (
+	"github.com/klippa-app/go-pdfium/references"
+)
+
+type AuditFormAccessibility struct {
+	Document references.FPDF_DOCUMENT
+}
@@ -0,0 +1,8 @@
+package requests
+
+import "github.com/klippa-app/go-pdfium/references"
+
+type RunFormActions struct {
+	Document references.FPDF_DOCUMENT
+	Values   map[string]interface{} // The values to fill before running the actions, accepted like FillForm does. Can be empty to run the actions on the current values.
+}
@@ -0,0 +1,24 @@
+package requests
+
+import "github.com/klippa-app/go-pdfium/references"
+
+type FormDataFormat string
+
+const (
+	FormDataFormatFDF  FormDataFormat = "fdf"  // Forms Data Format.
+	FormDataFormatXFDF FormDataFormat = "xfdf" // XML Forms Data Format.
+)
+
+type ExportFormData struct {
+	Document           references.FPDF_DOCUMENT
+	Format             FormDataFormat // The format to export the form data in.
+	IncludeAnnotations bool           // Whether to export the markup annotations of the document. Only supported for XFDF.
+	FileName           string         // The file name of the PDF document the data belongs to, written into the data when given.
+}
+
+type ImportFormData struct {
+	Document          references.FPDF_DOCUMENT
+	Data              []byte         // The FDF or XFDF data to import.
+	Format            FormDataFormat // The format of the data. Detected from the data when empty.
+	ImportAnnotations bool           // Whether to add the annotations in XFDF data to the document.
+}
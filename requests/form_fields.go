@@ -0,0 +1,7 @@
+package requests
+
+import "github.com/klippa-app/go-pdfium/references"
+
+type GetFormFields struct {
+	Document references.FPDF_DOCUMENT
+}
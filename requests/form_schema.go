@@ -0,0 +1,8 @@
+package requests
+
+import "github.com/klippa-app/go-pdfium/references"
+
+type GetFormSchema struct {
+	Document references.FPDF_DOCUMENT
+	Title    string // The title of the schema, left out when empty.
+}
@@ -0,0 +1,37 @@
+package requests
+
+import (
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// AddHeaderFooter adds headers and footers to every page of one or more
+// documents. The texts are templates that can contain the following variables:
+//   - {prefix}: the value of Prefix.
+//   - {suffix}: the value of Suffix.
+//   - {number}: the running number, it starts at StartNumber and continues over the documents (for example for Bates numbering).
+//   - {page}: the page number within the document (1-index based).
+//   - {total}: the amount of pages in the document.
+//   - {document}: the number of the document (1-index based).
+//
+// Number variables can be padded with zeros with a width, for example {number:06}.
+type AddHeaderFooter struct {
+	Documents    []references.FPDF_DOCUMENT // The documents to add the headers and footers to, the running number continues over the documents in the given order.
+	HeaderLeft   string                     // The template of the text in the top left corner.
+	HeaderCenter string                     // The template of the text in the top center.
+	HeaderRight  string                     // The template of the text in the top right corner.
+	FooterLeft   string                     // The template of the text in the bottom left corner.
+	FooterCenter string                     // The template of the text in the bottom center.
+	FooterRight  string                     // The template of the text in the bottom right corner.
+	Prefix       string                     // The value of the {prefix} variable.
+	Suffix       string                     // The value of the {suffix} variable.
+	StartNumber  int                        // The value of the {number} variable on the first page.
+	MarginX      float32                    // The space between the left and the right side of the page and the texts in points.
+	MarginY      float32                    // The space between the top and the bottom of the page and the texts in points.
+	Font         string                     // The name of the standard font to use, defaults to Helvetica. Ignored when FontData is given.
+	FontData     []byte                     // The data of a font to use, needed when the text contains chars that are not supported by the standard fonts.
+	FontType     enums.FPDF_FONT            // The type of the font in FontData, defaults to TrueType.
+	FontSize     float32                    // The font size in points, defaults to 10.
+	Color        *structs.FPDF_COLOR        // The color of the texts, defaults to black. The alpha channel is ignored.
+}
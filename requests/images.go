@@ -0,0 +1,26 @@
+package requests
+
+import "github.com/klippa-app/go-pdfium/references"
+
+type ImageExportFormat string
+
+const (
+	ImageExportFormatNone   ImageExportFormat = ""       // Don't export the images, this is the default.
+	ImageExportFormatNative ImageExportFormat = "native" // Export JPEG and JPEG 2000 images as they are stored in the PDF, other images as PNG.
+	ImageExportFormatPNG    ImageExportFormat = "png"    // Export all images as PNG.
+)
+
+type GetPageImages struct {
+	Page               Page
+	IncludeRawData     bool              // Whether to return the raw data of the images, the data as stored in the PDF.
+	IncludeDecodedData bool              // Whether to return the decoded data of the images, the data after applying the filters.
+	ExportFormat       ImageExportFormat // The format to export the images in.
+}
+
+type GetDocumentImages struct {
+	Document           references.FPDF_DOCUMENT
+	Pages              []int             // The pages to get the images of (0-index based). All pages when nil.
+	IncludeRawData     bool              // Whether to return the raw data of the images, the data as stored in the PDF.
+	IncludeDecodedData bool              // Whether to return the decoded data of the images, the data after applying the filters.
+	ExportFormat       ImageExportFormat // The format to export the images in.
+}
@@ -0,0 +1,37 @@
+package requests
+
+import "github.com/klippa-app/go-pdfium/references"
+
+type ImposeNUp struct {
+	Document       references.FPDF_DOCUMENT // The source document.
+	SheetWidth     float32                  // The width of the output sheets in points.
+	SheetHeight    float32                  // The height of the output sheets in points.
+	Columns        int                      // The amount of pages next to each other on a sheet.
+	Rows           int                      // The amount of pages below each other on a sheet.
+	Margin         float32                  // The space between the edge of the sheet and the pages in points.
+	Gutter         float32                  // The space between the pages in points.
+	CropMarks      bool                     // Whether to draw crop marks at the corners of every page.
+	CropMarkLength float32                  // The length of the crop marks in points. When 0, 10 points is used.
+}
+
+type ImposeBooklet struct {
+	Document       references.FPDF_DOCUMENT // The source document, blank pages are added when the page count is not a multiple of 4.
+	SheetWidth     float32                  // The width of the output sheets in points, two pages are placed next to each other.
+	SheetHeight    float32                  // The height of the output sheets in points.
+	Margin         float32                  // The space between the edge of the sheet and the pages in points.
+	Gutter         float32                  // The space between the two pages at the spine in points.
+	CropMarks      bool                     // Whether to draw crop marks at the corners of every page.
+	CropMarkLength float32                  // The length of the crop marks in points. When 0, 10 points is used.
+}
+
+type ImposeTiles struct {
+	Document       references.FPDF_DOCUMENT // The source document.
+	Pages          []int                    // The pages to tile (0-index based). All pages when nil.
+	SheetWidth     float32                  // The width of the output sheets in points.
+	SheetHeight    float32                  // The height of the output sheets in points.
+	Scale          float32                  // The scale of the source page, 2 makes the page twice as big. When 0, 1 is used.
+	Overlap        float32                  // The amount of points that the tiles overlap, to make gluing the tiles together easier.
+	Margin         float32                  // The space between the edge of the sheet and the tile in points, content in the margin is masked.
+	CropMarks      bool                     // Whether to draw crop marks at the corners of every tile.
+	CropMarkLength float32                  // The length of the crop marks in points. When 0, 10 points is used.
+}
@@ -0,0 +1,16 @@
+package requests
+
+import "github.com/klippa-app/go-pdfium/references"
+
+type GetDocumentMarkup struct {
+	Document references.FPDF_DOCUMENT
+	Format   GetDocumentMarkupFormat // The format to convert the document to, defaults to Markdown.
+	Pages    []int                   // The pages to convert (0-index based). When empty, all pages are converted.
+}
+
+type GetDocumentMarkupFormat string
+
+const (
+	GetDocumentMarkupFormatMarkdown GetDocumentMarkupFormat = "markdown" // Convert the document into Markdown.
+	GetDocumentMarkupFormatHTML     GetDocumentMarkupFormat = "html"     // Convert the document into simple HTML.
+)
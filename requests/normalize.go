@@ -0,0 +1,31 @@
+package requests
+
+import "github.com/klippa-app/go-pdfium/references"
+
+type NormalizePagesSize string
+
+const (
+	NormalizePagesSizeKeep   NormalizePagesSize = ""       // Keep the size of the pages (after cropping), this is the default.
+	NormalizePagesSizeA4     NormalizePagesSize = "a4"     // A4 (595.28 x 841.89 points).
+	NormalizePagesSizeLetter NormalizePagesSize = "letter" // US Letter (612 x 792 points).
+	NormalizePagesSizeCustom NormalizePagesSize = "custom" // The size in Width and Height.
+)
+
+type NormalizePagesScaleMode string
+
+const (
+	NormalizePagesScaleModeFit  NormalizePagesScaleMode = "fit"  // Scale the content so that it fits entirely on the page, this is the default.
+	NormalizePagesScaleModeFill NormalizePagesScaleMode = "fill" // Scale the content so that it fills the page entirely, content that falls outside of the page is cut off.
+)
+
+type NormalizePages struct {
+	Document          references.FPDF_DOCUMENT
+	Pages             []int                   // The pages to normalize (0-index based). All pages when nil.
+	AutoCrop          bool                    // Whether to crop the pages to the bounds of their content.
+	NormalizeRotation bool                    // Whether to apply the rotation of the pages to their content, so that the pages look the same without rotation.
+	Size              NormalizePagesSize      // The size to scale the pages to. The A4 and Letter sizes are turned to landscape for landscape content.
+	Width             float32                 // The width of the pages in points as displayed. When Size is NormalizePagesSizeCustom.
+	Height            float32                 // The height of the pages in points as displayed. When Size is NormalizePagesSizeCustom.
+	ScaleMode         NormalizePagesScaleMode // How to scale the content to the size of the pages.
+	Margin            float32                 // The margin to add around the content in points. When a size is given, the margin is within that size.
+}
@@ -0,0 +1,11 @@
+package requests
+
+import "github.com/klippa-app/go-pdfium/references"
+
+type OptimizeImages struct {
+	Document           references.FPDF_DOCUMENT
+	Pages              []int   // The pages to optimize the images of (0-index based). All pages when nil.
+	TargetDPI          float32 // Images with a higher resolution are downsampled to this resolution. When 0, images are not downsampled.
+	Quality            int     // The JPEG quality to recompress the images with, between 1 and 100. Defaults to 75.
+	ConvertToGrayscale bool    // Whether to store images that only contain gray pixels as grayscale images.
+}
@@ -0,0 +1,28 @@
+package requests
+
+import (
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+type RedactDocumentArea struct {
+	Page int                   // The page of the area (0-index based).
+	Rect structs.FPDF_FS_RECTF // The area to redact in page coordinates (points), like the positions of the text helpers.
+}
+
+type RedactDocumentSearch struct {
+	Query          string      // The text to search for, every match is redacted.
+	MatchCase      bool        // Whether to match the case of the query.
+	MatchWholeWord bool        // Whether to only match whole words.
+	TextOptions    TextOptions // Options to clean up the text before searching.
+}
+
+type RedactDocument struct {
+	Document               references.FPDF_DOCUMENT
+	Pages                  []int                  // The pages to apply the searches and the redact annotations on (0-index based). All pages when nil. Areas are always redacted.
+	Areas                  []RedactDocumentArea   // The areas to redact.
+	Searches               []RedactDocumentSearch // The texts to search for and redact.
+	ApplyRedactAnnotations bool                   // Whether to redact the areas of the redact annotations in the pages, the redact annotations are removed afterwards.
+	DrawBoxes              bool                   // Whether to draw boxes over the redacted areas.
+	BoxColor               *structs.FPDF_COLOR    // The color of the boxes, defaults to black. The alpha channel is ignored.
+}
@@ -0,0 +1,82 @@
+package requests
+
+import (
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+type StampDocumentType string
+
+const (
+	StampDocumentTypeText  StampDocumentType = "text"  // Stamp a text.
+	StampDocumentTypeImage StampDocumentType = "image" // Stamp an image.
+	StampDocumentTypePage  StampDocumentType = "page"  // Stamp a page of another PDF.
+)
+
+type StampDocumentPageBox string
+
+const (
+	StampDocumentPageBoxCrop  StampDocumentPageBox = "crop"  // Position the stamp relative to the crop box of the page, this is the default.
+	StampDocumentPageBoxMedia StampDocumentPageBox = "media" // Position the stamp relative to the media box of the page.
+)
+
+type StampDocumentPosition string
+
+const (
+	StampDocumentPositionCenter      StampDocumentPosition = "center" // This is the default.
+	StampDocumentPositionTopLeft     StampDocumentPosition = "top_left"
+	StampDocumentPositionTop         StampDocumentPosition = "top"
+	StampDocumentPositionTopRight    StampDocumentPosition = "top_right"
+	StampDocumentPositionLeft        StampDocumentPosition = "left"
+	StampDocumentPositionRight       StampDocumentPosition = "right"
+	StampDocumentPositionBottomLeft  StampDocumentPosition = "bottom_left"
+	StampDocumentPositionBottom      StampDocumentPosition = "bottom"
+	StampDocumentPositionBottomRight StampDocumentPosition = "bottom_right"
+)
+
+type StampDocumentPlacement string
+
+const (
+	StampDocumentPlacementContent    StampDocumentPlacement = "content"    // Add the stamp to the content of the page, this is the default.
+	StampDocumentPlacementAnnotation StampDocumentPlacement = "annotation" // Add the stamp as a printable, locked watermark annotation, so that it can be removed later. PDFium can't create watermark annotations, so the stamped document is returned as a copy and the loaded document is not changed. Not supported for encrypted documents.
+)
+
+type StampDocument struct {
+	Document    references.FPDF_DOCUMENT // The document to stamp.
+	Pages       []int                    // The pages to stamp (0-index based). All pages when nil.
+	Type        StampDocumentType        // The type of the stamp.
+	Box         StampDocumentPageBox     // The page box to position the stamp in.
+	Position    StampDocumentPosition    // The position of the stamp in the page box.
+	OffsetX     float32                  // Moves the stamp to the right in points, negative values move it to the left.
+	OffsetY     float32                  // Moves the stamp up in points, negative values move it down.
+	Rotation    float32                  // The rotation of the stamp in degrees counter-clockwise around its center.
+	Opacity     *float32                 // The opacity of the stamp between 0 and 1. Fully opaque when nil. Not supported for page stamps.
+	Placement   StampDocumentPlacement   // How to add the stamp to the page.
+	Text        StampDocumentText        // The text to stamp. When Type is StampDocumentTypeText.
+	Image       StampDocumentImage       // The image to stamp. When Type is StampDocumentTypeImage.
+	SourcePage  StampDocumentSourcePage  // The page to stamp. When Type is StampDocumentTypePage.
+	FileVersion int                      // The PDF file version of the stamped copy. File version: 14 for 1.4, 15 for 1.5, ... When 0, PDFium decides. When Placement is StampDocumentPlacementAnnotation.
+	FilePath    *string                  // A path to save the stamped copy to. When not given, the bytes are returned. When Placement is StampDocumentPlacementAnnotation.
+}
+
+type StampDocumentText struct {
+	Text     string             // The text of the stamp.
+	Font     string             // The name of the standard font to use, defaults to Helvetica. Ignored when FontData is given.
+	FontData []byte             // The data of a font to use, needed when the text contains chars that are not supported by the standard fonts.
+	FontType enums.FPDF_FONT    // The type of the font in FontData, defaults to TrueType.
+	FontSize float32            // The font size in points, defaults to 48.
+	Color    structs.FPDF_COLOR // The color of the text. The alpha channel is ignored, use Opacity instead.
+}
+
+type StampDocumentImage struct {
+	Data   []byte  // The data of the image, JPEG and PNG images are supported.
+	Width  float32 // The width of the image in points. When 0, it is calculated from the height, or the size in pixels when both are 0.
+	Height float32 // The height of the image in points. When 0, it is calculated from the width, or the size in pixels when both are 0.
+}
+
+type StampDocumentSourcePage struct {
+	Document DocumentInput // The document that contains the page to stamp.
+	Index    int           // The index of the page to stamp (0-index based).
+	Scale    float32       // The scale of the page, defaults to 1.
+}
@@ -3,14 +3,17 @@ package requests
 import "github.com/klippa-app/go-pdfium/references"
 
 type GetPageText struct {
-	Page Page
+	Page        Page
+	TextOptions TextOptions // Options to clean up the extracted text.
 }
 
 type GetPageTextStructured struct {
-	Page                   Page
-	Mode                   GetPageTextStructuredMode           // The mode to get structured text for.
-	CollectFontInformation bool                                // Whether to collect font information like name/size/weight.
-	PixelPositions         GetPageTextStructuredPixelPositions // Pixel position calculation settings.
+	Page                        Page
+	Mode                        GetPageTextStructuredMode           // The mode to get structured text for.
+	CollectFontInformation      bool                                // Whether to collect font information like name/size/weight.
+	CollectDirectionInformation bool                                // Whether to detect the writing direction of chars and rects. Lines and words always contain the direction.
+	PixelPositions              GetPageTextStructuredPixelPositions // Pixel position calculation settings.
+	TextOptions                 TextOptions                         // Options to clean up the extracted text. Generated chars can only be removed from chars, not from rects.
 }
 
 type GetPageTextStructuredMode string
@@ -19,6 +22,8 @@ const (
 	GetPageTextStructuredModeChars GetPageTextStructuredMode = "char" // Only get every separate char
 	GetPageTextStructuredModeRects GetPageTextStructuredMode = "rect" // Get char rects, strings on the same line with the same font settings.
 	GetPageTextStructuredModeBoth  GetPageTextStructuredMode = "both" // Get both rects and chars.
+	GetPageTextStructuredModeWords GetPageTextStructuredMode = "word" // Get the words of the page in logical (reading) order, right-to-left and vertical text is taken into account.
+	GetPageTextStructuredModeLines GetPageTextStructuredMode = "line" // Get the lines of the page with their words in logical (reading) order, right-to-left and vertical text is taken into account.
 )
 
 type GetPageTextStructuredPixelPositions struct {
@@ -28,3 +33,35 @@ type GetPageTextStructuredPixelPositions struct {
 	Width     int  // If rendered with a specific resolution, give the width resolution. Useful if you used RenderPageInPixels.
 	Height    int  // If rendered with a specific resolution, give the height resolution. Useful if you used RenderPageInPixels.
 }
+
+type TextOptions struct {
+	ExpandLigatures      bool                  // Expand ligature glyphs like "ﬁ" into separate letters.
+	JoinHyphenatedWords  bool                  // Join words that are hyphenated over a line break and remove soft hyphens.
+	RemoveGeneratedChars bool                  // Remove the chars that were generated by PDFium, like spaces and line breaks. Only supported when compiled with experimental support.
+	Normalization        TextNormalizationForm // The Unicode normalization form to apply to the text. Defaults to no normalization.
+}
+
+type TextNormalizationForm string
+
+const (
+	TextNormalizationFormNone TextNormalizationForm = ""     // Don't normalize the text.
+	TextNormalizationFormNFC  TextNormalizationForm = "NFC"  // Canonical decomposition followed by canonical composition.
+	TextNormalizationFormNFD  TextNormalizationForm = "NFD"  // Canonical decomposition.
+	TextNormalizationFormNFKC TextNormalizationForm = "NFKC" // Compatibility decomposition followed by canonical composition.
+	TextNormalizationFormNFKD TextNormalizationForm = "NFKD" // Compatibility decomposition.
+)
+
+type SearchPageText struct {
+	Page           Page
+	Query          string      // The text to search for, the text options are also applied to the query.
+	MatchCase      bool        // Whether to match the case of the query.
+	MatchWholeWord bool        // Whether to only match whole words.
+	TextOptions    TextOptions // Options to clean up the text before searching.
+}
+
+// GetPageTextQuality analyzes the text layer of a page. Chars with a unicode
+// map error of their font are only detected when compiled with experimental
+// support, see UnicodeMapErrorsChecked in the response.
+type GetPageTextQuality struct {
+	Page Page
+}
@@ -0,0 +1,26 @@
+package requests
+
+import "github.com/klippa-app/go-pdfium/enums"
+
+type AddPageTextLayer struct {
+	Page           Page
+	Words          []AddPageTextLayerWord         // The words to add to the page, for example the result of an OCR engine.
+	PixelPositions AddPageTextLayerPixelPositions // When the positions of the words are pixel positions in a rendered image of the page.
+	Font           string                         // The name of the standard font to use, defaults to Helvetica. Ignored when FontData is given.
+	FontData       []byte                         // The data of a font to use, needed when the text contains chars that are not supported by the standard fonts.
+	FontType       enums.FPDF_FONT                // The type of the font in FontData, defaults to TrueType.
+}
+
+type AddPageTextLayerWord struct {
+	Text   string  // The text of the word.
+	Left   float64 // The position of the left side of the word.
+	Top    float64 // The position of the top side of the word.
+	Right  float64 // The position of the right side of the word.
+	Bottom float64 // The position of the bottom side of the word.
+}
+
+type AddPageTextLayerPixelPositions struct {
+	Calculate bool // Whether the positions of the words are pixel positions with the origin in the top left corner. When false, the positions are in points in the PDF coordinate system.
+	Width     int  // The width of the image that the positions are in. Useful if you used RenderPageInPixels or RenderPageInDPI before running OCR.
+	Height    int  // The height of the image that the positions are in. Useful if you used RenderPageInPixels or RenderPageInDPI before running OCR.
+}
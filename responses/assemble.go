@@ -0,0 +1,24 @@
+package responses
+
+type MergeDocuments struct {
+	FileBytes *[]byte // The byte array if no path was given.
+	FilePath  *string // The path the document was saved to.
+	PageCount int     // The amount of pages in the output document.
+}
+
+type SplitDocumentPart struct {
+	FileBytes *[]byte // The byte array if no path format was given.
+	FilePath  *string // The path the part was saved to.
+	Pages     []int   // The pages of the original document in this part (0-index based).
+	Title     string  // The title of the bookmark that started this part. When Mode is SplitDocumentModeBookmarkLevel.
+}
+
+type SplitDocument struct {
+	Parts []SplitDocumentPart // The parts of the split document.
+}
+
+type ReorderPages struct {
+	FileBytes *[]byte // The byte array if no path was given.
+	FilePath  *string // The path the document was saved to.
+	PageCount int     // The amount of pages in the output document.
+}
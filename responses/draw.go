@@ -0,0 +1,10 @@
+package responses
+
+import (
+	"github.com/klippa-app/go-pdfium/references"
+)
+
+type DrawPage struct {
+	Page        int                          // The page that was drawn on (0-index based), -1 when unknown.
+	PageObjects []references.FPDF_PAGEOBJECT // The page objects that were added, in the order of the operations.
+}
@@ -0,0 +1,3 @@
+package responses
+
+type EditFormFields struct{}
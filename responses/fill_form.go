@@ -0,0 +1,12 @@
+package responses
+
+type FillFormField struct {
+	Name  string // The full name of the field.
+	Value string // The value of the field after filling, as PDFium reads it back.
+}
+
+type FillForm struct {
+	Fields    []FillFormField // The fields that were filled, in the order of the fields in the document.
+	FileBytes *[]byte         // The byte array if no path was given.
+	FilePath  *string         // The path the document was saved to.
+}
@@ -0,0 +1,7 @@
+package responses
+
+type FlattenAnnotations struct {
+	Fields        []string // The full names of the form fields that were flattened, in the order of the fields in the document.
+	Annotations   int      // The amount of annotations that were flattened, including the widgets of the form fields.
+	RemovedFields []string // The full names of the flattened form fields of which all widgets were flattened, which were removed from the form.
+}
@@ -0,0 +1,31 @@
+package responses
+
+type FormAccessibilityIssueType string
+
+const (
+	FormAccessibilityIssueTypeUntagged       FormAccessibilityIssueType = "untagged"         // The document has no structure tree, so fields can't have labels.
+	FormAccessibilityIssueTypeMissingTooltip FormAccessibilityIssueType = "missing_tooltip"  // The field has no alternate name, which is used as tooltip and read by screen readers.
+	FormAccessibilityIssueTypeMissingLabel   FormAccessibilityIssueType = "missing_label"    // The widget is not in the structure tree, or its structure element has no alternate text.
+	FormAccessibilityIssueTypeTabOrder       FormAccessibilityIssueType = "tab_order"        // The page doesn't use the structure tab order, or the tab order doesn't follow the layout of the page.
+	FormAccessibilityIssueTypeOutsideCropBox FormAccessibilityIssueType = "outside_crop_box" // The widget is (partly) outside the crop box of the page, so it's not visible.
+)
+
+type FormAccessibilityIssue struct {
+	Type    FormAccessibilityIssueType // The type of the issue.
+	Name    string                     // The full name of the field, empty for issues of a page or the document.
+	Page    int                        // The page of the issue (0-index based), -1 for issues of the document.
+	Widget  int                        // The index of the widget annotation in the annotations of the page, -1 for issues of a field, a page or the document.
+	Message string                     // A description of the issue.
+}
+
+type FormAccessibilityPage struct {
+	Page     int      // The page (0-index based).
+	TabOrder string   // The tab order of the page: S for the structure order, R for row order, C for column order, empty when the annotation order is used.
+	Fields   []string // The full names of the fields of the widgets on the page, in tab order.
+}
+
+type AuditFormAccessibility struct {
+	Tagged bool                     // Whether the document has a structure tree.
+	Pages  []FormAccessibilityPage  // The pages with form field widgets.
+	Issues []FormAccessibilityIssue // The issues that were found, ordered by page.
+}
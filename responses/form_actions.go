@@ -0,0 +1,22 @@
+package responses
+
+import "github.com/klippa-app/go-pdfium/enums"
+
+type FormActionsField struct {
+	Name           string // The full name of the field.
+	Value          string // The value of the field after running the actions.
+	FormattedValue string // The value as the format action formats it for display, the value itself when the field has no format action.
+	Calculated     bool   // Whether the value was calculated by the calculate action of the field.
+}
+
+type FormActionsMessage struct {
+	Name    string                   // The full name of the field.
+	Event   enums.FPDF_ANNOT_AACTION // The event of the action.
+	Message string                   // Why the value was rejected or why the action was skipped.
+}
+
+type RunFormActions struct {
+	Fields           []FormActionsField   // The fields with a keystroke, format, validate or calculate action, in the order of the fields in the document.
+	ValidationErrors []FormActionsMessage // The values that were rejected by a keystroke or validate action.
+	SkippedActions   []FormActionsMessage // The actions that could not be run, like custom JavaScript.
+}
@@ -0,0 +1,11 @@
+package responses
+
+type ExportFormData struct {
+	Data []byte // The exported form data.
+}
+
+type ImportFormData struct {
+	Fields        []FillFormField // The fields that were filled, in the order of the fields in the document.
+	SkippedFields []string        // The full names of the fields in the data that don't exist in the document or could not be filled, for example because they are read only.
+	Annotations   int             // The amount of annotations that were added to the document.
+}
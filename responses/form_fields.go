@@ -0,0 +1,40 @@
+package responses
+
+import (
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+type FormFieldOption struct {
+	Label    string // The label of the option.
+	Selected bool   // Whether the option is selected.
+}
+
+type FormFieldWidget struct {
+	Page         int                   // The page the widget is on (0-index based).
+	Index        int                   // The index of the widget annotation in the annotations of the page.
+	ControlIndex int                   // The index of the widget in the widgets of the field, -1 when unknown.
+	Rect         structs.FPDF_FS_RECTF // The rect of the widget in page coordinates.
+	ExportValue  string                // The export value of a checkbox or radio button widget.
+	IsChecked    bool                  // Whether a checkbox or radio button widget is checked.
+}
+
+type FormField struct {
+	Name          string                    // The full name of the field.
+	AlternateName string                    // The alternate name of the field, used as tooltip.
+	Type          enums.FPDF_FORMFIELD_TYPE // The type of the field.
+	Flags         enums.FPDF_FORMFLAG       // The field flags (Ff) of the field.
+	Value         string                    // The value of the field. For checkboxes and radio buttons, the export value of the checked widget, or Off.
+	DefaultValue  *string                   // The default value of the field. Nil when the first widget has no default value, PDFium doesn't expose the default value of parent fields.
+	ExportValues  []string                  // The export values of the widgets of a checkbox or radio button field.
+	Options       []FormFieldOption         // The options of a combobox or listbox field.
+	MaxLength     int                       // The maximum length of the value of a text field, 0 when not limited. Only read from the first widget.
+	FontSize      float32                   // The font size of the field, 0 means auto sized.
+	Page          int                       // The page of the first widget of the field (0-index based).
+	Rect          structs.FPDF_FS_RECTF     // The rect of the first widget of the field.
+	Widgets       []FormFieldWidget         // The widgets of the field.
+}
+
+type GetFormFields struct {
+	Fields []FormField // The fields of the document, in the order of their first widget.
+}
@@ -0,0 +1,20 @@
+package responses
+
+import (
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+type FormSchemaField struct {
+	Name     string                    // The full name of the field, this is the name of the property in the schema and the key of the value in FillForm.
+	Type     enums.FPDF_FORMFIELD_TYPE // The type of the field.
+	TabIndex int                       // The position of the field in the tab order of the document.
+	Page     int                       // The page of the first widget of the field (0-index based).
+	Rect     structs.FPDF_FS_RECTF     // The rect of the first widget of the field in page coordinates.
+	Widgets  []FormFieldWidget         // The widgets of the field.
+}
+
+type GetFormSchema struct {
+	Schema []byte            // The JSON Schema (draft 2020-12) of the values of the form, the values are accepted by FillForm.
+	Fields []FormSchemaField // The layout of the fields in the schema, in tab order.
+}
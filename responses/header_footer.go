@@ -0,0 +1,11 @@
+package responses
+
+type AddHeaderFooterDocument struct {
+	FirstNumber int // The running number of the first page of the document.
+	LastNumber  int // The running number of the last page of the document.
+}
+
+type AddHeaderFooter struct {
+	Documents  []AddHeaderFooterDocument // The numbers of every document, in the order of the request.
+	NextNumber int                       // The running number after the last page, useful to continue the numbering in another call.
+}
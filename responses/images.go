@@ -0,0 +1,34 @@
+package responses
+
+import (
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+type ImageExportFormat string
+
+const (
+	ImageExportFormatJPEG ImageExportFormat = "jpeg" // The image is exported as JPEG.
+	ImageExportFormatJPX  ImageExportFormat = "jpx"  // The image is exported as JPEG 2000.
+	ImageExportFormatPNG  ImageExportFormat = "png"  // The image is exported as PNG.
+)
+
+type GetPageImagesImage struct {
+	ObjectPath   []int                          // The index of the image object in the page, followed by the indexes in the form objects it is nested in.
+	Filters      []string                       // The filters of the image, in the order they need to be applied.
+	Metadata     structs.FPDF_IMAGEOBJ_METADATA // The metadata of the image, like the size in pixels, the DPI and the colorspace.
+	Rect         structs.FPDF_FS_RECTF          // The position of the image on the page in points. Without experimental support, images in form objects get the position of the outer form object.
+	Matrix       *structs.FPDF_FS_MATRIX        // The matrix that places the image on the page. Only supported when compiled with experimental support.
+	RawData      []byte                         // The raw data of the image. When IncludeRawData is set.
+	DecodedData  []byte                         // The decoded data of the image. When IncludeDecodedData is set.
+	ExportData   []byte                         // The exported image. When ExportFormat is set.
+	ExportFormat ImageExportFormat              // The format of ExportData.
+}
+
+type GetPageImages struct {
+	Page   int                  // The page the images are on (0-index based).
+	Images []GetPageImagesImage // The images on the page, in the order of the page objects.
+}
+
+type GetDocumentImages struct {
+	Pages []GetPageImages // The images per page.
+}
@@ -0,0 +1,25 @@
+package responses
+
+import "github.com/klippa-app/go-pdfium/references"
+
+type ImposeNUp struct {
+	Document  references.FPDF_DOCUMENT // The new document, it should be closed when it's not needed anymore.
+	PageCount int                      // The amount of sheets in the new document.
+}
+
+type ImposeBooklet struct {
+	Document  references.FPDF_DOCUMENT // The new document, it should be closed when it's not needed anymore. The sheets are in print order, front and back after each other.
+	PageCount int                      // The amount of sheet sides in the new document.
+}
+
+type ImposeTilesTile struct {
+	Page   int // The page of the source document (0-index based).
+	Column int // The column of the tile (0-index based), from left to right.
+	Row    int // The row of the tile (0-index based), from top to bottom.
+}
+
+type ImposeTiles struct {
+	Document  references.FPDF_DOCUMENT // The new document, it should be closed when it's not needed anymore.
+	PageCount int                      // The amount of sheets in the new document.
+	Tiles     []ImposeTilesTile        // The tile of every sheet in the new document.
+}
@@ -0,0 +1,11 @@
+package responses
+
+type GetDocumentMarkupPage struct {
+	Page    int    // The page this markup came from (0-index based).
+	Content string // The markup of this page.
+}
+
+type GetDocumentMarkup struct {
+	Content string                  // The markup of all the requested pages.
+	Pages   []GetDocumentMarkupPage // The markup of every separate page.
+}
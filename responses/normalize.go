@@ -0,0 +1,11 @@
+package responses
+
+type NormalizePagesPage struct {
+	Page   int     // The page that was normalized (0-index based).
+	Width  float64 // The width of the page in points as displayed.
+	Height float64 // The height of the page in points as displayed.
+}
+
+type NormalizePages struct {
+	Pages []NormalizePagesPage // The pages that were normalized.
+}
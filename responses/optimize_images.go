@@ -0,0 +1,32 @@
+package responses
+
+type OptimizeImagesSkipReason string
+
+const (
+	OptimizeImagesSkipReasonNone         OptimizeImagesSkipReason = ""             // The image was optimized.
+	OptimizeImagesSkipReasonBilevel      OptimizeImagesSkipReason = "bilevel"      // The image has 1 bit per pixel, those are stored more efficiently without JPEG.
+	OptimizeImagesSkipReasonTransparency OptimizeImagesSkipReason = "transparency" // The image has transparency, which JPEG doesn't support.
+	OptimizeImagesSkipReasonUnsupported  OptimizeImagesSkipReason = "unsupported"  // The pixels of the image could not be read.
+	OptimizeImagesSkipReasonNoSavings    OptimizeImagesSkipReason = "no_savings"   // The optimized image would not be smaller.
+)
+
+type OptimizeImagesImage struct {
+	Page           int                      // The page the image is on (0-index based).
+	ObjectPath     []int                    // The index of the image object in the page, followed by the indexes in the form objects it is nested in.
+	OriginalWidth  int                      // The original width of the image in pixels.
+	OriginalHeight int                      // The original height of the image in pixels.
+	Width          int                      // The width of the image in pixels after optimizing.
+	Height         int                      // The height of the image in pixels after optimizing.
+	OriginalSize   int                      // The original size of the image data in bytes.
+	Size           int                      // The size of the image data in bytes after optimizing.
+	Grayscale      bool                     // Whether the image was converted to grayscale.
+	Optimized      bool                     // Whether the image was replaced by the optimized image.
+	SkipReason     OptimizeImagesSkipReason // Why the image was not optimized.
+}
+
+type OptimizeImages struct {
+	Images        []OptimizeImagesImage // The images that were found.
+	OriginalSize  int64                 // The original size of the image data of all images in bytes.
+	OptimizedSize int64                 // The size of the image data of all images in bytes after optimizing.
+	Savings       int64                 // The amount of bytes that was saved.
+}
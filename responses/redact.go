@@ -0,0 +1,23 @@
+package responses
+
+import (
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+type RedactDocumentPage struct {
+	Page               int                     // The page that was redacted (0-index based).
+	Areas              []structs.FPDF_FS_RECTF // The areas that were redacted, including the matches of the searches and the areas of the redact annotations.
+	RemovedChars       int                     // The amount of chars that were removed.
+	RemovedObjects     int                     // The amount of page objects that were removed entirely.
+	RemovedFormObjects int                     // The amount of form objects that were removed entirely. PDFium can't remove the objects in a form object, so its content outside the areas is removed as well.
+	RedactedImages     int                     // The amount of images of which pixels were blacked out.
+	RemovedAnnotations int                     // The amount of annotations in the areas that were removed, like free text, markup and link annotations, including their popups. The applied redact annotations are not counted.
+	RemainingWidgets   int                     // The amount of widgets of form fields in the areas. Widgets are not removed, since the value of a field is stored in the field. Flatten the form fields before redacting to redact their values.
+	RemainingText      []string                // The text that was still found in the areas or by the searches after redacting.
+	Verified           bool                    // Whether no text remained in the areas, no search matched anymore, no form objects were removed and no widgets are in the areas.
+}
+
+type RedactDocument struct {
+	Pages    []RedactDocumentPage // The pages that had something to redact.
+	Verified bool                 // Whether all pages are verified, see RedactDocumentPage.Verified.
+}
@@ -0,0 +1,7 @@
+package responses
+
+type StampDocument struct {
+	Pages     []int   // The pages that were stamped (0-index based).
+	FileBytes *[]byte // The stamped copy of the document, when Placement is StampDocumentPlacementAnnotation and no FilePath was given.
+	FilePath  *string // The path the stamped copy was saved to, when Placement is StampDocumentPlacementAnnotation and a FilePath was given.
+}
@@ -20,12 +20,21 @@ type FontInformation struct {
 	Flags        int     // Font flags, should be interpreted per PDF spec 1.7, Section 5.7.1 Font Descriptor Flags. Will only be filled when compiled with experimental support.
 }
 
+type TextDirection string
+
+const (
+	TextDirectionLeftToRight TextDirection = "ltr" // Horizontal text that is read from left to right.
+	TextDirectionRightToLeft TextDirection = "rtl" // Horizontal text that is read from right to left, like Arabic and Hebrew.
+	TextDirectionTopToBottom TextDirection = "ttb" // Vertical text that is read from top to bottom, like vertical CJK text.
+)
+
 type GetPageTextStructuredChar struct {
 	Text            string           // The text of this char.
 	Angle           float64          // The angle this char is in.
 	PointPosition   CharPosition     // The position of this char in points.
 	PixelPosition   *CharPosition    // The position of this char in pixels. When PixelPositions are requested.
 	FontInformation *FontInformation // The font information of this char. When CollectFontInformation is enabled.
+	Direction       TextDirection    // The writing direction of this char. When CollectDirectionInformation is enabled.
 }
 
 type GetPageTextStructuredRect struct {
@@ -33,11 +42,66 @@ type GetPageTextStructuredRect struct {
 	PointPosition   CharPosition     // The position of this rect in points.
 	PixelPosition   *CharPosition    // The position of this rect in pixels. When PixelPositions are requested.
 	FontInformation *FontInformation // The font information of this rect. When CollectFontInformation is enabled.
+	Direction       TextDirection    // The writing direction of this rect. When CollectDirectionInformation is enabled.
+}
+
+type GetPageTextStructuredWord struct {
+	Text          string        // The text of this word in logical order.
+	Direction     TextDirection // The writing direction of this word.
+	Angle         float64       // The angle this word is in.
+	PointPosition CharPosition  // The position of this word in points.
+	PixelPosition *CharPosition // The position of this word in pixels. When PixelPositions are requested.
+}
+
+type GetPageTextStructuredLine struct {
+	Text          string                       // The text of this line in logical order.
+	Direction     TextDirection                // The writing direction of this line.
+	Angle         float64                      // The angle this line is in.
+	PointPosition CharPosition                 // The position of this line in points.
+	PixelPosition *CharPosition                // The position of this line in pixels. When PixelPositions are requested.
+	Words         []*GetPageTextStructuredWord // The words of this line in logical order.
 }
 
 type GetPageTextStructured struct {
 	Page              int                          // The page structured this text came from (0-index based).
 	Chars             []*GetPageTextStructuredChar // A list of chars in a page. When Mode is GetPageTextStructuredModeChars or GetPageTextStructuredModeBoth.
 	Rects             []*GetPageTextStructuredRect // A list of rects in a page. When Mode is GetPageTextStructuredModeRects or GetPageTextStructuredModeBoth.
+	Words             []*GetPageTextStructuredWord // A list of words in a page in logical order. When Mode is GetPageTextStructuredModeWords.
+	Lines             []*GetPageTextStructuredLine // A list of lines in a page in logical order. When Mode is GetPageTextStructuredModeLines.
 	PointToPixelRatio float64                      // The point to pixel ratio for the calculated positions.
 }
+
+type SearchPageTextMatch struct {
+	Text       string         // The text of the match, after applying the text options.
+	StartIndex int            // The index of the first char of the match in the page (0-index based).
+	Count      int            // The amount of chars of the page in the match.
+	Rects      []CharPosition // The positions of the match in points, one rect per line.
+}
+
+type SearchPageText struct {
+	Page    int                   // The page that was searched (0-index based).
+	Matches []SearchPageTextMatch // The matches of the query in the page.
+}
+
+type GetPageTextQualityStatus string
+
+const (
+	GetPageTextQualityStatusText          GetPageTextQualityStatus = "text"           // The page has a real text layer.
+	GetPageTextQualityStatusImageOnly     GetPageTextQualityStatus = "image_only"     // The page only contains images and no text, it needs OCR.
+	GetPageTextQualityStatusInvisibleText GetPageTextQualityStatus = "invisible_text" // The text of the page is invisible, most likely the page was already OCR-ed.
+	GetPageTextQualityStatusBrokenText    GetPageTextQualityStatus = "broken_text"    // The text of the page can't be mapped to unicode (broken ToUnicode maps), it needs OCR.
+	GetPageTextQualityStatusEmpty         GetPageTextQualityStatus = "empty"          // The page has no text and no images.
+)
+
+type GetPageTextQuality struct {
+	Page                    int                      // The page that was analyzed (0-index based).
+	Status                  GetPageTextQualityStatus // The status of the text layer of the page.
+	NeedsOCR                bool                     // Whether the page should be OCR-ed to get reliable text.
+	Confidence              float64                  // The confidence of the status, between 0 and 1.
+	CharCount               int                      // The amount of chars in the text layer, whitespace and generated chars are not counted.
+	InvisibleCharCount      int                      // The amount of chars that are rendered invisible (text render mode invisible).
+	UnmappableCharCount     int                      // The amount of chars that could not be mapped to unicode.
+	UnicodeMapErrorsChecked bool                     // Whether the chars were checked for unicode map errors of their font. Only when compiled with experimental support, otherwise UnmappableCharCount only contains the chars that are unmappable by their value.
+	ImageCount              int                      // The amount of images on the page, including images in form objects.
+	ImageCoverage           float64                  // The part of the page that is covered by images, between 0 and 1.
+}
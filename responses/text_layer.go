@@ -0,0 +1,6 @@
+package responses
+
+type AddPageTextLayer struct {
+	Page       int // The page the text layer was added to (0-index based).
+	WordsAdded int // The amount of words that were added to the page, words without text or size are skipped.
+}
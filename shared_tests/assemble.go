@@ -0,0 +1,357 @@
+package shared_tests
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func getAssemblePageCount(fileBytes *[]byte) int {
+	Expect(fileBytes).To(Not(BeNil()))
+
+	newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+		Data: fileBytes,
+	})
+	Expect(err).To(BeNil())
+
+	defer PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+		Document: newDoc.Document,
+	})
+
+	pageCount, err := PdfiumInstance.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+		Document: newDoc.Document,
+	})
+	Expect(err).To(BeNil())
+
+	return pageCount.PageCount
+}
+
+var _ = Describe("assemble", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling MergeDocuments", func() {
+				MergeDocuments, err := PdfiumInstance.MergeDocuments(&requests.MergeDocuments{})
+				Expect(err).To(MatchError("no documents given"))
+				Expect(MergeDocuments).To(BeNil())
+			})
+
+			It("returns an error when calling SplitDocument", func() {
+				SplitDocument, err := PdfiumInstance.SplitDocument(&requests.SplitDocument{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(SplitDocument).To(BeNil())
+			})
+
+			It("returns an error when calling ReorderPages", func() {
+				ReorderPages, err := PdfiumInstance.ReorderPages(&requests.ReorderPages{
+					Order: []int{0},
+				})
+				Expect(err).To(MatchError("document not given"))
+				Expect(ReorderPages).To(BeNil())
+			})
+		})
+	})
+
+	Context("multiple PDF files", func() {
+		var pdfData []byte
+		var multiPagePdfData []byte
+
+		BeforeEach(func() {
+			var err error
+			pdfData, err = ioutil.ReadFile(TestDataPath + "/testdata/test.pdf")
+			Expect(err).To(BeNil())
+
+			multiPagePdfData, err = ioutil.ReadFile(TestDataPath + "/testdata/test_multipage.pdf")
+			Expect(err).To(BeNil())
+		})
+
+		When("MergeDocuments is called", func() {
+			It("merges documents from bytes, paths and readers", func() {
+				filePath := TestDataPath + "/testdata/test.pdf"
+				MergeDocuments, err := PdfiumInstance.MergeDocuments(&requests.MergeDocuments{
+					Documents: []requests.DocumentInput{
+						{File: &pdfData},
+						{FilePath: &filePath},
+						{FileReader: bytes.NewReader(multiPagePdfData), FileReaderSize: int64(len(multiPagePdfData))},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(MergeDocuments).To(Not(BeNil()))
+				Expect(MergeDocuments.PageCount).To(Equal(4))
+				Expect(MergeDocuments.FilePath).To(BeNil())
+				Expect(getAssemblePageCount(MergeDocuments.FileBytes)).To(Equal(4))
+			})
+
+			It("returns an error when the size of a reader is not given", func() {
+				if TestType == "multi" {
+					Skip("Multi-threaded usage reads the reader into memory")
+				}
+
+				MergeDocuments, err := PdfiumInstance.MergeDocuments(&requests.MergeDocuments{
+					Documents: []requests.DocumentInput{
+						{FileReader: bytes.NewReader(multiPagePdfData)},
+					},
+				})
+				Expect(err).To(MatchError("FileReaderSize should be given when FileReader is set"))
+				Expect(MergeDocuments).To(BeNil())
+			})
+
+			It("merges the selected pages", func() {
+				pages := "2,1,2"
+				MergeDocuments, err := PdfiumInstance.MergeDocuments(&requests.MergeDocuments{
+					Documents: []requests.DocumentInput{
+						{File: &multiPagePdfData, Pages: &pages},
+						{File: &pdfData},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(MergeDocuments).To(Not(BeNil()))
+				Expect(MergeDocuments.PageCount).To(Equal(4))
+			})
+
+			It("merges documents that are already opened", func() {
+				newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+					Data: &multiPagePdfData,
+				})
+				Expect(err).To(BeNil())
+
+				MergeDocuments, err := PdfiumInstance.MergeDocuments(&requests.MergeDocuments{
+					Documents: []requests.DocumentInput{
+						{Document: newDoc.Document},
+						{Document: newDoc.Document},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(MergeDocuments).To(Not(BeNil()))
+				Expect(MergeDocuments.PageCount).To(Equal(4))
+
+				// The opened document should still be usable.
+				pageCount, err := PdfiumInstance.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+					Document: newDoc.Document,
+				})
+				Expect(err).To(BeNil())
+				Expect(pageCount.PageCount).To(Equal(2))
+
+				FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+					Document: newDoc.Document,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDF_CloseDocument).To(Not(BeNil()))
+			})
+
+			It("saves the merged document to a path", func() {
+				tempDir, err := ioutil.TempDir("", "go-pdfium-assemble")
+				Expect(err).To(BeNil())
+				defer os.RemoveAll(tempDir)
+
+				filePath := filepath.Join(tempDir, "merged.pdf")
+				MergeDocuments, err := PdfiumInstance.MergeDocuments(&requests.MergeDocuments{
+					Documents: []requests.DocumentInput{
+						{File: &pdfData},
+						{File: &pdfData},
+					},
+					FilePath: &filePath,
+				})
+				Expect(err).To(BeNil())
+				Expect(MergeDocuments).To(Not(BeNil()))
+				Expect(MergeDocuments.FileBytes).To(BeNil())
+				Expect(MergeDocuments.FilePath).To(Equal(&filePath))
+
+				savedData, err := ioutil.ReadFile(filePath)
+				Expect(err).To(BeNil())
+				Expect(getAssemblePageCount(&savedData)).To(Equal(2))
+			})
+
+			It("returns an error for an invalid page range", func() {
+				pages := "1-3"
+				MergeDocuments, err := PdfiumInstance.MergeDocuments(&requests.MergeDocuments{
+					Documents: []requests.DocumentInput{
+						{File: &multiPagePdfData, Pages: &pages},
+					},
+				})
+				Expect(err).To(MatchError("could not merge document 0: page range \"1-3\" is out of bounds, document has 2 pages"))
+				Expect(MergeDocuments).To(BeNil())
+			})
+		})
+
+		When("SplitDocument is called", func() {
+			It("returns an error for an unknown mode", func() {
+				SplitDocument, err := PdfiumInstance.SplitDocument(&requests.SplitDocument{
+					Document: requests.DocumentInput{File: &multiPagePdfData},
+					Mode:     "chapters",
+				})
+				Expect(err).To(MatchError("unsupported split mode chapters"))
+				Expect(SplitDocument).To(BeNil())
+			})
+
+			It("splits the document by ranges", func() {
+				SplitDocument, err := PdfiumInstance.SplitDocument(&requests.SplitDocument{
+					Document: requests.DocumentInput{File: &multiPagePdfData},
+					Mode:     requests.SplitDocumentModeRanges,
+					Ranges:   []string{"2", "1-2"},
+				})
+				Expect(err).To(BeNil())
+				Expect(SplitDocument).To(Not(BeNil()))
+				Expect(SplitDocument.Parts).To(HaveLen(2))
+				Expect(SplitDocument.Parts[0].Pages).To(Equal([]int{1}))
+				Expect(SplitDocument.Parts[1].Pages).To(Equal([]int{0, 1}))
+				Expect(getAssemblePageCount(SplitDocument.Parts[0].FileBytes)).To(Equal(1))
+				Expect(getAssemblePageCount(SplitDocument.Parts[1].FileBytes)).To(Equal(2))
+			})
+
+			It("returns an error when pages are combined with ranges", func() {
+				pages := "1"
+				SplitDocument, err := PdfiumInstance.SplitDocument(&requests.SplitDocument{
+					Document: requests.DocumentInput{File: &multiPagePdfData, Pages: &pages},
+					Mode:     requests.SplitDocumentModeRanges,
+					Ranges:   []string{"2"},
+				})
+				Expect(err).To(MatchError("document pages can't be combined with ranges, give the pages in the ranges"))
+				Expect(SplitDocument).To(BeNil())
+			})
+
+			It("splits the document by page count", func() {
+				SplitDocument, err := PdfiumInstance.SplitDocument(&requests.SplitDocument{
+					Document:  requests.DocumentInput{File: &multiPagePdfData},
+					Mode:      requests.SplitDocumentModePageCount,
+					PageCount: 1,
+				})
+				Expect(err).To(BeNil())
+				Expect(SplitDocument).To(Not(BeNil()))
+				Expect(SplitDocument.Parts).To(HaveLen(2))
+				Expect(SplitDocument.Parts[0].Pages).To(Equal([]int{0}))
+				Expect(SplitDocument.Parts[1].Pages).To(Equal([]int{1}))
+			})
+
+			It("splits the document by file size", func() {
+				SplitDocument, err := PdfiumInstance.SplitDocument(&requests.SplitDocument{
+					Document:    requests.DocumentInput{File: &multiPagePdfData},
+					Mode:        requests.SplitDocumentModeFileSize,
+					MaxFileSize: 1,
+				})
+				Expect(err).To(BeNil())
+				Expect(SplitDocument).To(Not(BeNil()))
+				Expect(SplitDocument.Parts).To(HaveLen(2))
+				Expect(SplitDocument.Parts[0].Pages).To(Equal([]int{0}))
+				Expect(SplitDocument.Parts[1].Pages).To(Equal([]int{1}))
+			})
+
+			It("keeps the pages together when they fit in the file size", func() {
+				SplitDocument, err := PdfiumInstance.SplitDocument(&requests.SplitDocument{
+					Document:    requests.DocumentInput{File: &multiPagePdfData},
+					Mode:        requests.SplitDocumentModeFileSize,
+					MaxFileSize: int64(len(multiPagePdfData)) * 10,
+				})
+				Expect(err).To(BeNil())
+				Expect(SplitDocument).To(Not(BeNil()))
+				Expect(SplitDocument.Parts).To(HaveLen(1))
+				Expect(SplitDocument.Parts[0].Pages).To(Equal([]int{0, 1}))
+				Expect(getAssemblePageCount(SplitDocument.Parts[0].FileBytes)).To(Equal(2))
+			})
+
+			It("splits the document by bookmark level", func() {
+				SplitDocument, err := PdfiumInstance.SplitDocument(&requests.SplitDocument{
+					Document:      requests.DocumentInput{File: &multiPagePdfData},
+					Mode:          requests.SplitDocumentModeBookmarkLevel,
+					BookmarkLevel: 1,
+				})
+				Expect(err).To(BeNil())
+				Expect(SplitDocument).To(Not(BeNil()))
+				Expect(SplitDocument.Parts).To(HaveLen(1))
+				Expect(SplitDocument.Parts[0].Pages).To(Equal([]int{0, 1}))
+			})
+
+			It("saves the parts to paths", func() {
+				tempDir, err := ioutil.TempDir("", "go-pdfium-assemble")
+				Expect(err).To(BeNil())
+				defer os.RemoveAll(tempDir)
+
+				filePathFormat := filepath.Join(tempDir, "part-%d.pdf")
+				SplitDocument, err := PdfiumInstance.SplitDocument(&requests.SplitDocument{
+					Document:       requests.DocumentInput{File: &multiPagePdfData},
+					Mode:           requests.SplitDocumentModePageCount,
+					PageCount:      1,
+					FilePathFormat: &filePathFormat,
+				})
+				Expect(err).To(BeNil())
+				Expect(SplitDocument).To(Not(BeNil()))
+				Expect(SplitDocument.Parts).To(HaveLen(2))
+				Expect(SplitDocument.Parts[0].FileBytes).To(BeNil())
+				Expect(*SplitDocument.Parts[1].FilePath).To(Equal(filepath.Join(tempDir, "part-2.pdf")))
+				Expect(filepath.Join(tempDir, "part-1.pdf")).To(BeAnExistingFile())
+				Expect(filepath.Join(tempDir, "part-2.pdf")).To(BeAnExistingFile())
+			})
+		})
+
+		When("ReorderPages is called", func() {
+			It("returns an error for a page that does not exist", func() {
+				ReorderPages, err := PdfiumInstance.ReorderPages(&requests.ReorderPages{
+					Document: requests.DocumentInput{File: &multiPagePdfData},
+					Order:    []int{2},
+				})
+				Expect(err).To(MatchError("page 2 is out of bounds, document has 2 pages"))
+				Expect(ReorderPages).To(BeNil())
+			})
+
+			It("reorders the pages", func() {
+				ReorderPages, err := PdfiumInstance.ReorderPages(&requests.ReorderPages{
+					Document: requests.DocumentInput{File: &multiPagePdfData},
+					Order:    []int{1, 0, 1},
+				})
+				Expect(err).To(BeNil())
+				Expect(ReorderPages).To(Not(BeNil()))
+				Expect(ReorderPages.PageCount).To(Equal(3))
+				Expect(getAssemblePageCount(ReorderPages.FileBytes)).To(Equal(3))
+			})
+		})
+	})
+
+	Context("a PDF file with bookmarks", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/bookmarks.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("SplitDocument is called", func() {
+			It("returns an error for an invalid bookmark level", func() {
+				SplitDocument, err := PdfiumInstance.SplitDocument(&requests.SplitDocument{
+					Document: requests.DocumentInput{Document: doc},
+					Mode:     requests.SplitDocumentModeBookmarkLevel,
+				})
+				Expect(err).To(MatchError("bookmark level should be at least 1"))
+				Expect(SplitDocument).To(BeNil())
+			})
+		})
+	})
+})
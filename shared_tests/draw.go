@@ -0,0 +1,193 @@
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/structs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("draw", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling DrawPage", func() {
+				DrawPage, err := PdfiumInstance.DrawPage(&requests.DrawPage{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Index: 0,
+						},
+					},
+				})
+				Expect(err).To(MatchError("document not given"))
+				Expect(DrawPage).To(BeNil())
+			})
+		})
+	})
+
+	Context("a normal PDF file", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/hello_world.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		countObjects := func() int {
+			FPDFPage_CountObjects, err := PdfiumInstance.FPDFPage_CountObjects(&requests.FPDFPage_CountObjects{
+				Page: requests.Page{
+					ByIndex: &requests.PageByIndex{
+						Document: doc,
+						Index:    0,
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			return FPDFPage_CountObjects.Count
+		}
+
+		It("returns an error and draws nothing for an invalid operation", func() {
+			objectCount := countObjects()
+
+			DrawPage, err := PdfiumInstance.DrawPage(&requests.DrawPage{
+				Page: requests.Page{
+					ByIndex: &requests.PageByIndex{
+						Document: doc,
+						Index:    0,
+					},
+				},
+				Operations: []requests.DrawPageOperation{
+					{
+						Type: requests.DrawPageOperationTypeRect,
+						Rect: structs.FPDF_FS_RECTF{Left: 10, Bottom: 10, Right: 110, Top: 60},
+					},
+					{
+						Type: requests.DrawPageOperationTypePath,
+						Path: []requests.DrawPagePathSegment{
+							{Type: requests.DrawPagePathSegmentTypeLineTo, X: 10, Y: 10},
+						},
+					},
+				},
+			})
+			Expect(err).To(MatchError("could not draw operation 1: path should start with a move"))
+			Expect(DrawPage).To(BeNil())
+			Expect(countObjects()).To(Equal(objectCount))
+		})
+
+		It("returns an error for an unsupported operation type", func() {
+			DrawPage, err := PdfiumInstance.DrawPage(&requests.DrawPage{
+				Page: requests.Page{
+					ByIndex: &requests.PageByIndex{
+						Document: doc,
+						Index:    0,
+					},
+				},
+				Operations: []requests.DrawPageOperation{
+					{
+						Type: "circle",
+					},
+				},
+			})
+			Expect(err).To(MatchError("could not draw operation 0: unsupported operation type circle"))
+			Expect(DrawPage).To(BeNil())
+		})
+
+		It("draws paths, rectangles, text and images", func() {
+			objectCount := countObjects()
+
+			imageData, err := ioutil.ReadFile(TestDataPath + "/testdata/mona_lisa.jpg")
+			Expect(err).To(BeNil())
+
+			DrawPage, err := PdfiumInstance.DrawPage(&requests.DrawPage{
+				Page: requests.Page{
+					ByIndex: &requests.PageByIndex{
+						Document: doc,
+						Index:    0,
+					},
+				},
+				Operations: []requests.DrawPageOperation{
+					{
+						Type:        requests.DrawPageOperationTypePath,
+						StrokeColor: &structs.FPDF_COLOR{R: 255, A: 255},
+						StrokeWidth: 2,
+						LineJoin:    enums.FPDF_LINEJOIN_ROUND,
+						Path: []requests.DrawPagePathSegment{
+							{Type: requests.DrawPagePathSegmentTypeMoveTo, X: 10, Y: 10},
+							{Type: requests.DrawPagePathSegmentTypeLineTo, X: 100, Y: 10},
+							{Type: requests.DrawPagePathSegmentTypeBezierTo, X1: 120, Y1: 20, X2: 120, Y2: 80, X: 100, Y: 100},
+							{Type: requests.DrawPagePathSegmentTypeClose},
+						},
+					},
+					{
+						Type:      requests.DrawPageOperationTypeRect,
+						FillColor: &structs.FPDF_COLOR{B: 255, A: 128},
+						Rect:      structs.FPDF_FS_RECTF{Left: 200, Bottom: 200, Right: 300, Top: 250},
+					},
+					{
+						Type: requests.DrawPageOperationTypeText,
+						Text: requests.DrawPageText{
+							Text:     "Drawn text",
+							X:        50,
+							Y:        400,
+							FontSize: 20,
+						},
+						Matrix: &structs.FPDF_FS_MATRIX{A: 1, D: 1, E: 10, F: 20},
+					},
+					{
+						Type: requests.DrawPageOperationTypeImage,
+						Image: requests.DrawPageImage{
+							Data:  imageData,
+							X:     300,
+							Y:     500,
+							Width: 100,
+						},
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(DrawPage.Page).To(Equal(0))
+			Expect(DrawPage.PageObjects).To(HaveLen(4))
+			Expect(countObjects()).To(Equal(objectCount + 4))
+
+			FPDFPageObj_GetType, err := PdfiumInstance.FPDFPageObj_GetType(&requests.FPDFPageObj_GetType{
+				PageObject: DrawPage.PageObjects[3],
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDFPageObj_GetType.Type).To(Equal(enums.FPDF_PAGEOBJ_IMAGE))
+
+			FPDFPageObj_GetBounds, err := PdfiumInstance.FPDFPageObj_GetBounds(&requests.FPDFPageObj_GetBounds{
+				PageObject: DrawPage.PageObjects[1],
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDFPageObj_GetBounds.Left).To(BeNumerically("~", 200, 0.01))
+			Expect(FPDFPageObj_GetBounds.Right).To(BeNumerically("~", 300, 0.01))
+		})
+	})
+})
@@ -0,0 +1,297 @@
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("edit_form_fields", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling EditFormFields", func() {
+				EditFormFields, err := PdfiumInstance.EditFormFields(&requests.EditFormFields{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(EditFormFields).To(BeNil())
+			})
+		})
+	})
+
+	Context("a PDF file with text fields", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/form_actions.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		rect := structs.FPDF_FS_RECTF{Left: 50, Top: 100, Right: 70, Bottom: 80}
+
+		It("returns an error when nothing is given", func() {
+			EditFormFields, err := PdfiumInstance.EditFormFields(&requests.EditFormFields{
+				Document: doc,
+			})
+			Expect(err).To(MatchError("no fields to create or edit given"))
+			Expect(EditFormFields).To(BeNil())
+		})
+
+		It("returns an error when a field doesn't exist", func() {
+			EditFormFields, err := PdfiumInstance.EditFormFields(&requests.EditFormFields{
+				Document: doc,
+				Edit: []requests.EditFormField{
+					{Name: "unknown", Delete: true},
+				},
+			})
+			Expect(err).To(MatchError("field unknown not found"))
+			Expect(EditFormFields).To(BeNil())
+		})
+
+		It("returns an error when a field already exists", func() {
+			EditFormFields, err := PdfiumInstance.EditFormFields(&requests.EditFormFields{
+				Document: doc,
+				Create: []requests.CreateFormField{
+					{Name: "price", Type: enums.FPDF_FORMFIELD_TYPE_TEXTFIELD, Widgets: []requests.CreateFormFieldWidget{{Page: 0, Rect: rect}}},
+				},
+			})
+			Expect(err).To(MatchError("field price already exists"))
+			Expect(EditFormFields).To(BeNil())
+		})
+
+		It("returns an error when a field is renamed outside of its parent", func() {
+			EditFormFields, err := PdfiumInstance.EditFormFields(&requests.EditFormFields{
+				Document: doc,
+				Edit: []requests.EditFormField{
+					{Name: "price", NewName: "order.price"},
+				},
+			})
+			Expect(err).To(MatchError("field price can only be renamed within its parent"))
+			Expect(EditFormFields).To(BeNil())
+		})
+
+		It("returns an error when a radio button has no export value", func() {
+			EditFormFields, err := PdfiumInstance.EditFormFields(&requests.EditFormFields{
+				Document: doc,
+				Create: []requests.CreateFormField{
+					{Name: "color", Type: enums.FPDF_FORMFIELD_TYPE_RADIOBUTTON, Widgets: []requests.CreateFormFieldWidget{{Page: 0, Rect: rect}}},
+				},
+			})
+			Expect(err).To(MatchError("widget 0 of field color has no export value"))
+			Expect(EditFormFields).To(BeNil())
+		})
+
+		It("returns an error when a page doesn't exist", func() {
+			EditFormFields, err := PdfiumInstance.EditFormFields(&requests.EditFormFields{
+				Document: doc,
+				Create: []requests.CreateFormField{
+					{Name: "name", Type: enums.FPDF_FORMFIELD_TYPE_TEXTFIELD, Widgets: []requests.CreateFormFieldWidget{{Page: 1, Rect: rect}}},
+				},
+			})
+			Expect(err).To(MatchError("page 1 is out of bounds, document has 1 pages"))
+			Expect(EditFormFields).To(BeNil())
+		})
+
+		It("creates and edits fields in the document", func() {
+			EditFormFields, err := PdfiumInstance.EditFormFields(&requests.EditFormFields{
+				Document: doc,
+				Create: []requests.CreateFormField{
+					{Name: "person.name", Type: enums.FPDF_FORMFIELD_TYPE_TEXTFIELD, Widgets: []requests.CreateFormFieldWidget{{Page: 0, Rect: rect}}, Value: "Jane", AlternateName: "Your name"},
+					{Name: "person.agree", Type: enums.FPDF_FORMFIELD_TYPE_CHECKBOX, Widgets: []requests.CreateFormFieldWidget{{Page: 0, Rect: rect}}, Value: "Yes"},
+					{Name: "color", Type: enums.FPDF_FORMFIELD_TYPE_RADIOBUTTON, Widgets: []requests.CreateFormFieldWidget{{Page: 0, Rect: rect, ExportValue: "red"}, {Page: 0, Rect: rect, ExportValue: "blue"}}},
+					{Name: "size", Type: enums.FPDF_FORMFIELD_TYPE_COMBOBOX, Widgets: []requests.CreateFormFieldWidget{{Page: 0, Rect: rect}}, Options: []string{"S", "M", "L"}, Value: "M"},
+					{Name: "signature", Type: enums.FPDF_FORMFIELD_TYPE_SIGNATURE, Widgets: []requests.CreateFormFieldWidget{{Page: 0, Rect: rect}}},
+				},
+				Edit: []requests.EditFormField{
+					{Name: "note", Delete: true},
+					{Name: "total", NewName: "sum", Rect: &rect},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(EditFormFields).To(Equal(&responses.EditFormFields{}))
+
+			// The document reference stays valid.
+			FPDF_GetPageCount, err := PdfiumInstance.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_GetPageCount.PageCount).To(Equal(1))
+
+			// The fields of the document can be edited again.
+			EditFormFields, err = PdfiumInstance.EditFormFields(&requests.EditFormFields{
+				Document: doc,
+				Edit: []requests.EditFormField{
+					{Name: "person.name", NewName: "person.full_name"},
+					{Name: "color", Rect: &rect, Widget: 1},
+					{Name: "sum", Delete: true},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(EditFormFields).To(Not(BeNil()))
+
+			EditFormFields, err = PdfiumInstance.EditFormFields(&requests.EditFormFields{
+				Document: doc,
+				Edit: []requests.EditFormField{
+					{Name: "note", Delete: true},
+				},
+			})
+			Expect(err).To(MatchError("field note not found"))
+			Expect(EditFormFields).To(BeNil())
+
+			EditFormFields, err = PdfiumInstance.EditFormFields(&requests.EditFormFields{
+				Document: doc,
+				Create: []requests.CreateFormField{
+					{Name: "person.agree", Type: enums.FPDF_FORMFIELD_TYPE_CHECKBOX, Widgets: []requests.CreateFormFieldWidget{{Page: 0, Rect: rect}}},
+				},
+			})
+			Expect(err).To(MatchError("field person.agree already exists"))
+			Expect(EditFormFields).To(BeNil())
+		})
+	})
+
+	Context("a PDF file with a cross-reference stream and object streams", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/form_xref_stream.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("creates and edits fields", func() {
+			rect := structs.FPDF_FS_RECTF{Left: 100, Top: 200, Right: 200, Bottom: 180}
+
+			EditFormFields, err := PdfiumInstance.EditFormFields(&requests.EditFormFields{
+				Document: doc,
+				Create: []requests.CreateFormField{
+					{Name: "email", Type: enums.FPDF_FORMFIELD_TYPE_TEXTFIELD, Widgets: []requests.CreateFormFieldWidget{{Page: 0, Rect: rect}}},
+				},
+				Edit: []requests.EditFormField{
+					{Name: "name", NewName: "full_name"},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(EditFormFields).To(Not(BeNil()))
+
+			// The field that was in an object stream was renamed.
+			EditFormFields, err = PdfiumInstance.EditFormFields(&requests.EditFormFields{
+				Document: doc,
+				Edit: []requests.EditFormField{
+					{Name: "name", Delete: true},
+				},
+			})
+			Expect(err).To(MatchError("field name not found"))
+			Expect(EditFormFields).To(BeNil())
+
+			EditFormFields, err = PdfiumInstance.EditFormFields(&requests.EditFormFields{
+				Document: doc,
+				Edit: []requests.EditFormField{
+					{Name: "full_name", Delete: true},
+					{Name: "email", Delete: true},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(EditFormFields).To(Not(BeNil()))
+		})
+	})
+
+	Context("an encrypted PDF file with text fields", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/form_fields_encrypted.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("creates and edits fields and removes the security", func() {
+			rect := structs.FPDF_FS_RECTF{Left: 100, Top: 170, Right: 200, Bottom: 150}
+
+			EditFormFields, err := PdfiumInstance.EditFormFields(&requests.EditFormFields{
+				Document: doc,
+				Create: []requests.CreateFormField{
+					{Name: "email", Type: enums.FPDF_FORMFIELD_TYPE_TEXTFIELD, Widgets: []requests.CreateFormFieldWidget{{Page: 0, Rect: rect}}},
+				},
+				Edit: []requests.EditFormField{
+					{Name: "name", NewName: "full_name"},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(EditFormFields).To(Not(BeNil()))
+
+			FPDF_GetSecurityHandlerRevision, err := PdfiumInstance.FPDF_GetSecurityHandlerRevision(&requests.FPDF_GetSecurityHandlerRevision{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_GetSecurityHandlerRevision.SecurityHandlerRevision).To(Equal(-1))
+
+			// The encrypted names were read.
+			EditFormFields, err = PdfiumInstance.EditFormFields(&requests.EditFormFields{
+				Document: doc,
+				Edit: []requests.EditFormField{
+					{Name: "full_name", Delete: true},
+					{Name: "city", Delete: true},
+					{Name: "email", Delete: true},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(EditFormFields).To(Not(BeNil()))
+		})
+	})
+})
@@ -0,0 +1,122 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/structs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("edit_form_fields_experimental", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("a PDF file with text fields", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/form_actions.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("creates the fields in the form of the document and builds their appearances", func() {
+			rect := structs.FPDF_FS_RECTF{Left: 50, Top: 100, Right: 150, Bottom: 80}
+
+			EditFormFields, err := PdfiumInstance.EditFormFields(&requests.EditFormFields{
+				Document: doc,
+				Create: []requests.CreateFormField{
+					{Name: "person.name", Type: enums.FPDF_FORMFIELD_TYPE_TEXTFIELD, Widgets: []requests.CreateFormFieldWidget{{Page: 0, Rect: rect}}, Value: "Jane", AlternateName: "Your name"},
+					{Name: "person.agree", Type: enums.FPDF_FORMFIELD_TYPE_CHECKBOX, Widgets: []requests.CreateFormFieldWidget{{Page: 0, Rect: rect}}, Value: "Yes"},
+					{Name: "color", Type: enums.FPDF_FORMFIELD_TYPE_RADIOBUTTON, Widgets: []requests.CreateFormFieldWidget{{Page: 0, Rect: rect, ExportValue: "red"}, {Page: 0, Rect: rect, ExportValue: "blue"}}},
+					{Name: "size", Type: enums.FPDF_FORMFIELD_TYPE_COMBOBOX, Widgets: []requests.CreateFormFieldWidget{{Page: 0, Rect: rect}}, Options: []string{"S", "M", "L"}, Value: "M"},
+				},
+				Edit: []requests.EditFormField{
+					{Name: "note", Delete: true},
+					{Name: "total", NewName: "sum", Rect: &rect},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(EditFormFields).To(Not(BeNil()))
+
+			GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+
+			names := []string{}
+			for _, field := range GetFormFields.Fields {
+				names = append(names, field.Name)
+			}
+			Expect(names).To(Equal([]string{"price", "quantity", "sum", "person.name", "person.agree", "color", "size"}))
+
+			Expect(GetFormFields.Fields[2].Rect).To(Equal(rect))
+			Expect(GetFormFields.Fields[3].Type).To(Equal(enums.FPDF_FORMFIELD_TYPE_TEXTFIELD))
+			Expect(GetFormFields.Fields[3].Value).To(Equal("Jane"))
+			Expect(GetFormFields.Fields[3].AlternateName).To(Equal("Your name"))
+			Expect(GetFormFields.Fields[4].Type).To(Equal(enums.FPDF_FORMFIELD_TYPE_CHECKBOX))
+			Expect(GetFormFields.Fields[4].Value).To(Equal("Yes"))
+			Expect(GetFormFields.Fields[5].Type).To(Equal(enums.FPDF_FORMFIELD_TYPE_RADIOBUTTON))
+			Expect(GetFormFields.Fields[5].ExportValues).To(Equal([]string{"red", "blue"}))
+			Expect(GetFormFields.Fields[5].Value).To(Equal("Off"))
+			Expect(GetFormFields.Fields[6].Type).To(Equal(enums.FPDF_FORMFIELD_TYPE_COMBOBOX))
+			Expect(GetFormFields.Fields[6].Value).To(Equal("M"))
+
+			// The widgets of the moved and the created fields have an
+			// appearance.
+			for _, field := range GetFormFields.Fields[2:] {
+				for _, widget := range field.Widgets {
+					FPDFPage_GetAnnot, err := PdfiumInstance.FPDFPage_GetAnnot(&requests.FPDFPage_GetAnnot{
+						Page: requests.Page{
+							ByIndex: &requests.PageByIndex{
+								Document: doc,
+								Index:    widget.Page,
+							},
+						},
+						Index: widget.Index,
+					})
+					Expect(err).To(BeNil())
+
+					FPDFAnnot_HasKey, err := PdfiumInstance.FPDFAnnot_HasKey(&requests.FPDFAnnot_HasKey{
+						Annotation: FPDFPage_GetAnnot.Annotation,
+						Key:        "AP",
+					})
+					Expect(err).To(BeNil())
+					Expect(FPDFAnnot_HasKey.HasKey).To(BeTrue(), field.Name)
+
+					_, err = PdfiumInstance.FPDFPage_CloseAnnot(&requests.FPDFPage_CloseAnnot{
+						Annotation: FPDFPage_GetAnnot.Annotation,
+					})
+					Expect(err).To(BeNil())
+				}
+			}
+		})
+	})
+})
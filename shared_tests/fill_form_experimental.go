@@ -0,0 +1,192 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("fill_form", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling FillForm", func() {
+				FillForm, err := PdfiumInstance.FillForm(&requests.FillForm{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(FillForm).To(BeNil())
+			})
+		})
+	})
+
+	Context("a PDF file with a text field", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/text_form.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("returns an error for an unknown field", func() {
+			FillForm, err := PdfiumInstance.FillForm(&requests.FillForm{
+				Document: doc,
+				Values: map[string]interface{}{
+					"Unknown": "value",
+				},
+			})
+			Expect(err).To(MatchError("field Unknown not found"))
+			Expect(FillForm).To(BeNil())
+		})
+
+		It("returns an error for an unsupported value type", func() {
+			FillForm, err := PdfiumInstance.FillForm(&requests.FillForm{
+				Document: doc,
+				Values: map[string]interface{}{
+					"Text Box": []string{"value"},
+				},
+			})
+			Expect(err).To(MatchError("unsupported value type []string for field Text Box"))
+			Expect(FillForm).To(BeNil())
+		})
+
+		It("fills the text field and returns the document", func() {
+			FillForm, err := PdfiumInstance.FillForm(&requests.FillForm{
+				Document: doc,
+				Values: map[string]interface{}{
+					"Text Box": "Hello",
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(FillForm.Fields).To(Equal([]responses.FillFormField{
+				{Name: "Text Box", Value: "Hello"},
+			}))
+			Expect(FillForm.FileBytes).To(Not(BeNil()))
+
+			GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(GetFormFields.Fields[0].Value).To(Equal("Hello"))
+		})
+
+		It("flattens the form fields", func() {
+			FillForm, err := PdfiumInstance.FillForm(&requests.FillForm{
+				Document: doc,
+				Values: map[string]interface{}{
+					"Text Box": 42,
+				},
+				Flatten: true,
+			})
+			Expect(err).To(BeNil())
+			Expect(FillForm.Fields).To(Equal([]responses.FillFormField{
+				{Name: "Text Box", Value: "42"},
+			}))
+			Expect(FillForm.FileBytes).To(Not(BeNil()))
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: FillForm.FileBytes,
+			})
+			Expect(err).To(BeNil())
+
+			GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+				Document: newDoc.Document,
+			})
+			Expect(err).To(BeNil())
+			Expect(GetFormFields.Fields).To(BeEmpty())
+
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: newDoc.Document,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+	})
+
+	Context("a PDF file with comboboxes", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/combobox_form.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("returns an error for a read only field", func() {
+			FillForm, err := PdfiumInstance.FillForm(&requests.FillForm{
+				Document: doc,
+				Values: map[string]interface{}{
+					"Combo_ReadOnly": "Dog",
+				},
+			})
+			Expect(err).To(MatchError("field Combo_ReadOnly is read only"))
+			Expect(FillForm).To(BeNil())
+		})
+
+		It("returns an error for a value that is not an option", func() {
+			FillForm, err := PdfiumInstance.FillForm(&requests.FillForm{
+				Document: doc,
+				Values: map[string]interface{}{
+					"Combo1": "Kiwi",
+				},
+			})
+			Expect(err).To(MatchError("value Kiwi is not an option of field Combo1"))
+			Expect(FillForm).To(BeNil())
+		})
+
+		It("selects an option", func() {
+			FillForm, err := PdfiumInstance.FillForm(&requests.FillForm{
+				Document: doc,
+				Values: map[string]interface{}{
+					"Combo1": "Cherry",
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(FillForm.Fields).To(Equal([]responses.FillFormField{
+				{Name: "Combo1", Value: "Cherry"},
+			}))
+		})
+	})
+})
@@ -0,0 +1,28 @@
+//go:build !pdfium_experimental
+// +build !pdfium_experimental
+
+package shared_tests
+
+import (
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("fill_form", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	It("returns an error when calling FillForm", func() {
+		FillForm, err := PdfiumInstance.FillForm(&requests.FillForm{})
+		Expect(err).To(MatchError(pdfium_errors.ErrExperimentalUnsupported.Error()))
+		Expect(FillForm).To(BeNil())
+	})
+})
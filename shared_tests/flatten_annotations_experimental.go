@@ -0,0 +1,205 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("flatten_annotations", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling FlattenAnnotations", func() {
+				FlattenAnnotations, err := PdfiumInstance.FlattenAnnotations(&requests.FlattenAnnotations{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(FlattenAnnotations).To(BeNil())
+			})
+		})
+	})
+
+	Context("a PDF file with text fields", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/form_actions.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		getFieldNames := func() []string {
+			GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+
+			names := []string{}
+			for _, field := range GetFormFields.Fields {
+				names = append(names, field.Name)
+			}
+			return names
+		}
+
+		It("returns an error when nothing is selected", func() {
+			FlattenAnnotations, err := PdfiumInstance.FlattenAnnotations(&requests.FlattenAnnotations{
+				Document: doc,
+			})
+			Expect(err).To(MatchError("no field names, field types or subtypes given"))
+			Expect(FlattenAnnotations).To(BeNil())
+		})
+
+		It("returns an error when a page doesn't exist", func() {
+			FlattenAnnotations, err := PdfiumInstance.FlattenAnnotations(&requests.FlattenAnnotations{
+				Document:   doc,
+				Pages:      []int{1},
+				FieldNames: []string{"total"},
+			})
+			Expect(err).To(MatchError("page 1 is out of bounds, document has 1 pages"))
+			Expect(FlattenAnnotations).To(BeNil())
+		})
+
+		It("flattens only the selected field", func() {
+			FlattenAnnotations, err := PdfiumInstance.FlattenAnnotations(&requests.FlattenAnnotations{
+				Document:   doc,
+				FieldNames: []string{"total"},
+			})
+			Expect(err).To(BeNil())
+			Expect(FlattenAnnotations.Fields).To(Equal([]string{"total"}))
+			Expect(FlattenAnnotations.Annotations).To(Equal(1))
+			Expect(getFieldNames()).To(Equal([]string{"price", "quantity", "note"}))
+		})
+
+		It("removes the flattened field from the form of the document", func() {
+			FlattenAnnotations, err := PdfiumInstance.FlattenAnnotations(&requests.FlattenAnnotations{
+				Document:   doc,
+				FieldNames: []string{"total"},
+			})
+			Expect(err).To(BeNil())
+			Expect(FlattenAnnotations.RemovedFields).To(Equal([]string{"total"}))
+
+			// The other fields stay interactive.
+			GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(GetFormFields.Fields).To(HaveLen(3))
+			for i, name := range []string{"price", "quantity", "note"} {
+				Expect(GetFormFields.Fields[i].Name).To(Equal(name))
+				Expect(GetFormFields.Fields[i].Type).To(Equal(enums.FPDF_FORMFIELD_TYPE_TEXTFIELD))
+				Expect(GetFormFields.Fields[i].Widgets).To(HaveLen(1))
+			}
+
+			// The form editor reads the fields from the form, not from the
+			// widgets.
+			EditFormFields, err := PdfiumInstance.EditFormFields(&requests.EditFormFields{
+				Document: doc,
+				Edit: []requests.EditFormField{
+					{Name: "total", Delete: true},
+				},
+			})
+			Expect(err).To(MatchError("field total not found"))
+			Expect(EditFormFields).To(BeNil())
+
+			// The document can still be saved.
+			FPDF_SaveAsCopy, err := PdfiumInstance.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_SaveAsCopy.FileBytes).To(Not(BeNil()))
+		})
+
+		It("flattens the fields of a type", func() {
+			FlattenAnnotations, err := PdfiumInstance.FlattenAnnotations(&requests.FlattenAnnotations{
+				Document:   doc,
+				FieldTypes: []enums.FPDF_FORMFIELD_TYPE{enums.FPDF_FORMFIELD_TYPE_TEXTFIELD},
+			})
+			Expect(err).To(BeNil())
+			Expect(FlattenAnnotations.Fields).To(Equal([]string{"price", "quantity", "total", "note"}))
+			Expect(FlattenAnnotations.Annotations).To(Equal(4))
+			Expect(getFieldNames()).To(BeEmpty())
+		})
+
+		It("flattens nothing when no annotation has a selected subtype", func() {
+			FlattenAnnotations, err := PdfiumInstance.FlattenAnnotations(&requests.FlattenAnnotations{
+				Document: doc,
+				Subtypes: []enums.FPDF_ANNOTATION_SUBTYPE{enums.FPDF_ANNOT_SUBTYPE_SQUARE},
+			})
+			Expect(err).To(BeNil())
+			Expect(FlattenAnnotations.Fields).To(BeEmpty())
+			Expect(FlattenAnnotations.Annotations).To(Equal(0))
+			Expect(getFieldNames()).To(HaveLen(4))
+		})
+	})
+
+	Context("an encrypted PDF file with text fields", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/form_fields_encrypted.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("flattens the selected field and keeps it in the form", func() {
+			FlattenAnnotations, err := PdfiumInstance.FlattenAnnotations(&requests.FlattenAnnotations{
+				Document:   doc,
+				FieldNames: []string{"name"},
+			})
+			Expect(err).To(BeNil())
+			Expect(FlattenAnnotations.Fields).To(Equal([]string{"name"}))
+			Expect(FlattenAnnotations.Annotations).To(Equal(1))
+			Expect(FlattenAnnotations.RemovedFields).To(BeEmpty())
+
+			GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(GetFormFields.Fields).To(HaveLen(1))
+			Expect(GetFormFields.Fields[0].Name).To(Equal("city"))
+			Expect(GetFormFields.Fields[0].Value).To(Equal("Amsterdam"))
+		})
+	})
+})
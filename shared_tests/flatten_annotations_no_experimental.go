@@ -0,0 +1,28 @@
+//go:build !pdfium_experimental
+// +build !pdfium_experimental
+
+package shared_tests
+
+import (
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("flatten_annotations", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	It("returns an error when calling FlattenAnnotations", func() {
+		FlattenAnnotations, err := PdfiumInstance.FlattenAnnotations(&requests.FlattenAnnotations{})
+		Expect(err).To(MatchError(pdfium_errors.ErrExperimentalUnsupported.Error()))
+		Expect(FlattenAnnotations).To(BeNil())
+	})
+})
@@ -0,0 +1,193 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("form_accessibility", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling AuditFormAccessibility", func() {
+				AuditFormAccessibility, err := PdfiumInstance.AuditFormAccessibility(&requests.AuditFormAccessibility{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(AuditFormAccessibility).To(BeNil())
+			})
+		})
+	})
+
+	Context("a PDF file without structure tree", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/text_form.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("reports that the document is not tagged", func() {
+			AuditFormAccessibility, err := PdfiumInstance.AuditFormAccessibility(&requests.AuditFormAccessibility{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(AuditFormAccessibility.Tagged).To(BeFalse())
+			Expect(AuditFormAccessibility.Issues).To(ContainElement(responses.FormAccessibilityIssue{
+				Type:    responses.FormAccessibilityIssueTypeUntagged,
+				Page:    -1,
+				Widget:  -1,
+				Message: "document has no structure tree, fields can't have labels",
+			}))
+		})
+	})
+
+	Context("a tagged PDF file with text fields", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/form_accessibility.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("returns the accessibility issues of the fields", func() {
+			AuditFormAccessibility, err := PdfiumInstance.AuditFormAccessibility(&requests.AuditFormAccessibility{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(AuditFormAccessibility).To(Equal(&responses.AuditFormAccessibility{
+				Tagged: true,
+				Pages: []responses.FormAccessibilityPage{
+					{Page: 0, TabOrder: "S", Fields: []string{"last", "first", "email", "offpage"}},
+				},
+				Issues: []responses.FormAccessibilityIssue{
+					{Type: responses.FormAccessibilityIssueTypeMissingTooltip, Name: "last", Page: 0, Widget: -1, Message: "field last has no tooltip"},
+					{Type: responses.FormAccessibilityIssueTypeMissingTooltip, Name: "email", Page: 0, Widget: -1, Message: "field email has no tooltip"},
+					{Type: responses.FormAccessibilityIssueTypeMissingTooltip, Name: "offpage", Page: 0, Widget: -1, Message: "field offpage has no tooltip"},
+					{Type: responses.FormAccessibilityIssueTypeMissingLabel, Name: "last", Page: 0, Widget: 1, Message: "widget of field last has no alternate text in the structure tree"},
+					{Type: responses.FormAccessibilityIssueTypeMissingLabel, Name: "email", Page: 0, Widget: 2, Message: "widget of field email is not in the structure tree"},
+					{Type: responses.FormAccessibilityIssueTypeMissingLabel, Name: "offpage", Page: 0, Widget: 3, Message: "widget of field offpage is not in the structure tree"},
+					{Type: responses.FormAccessibilityIssueTypeOutsideCropBox, Name: "offpage", Page: 0, Widget: 3, Message: "widget of field offpage is outside the crop box"},
+					{Type: responses.FormAccessibilityIssueTypeTabOrder, Name: "first", Page: 0, Widget: 0, Message: "field first follows field last in the tab order, but is placed before it on the page"},
+				},
+			}))
+		})
+	})
+
+	Context("a tagged PDF file with a popup before the widgets", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/form_accessibility_popup.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("finds the structure elements of the widgets", func() {
+			AuditFormAccessibility, err := PdfiumInstance.AuditFormAccessibility(&requests.AuditFormAccessibility{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(AuditFormAccessibility).To(Equal(&responses.AuditFormAccessibility{
+				Tagged: true,
+				Pages: []responses.FormAccessibilityPage{
+					{Page: 0, TabOrder: "S", Fields: []string{"name", "city"}},
+				},
+				Issues: []responses.FormAccessibilityIssue{},
+			}))
+		})
+	})
+
+	Context("an encrypted PDF file", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/password_test123.pdf")
+			Expect(err).To(BeNil())
+
+			password := "test123"
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data:     &pdfData,
+				Password: &password,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("audits a copy without security", func() {
+			AuditFormAccessibility, err := PdfiumInstance.AuditFormAccessibility(&requests.AuditFormAccessibility{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(AuditFormAccessibility.Tagged).To(BeFalse())
+			Expect(AuditFormAccessibility.Pages).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,184 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("form_actions", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling RunFormActions", func() {
+				RunFormActions, err := PdfiumInstance.RunFormActions(&requests.RunFormActions{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(RunFormActions).To(BeNil())
+			})
+		})
+	})
+
+	Context("a PDF file with form actions", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/form_actions.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("returns an error when a field doesn't exist", func() {
+			RunFormActions, err := PdfiumInstance.RunFormActions(&requests.RunFormActions{
+				Document: doc,
+				Values: map[string]interface{}{
+					"unknown": "value",
+				},
+			})
+			Expect(err).To(MatchError("field unknown not found"))
+			Expect(RunFormActions).To(BeNil())
+		})
+
+		It("calculates and formats the fields", func() {
+			RunFormActions, err := PdfiumInstance.RunFormActions(&requests.RunFormActions{
+				Document: doc,
+				Values: map[string]interface{}{
+					"price":    "12.5",
+					"quantity": 4,
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(RunFormActions.ValidationErrors).To(BeEmpty())
+			Expect(RunFormActions.Fields).To(Equal([]responses.FormActionsField{
+				{Name: "price", Value: "12.5", FormattedValue: "$12.50"},
+				{Name: "quantity", Value: "4", FormattedValue: "4"},
+				{Name: "total", Value: "50", FormattedValue: "$50.00", Calculated: true},
+				{Name: "note", Value: "", FormattedValue: ""},
+			}))
+			Expect(RunFormActions.SkippedActions).To(Equal([]responses.FormActionsMessage{
+				{Name: "note", Event: enums.FPDF_ANNOT_AACTION_CALCULATE, Message: "script is not a function call"},
+			}))
+		})
+
+		It("returns the validation errors", func() {
+			RunFormActions, err := PdfiumInstance.RunFormActions(&requests.RunFormActions{
+				Document: doc,
+				Values: map[string]interface{}{
+					"price":    "2000",
+					"quantity": "a few",
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(RunFormActions.ValidationErrors).To(Equal([]responses.FormActionsMessage{
+				{Name: "price", Event: enums.FPDF_ANNOT_AACTION_VALIDATE, Message: "Invalid value: must be greater than or equal to 0 and less than or equal to 1000."},
+				{Name: "quantity", Event: enums.FPDF_ANNOT_AACTION_KEY_STROKE, Message: "The value entered does not match the format of the field [ quantity ]"},
+			}))
+		})
+	})
+
+	Context("a PDF file with a calculation order", func() {
+		var pdfData []byte
+		var doc references.FPDF_DOCUMENT
+
+		loadDocument := func(data []byte) {
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &data,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		}
+
+		BeforeEach(func() {
+			var err error
+			pdfData, err = ioutil.ReadFile(TestDataPath + "/testdata/form_calculation_order.pdf")
+			Expect(err).To(BeNil())
+			doc = ""
+		})
+
+		AfterEach(func() {
+			if doc == "" {
+				return
+			}
+
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("calculates the fields in the calculation order", func() {
+			loadDocument(pdfData)
+
+			RunFormActions, err := PdfiumInstance.RunFormActions(&requests.RunFormActions{
+				Document: doc,
+				Values: map[string]interface{}{
+					"a": "5",
+				},
+			})
+			Expect(err).To(BeNil())
+
+			// The calculation order calculates totals.c before totals.b and
+			// doesn't contain d.
+			Expect(RunFormActions.Fields).To(Equal([]responses.FormActionsField{
+				{Name: "totals.b", Value: "5", FormattedValue: "5", Calculated: true},
+				{Name: "totals.c", Value: "0", FormattedValue: "0", Calculated: true},
+				{Name: "d", Value: "", FormattedValue: ""},
+			}))
+			Expect(RunFormActions.SkippedActions).To(Equal([]responses.FormActionsMessage{
+				{Name: "d", Event: enums.FPDF_ANNOT_AACTION_CALCULATE, Message: "field is not in the calculation order of the form"},
+			}))
+		})
+
+		It("calculates the fields in the order of their dependencies without a calculation order", func() {
+			withoutCalculationOrder := bytes.Replace(pdfData, []byte("/CO [8 0 R 7 0 R]"), bytes.Repeat([]byte(" "), len("/CO [8 0 R 7 0 R]")), 1)
+			Expect(withoutCalculationOrder).To(Not(Equal(pdfData)))
+			loadDocument(withoutCalculationOrder)
+
+			RunFormActions, err := PdfiumInstance.RunFormActions(&requests.RunFormActions{
+				Document: doc,
+				Values: map[string]interface{}{
+					"a": "5",
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(RunFormActions.Fields).To(Equal([]responses.FormActionsField{
+				{Name: "totals.b", Value: "5", FormattedValue: "5", Calculated: true},
+				{Name: "totals.c", Value: "5", FormattedValue: "5", Calculated: true},
+				{Name: "d", Value: "5", FormattedValue: "5", Calculated: true},
+			}))
+			Expect(RunFormActions.SkippedActions).To(BeEmpty())
+		})
+	})
+})
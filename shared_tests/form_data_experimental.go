@@ -0,0 +1,244 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("form_data", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling ExportFormData", func() {
+				ExportFormData, err := PdfiumInstance.ExportFormData(&requests.ExportFormData{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(ExportFormData).To(BeNil())
+			})
+
+			It("returns an error when calling ImportFormData", func() {
+				ImportFormData, err := PdfiumInstance.ImportFormData(&requests.ImportFormData{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(ImportFormData).To(BeNil())
+			})
+		})
+	})
+
+	Context("a PDF file with a text field", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/text_form.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("returns an error for an unsupported format", func() {
+			ExportFormData, err := PdfiumInstance.ExportFormData(&requests.ExportFormData{
+				Document: doc,
+				Format:   "json",
+			})
+			Expect(err).To(MatchError("unsupported form data format json"))
+			Expect(ExportFormData).To(BeNil())
+		})
+
+		It("returns an error when including annotations in FDF", func() {
+			ExportFormData, err := PdfiumInstance.ExportFormData(&requests.ExportFormData{
+				Document:           doc,
+				Format:             requests.FormDataFormatFDF,
+				IncludeAnnotations: true,
+			})
+			Expect(err).To(MatchError("annotations are only supported for XFDF"))
+			Expect(ExportFormData).To(BeNil())
+		})
+
+		It("returns an error for data in an unknown format", func() {
+			ImportFormData, err := PdfiumInstance.ImportFormData(&requests.ImportFormData{
+				Document: doc,
+				Data:     []byte("Text Box=Hello"),
+			})
+			Expect(err).To(MatchError("could not detect the form data format"))
+			Expect(ImportFormData).To(BeNil())
+		})
+
+		It("exports the form data as FDF", func() {
+			_, err := PdfiumInstance.FillForm(&requests.FillForm{
+				Document: doc,
+				Values: map[string]interface{}{
+					"Text Box": "Hello (world)",
+				},
+			})
+			Expect(err).To(BeNil())
+
+			ExportFormData, err := PdfiumInstance.ExportFormData(&requests.ExportFormData{
+				Document: doc,
+				Format:   requests.FormDataFormatFDF,
+				FileName: "text_form.pdf",
+			})
+			Expect(err).To(BeNil())
+			Expect(string(ExportFormData.Data)).To(HavePrefix("%FDF-1.2"))
+			Expect(string(ExportFormData.Data)).To(ContainSubstring("/F (text_form.pdf)"))
+			Expect(string(ExportFormData.Data)).To(ContainSubstring("<< /T (Text Box) /V (Hello \\(world\\)) >>"))
+		})
+
+		It("exports the form data as XFDF", func() {
+			_, err := PdfiumInstance.FillForm(&requests.FillForm{
+				Document: doc,
+				Values: map[string]interface{}{
+					"Text Box": "Hello",
+				},
+			})
+			Expect(err).To(BeNil())
+
+			ExportFormData, err := PdfiumInstance.ExportFormData(&requests.ExportFormData{
+				Document: doc,
+				Format:   requests.FormDataFormatXFDF,
+			})
+			Expect(err).To(BeNil())
+			Expect(string(ExportFormData.Data)).To(ContainSubstring(`<xfdf xmlns="http://ns.adobe.com/xfdf/">`))
+			Expect(string(ExportFormData.Data)).To(ContainSubstring(`<field name="Text Box">`))
+			Expect(string(ExportFormData.Data)).To(ContainSubstring(`<value>Hello</value>`))
+		})
+
+		It("imports FDF data and skips unknown fields", func() {
+			ImportFormData, err := PdfiumInstance.ImportFormData(&requests.ImportFormData{
+				Document: doc,
+				Data: []byte("%FDF-1.2\n1 0 obj\n<< /FDF << /Fields [ << /T (Text Box) /V <FEFF00480069> >> << /T (Unknown) /V (value) >> ] >> >>\nendobj\n" +
+					"trailer\n<< /Root 1 0 R >>\n%%EOF\n"),
+			})
+			Expect(err).To(BeNil())
+			Expect(ImportFormData.Fields).To(Equal([]responses.FillFormField{
+				{Name: "Text Box", Value: "Hi"},
+			}))
+			Expect(ImportFormData.SkippedFields).To(Equal([]string{"Unknown"}))
+		})
+
+		It("imports exported XFDF data with annotations", func() {
+			ImportFormData, err := PdfiumInstance.ImportFormData(&requests.ImportFormData{
+				Document: doc,
+				Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<xfdf xmlns="http://ns.adobe.com/xfdf/">
+  <fields>
+    <field name="Text Box"><value>Hello</value></field>
+  </fields>
+  <annots>
+    <square page="0" rect="10,10,50,50" color="#FF0000" title="Reviewer">
+      <contents>Check this</contents>
+    </square>
+  </annots>
+</xfdf>`),
+				ImportAnnotations: true,
+			})
+			Expect(err).To(BeNil())
+			Expect(ImportFormData.Fields).To(Equal([]responses.FillFormField{
+				{Name: "Text Box", Value: "Hello"},
+			}))
+			Expect(ImportFormData.Annotations).To(Equal(1))
+
+			ExportFormData, err := PdfiumInstance.ExportFormData(&requests.ExportFormData{
+				Document:           doc,
+				Format:             requests.FormDataFormatXFDF,
+				IncludeAnnotations: true,
+			})
+			Expect(err).To(BeNil())
+			Expect(string(ExportFormData.Data)).To(ContainSubstring(`<square page="0" rect="10,10,50,50" color="#FF0000" title="Reviewer">`))
+			Expect(string(ExportFormData.Data)).To(ContainSubstring(`<contents>Check this</contents>`))
+		})
+
+		It("doesn't import anything when an annotation is on a page that doesn't exist", func() {
+			ImportFormData, err := PdfiumInstance.ImportFormData(&requests.ImportFormData{
+				Document: doc,
+				Data: []byte(`<xfdf xmlns="http://ns.adobe.com/xfdf/">
+  <fields>
+    <field name="Text Box"><value>Hello</value></field>
+  </fields>
+  <annots>
+    <square page="0" rect="10,10,50,50"/>
+    <square page="3" rect="10,10,50,50"/>
+  </annots>
+</xfdf>`),
+				ImportAnnotations: true,
+			})
+			Expect(err).To(MatchError("page 3 of annotation 1 doesn't exist"))
+			Expect(ImportFormData).To(BeNil())
+
+			ExportFormData, err := PdfiumInstance.ExportFormData(&requests.ExportFormData{
+				Document:           doc,
+				Format:             requests.FormDataFormatXFDF,
+				IncludeAnnotations: true,
+			})
+			Expect(err).To(BeNil())
+			Expect(string(ExportFormData.Data)).To(Not(ContainSubstring("Hello")))
+			Expect(string(ExportFormData.Data)).To(Not(ContainSubstring("<square")))
+		})
+	})
+
+	Context("a PDF file with comboboxes", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/combobox_form.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("skips fields that can't be filled", func() {
+			ImportFormData, err := PdfiumInstance.ImportFormData(&requests.ImportFormData{
+				Document: doc,
+				Format:   requests.FormDataFormatXFDF,
+				Data: []byte(`<xfdf xmlns="http://ns.adobe.com/xfdf/"><fields>` +
+					`<field name="Combo1"><value>Cherry</value></field>` +
+					`<field name="Combo_ReadOnly"><value>Frog</value></field>` +
+					`</fields></xfdf>`),
+			})
+			Expect(err).To(BeNil())
+			Expect(ImportFormData.Fields).To(Equal([]responses.FillFormField{
+				{Name: "Combo1", Value: "Cherry"},
+			}))
+			Expect(ImportFormData.SkippedFields).To(Equal([]string{"Combo_ReadOnly"}))
+		})
+	})
+})
@@ -0,0 +1,34 @@
+//go:build !pdfium_experimental
+// +build !pdfium_experimental
+
+package shared_tests
+
+import (
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("form_data", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	It("returns an error when calling ExportFormData", func() {
+		ExportFormData, err := PdfiumInstance.ExportFormData(&requests.ExportFormData{})
+		Expect(err).To(MatchError(pdfium_errors.ErrExperimentalUnsupported.Error()))
+		Expect(ExportFormData).To(BeNil())
+	})
+
+	It("returns an error when calling ImportFormData", func() {
+		ImportFormData, err := PdfiumInstance.ImportFormData(&requests.ImportFormData{})
+		Expect(err).To(MatchError(pdfium_errors.ErrExperimentalUnsupported.Error()))
+		Expect(ImportFormData).To(BeNil())
+	})
+})
@@ -0,0 +1,131 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("form_fields", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling GetFormFields", func() {
+				GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(GetFormFields).To(BeNil())
+			})
+		})
+	})
+
+	Context("a PDF file with a text field", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/text_form.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("returns the form fields", func() {
+			GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(GetFormFields.Fields).To(HaveLen(1))
+
+			field := GetFormFields.Fields[0]
+			Expect(field.Name).To(Equal("Text Box"))
+			Expect(field.Type).To(Equal(enums.FPDF_FORMFIELD_TYPE_TEXTFIELD))
+			Expect(field.Value).To(Equal(""))
+			Expect(field.Page).To(Equal(0))
+			Expect(field.Rect.Left).To(BeNumerically("~", 100, 0.01))
+			Expect(field.Rect.Bottom).To(BeNumerically("~", 100, 0.01))
+			Expect(field.Rect.Right).To(BeNumerically("~", 200, 0.01))
+			Expect(field.Rect.Top).To(BeNumerically("~", 130, 0.01))
+			Expect(field.Widgets).To(HaveLen(1))
+			Expect(field.Widgets[0].Page).To(Equal(0))
+			Expect(field.Widgets[0].Rect).To(Equal(field.Rect))
+		})
+	})
+
+	Context("a PDF file with comboboxes", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/combobox_form.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("returns the form fields with their options", func() {
+			GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(GetFormFields.Fields).To(HaveLen(3))
+
+			Expect(GetFormFields.Fields[0].Name).To(Equal("Combo_Editable"))
+			Expect(GetFormFields.Fields[0].Type).To(Equal(enums.FPDF_FORMFIELD_TYPE_COMBOBOX))
+			Expect(GetFormFields.Fields[0].Options).To(Equal([]responses.FormFieldOption{
+				{Label: "Foo"},
+				{Label: "Bar"},
+				{Label: "Qux"},
+			}))
+
+			Expect(GetFormFields.Fields[1].Name).To(Equal("Combo1"))
+			Expect(GetFormFields.Fields[1].Value).To(Equal("Banana"))
+			Expect(GetFormFields.Fields[1].Options[1]).To(Equal(responses.FormFieldOption{
+				Label:    "Banana",
+				Selected: true,
+			}))
+
+			Expect(GetFormFields.Fields[2].Name).To(Equal("Combo_ReadOnly"))
+			Expect(GetFormFields.Fields[2].Flags & enums.FPDF_FORMFLAG_READONLY).To(Equal(enums.FPDF_FORMFLAG_READONLY))
+		})
+	})
+})
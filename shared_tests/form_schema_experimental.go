@@ -0,0 +1,135 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("form_schema", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling GetFormSchema", func() {
+				GetFormSchema, err := PdfiumInstance.GetFormSchema(&requests.GetFormSchema{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(GetFormSchema).To(BeNil())
+			})
+		})
+	})
+
+	Context("a PDF file with a text field", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/text_form.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("returns the schema with the layout of the field", func() {
+			GetFormSchema, err := PdfiumInstance.GetFormSchema(&requests.GetFormSchema{
+				Document: doc,
+				Title:    "Text form",
+			})
+			Expect(err).To(BeNil())
+			Expect(GetFormSchema.Fields).To(HaveLen(1))
+			Expect(GetFormSchema.Fields[0].Name).To(Equal("Text Box"))
+			Expect(GetFormSchema.Fields[0].Type).To(Equal(enums.FPDF_FORMFIELD_TYPE_TEXTFIELD))
+			Expect(GetFormSchema.Fields[0].TabIndex).To(Equal(0))
+			Expect(GetFormSchema.Fields[0].Rect.Left).To(BeNumerically("~", 100, 0.01))
+
+			schema := map[string]interface{}{}
+			Expect(json.Unmarshal(GetFormSchema.Schema, &schema)).To(BeNil())
+			Expect(schema["title"]).To(Equal("Text form"))
+			Expect(schema["type"]).To(Equal("object"))
+
+			properties := schema["properties"].(map[string]interface{})
+			property := properties["Text Box"].(map[string]interface{})
+			Expect(property["type"]).To(Equal("string"))
+			Expect(property["default"]).To(Equal(""))
+
+			layout := property["x-pdf"].(map[string]interface{})
+			Expect(layout["type"]).To(Equal("text"))
+			Expect(layout["page"]).To(Equal(float64(0)))
+			Expect(layout["tabIndex"]).To(Equal(float64(0)))
+		})
+	})
+
+	Context("a PDF file with comboboxes", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/combobox_form.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("returns the options and flags of the fields", func() {
+			GetFormSchema, err := PdfiumInstance.GetFormSchema(&requests.GetFormSchema{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(GetFormSchema.Fields).To(HaveLen(3))
+
+			schema := map[string]interface{}{}
+			Expect(json.Unmarshal(GetFormSchema.Schema, &schema)).To(BeNil())
+
+			properties := schema["properties"].(map[string]interface{})
+
+			combo1 := properties["Combo1"].(map[string]interface{})
+			Expect(combo1["enum"]).To(ContainElement("Banana"))
+			Expect(combo1["default"]).To(Equal("Banana"))
+
+			comboEditable := properties["Combo_Editable"].(map[string]interface{})
+			Expect(comboEditable["examples"]).To(Equal([]interface{}{"Foo", "Bar", "Qux"}))
+
+			comboReadOnly := properties["Combo_ReadOnly"].(map[string]interface{})
+			Expect(comboReadOnly["readOnly"]).To(Equal(true))
+		})
+	})
+})
@@ -0,0 +1,227 @@
+package shared_tests
+
+import (
+	"io/ioutil"
+	"time"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/helpers"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("form session", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no instance", func() {
+		It("returns an error when calling NewFormSession", func() {
+			formSession, err := helpers.NewFormSession(nil, "", helpers.FormSessionConfig{})
+			Expect(err).To(MatchError("instance not given"))
+			Expect(formSession).To(BeNil())
+		})
+	})
+
+	Context("a normal PDF file with a text form", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/text_form.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("returns an error when the DPI is negative", func() {
+			formSession, err := helpers.NewFormSession(PdfiumInstance, doc, helpers.FormSessionConfig{
+				DPI: -1,
+			})
+			Expect(err).To(MatchError("DPI can't be negative"))
+			Expect(formSession).To(BeNil())
+		})
+
+		When("a form session is started", func() {
+			var formSession *helpers.FormSession
+
+			BeforeEach(func() {
+				newFormSession, err := helpers.NewFormSession(PdfiumInstance, doc, helpers.FormSessionConfig{
+					DPI:      144,
+					TileSize: 128,
+				})
+				Expect(err).To(BeNil())
+				formSession = newFormSession
+			})
+
+			AfterEach(func() {
+				Expect(formSession.Close()).To(BeNil())
+			})
+
+			It("returns the page size in pixels", func() {
+				width, height, err := formSession.PageSize(0)
+				Expect(err).To(BeNil())
+				Expect(width).To(Equal(600))
+				Expect(height).To(Equal(600))
+			})
+
+			It("renders the page", func() {
+				img, err := formSession.RenderPage(0)
+				Expect(err).To(BeNil())
+				Expect(img.Bounds().Dx()).To(Equal(600))
+				Expect(img.Bounds().Dy()).To(Equal(600))
+			})
+
+			It("returns an error when the page doesn't exist", func() {
+				update, err := formSession.MouseDown(1, 0, 0, 0)
+				Expect(err).To(Not(BeNil()))
+				Expect(update).To(BeNil())
+			})
+
+			It("ignores keyboard events when nothing has been clicked", func() {
+				update, err := formSession.Char('A', 0)
+				Expect(err).To(BeNil())
+				Expect(update.Tiles).To(BeEmpty())
+				Expect(update.Changed).To(BeFalse())
+			})
+
+			It("allows typing into the text field and undoing it", func() {
+				update, err := formSession.MouseDown(0, 240, 360, 0)
+				Expect(err).To(BeNil())
+				Expect(update.Tiles).To(Not(BeEmpty()))
+				for _, tile := range update.Tiles {
+					Expect(tile.Page).To(Equal(0))
+					Expect(tile.X % 128).To(Equal(0))
+					Expect(tile.Y % 128).To(Equal(0))
+					Expect(tile.Image.Bounds().Dx()).To(BeNumerically("<=", 128))
+					Expect(tile.Image.Bounds().Dy()).To(BeNumerically("<=", 128))
+				}
+
+				_, err = formSession.MouseUp(0, 240, 360, 0)
+				Expect(err).To(BeNil())
+
+				for _, char := range "go" {
+					update, err = formSession.Char(char, 0)
+					Expect(err).To(BeNil())
+				}
+				Expect(update.Changed).To(BeTrue())
+				Expect(update.Tiles).To(Not(BeEmpty()))
+				Expect(update.CanUndo).To(BeTrue())
+
+				update, err = formSession.KeyDown(enums.FWL_VKEY_Left, enums.FWL_EVENTFLAG_ShiftKey)
+				Expect(err).To(BeNil())
+				Expect(update.SelectedText).To(Equal("o"))
+
+				update, err = formSession.Undo()
+				Expect(err).To(BeNil())
+				Expect(update.CanRedo).To(BeTrue())
+			})
+
+			It("fires the timer of the caret on tick", func() {
+				_, err := formSession.MouseDown(0, 240, 360, 0)
+				Expect(err).To(BeNil())
+
+				_, err = formSession.MouseUp(0, 240, 360, 0)
+				Expect(err).To(BeNil())
+
+				// The caret blinks every 500 milliseconds.
+				time.Sleep(time.Millisecond * 600)
+
+				update, err := formSession.Tick()
+				Expect(err).To(BeNil())
+				Expect(update.Tiles).To(Not(BeEmpty()))
+			})
+
+			It("returns an error when the session is closed twice", func() {
+				Expect(formSession.Close()).To(BeNil())
+				Expect(formSession.Close()).To(MatchError("form session is already closed"))
+
+				newFormSession, err := helpers.NewFormSession(PdfiumInstance, doc, helpers.FormSessionConfig{})
+				Expect(err).To(BeNil())
+				formSession = newFormSession
+			})
+		})
+	})
+
+	Context("a PDF file with a text form on a rotated page", func() {
+		var doc references.FPDF_DOCUMENT
+		var formSession *helpers.FormSession
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/text_form_rotated.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+
+			newFormSession, err := helpers.NewFormSession(PdfiumInstance, doc, helpers.FormSessionConfig{
+				DPI: 144,
+			})
+			Expect(err).To(BeNil())
+			formSession = newFormSession
+		})
+
+		AfterEach(func() {
+			Expect(formSession.Close()).To(BeNil())
+
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("returns the rotated page size in pixels", func() {
+			width, height, err := formSession.PageSize(0)
+			Expect(err).To(BeNil())
+			Expect(width).To(Equal(800))
+			Expect(height).To(Equal(600))
+		})
+
+		It("allows typing into the text field", func() {
+			// The field is at 100,100 - 200,130 on the page, which is rotated
+			// 90 degrees clockwise, so it's at 200,200 - 260,400 in pixels.
+			_, err := formSession.MouseDown(0, 230, 300, 0)
+			Expect(err).To(BeNil())
+
+			_, err = formSession.MouseUp(0, 230, 300, 0)
+			Expect(err).To(BeNil())
+
+			update, err := formSession.Char('A', 0)
+			Expect(err).To(BeNil())
+			Expect(update.Changed).To(BeTrue())
+			Expect(update.Tiles).To(Not(BeEmpty()))
+			for _, tile := range update.Tiles {
+				Expect(tile.X).To(BeNumerically("<", 260))
+				Expect(tile.Y).To(BeNumerically("<", 400))
+			}
+
+			update, err = formSession.KeyDown(enums.FWL_VKEY_Left, enums.FWL_EVENTFLAG_ShiftKey)
+			Expect(err).To(BeNil())
+			Expect(update.SelectedText).To(Equal("A"))
+		})
+	})
+})
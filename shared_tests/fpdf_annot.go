@@ -1630,10 +1630,6 @@ var _ = Describe("fpdf_annot", func() {
 		var formHandle references.FPDF_FORMHANDLE
 
 		BeforeEach(func() {
-			if TestType == "multi" {
-				Skip("Form filling is not supported on multi-threaded usage")
-			}
-
 			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/click_form.pdf")
 			Expect(err).To(BeNil())
 
@@ -1673,10 +1669,6 @@ var _ = Describe("fpdf_annot", func() {
 		})
 
 		AfterEach(func() {
-			if TestType == "multi" {
-				Skip("Form filling is not supported on multi-threaded usage")
-			}
-
 			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
 				Document: doc,
 			})
@@ -1737,10 +1729,6 @@ var _ = Describe("fpdf_annot", func() {
 		var formHandle references.FPDF_FORMHANDLE
 
 		BeforeEach(func() {
-			if TestType == "multi" {
-				Skip("Form filling is not supported on multi-threaded usage")
-			}
-
 			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/annot_javascript.pdf")
 			Expect(err).To(BeNil())
 
@@ -1780,10 +1768,6 @@ var _ = Describe("fpdf_annot", func() {
 		})
 
 		AfterEach(func() {
-			if TestType == "multi" {
-				Skip("Form filling is not supported on multi-threaded usage")
-			}
-
 			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
 				Document: doc,
 			})
@@ -7,6 +7,7 @@ import (
 	"image"
 	"image/jpeg"
 	"io/ioutil"
+	"sync"
 	"time"
 
 	"github.com/klippa-app/go-pdfium/enums"
@@ -21,16 +22,10 @@ import (
 
 var _ = Describe("fpdf_formfill", func() {
 	BeforeEach(func() {
-		if TestType == "multi" {
-			Skip("Form filling is not supported on multi-threaded usage")
-		}
 		Locker.Lock()
 	})
 
 	AfterEach(func() {
-		if TestType == "multi" {
-			Skip("Form filling is not supported on multi-threaded usage")
-		}
 		Locker.Unlock()
 	})
 
@@ -1388,6 +1383,180 @@ var _ = Describe("fpdf_formfill", func() {
 		})
 	})
 
+	Context("a PDF file with a text form and recorded callbacks", func() {
+		var doc references.FPDF_DOCUMENT
+		var formHandle references.FPDF_FORMHANDLE
+		var page references.FPDF_PAGE
+
+		// The callbacks can be called from another goroutine in
+		// multi-threaded usage.
+		var callbackLock sync.Mutex
+		var invalidated []references.FPDF_PAGE
+		var setTimers []int
+		var killedTimers []int
+		timerFuncs := map[int]func(idEvent int){}
+
+		getInvalidated := func() []references.FPDF_PAGE {
+			callbackLock.Lock()
+			defer callbackLock.Unlock()
+			return append([]references.FPDF_PAGE{}, invalidated...)
+		}
+
+		BeforeEach(func() {
+			invalidated = nil
+			setTimers = nil
+			killedTimers = nil
+			timerFuncs = map[int]func(idEvent int){}
+
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/text_form.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+
+			FPDFDOC_InitFormFillEnvironment, err := PdfiumInstance.FPDFDOC_InitFormFillEnvironment(&requests.FPDFDOC_InitFormFillEnvironment{
+				Document: doc,
+				FormFillInfo: structs.FPDF_FORMFILLINFO{
+					FFI_Invalidate: func(page references.FPDF_PAGE, left, top, right, bottom float64) {
+						callbackLock.Lock()
+						defer callbackLock.Unlock()
+						invalidated = append(invalidated, page)
+					},
+					FFI_SetCursor: func(cursorType enums.FXCT) {},
+					FFI_SetTimer: func(elapse int, timerFunc func(idEvent int)) int {
+						callbackLock.Lock()
+						defer callbackLock.Unlock()
+
+						// The timers are fired by the test, ID can't be 0.
+						id := len(timerFuncs) + 1
+						timerFuncs[id] = timerFunc
+						setTimers = append(setTimers, id)
+						return id
+					},
+					FFI_KillTimer: func(timerID int) {
+						callbackLock.Lock()
+						defer callbackLock.Unlock()
+						killedTimers = append(killedTimers, timerID)
+					},
+					FFI_GetLocalTime: func() structs.FPDF_SYSTEMTIME {
+						return structs.FPDF_SYSTEMTIME{}
+					},
+					FFI_GetPage: func(document references.FPDF_DOCUMENT, index int) *references.FPDF_PAGE {
+						return nil
+					},
+					FFI_GetRotation: func(page references.FPDF_PAGE) enums.FPDF_PAGE_ROTATION {
+						return enums.FPDF_PAGE_ROTATION_NONE
+					},
+					FFI_ExecuteNamedAction: func(namedAction string) {},
+				},
+			})
+			Expect(err).To(BeNil())
+			formHandle = FPDFDOC_InitFormFillEnvironment.FormHandle
+
+			FPDF_LoadPage, err := PdfiumInstance.FPDF_LoadPage(&requests.FPDF_LoadPage{
+				Document: doc,
+				Index:    0,
+			})
+			Expect(err).To(BeNil())
+			page = FPDF_LoadPage.Page
+
+			_, err = PdfiumInstance.FORM_OnAfterLoadPage(&requests.FORM_OnAfterLoadPage{
+				Page: requests.Page{
+					ByReference: &page,
+				},
+				FormHandle: formHandle,
+			})
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			_, err := PdfiumInstance.FORM_OnBeforeClosePage(&requests.FORM_OnBeforeClosePage{
+				Page: requests.Page{
+					ByReference: &page,
+				},
+				FormHandle: formHandle,
+			})
+			Expect(err).To(BeNil())
+
+			_, err = PdfiumInstance.FPDF_ClosePage(&requests.FPDF_ClosePage{
+				Page: page,
+			})
+			Expect(err).To(BeNil())
+
+			_, err = PdfiumInstance.FPDFDOC_ExitFormFillEnvironment(&requests.FPDFDOC_ExitFormFillEnvironment{
+				FormHandle: formHandle,
+			})
+			Expect(err).To(BeNil())
+
+			_, err = PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+		})
+
+		It("calls the callbacks and allows to fire the timers", func() {
+			_, err := PdfiumInstance.FORM_OnLButtonDown(&requests.FORM_OnLButtonDown{
+				Page: requests.Page{
+					ByReference: &page,
+				},
+				FormHandle: formHandle,
+				PageX:      120,
+				PageY:      120,
+			})
+			Expect(err).To(BeNil())
+
+			_, err = PdfiumInstance.FORM_OnLButtonUp(&requests.FORM_OnLButtonUp{
+				Page: requests.Page{
+					ByReference: &page,
+				},
+				FormHandle: formHandle,
+				PageX:      120,
+				PageY:      120,
+			})
+			Expect(err).To(BeNil())
+
+			_, err = PdfiumInstance.FORM_OnChar(&requests.FORM_OnChar{
+				Page: requests.Page{
+					ByReference: &page,
+				},
+				FormHandle: formHandle,
+				NChar:      'A',
+			})
+			Expect(err).To(BeNil())
+
+			// Focusing the text field invalidates it and starts the timer of
+			// the caret.
+			Expect(getInvalidated()).ToNot(BeEmpty())
+			Expect(getInvalidated()).To(HaveEach(page))
+
+			callbackLock.Lock()
+			Expect(setTimers).ToNot(BeEmpty())
+			timerID := setTimers[0]
+			timerFunc := timerFuncs[timerID]
+			invalidated = nil
+			callbackLock.Unlock()
+
+			// Firing the timer makes the caret blink, which invalidates the
+			// field again. The timer function returns when PDFium is done.
+			timerFunc(timerID)
+			Expect(getInvalidated()).ToNot(BeEmpty())
+			Expect(getInvalidated()).To(HaveEach(page))
+
+			_, err = PdfiumInstance.FORM_ForceToKillFocus(&requests.FORM_ForceToKillFocus{
+				FormHandle: formHandle,
+			})
+			Expect(err).To(BeNil())
+
+			callbackLock.Lock()
+			defer callbackLock.Unlock()
+			Expect(killedTimers).To(ContainElement(timerID))
+		})
+	})
+
 	Context("a normal PDF file", func() {
 		var doc references.FPDF_DOCUMENT
 
@@ -24,16 +24,10 @@ import (
 
 var _ = Describe("fpdf_formfill_experimental", func() {
 	BeforeEach(func() {
-		if TestType == "multi" {
-			Skip("Form filling is not supported on multi-threaded usage")
-		}
 		Locker.Lock()
 	})
 
 	AfterEach(func() {
-		if TestType == "multi" {
-			Skip("Form filling is not supported on multi-threaded usage")
-		}
 		Locker.Unlock()
 	})
 
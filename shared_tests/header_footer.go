@@ -0,0 +1,127 @@
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("header footer", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling AddHeaderFooter", func() {
+				AddHeaderFooter, err := PdfiumInstance.AddHeaderFooter(&requests.AddHeaderFooter{})
+				Expect(err).To(MatchError("no documents given"))
+				Expect(AddHeaderFooter).To(BeNil())
+			})
+
+			It("returns an error when calling AddHeaderFooter with an unknown document", func() {
+				AddHeaderFooter, err := PdfiumInstance.AddHeaderFooter(&requests.AddHeaderFooter{
+					Documents: []references.FPDF_DOCUMENT{""},
+				})
+				Expect(err).To(MatchError("document not given"))
+				Expect(AddHeaderFooter).To(BeNil())
+			})
+		})
+	})
+
+	Context("multiple PDF files", func() {
+		var doc references.FPDF_DOCUMENT
+		var multiPageDoc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/hello_world.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+			doc = newDoc.Document
+
+			multiPagePdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/test_multipage.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err = PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &multiPagePdfData,
+			})
+			Expect(err).To(BeNil())
+			multiPageDoc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			for _, document := range []references.FPDF_DOCUMENT{doc, multiPageDoc} {
+				FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+					Document: document,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDF_CloseDocument).To(Not(BeNil()))
+			}
+		})
+
+		When("AddHeaderFooter is called", func() {
+			It("returns an error for an unknown template variable", func() {
+				AddHeaderFooter, err := PdfiumInstance.AddHeaderFooter(&requests.AddHeaderFooter{
+					Documents:   []references.FPDF_DOCUMENT{doc},
+					FooterRight: "{chapter}",
+				})
+				Expect(err).To(MatchError("unknown template variable {chapter}"))
+				Expect(AddHeaderFooter).To(BeNil())
+			})
+
+			It("returns an error for a width on a text variable", func() {
+				AddHeaderFooter, err := PdfiumInstance.AddHeaderFooter(&requests.AddHeaderFooter{
+					Documents:  []references.FPDF_DOCUMENT{doc},
+					HeaderLeft: "{prefix:06}",
+				})
+				Expect(err).To(MatchError("template variable {prefix} can't have a width"))
+				Expect(AddHeaderFooter).To(BeNil())
+			})
+
+			It("adds Bates numbers over multiple documents", func() {
+				AddHeaderFooter, err := PdfiumInstance.AddHeaderFooter(&requests.AddHeaderFooter{
+					Documents:    []references.FPDF_DOCUMENT{doc, multiPageDoc},
+					FooterRight:  "{prefix}{number:06} - Page {page} of {total}",
+					HeaderCenter: "Confidential",
+					Prefix:       "ABC",
+					StartNumber:  41,
+					MarginX:      36,
+					MarginY:      24,
+				})
+				Expect(err).To(BeNil())
+				Expect(AddHeaderFooter).To(Equal(&responses.AddHeaderFooter{
+					Documents: []responses.AddHeaderFooterDocument{
+						{FirstNumber: 41, LastNumber: 41},
+						{FirstNumber: 42, LastNumber: 43},
+					},
+					NextNumber: 44,
+				}))
+
+				GetPageText, err := PdfiumInstance.GetPageText(&requests.GetPageText{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: multiPageDoc,
+							Index:    1,
+						},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(GetPageText.Text).To(ContainSubstring("ABC000043 - Page 2 of 2"))
+				Expect(GetPageText.Text).To(ContainSubstring("Confidential"))
+			})
+		})
+	})
+})
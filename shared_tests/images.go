@@ -0,0 +1,141 @@
+package shared_tests
+
+import (
+	"bytes"
+	"image/png"
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("images", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling GetPageImages", func() {
+				GetPageImages, err := PdfiumInstance.GetPageImages(&requests.GetPageImages{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Index: 0,
+						},
+					},
+				})
+				Expect(err).To(MatchError("document not given"))
+				Expect(GetPageImages).To(BeNil())
+			})
+
+			It("returns an error when calling GetDocumentImages", func() {
+				GetDocumentImages, err := PdfiumInstance.GetDocumentImages(&requests.GetDocumentImages{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(GetDocumentImages).To(BeNil())
+			})
+		})
+	})
+
+	Context("a PDF file with embedded images", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/embedded_images.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("returns an error for an unsupported export format", func() {
+			GetPageImages, err := PdfiumInstance.GetPageImages(&requests.GetPageImages{
+				Page: requests.Page{
+					ByIndex: &requests.PageByIndex{
+						Document: doc,
+						Index:    0,
+					},
+				},
+				ExportFormat: "gif",
+			})
+			Expect(err).To(MatchError("unsupported export format gif"))
+			Expect(GetPageImages).To(BeNil())
+		})
+
+		It("returns the images of a page", func() {
+			GetPageImages, err := PdfiumInstance.GetPageImages(&requests.GetPageImages{
+				Page: requests.Page{
+					ByIndex: &requests.PageByIndex{
+						Document: doc,
+						Index:    0,
+					},
+				},
+				IncludeRawData:     true,
+				IncludeDecodedData: true,
+			})
+			Expect(err).To(BeNil())
+			Expect(GetPageImages.Page).To(Equal(0))
+			Expect(GetPageImages.Images).To(Not(BeEmpty()))
+
+			firstImage := GetPageImages.Images[0]
+			Expect(firstImage.ObjectPath).To(Equal([]int{33}))
+			Expect(firstImage.Filters).To(Equal([]string{"FlateDecode"}))
+			Expect(firstImage.Metadata.Width).To(Equal(uint(109)))
+			Expect(firstImage.Metadata.Height).To(Equal(uint(88)))
+			Expect(firstImage.Metadata.Colorspace).To(Equal(enums.FPDF_COLORSPACE_DEVICERGB))
+			Expect(firstImage.Rect.Left).To(BeNumerically("~", 72, 0.01))
+			Expect(firstImage.Rect.Top).To(BeNumerically("~", 689.51, 0.01))
+			Expect(firstImage.RawData).To(HaveLen(4091))
+			Expect(firstImage.DecodedData).To(HaveLen(28776))
+			Expect(firstImage.ExportData).To(BeNil())
+		})
+
+		It("exports the images of a document as PNG", func() {
+			GetDocumentImages, err := PdfiumInstance.GetDocumentImages(&requests.GetDocumentImages{
+				Document:     doc,
+				ExportFormat: requests.ImageExportFormatPNG,
+			})
+			Expect(err).To(BeNil())
+			Expect(GetDocumentImages.Pages).To(HaveLen(1))
+			Expect(GetDocumentImages.Pages[0].Images).To(Not(BeEmpty()))
+
+			firstImage := GetDocumentImages.Pages[0].Images[0]
+			Expect(firstImage.RawData).To(BeNil())
+			Expect(firstImage.ExportFormat).To(Equal(responses.ImageExportFormatPNG))
+
+			exportedImage, err := png.Decode(bytes.NewReader(firstImage.ExportData))
+			Expect(err).To(BeNil())
+			Expect(exportedImage.Bounds().Dx()).To(Equal(109))
+			Expect(exportedImage.Bounds().Dy()).To(Equal(88))
+		})
+
+		It("returns an error when a page is out of bounds", func() {
+			GetDocumentImages, err := PdfiumInstance.GetDocumentImages(&requests.GetDocumentImages{
+				Document: doc,
+				Pages:    []int{1},
+			})
+			Expect(err).To(MatchError("page 1 is out of bounds, document has 1 pages"))
+			Expect(GetDocumentImages).To(BeNil())
+		})
+	})
+})
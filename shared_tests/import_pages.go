@@ -0,0 +1,243 @@
+package shared_tests
+
+import (
+	"io/ioutil"
+	"time"
+
+	"github.com/klippa-app/go-pdfium"
+	"github.com/klippa-app/go-pdfium/helpers"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("import pages", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no instance", func() {
+		It("returns an error when calling ImportPages", func() {
+			err := helpers.ImportPages(nil, "", 0)
+			Expect(err).To(MatchError("destination instance not given"))
+		})
+	})
+
+	Context("a destination document", func() {
+		var destinationDoc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			newDoc, err := PdfiumInstance.FPDF_CreateNewDocument(&requests.FPDF_CreateNewDocument{})
+			Expect(err).To(BeNil())
+
+			destinationDoc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: destinationDoc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		getDestinationPageCount := func() int {
+			pageCount, err := PdfiumInstance.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+				Document: destinationDoc,
+			})
+			Expect(err).To(BeNil())
+			return pageCount.PageCount
+		}
+
+		It("returns an error when the source instance is not given", func() {
+			err := helpers.ImportPages(PdfiumInstance, destinationDoc, 0, helpers.ImportPagesSource{})
+			Expect(err).To(MatchError("instance of source 0 not given"))
+		})
+
+		When("the source document is in the same instance", func() {
+			var sourceDoc references.FPDF_DOCUMENT
+
+			BeforeEach(func() {
+				pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/test_multipage.pdf")
+				Expect(err).To(BeNil())
+
+				newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+					Data: &pdfData,
+				})
+				Expect(err).To(BeNil())
+
+				sourceDoc = newDoc.Document
+			})
+
+			AfterEach(func() {
+				FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+					Document: sourceDoc,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDF_CloseDocument).To(Not(BeNil()))
+			})
+
+			It("imports the pages of multiple sources", func() {
+				pageRange := "2"
+				err := helpers.ImportPages(PdfiumInstance, destinationDoc, 0,
+					helpers.ImportPagesSource{Instance: PdfiumInstance, Document: sourceDoc},
+					helpers.ImportPagesSource{Instance: PdfiumInstance, Document: sourceDoc, PageRange: &pageRange},
+				)
+				Expect(err).To(BeNil())
+				Expect(getDestinationPageCount()).To(Equal(3))
+			})
+
+			It("returns an error for an invalid page range", func() {
+				pageRange := "3"
+				err := helpers.ImportPages(PdfiumInstance, destinationDoc, 0,
+					helpers.ImportPagesSource{Instance: PdfiumInstance, Document: sourceDoc, PageRange: &pageRange},
+				)
+				Expect(err).To(MatchError("could not import pages of source 0: import of pages failed"))
+			})
+		})
+
+		When("the source document is in another instance", func() {
+			var sourcePool pdfium.Pool
+			var sourceInstance pdfium.Pdfium
+			var sourceDoc references.FPDF_DOCUMENT
+
+			BeforeEach(func() {
+				// A pool of its own, so that the source document is in
+				// another worker in multi-threaded usage.
+				sourcePool = NewPdfiumPool()
+
+				var err error
+				sourceInstance, err = sourcePool.GetInstance(time.Second * 30)
+				Expect(err).To(BeNil())
+
+				pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/test_multipage.pdf")
+				Expect(err).To(BeNil())
+
+				newDoc, err := sourceInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+					Data: &pdfData,
+				})
+				Expect(err).To(BeNil())
+
+				sourceDoc = newDoc.Document
+			})
+
+			AfterEach(func() {
+				err := sourceInstance.Close()
+				Expect(err).To(BeNil())
+
+				err = sourcePool.Close()
+				Expect(err).To(BeNil())
+			})
+
+			It("can't import the pages directly", func() {
+				FPDF_ImportPages, err := PdfiumInstance.FPDF_ImportPages(&requests.FPDF_ImportPages{
+					Source:      sourceDoc,
+					Destination: destinationDoc,
+				})
+				Expect(err).To(MatchError("could not find document handle, perhaps the doc was already closed or you tried to share documents between instances"))
+				Expect(FPDF_ImportPages).To(BeNil())
+			})
+
+			It("imports all pages", func() {
+				err := helpers.ImportPages(PdfiumInstance, destinationDoc, 0,
+					helpers.ImportPagesSource{Instance: sourceInstance, Document: sourceDoc},
+				)
+				Expect(err).To(BeNil())
+				Expect(getDestinationPageCount()).To(Equal(2))
+			})
+
+			It("imports a page range from multiple instances", func() {
+				pageRange := "2"
+				err := helpers.ImportPages(PdfiumInstance, destinationDoc, 0,
+					helpers.ImportPagesSource{Instance: sourceInstance, Document: sourceDoc, PageRange: &pageRange},
+					helpers.ImportPagesSource{Instance: sourceInstance, Document: sourceDoc},
+				)
+				Expect(err).To(BeNil())
+				Expect(getDestinationPageCount()).To(Equal(3))
+
+				// The pages should arrive in the order of the sources.
+				for destinationIndex, sourceIndex := range []int{1, 0, 1} {
+					sourceText, err := sourceInstance.GetPageText(&requests.GetPageText{
+						Page: requests.Page{
+							ByIndex: &requests.PageByIndex{
+								Document: sourceDoc,
+								Index:    sourceIndex,
+							},
+						},
+					})
+					Expect(err).To(BeNil())
+
+					destinationText, err := PdfiumInstance.GetPageText(&requests.GetPageText{
+						Page: requests.Page{
+							ByIndex: &requests.PageByIndex{
+								Document: destinationDoc,
+								Index:    destinationIndex,
+							},
+						},
+					})
+					Expect(err).To(BeNil())
+					Expect(destinationText.Text).To(Equal(sourceText.Text))
+				}
+			})
+		})
+
+		When("an encrypted source document is in another instance", func() {
+			var sourcePool pdfium.Pool
+			var sourceInstance pdfium.Pdfium
+			var sourceDoc references.FPDF_DOCUMENT
+
+			BeforeEach(func() {
+				// A pool of its own, so that the source document is in
+				// another worker in multi-threaded usage.
+				sourcePool = NewPdfiumPool()
+
+				var err error
+				sourceInstance, err = sourcePool.GetInstance(time.Second * 30)
+				Expect(err).To(BeNil())
+
+				pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/password_test123.pdf")
+				Expect(err).To(BeNil())
+
+				pdfPassword := "test123"
+				newDoc, err := sourceInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+					Data:     &pdfData,
+					Password: &pdfPassword,
+				})
+				Expect(err).To(BeNil())
+
+				sourceDoc = newDoc.Document
+			})
+
+			AfterEach(func() {
+				err := sourceInstance.Close()
+				Expect(err).To(BeNil())
+
+				err = sourcePool.Close()
+				Expect(err).To(BeNil())
+			})
+
+			It("returns an error when the password is not given", func() {
+				err := helpers.ImportPages(PdfiumInstance, destinationDoc, 0,
+					helpers.ImportPagesSource{Instance: sourceInstance, Document: sourceDoc},
+				)
+				Expect(err).To(MatchError("could not import pages of source 0: source document is encrypted, the password of the source document is required"))
+				Expect(getDestinationPageCount()).To(Equal(0))
+			})
+
+			It("imports all pages with the password", func() {
+				pdfPassword := "test123"
+				err := helpers.ImportPages(PdfiumInstance, destinationDoc, 0,
+					helpers.ImportPagesSource{Instance: sourceInstance, Document: sourceDoc, Password: &pdfPassword},
+				)
+				Expect(err).To(BeNil())
+				Expect(getDestinationPageCount()).To(Equal(1))
+			})
+		})
+	})
+})
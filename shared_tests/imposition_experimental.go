@@ -0,0 +1,200 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("imposition", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling ImposeNUp", func() {
+				ImposeNUp, err := PdfiumInstance.ImposeNUp(&requests.ImposeNUp{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(ImposeNUp).To(BeNil())
+			})
+
+			It("returns an error when calling ImposeBooklet", func() {
+				ImposeBooklet, err := PdfiumInstance.ImposeBooklet(&requests.ImposeBooklet{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(ImposeBooklet).To(BeNil())
+			})
+
+			It("returns an error when calling ImposeTiles", func() {
+				ImposeTiles, err := PdfiumInstance.ImposeTiles(&requests.ImposeTiles{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(ImposeTiles).To(BeNil())
+			})
+		})
+	})
+
+	Context("a PDF file with multiple pages", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/test_multipage.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		closeDocument := func(document references.FPDF_DOCUMENT) {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: document,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		}
+
+		getPageSize := func(document references.FPDF_DOCUMENT, index int) *responses.FPDF_GetPageSizeByIndex {
+			pageSize, err := PdfiumInstance.FPDF_GetPageSizeByIndex(&requests.FPDF_GetPageSizeByIndex{
+				Document: document,
+				Index:    index,
+			})
+			Expect(err).To(BeNil())
+			return pageSize
+		}
+
+		When("ImposeNUp is called", func() {
+			It("returns an error when no sheet size is given", func() {
+				ImposeNUp, err := PdfiumInstance.ImposeNUp(&requests.ImposeNUp{
+					Document: doc,
+					Columns:  2,
+					Rows:     1,
+				})
+				Expect(err).To(MatchError("sheet width and height should be larger than 0"))
+				Expect(ImposeNUp).To(BeNil())
+			})
+
+			It("returns an error when the gutter is too big", func() {
+				ImposeNUp, err := PdfiumInstance.ImposeNUp(&requests.ImposeNUp{
+					Document:    doc,
+					SheetWidth:  842,
+					SheetHeight: 595,
+					Columns:     2,
+					Rows:        1,
+					Gutter:      1000,
+				})
+				Expect(err).To(MatchError("margin and gutter leave no space for the pages"))
+				Expect(ImposeNUp).To(BeNil())
+			})
+
+			It("puts multiple pages on a sheet", func() {
+				ImposeNUp, err := PdfiumInstance.ImposeNUp(&requests.ImposeNUp{
+					Document:    doc,
+					SheetWidth:  842,
+					SheetHeight: 595,
+					Columns:     2,
+					Rows:        1,
+					Margin:      20,
+					Gutter:      10,
+					CropMarks:   true,
+				})
+				Expect(err).To(BeNil())
+				Expect(ImposeNUp).To(Not(BeNil()))
+				Expect(ImposeNUp.PageCount).To(Equal(1))
+				defer closeDocument(ImposeNUp.Document)
+
+				Expect(getPageSize(ImposeNUp.Document, 0)).To(Equal(&responses.FPDF_GetPageSizeByIndex{
+					Width:  842,
+					Height: 595,
+				}))
+
+				FPDFPage_CountObjects, err := PdfiumInstance.FPDFPage_CountObjects(&requests.FPDFPage_CountObjects{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: ImposeNUp.Document,
+							Index:    0,
+						},
+					},
+				})
+				Expect(err).To(BeNil())
+
+				// 2 pages and 8 crop marks per page.
+				Expect(FPDFPage_CountObjects.Count).To(Equal(18))
+			})
+		})
+
+		When("ImposeBooklet is called", func() {
+			It("creates a booklet with a front and a back", func() {
+				ImposeBooklet, err := PdfiumInstance.ImposeBooklet(&requests.ImposeBooklet{
+					Document:    doc,
+					SheetWidth:  842,
+					SheetHeight: 595,
+				})
+				Expect(err).To(BeNil())
+				Expect(ImposeBooklet).To(Not(BeNil()))
+				defer closeDocument(ImposeBooklet.Document)
+
+				// The 2 pages are padded to 4, which fits on 1 sheet with 2 sides.
+				Expect(ImposeBooklet.PageCount).To(Equal(2))
+			})
+		})
+
+		When("ImposeTiles is called", func() {
+			It("returns an error for a page that does not exist", func() {
+				ImposeTiles, err := PdfiumInstance.ImposeTiles(&requests.ImposeTiles{
+					Document:    doc,
+					Pages:       []int{2},
+					SheetWidth:  595,
+					SheetHeight: 842,
+				})
+				Expect(err).To(MatchError("page 2 does not exist"))
+				Expect(ImposeTiles).To(BeNil())
+			})
+
+			It("tiles a scaled page across multiple sheets", func() {
+				pageSize := getPageSize(doc, 0)
+
+				ImposeTiles, err := PdfiumInstance.ImposeTiles(&requests.ImposeTiles{
+					Document:    doc,
+					Pages:       []int{0},
+					SheetWidth:  float32(pageSize.Width),
+					SheetHeight: float32(pageSize.Height),
+					Scale:       2,
+				})
+				Expect(err).To(BeNil())
+				Expect(ImposeTiles).To(Not(BeNil()))
+				defer closeDocument(ImposeTiles.Document)
+
+				Expect(ImposeTiles.PageCount).To(Equal(4))
+				Expect(ImposeTiles.Tiles).To(Equal([]responses.ImposeTilesTile{
+					{Page: 0, Column: 0, Row: 0},
+					{Page: 0, Column: 1, Row: 0},
+					{Page: 0, Column: 0, Row: 1},
+					{Page: 0, Column: 1, Row: 1},
+				}))
+			})
+		})
+	})
+})
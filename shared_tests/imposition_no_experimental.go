@@ -0,0 +1,40 @@
+//go:build !pdfium_experimental
+// +build !pdfium_experimental
+
+package shared_tests
+
+import (
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("imposition", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	It("returns an error when calling ImposeNUp", func() {
+		ImposeNUp, err := PdfiumInstance.ImposeNUp(&requests.ImposeNUp{})
+		Expect(err).To(MatchError(pdfium_errors.ErrExperimentalUnsupported.Error()))
+		Expect(ImposeNUp).To(BeNil())
+	})
+
+	It("returns an error when calling ImposeBooklet", func() {
+		ImposeBooklet, err := PdfiumInstance.ImposeBooklet(&requests.ImposeBooklet{})
+		Expect(err).To(MatchError(pdfium_errors.ErrExperimentalUnsupported.Error()))
+		Expect(ImposeBooklet).To(BeNil())
+	})
+
+	It("returns an error when calling ImposeTiles", func() {
+		ImposeTiles, err := PdfiumInstance.ImposeTiles(&requests.ImposeTiles{})
+		Expect(err).To(MatchError(pdfium_errors.ErrExperimentalUnsupported.Error()))
+		Expect(ImposeTiles).To(BeNil())
+	})
+})
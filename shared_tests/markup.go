@@ -0,0 +1,167 @@
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("markup", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling GetDocumentMarkup", func() {
+				GetDocumentMarkup, err := PdfiumInstance.GetDocumentMarkup(&requests.GetDocumentMarkup{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(GetDocumentMarkup).To(BeNil())
+			})
+		})
+	})
+
+	Context("a normal PDF file", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/hello_world.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("GetDocumentMarkup is called", func() {
+			It("returns an error for an unknown format", func() {
+				GetDocumentMarkup, err := PdfiumInstance.GetDocumentMarkup(&requests.GetDocumentMarkup{
+					Document: doc,
+					Format:   "rtf",
+				})
+				Expect(err).To(MatchError("unsupported markup format rtf"))
+				Expect(GetDocumentMarkup).To(BeNil())
+			})
+
+			It("returns an error for a page that does not exist", func() {
+				GetDocumentMarkup, err := PdfiumInstance.GetDocumentMarkup(&requests.GetDocumentMarkup{
+					Document: doc,
+					Pages:    []int{1},
+				})
+				Expect(err).To(MatchError("page 1 does not exist"))
+				Expect(GetDocumentMarkup).To(BeNil())
+			})
+
+			It("returns the document as Markdown", func() {
+				GetDocumentMarkup, err := PdfiumInstance.GetDocumentMarkup(&requests.GetDocumentMarkup{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetDocumentMarkup).To(Not(BeNil()))
+				Expect(GetDocumentMarkup.Pages).To(HaveLen(1))
+				Expect(GetDocumentMarkup.Pages[0].Page).To(Equal(0))
+				Expect(GetDocumentMarkup.Content).To(ContainSubstring("Hello, world!"))
+				Expect(GetDocumentMarkup.Content).To(ContainSubstring("Goodbye, world!"))
+				Expect(GetDocumentMarkup.Content).To(Equal(GetDocumentMarkup.Pages[0].Content))
+			})
+
+			It("returns the document as HTML", func() {
+				GetDocumentMarkup, err := PdfiumInstance.GetDocumentMarkup(&requests.GetDocumentMarkup{
+					Document: doc,
+					Format:   requests.GetDocumentMarkupFormatHTML,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetDocumentMarkup).To(Not(BeNil()))
+				Expect(GetDocumentMarkup.Content).To(ContainSubstring("Hello, world!"))
+				Expect(GetDocumentMarkup.Content).To(ContainSubstring("Goodbye, world!"))
+				Expect(GetDocumentMarkup.Content).To(MatchRegexp(`<(p|h[1-6])>`))
+			})
+		})
+	})
+	Context("a PDF file with headings, lists, links and bookmarks", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/markup_structures.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("GetDocumentMarkup is called", func() {
+			It("returns the structures as Markdown", func() {
+				GetDocumentMarkup, err := PdfiumInstance.GetDocumentMarkup(&requests.GetDocumentMarkup{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetDocumentMarkup).To(Not(BeNil()))
+
+				// Headings by font size and bookmarks.
+				Expect(GetDocumentMarkup.Content).To(HavePrefix("# Annual Report\n\n# Introduction\n\n"))
+				Expect(GetDocumentMarkup.Content).To(ContainSubstring("\n\n## Details\n\n"))
+
+				// Paragraph lines are joined.
+				Expect(GetDocumentMarkup.Content).To(ContainSubstring("\n\nThe first line of a paragraph continues on the second line.\n\n"))
+
+				// Lists.
+				Expect(GetDocumentMarkup.Content).To(ContainSubstring("\n\n- Apples\n- Pears\n\nSteps to follow:\n\n"))
+				Expect(GetDocumentMarkup.Content).To(ContainSubstring("\n\n1. Open the file\n2. Save the file\n\n"))
+
+				// Heading by bold font.
+				Expect(GetDocumentMarkup.Content).To(ContainSubstring("\n\n## Summary\n\n"))
+
+				// Web links and link annotations.
+				Expect(GetDocumentMarkup.Content).To(ContainSubstring("\n\nVisit [https://example.com](https://example.com) for more.\n\n"))
+				Expect(GetDocumentMarkup.Content).To(HaveSuffix("\n\nRead the [manual](https://example.com/manual) online."))
+			})
+
+			It("returns the structures as HTML", func() {
+				GetDocumentMarkup, err := PdfiumInstance.GetDocumentMarkup(&requests.GetDocumentMarkup{
+					Document: doc,
+					Format:   requests.GetDocumentMarkupFormatHTML,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetDocumentMarkup).To(Not(BeNil()))
+				Expect(GetDocumentMarkup.Content).To(HavePrefix("<h1>Annual Report</h1>\n<h1>Introduction</h1>\n"))
+				Expect(GetDocumentMarkup.Content).To(ContainSubstring("<p>The first line of a paragraph continues on the second line.</p>\n"))
+				Expect(GetDocumentMarkup.Content).To(ContainSubstring("<h2>Details</h2>\n<ul>\n<li>Apples</li>\n<li>Pears</li>\n</ul>\n"))
+				Expect(GetDocumentMarkup.Content).To(ContainSubstring("<ol>\n<li>1. Open the file</li>\n<li>2. Save the file</li>\n</ol>\n"))
+				Expect(GetDocumentMarkup.Content).To(ContainSubstring("<h2>Summary</h2>\n"))
+				Expect(GetDocumentMarkup.Content).To(ContainSubstring("<p>Visit <a href=\"https://example.com\">https://example.com</a> for more.</p>\n"))
+				Expect(GetDocumentMarkup.Content).To(ContainSubstring("<p>Read the <a href=\"https://example.com/manual\">manual</a> online.</p>\n"))
+			})
+		})
+	})
+})
@@ -0,0 +1,69 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("markup", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("a tagged PDF file with a table", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/markup_table.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("GetDocumentMarkup is called", func() {
+			It("returns the table as Markdown", func() {
+				GetDocumentMarkup, err := PdfiumInstance.GetDocumentMarkup(&requests.GetDocumentMarkup{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetDocumentMarkup).To(Not(BeNil()))
+				Expect(GetDocumentMarkup.Content).To(Equal("# People\n\n| Name | Age |\n| --- | --- |\n| Alice | 30 |\n| Bob | 25 |"))
+			})
+
+			It("returns the table as HTML", func() {
+				GetDocumentMarkup, err := PdfiumInstance.GetDocumentMarkup(&requests.GetDocumentMarkup{
+					Document: doc,
+					Format:   requests.GetDocumentMarkupFormatHTML,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetDocumentMarkup).To(Not(BeNil()))
+				Expect(GetDocumentMarkup.Content).To(Equal("<h1>People</h1>\n<table>\n<tr><th>Name</th><th>Age</th></tr>\n<tr><td>Alice</td><td>30</td></tr>\n<tr><td>Bob</td><td>25</td></tr>\n</table>\n"))
+			})
+		})
+	})
+})
@@ -0,0 +1,155 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("normalize", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling NormalizePages", func() {
+				NormalizePages, err := PdfiumInstance.NormalizePages(&requests.NormalizePages{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(NormalizePages).To(BeNil())
+			})
+		})
+	})
+
+	Context("a PDF file with multiple pages", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/test_multipage.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		getPageSize := func(index int) (float64, float64) {
+			FPDF_GetPageSizeByIndex, err := PdfiumInstance.FPDF_GetPageSizeByIndex(&requests.FPDF_GetPageSizeByIndex{
+				Document: doc,
+				Index:    index,
+			})
+			Expect(err).To(BeNil())
+			return FPDF_GetPageSizeByIndex.Width, FPDF_GetPageSizeByIndex.Height
+		}
+
+		It("returns an error for an unsupported page size", func() {
+			NormalizePages, err := PdfiumInstance.NormalizePages(&requests.NormalizePages{
+				Document: doc,
+				Size:     "a5",
+			})
+			Expect(err).To(MatchError("unsupported page size a5"))
+			Expect(NormalizePages).To(BeNil())
+		})
+
+		It("returns an error when a custom size is not given", func() {
+			NormalizePages, err := PdfiumInstance.NormalizePages(&requests.NormalizePages{
+				Document: doc,
+				Size:     requests.NormalizePagesSizeCustom,
+			})
+			Expect(err).To(MatchError("width and height should be given for a custom size"))
+			Expect(NormalizePages).To(BeNil())
+		})
+
+		It("returns an error when a page is out of bounds", func() {
+			NormalizePages, err := PdfiumInstance.NormalizePages(&requests.NormalizePages{
+				Document: doc,
+				Pages:    []int{2},
+			})
+			Expect(err).To(MatchError("page 2 is out of bounds, document has 2 pages"))
+			Expect(NormalizePages).To(BeNil())
+		})
+
+		It("returns an error when the margin doesn't fit", func() {
+			NormalizePages, err := PdfiumInstance.NormalizePages(&requests.NormalizePages{
+				Document: doc,
+				Size:     requests.NormalizePagesSizeCustom,
+				Width:    100,
+				Height:   100,
+				Margin:   50,
+			})
+			Expect(err).To(MatchError("margin is too large for the page size"))
+			Expect(NormalizePages).To(BeNil())
+		})
+
+		It("scales the pages to Letter", func() {
+			NormalizePages, err := PdfiumInstance.NormalizePages(&requests.NormalizePages{
+				Document: doc,
+				Size:     requests.NormalizePagesSizeLetter,
+				Margin:   20,
+			})
+			Expect(err).To(BeNil())
+			Expect(NormalizePages.Pages).To(HaveLen(2))
+			Expect(NormalizePages.Pages[1].Page).To(Equal(1))
+			Expect(NormalizePages.Pages[1].Width).To(BeNumerically("~", 612, 0.01))
+			Expect(NormalizePages.Pages[1].Height).To(BeNumerically("~", 792, 0.01))
+
+			width, height := getPageSize(1)
+			Expect(width).To(BeNumerically("~", 612, 0.01))
+			Expect(height).To(BeNumerically("~", 792, 0.01))
+		})
+
+		It("adds margins to the pages", func() {
+			originalWidth, originalHeight := getPageSize(0)
+
+			NormalizePages, err := PdfiumInstance.NormalizePages(&requests.NormalizePages{
+				Document: doc,
+				Pages:    []int{0},
+				Margin:   10,
+			})
+			Expect(err).To(BeNil())
+			Expect(NormalizePages.Pages).To(HaveLen(1))
+
+			width, height := getPageSize(0)
+			Expect(width).To(BeNumerically("~", originalWidth+20, 0.01))
+			Expect(height).To(BeNumerically("~", originalHeight+20, 0.01))
+		})
+
+		It("crops the pages to their content", func() {
+			originalWidth, originalHeight := getPageSize(0)
+
+			NormalizePages, err := PdfiumInstance.NormalizePages(&requests.NormalizePages{
+				Document:          doc,
+				AutoCrop:          true,
+				NormalizeRotation: true,
+			})
+			Expect(err).To(BeNil())
+			Expect(NormalizePages.Pages).To(HaveLen(2))
+
+			width, height := getPageSize(0)
+			Expect(width).To(BeNumerically("<", originalWidth))
+			Expect(height).To(BeNumerically("<", originalHeight))
+		})
+	})
+})
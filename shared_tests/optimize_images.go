@@ -0,0 +1,120 @@
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("optimize_images", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling OptimizeImages", func() {
+				OptimizeImages, err := PdfiumInstance.OptimizeImages(&requests.OptimizeImages{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(OptimizeImages).To(BeNil())
+			})
+		})
+	})
+
+	Context("a PDF file with embedded images", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/embedded_images.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("returns an error for an invalid quality", func() {
+			OptimizeImages, err := PdfiumInstance.OptimizeImages(&requests.OptimizeImages{
+				Document: doc,
+				Quality:  101,
+			})
+			Expect(err).To(MatchError("quality should be between 1 and 100"))
+			Expect(OptimizeImages).To(BeNil())
+		})
+
+		It("returns an error for a negative target DPI", func() {
+			OptimizeImages, err := PdfiumInstance.OptimizeImages(&requests.OptimizeImages{
+				Document:  doc,
+				TargetDPI: -1,
+			})
+			Expect(err).To(MatchError("target DPI can't be negative"))
+			Expect(OptimizeImages).To(BeNil())
+		})
+
+		It("returns an error when a page is out of bounds", func() {
+			OptimizeImages, err := PdfiumInstance.OptimizeImages(&requests.OptimizeImages{
+				Document: doc,
+				Pages:    []int{1},
+			})
+			Expect(err).To(MatchError("page 1 is out of bounds, document has 1 pages"))
+			Expect(OptimizeImages).To(BeNil())
+		})
+
+		It("downsamples and recompresses the images", func() {
+			OptimizeImages, err := PdfiumInstance.OptimizeImages(&requests.OptimizeImages{
+				Document:  doc,
+				TargetDPI: 36,
+				Quality:   50,
+			})
+			Expect(err).To(BeNil())
+			Expect(OptimizeImages.Images).To(Not(BeEmpty()))
+
+			firstImage := OptimizeImages.Images[0]
+			Expect(firstImage.Page).To(Equal(0))
+			Expect(firstImage.ObjectPath).To(Equal([]int{33}))
+			Expect(firstImage.OriginalWidth).To(Equal(109))
+			Expect(firstImage.OriginalHeight).To(Equal(88))
+			Expect(firstImage.OriginalSize).To(Equal(4091))
+			Expect(firstImage.Optimized).To(BeTrue())
+			Expect(firstImage.SkipReason).To(Equal(responses.OptimizeImagesSkipReasonNone))
+			Expect(firstImage.Width).To(BeNumerically("<", 109))
+			Expect(firstImage.Height).To(BeNumerically("<", 88))
+			Expect(firstImage.Size).To(BeNumerically("<", 4091))
+
+			Expect(OptimizeImages.OptimizedSize).To(BeNumerically("<", OptimizeImages.OriginalSize))
+			Expect(OptimizeImages.Savings).To(Equal(OptimizeImages.OriginalSize - OptimizeImages.OptimizedSize))
+
+			GetPageImages, err := PdfiumInstance.GetPageImages(&requests.GetPageImages{
+				Page: requests.Page{
+					ByIndex: &requests.PageByIndex{
+						Document: doc,
+						Index:    0,
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(GetPageImages.Images[0].Filters).To(Equal([]string{"DCTDecode"}))
+			Expect(GetPageImages.Images[0].Metadata.Width).To(Equal(uint(firstImage.Width)))
+		})
+	})
+})
@@ -0,0 +1,321 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/structs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("redact", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling RedactDocument", func() {
+				RedactDocument, err := PdfiumInstance.RedactDocument(&requests.RedactDocument{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(RedactDocument).To(BeNil())
+			})
+		})
+	})
+
+	Context("a normal PDF file", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/hello_world.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		searchPage := func(query string) int {
+			SearchPageText, err := PdfiumInstance.SearchPageText(&requests.SearchPageText{
+				Page: requests.Page{
+					ByIndex: &requests.PageByIndex{
+						Document: doc,
+						Index:    0,
+					},
+				},
+				Query: query,
+			})
+			Expect(err).To(BeNil())
+			return len(SearchPageText.Matches)
+		}
+
+		It("returns an error when an area is out of bounds", func() {
+			RedactDocument, err := PdfiumInstance.RedactDocument(&requests.RedactDocument{
+				Document: doc,
+				Areas: []requests.RedactDocumentArea{
+					{Page: 1},
+				},
+			})
+			Expect(err).To(MatchError("page 1 of area 0 is out of bounds, document has 1 pages"))
+			Expect(RedactDocument).To(BeNil())
+		})
+
+		It("returns an error when a search has no query", func() {
+			RedactDocument, err := PdfiumInstance.RedactDocument(&requests.RedactDocument{
+				Document: doc,
+				Searches: []requests.RedactDocumentSearch{
+					{},
+				},
+			})
+			Expect(err).To(MatchError("no query given"))
+			Expect(RedactDocument).To(BeNil())
+		})
+
+		It("doesn't change anything when there is nothing to redact", func() {
+			RedactDocument, err := PdfiumInstance.RedactDocument(&requests.RedactDocument{
+				Document: doc,
+				Searches: []requests.RedactDocumentSearch{
+					{Query: "goodbye"},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(RedactDocument.Pages).To(BeEmpty())
+			Expect(RedactDocument.Verified).To(BeTrue())
+			Expect(searchPage("world")).To(Equal(1))
+		})
+
+		It("removes the text of a search match and keeps the other text", func() {
+			RedactDocument, err := PdfiumInstance.RedactDocument(&requests.RedactDocument{
+				Document: doc,
+				Searches: []requests.RedactDocumentSearch{
+					{Query: "world"},
+				},
+				DrawBoxes: true,
+			})
+			Expect(err).To(BeNil())
+			Expect(RedactDocument.Verified).To(BeTrue())
+			Expect(RedactDocument.Pages).To(HaveLen(1))
+			Expect(RedactDocument.Pages[0].Page).To(Equal(0))
+			Expect(RedactDocument.Pages[0].Areas).To(HaveLen(1))
+			Expect(RedactDocument.Pages[0].RemovedChars).To(Equal(5))
+			Expect(RedactDocument.Pages[0].RemainingText).To(BeEmpty())
+			Expect(searchPage("world")).To(Equal(0))
+			Expect(searchPage("Hello")).To(Equal(1))
+		})
+
+		It("removes everything in an area", func() {
+			boxColor := structs.FPDF_COLOR{R: 255}
+			RedactDocument, err := PdfiumInstance.RedactDocument(&requests.RedactDocument{
+				Document: doc,
+				Areas: []requests.RedactDocumentArea{
+					{
+						Page: 0,
+						Rect: structs.FPDF_FS_RECTF{Left: 0, Top: 10000, Right: 10000, Bottom: 0},
+					},
+				},
+				DrawBoxes: true,
+				BoxColor:  &boxColor,
+			})
+			Expect(err).To(BeNil())
+			Expect(RedactDocument.Verified).To(BeTrue())
+			Expect(RedactDocument.Pages).To(HaveLen(1))
+			Expect(RedactDocument.Pages[0].RemovedObjects).To(BeNumerically(">", 0))
+			Expect(searchPage("Hello")).To(Equal(0))
+			Expect(searchPage("world")).To(Equal(0))
+		})
+
+		It("removes the text from the saved document", func() {
+			RedactDocument, err := PdfiumInstance.RedactDocument(&requests.RedactDocument{
+				Document: doc,
+				Areas: []requests.RedactDocumentArea{
+					{
+						Page: 0,
+						Rect: structs.FPDF_FS_RECTF{Left: 0, Top: 10000, Right: 10000, Bottom: 0},
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(RedactDocument.Verified).To(BeTrue())
+
+			FPDF_SaveAsCopy, err := PdfiumInstance.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+				Document: doc,
+				Flags:    requests.SaveFlagNoIncremental,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_SaveAsCopy.FileBytes).To(Not(BeNil()))
+
+			savedContent := getRedactSavedContent(*FPDF_SaveAsCopy.FileBytes)
+			for _, text := range []string{"Hello", "world"} {
+				Expect(savedContent).To(Not(ContainSubstring(text)))
+				Expect(strings.ToUpper(savedContent)).To(Not(ContainSubstring(strings.ToUpper(hex.EncodeToString([]byte(text))))))
+			}
+		})
+	})
+
+	Context("a PDF file with a form object and annotations", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/redact_form_objects_annots.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		firstPage := func() requests.Page {
+			return requests.Page{
+				ByIndex: &requests.PageByIndex{
+					Document: doc,
+					Index:    0,
+				},
+			}
+		}
+
+		searchPage := func(query string) int {
+			SearchPageText, err := PdfiumInstance.SearchPageText(&requests.SearchPageText{
+				Page:  firstPage(),
+				Query: query,
+			})
+			Expect(err).To(BeNil())
+			return len(SearchPageText.Matches)
+		}
+
+		annotationCount := func() int {
+			FPDFPage_GetAnnotCount, err := PdfiumInstance.FPDFPage_GetAnnotCount(&requests.FPDFPage_GetAnnotCount{
+				Page: firstPage(),
+			})
+			Expect(err).To(BeNil())
+			return FPDFPage_GetAnnotCount.Count
+		}
+
+		It("removes a form object entirely and doesn't verify the page", func() {
+			RedactDocument, err := PdfiumInstance.RedactDocument(&requests.RedactDocument{
+				Document: doc,
+				Areas: []requests.RedactDocumentArea{
+					{
+						Page: 0,
+						Rect: structs.FPDF_FS_RECTF{Left: 15, Top: 215, Right: 70, Bottom: 195},
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(RedactDocument.Verified).To(BeFalse())
+			Expect(RedactDocument.Pages).To(HaveLen(1))
+			Expect(RedactDocument.Pages[0].RemovedFormObjects).To(Equal(1))
+			Expect(RedactDocument.Pages[0].RemovedObjects).To(Equal(0))
+			Expect(RedactDocument.Pages[0].RemainingText).To(BeEmpty())
+			Expect(RedactDocument.Pages[0].Verified).To(BeFalse())
+
+			// The text of the form object outside the area is gone as well.
+			Expect(searchPage("Hidden")).To(Equal(0))
+			Expect(searchPage("Outside")).To(Equal(0))
+			Expect(searchPage("Secret")).To(Equal(1))
+		})
+
+		It("removes the annotations in the areas with their popups", func() {
+			Expect(annotationCount()).To(Equal(4))
+
+			RedactDocument, err := PdfiumInstance.RedactDocument(&requests.RedactDocument{
+				Document: doc,
+				Areas: []requests.RedactDocumentArea{
+					{
+						Page: 0,
+						Rect: structs.FPDF_FS_RECTF{Left: 10, Top: 140, Right: 130, Bottom: 90},
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(RedactDocument.Verified).To(BeTrue())
+			Expect(RedactDocument.Pages).To(HaveLen(1))
+			Expect(RedactDocument.Pages[0].RemovedAnnotations).To(Equal(2))
+			Expect(RedactDocument.Pages[0].RemainingWidgets).To(Equal(0))
+			Expect(RedactDocument.Pages[0].Verified).To(BeTrue())
+			Expect(annotationCount()).To(Equal(2))
+
+			FPDF_SaveAsCopy, err := PdfiumInstance.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+				Document: doc,
+				Flags:    requests.SaveFlagNoIncremental,
+			})
+			Expect(err).To(BeNil())
+			Expect(getRedactSavedContent(*FPDF_SaveAsCopy.FileBytes)).To(Not(ContainSubstring("Private note")))
+		})
+
+		It("reports the widgets in the areas and doesn't verify the page", func() {
+			RedactDocument, err := PdfiumInstance.RedactDocument(&requests.RedactDocument{
+				Document: doc,
+				Areas: []requests.RedactDocumentArea{
+					{
+						Page: 0,
+						Rect: structs.FPDF_FS_RECTF{Left: 10, Top: 80, Right: 130, Bottom: 40},
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(RedactDocument.Verified).To(BeFalse())
+			Expect(RedactDocument.Pages).To(HaveLen(1))
+			Expect(RedactDocument.Pages[0].RemovedAnnotations).To(Equal(0))
+			Expect(RedactDocument.Pages[0].RemainingWidgets).To(Equal(1))
+			Expect(RedactDocument.Pages[0].Verified).To(BeFalse())
+			Expect(annotationCount()).To(Equal(4))
+		})
+	})
+})
+
+// getRedactSavedContent returns the bytes of a saved document followed by its
+// inflated streams, so that text can be searched in the content streams.
+func getRedactSavedContent(data []byte) string {
+	content := &bytes.Buffer{}
+	content.Write(data)
+
+	streamRegex := regexp.MustCompile(`(?s)stream\r?\n(.*?)endstream`)
+	for _, match := range streamRegex.FindAllSubmatch(data, -1) {
+		reader, err := zlib.NewReader(bytes.NewReader(match[1]))
+		if err != nil {
+			continue
+		}
+
+		inflated, _ := ioutil.ReadAll(reader)
+		content.Write(inflated)
+	}
+
+	return content.String()
+}
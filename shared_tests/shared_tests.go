@@ -8,6 +8,7 @@ import (
 
 var PdfiumInstance pdfium.Pdfium
 var PdfiumPool pdfium.Pool
+var NewPdfiumPool func() pdfium.Pool // Creates a pool with the same config as PdfiumPool, for tests that need an instance of their own.
 var TestDataPath string
 var TestType string
 var Locker = &sync.Mutex{} // A locker, sometimes we need to make sure things can't run concurrently.
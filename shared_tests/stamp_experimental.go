@@ -0,0 +1,320 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/structs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("stamp", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling StampDocument", func() {
+				StampDocument, err := PdfiumInstance.StampDocument(&requests.StampDocument{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(StampDocument).To(BeNil())
+			})
+		})
+	})
+
+	Context("a PDF file with multiple pages", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/test_multipage.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		countObjects := func(index int) int {
+			FPDFPage_CountObjects, err := PdfiumInstance.FPDFPage_CountObjects(&requests.FPDFPage_CountObjects{
+				Page: requests.Page{
+					ByIndex: &requests.PageByIndex{
+						Document: doc,
+						Index:    index,
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			return FPDFPage_CountObjects.Count
+		}
+
+		countAnnotations := func(index int) int {
+			FPDFPage_GetAnnotCount, err := PdfiumInstance.FPDFPage_GetAnnotCount(&requests.FPDFPage_GetAnnotCount{
+				Page: requests.Page{
+					ByIndex: &requests.PageByIndex{
+						Document: doc,
+						Index:    index,
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			return FPDFPage_GetAnnotCount.Count
+		}
+
+		When("StampDocument is called", func() {
+			It("returns an error for an unknown type", func() {
+				StampDocument, err := PdfiumInstance.StampDocument(&requests.StampDocument{
+					Document: doc,
+					Type:     "video",
+				})
+				Expect(err).To(MatchError("unsupported stamp type video"))
+				Expect(StampDocument).To(BeNil())
+			})
+
+			It("returns an error for a page that does not exist", func() {
+				StampDocument, err := PdfiumInstance.StampDocument(&requests.StampDocument{
+					Document: doc,
+					Pages:    []int{2},
+					Type:     requests.StampDocumentTypeText,
+					Text: requests.StampDocumentText{
+						Text: "DRAFT",
+					},
+				})
+				Expect(err).To(MatchError("page 2 does not exist"))
+				Expect(StampDocument).To(BeNil())
+			})
+
+			It("returns an error for an invalid opacity", func() {
+				opacity := float32(2)
+				StampDocument, err := PdfiumInstance.StampDocument(&requests.StampDocument{
+					Document: doc,
+					Type:     requests.StampDocumentTypeText,
+					Opacity:  &opacity,
+					Text: requests.StampDocumentText{
+						Text: "DRAFT",
+					},
+				})
+				Expect(err).To(MatchError("opacity should be between 0 and 1"))
+				Expect(StampDocument).To(BeNil())
+			})
+
+			It("stamps a text on all pages", func() {
+				objectsBefore := countObjects(0)
+				opacity := float32(0.5)
+
+				StampDocument, err := PdfiumInstance.StampDocument(&requests.StampDocument{
+					Document: doc,
+					Type:     requests.StampDocumentTypeText,
+					Rotation: 45,
+					Opacity:  &opacity,
+					Text: requests.StampDocumentText{
+						Text:     "DRAFT",
+						FontSize: 72,
+						Color:    structs.FPDF_COLOR{R: 255},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(StampDocument).To(Not(BeNil()))
+				Expect(StampDocument.Pages).To(Equal([]int{0, 1}))
+				Expect(countObjects(0)).To(Equal(objectsBefore + 1))
+			})
+
+			It("stamps an image as watermark annotation in a copy", func() {
+				imageData, err := ioutil.ReadFile(TestDataPath + "/testdata/mona_lisa.jpg")
+				Expect(err).To(BeNil())
+
+				annotationsBefore := countAnnotations(1)
+
+				StampDocument, err := PdfiumInstance.StampDocument(&requests.StampDocument{
+					Document:  doc,
+					Pages:     []int{1},
+					Type:      requests.StampDocumentTypeImage,
+					Position:  requests.StampDocumentPositionBottomRight,
+					OffsetX:   -20,
+					OffsetY:   20,
+					Placement: requests.StampDocumentPlacementAnnotation,
+					Image: requests.StampDocumentImage{
+						Data:  imageData,
+						Width: 100,
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(StampDocument).To(Not(BeNil()))
+				Expect(StampDocument.Pages).To(Equal([]int{1}))
+				Expect(StampDocument.FileBytes).To(Not(BeNil()))
+
+				// The loaded document is not changed.
+				Expect(countAnnotations(1)).To(Equal(annotationsBefore))
+
+				stampedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+					Data: StampDocument.FileBytes,
+				})
+				Expect(err).To(BeNil())
+				defer PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+					Document: stampedDoc.Document,
+				})
+
+				stampedPage := requests.Page{
+					ByIndex: &requests.PageByIndex{
+						Document: stampedDoc.Document,
+						Index:    1,
+					},
+				}
+
+				FPDFPage_GetAnnotCount, err := PdfiumInstance.FPDFPage_GetAnnotCount(&requests.FPDFPage_GetAnnotCount{
+					Page: stampedPage,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDFPage_GetAnnotCount.Count).To(Equal(annotationsBefore + 1))
+
+				FPDFPage_GetAnnot, err := PdfiumInstance.FPDFPage_GetAnnot(&requests.FPDFPage_GetAnnot{
+					Page:  stampedPage,
+					Index: annotationsBefore,
+				})
+				Expect(err).To(BeNil())
+				defer PdfiumInstance.FPDFPage_CloseAnnot(&requests.FPDFPage_CloseAnnot{
+					Annotation: FPDFPage_GetAnnot.Annotation,
+				})
+
+				FPDFAnnot_GetSubtype, err := PdfiumInstance.FPDFAnnot_GetSubtype(&requests.FPDFAnnot_GetSubtype{
+					Annotation: FPDFPage_GetAnnot.Annotation,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDFAnnot_GetSubtype.Subtype).To(Equal(enums.FPDF_ANNOT_SUBTYPE_WATERMARK))
+
+				FPDFAnnot_HasKey, err := PdfiumInstance.FPDFAnnot_HasKey(&requests.FPDFAnnot_HasKey{
+					Annotation: FPDFPage_GetAnnot.Annotation,
+					Key:        "AP",
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDFAnnot_HasKey.HasKey).To(BeTrue())
+			})
+
+			It("stamps a page of another document", func() {
+				pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/test.pdf")
+				Expect(err).To(BeNil())
+
+				objectsBefore := countObjects(0)
+
+				StampDocument, err := PdfiumInstance.StampDocument(&requests.StampDocument{
+					Document: doc,
+					Pages:    []int{0},
+					Type:     requests.StampDocumentTypePage,
+					Box:      requests.StampDocumentPageBoxMedia,
+					SourcePage: requests.StampDocumentSourcePage{
+						Document: requests.DocumentInput{
+							File: &pdfData,
+						},
+						Scale: 0.25,
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(StampDocument).To(Not(BeNil()))
+				Expect(StampDocument.Pages).To(Equal([]int{0}))
+				Expect(countObjects(0)).To(Equal(objectsBefore + 1))
+			})
+
+			It("returns an error when using opacity with a page stamp", func() {
+				opacity := float32(0.5)
+				StampDocument, err := PdfiumInstance.StampDocument(&requests.StampDocument{
+					Document: doc,
+					Type:     requests.StampDocumentTypePage,
+					Opacity:  &opacity,
+				})
+				Expect(err).To(MatchError("opacity is not supported for page stamps"))
+				Expect(StampDocument).To(BeNil())
+			})
+		})
+	})
+
+	Context("an encrypted PDF file", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/password_test123.pdf")
+			Expect(err).To(BeNil())
+
+			pdfPassword := "test123"
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data:     &pdfData,
+				Password: &pdfPassword,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("returns an error when stamping a watermark annotation", func() {
+			page := requests.Page{
+				ByIndex: &requests.PageByIndex{
+					Document: doc,
+					Index:    0,
+				},
+			}
+
+			annotationsBefore, err := PdfiumInstance.FPDFPage_GetAnnotCount(&requests.FPDFPage_GetAnnotCount{
+				Page: page,
+			})
+			Expect(err).To(BeNil())
+
+			StampDocument, err := PdfiumInstance.StampDocument(&requests.StampDocument{
+				Document:  doc,
+				Type:      requests.StampDocumentTypeText,
+				Placement: requests.StampDocumentPlacementAnnotation,
+				Text: requests.StampDocumentText{
+					Text: "CONFIDENTIAL",
+				},
+			})
+			Expect(err).To(MatchError("watermark annotations are not supported for encrypted documents"))
+			Expect(StampDocument).To(BeNil())
+
+			annotationsAfter, err := PdfiumInstance.FPDFPage_GetAnnotCount(&requests.FPDFPage_GetAnnotCount{
+				Page: page,
+			})
+			Expect(err).To(BeNil())
+			Expect(annotationsAfter.Count).To(Equal(annotationsBefore.Count))
+		})
+
+		It("stamps a text into the page content", func() {
+			StampDocument, err := PdfiumInstance.StampDocument(&requests.StampDocument{
+				Document: doc,
+				Type:     requests.StampDocumentTypeText,
+				Text: requests.StampDocumentText{
+					Text: "CONFIDENTIAL",
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(StampDocument.Pages).To(Equal([]int{0}))
+		})
+	})
+})
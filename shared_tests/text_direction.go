@@ -0,0 +1,222 @@
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("text direction", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("a left-to-right PDF file", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/hello_world.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("is opened", func() {
+			It("returns the lines with their words", func() {
+				pageTextStructured, err := PdfiumInstance.GetPageTextStructured(&requests.GetPageTextStructured{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					Mode: requests.GetPageTextStructuredModeLines,
+				})
+				Expect(err).To(BeNil())
+				Expect(pageTextStructured.Chars).To(BeEmpty())
+				Expect(pageTextStructured.Rects).To(BeEmpty())
+				Expect(pageTextStructured.Lines).To(HaveLen(2))
+				Expect(pageTextStructured.Lines[0].Text).To(Equal("Hello, world!"))
+				Expect(pageTextStructured.Lines[0].Direction).To(Equal(responses.TextDirectionLeftToRight))
+				Expect(pageTextStructured.Lines[0].Words).To(HaveLen(2))
+				Expect(pageTextStructured.Lines[0].Words[0].Text).To(Equal("Hello,"))
+				Expect(pageTextStructured.Lines[0].Words[1].Text).To(Equal("world!"))
+				Expect(pageTextStructured.Lines[1].Text).To(Equal("Goodbye, world!"))
+			})
+
+			It("returns the words", func() {
+				pageTextStructured, err := PdfiumInstance.GetPageTextStructured(&requests.GetPageTextStructured{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					Mode: requests.GetPageTextStructuredModeWords,
+				})
+				Expect(err).To(BeNil())
+				Expect(pageTextStructured.Lines).To(BeNil())
+				Expect(pageTextStructured.Words).To(HaveLen(4))
+				Expect(pageTextStructured.Words[2].Text).To(Equal("Goodbye,"))
+				Expect(pageTextStructured.Words[2].Direction).To(Equal(responses.TextDirectionLeftToRight))
+			})
+
+			It("returns the direction of the chars and rects", func() {
+				pageTextStructured, err := PdfiumInstance.GetPageTextStructured(&requests.GetPageTextStructured{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					Mode:                        requests.GetPageTextStructuredModeBoth,
+					CollectDirectionInformation: true,
+				})
+				Expect(err).To(BeNil())
+				Expect(pageTextStructured.Chars).To(Not(BeEmpty()))
+				for _, char := range pageTextStructured.Chars {
+					Expect(char.Direction).To(Equal(responses.TextDirectionLeftToRight))
+				}
+				Expect(pageTextStructured.Rects).To(Not(BeEmpty()))
+				for _, rect := range pageTextStructured.Rects {
+					Expect(rect.Direction).To(Equal(responses.TextDirectionLeftToRight))
+				}
+			})
+		})
+	})
+
+	Context("a right-to-left PDF file", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/hebrew_mirrored.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("is opened", func() {
+			It("returns the lines as right-to-left", func() {
+				pageTextStructured, err := PdfiumInstance.GetPageTextStructured(&requests.GetPageTextStructured{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					Mode: requests.GetPageTextStructuredModeLines,
+				})
+				Expect(err).To(BeNil())
+				Expect(pageTextStructured.Lines).To(Not(BeEmpty()))
+				Expect(pageTextStructured.Lines[0].Direction).To(Equal(responses.TextDirectionRightToLeft))
+			})
+		})
+	})
+
+	Context("a vertical CJK PDF file", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/vertical_cjk.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("is opened", func() {
+			It("returns the columns as top-to-bottom lines from right to left", func() {
+				pageTextStructured, err := PdfiumInstance.GetPageTextStructured(&requests.GetPageTextStructured{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					Mode: requests.GetPageTextStructuredModeLines,
+				})
+				Expect(err).To(BeNil())
+				Expect(pageTextStructured.Lines).To(HaveLen(2))
+				Expect(pageTextStructured.Lines[0].Text).To(Equal("春夏秋冬"))
+				Expect(pageTextStructured.Lines[0].Direction).To(Equal(responses.TextDirectionTopToBottom))
+				Expect(pageTextStructured.Lines[0].Words).To(HaveLen(1))
+				Expect(pageTextStructured.Lines[0].Words[0].Text).To(Equal("春夏秋冬"))
+				Expect(pageTextStructured.Lines[0].Words[0].Direction).To(Equal(responses.TextDirectionTopToBottom))
+				Expect(pageTextStructured.Lines[1].Text).To(Equal("东西南北"))
+				Expect(pageTextStructured.Lines[1].Direction).To(Equal(responses.TextDirectionTopToBottom))
+
+				// The first column is on the right of the second column and
+				// both columns are higher than they are wide.
+				Expect(pageTextStructured.Lines[0].PointPosition.Left).To(BeNumerically(">", pageTextStructured.Lines[1].PointPosition.Right))
+				for _, line := range pageTextStructured.Lines {
+					Expect(line.PointPosition.Top - line.PointPosition.Bottom).To(BeNumerically(">", line.PointPosition.Right-line.PointPosition.Left))
+				}
+			})
+
+			It("returns the direction of the chars", func() {
+				pageTextStructured, err := PdfiumInstance.GetPageTextStructured(&requests.GetPageTextStructured{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					Mode:                        requests.GetPageTextStructuredModeChars,
+					CollectDirectionInformation: true,
+				})
+				Expect(err).To(BeNil())
+				Expect(pageTextStructured.Chars).To(Not(BeEmpty()))
+				for _, char := range pageTextStructured.Chars {
+					Expect(char.Direction).To(Equal(responses.TextDirectionTopToBottom))
+				}
+			})
+		})
+	})
+})
@@ -0,0 +1,116 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("text layer", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling AddPageTextLayer", func() {
+				AddPageTextLayer, err := PdfiumInstance.AddPageTextLayer(&requests.AddPageTextLayer{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Index: 0,
+						},
+					},
+				})
+				Expect(err).To(MatchError("document not given"))
+				Expect(AddPageTextLayer).To(BeNil())
+			})
+		})
+	})
+
+	Context("a new document with an empty page", func() {
+		var doc references.FPDF_DOCUMENT
+		var page references.FPDF_PAGE
+
+		BeforeEach(func() {
+			newDoc, err := PdfiumInstance.FPDF_CreateNewDocument(&requests.FPDF_CreateNewDocument{})
+			Expect(err).To(BeNil())
+			doc = newDoc.Document
+
+			FPDFPage_New, err := PdfiumInstance.FPDFPage_New(&requests.FPDFPage_New{
+				Document:  doc,
+				PageIndex: 0,
+				Width:     612,
+				Height:    792,
+			})
+			Expect(err).To(BeNil())
+			page = FPDFPage_New.Page
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("returns an error when pixel positions are used without a resolution", func() {
+			AddPageTextLayer, err := PdfiumInstance.AddPageTextLayer(&requests.AddPageTextLayer{
+				Page: requests.Page{
+					ByReference: &page,
+				},
+				PixelPositions: requests.AddPageTextLayerPixelPositions{
+					Calculate: true,
+				},
+			})
+			Expect(err).To(MatchError("no resolution given to calculate point positions"))
+			Expect(AddPageTextLayer).To(BeNil())
+		})
+
+		It("adds an invisible text layer", func() {
+			AddPageTextLayer, err := PdfiumInstance.AddPageTextLayer(&requests.AddPageTextLayer{
+				Page: requests.Page{
+					ByReference: &page,
+				},
+				Words: []requests.AddPageTextLayerWord{
+					{Text: "Hello", Left: 100, Top: 700, Right: 160, Bottom: 680},
+					{Text: "world", Left: 170, Top: 700, Right: 230, Bottom: 680},
+					{Text: " ", Left: 240, Top: 700, Right: 250, Bottom: 680},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(AddPageTextLayer).To(Equal(&responses.AddPageTextLayer{
+				Page:       -1,
+				WordsAdded: 2,
+			}))
+
+			GetPageTextQuality, err := PdfiumInstance.GetPageTextQuality(&requests.GetPageTextQuality{
+				Page: requests.Page{
+					ByReference: &page,
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(GetPageTextQuality.Status).To(Equal(responses.GetPageTextQualityStatusInvisibleText))
+			Expect(GetPageTextQuality.InvisibleCharCount).To(Equal(10))
+
+			pageText, err := PdfiumInstance.GetPageText(&requests.GetPageText{
+				Page: requests.Page{
+					ByReference: &page,
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(pageText.Text).To(ContainSubstring("Hello"))
+			Expect(pageText.Text).To(ContainSubstring("world"))
+		})
+	})
+})
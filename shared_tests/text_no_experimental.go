@@ -6,6 +6,7 @@ package shared_tests
 import (
 	"io/ioutil"
 
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
 	"github.com/klippa-app/go-pdfium/references"
 	"github.com/klippa-app/go-pdfium/requests"
 	. "github.com/onsi/ginkgo/v2"
@@ -45,6 +46,20 @@ var _ = Describe("text", func() {
 		})
 
 		When("is opened", func() {
+			It("reports that the chars were not checked for unicode map errors when the text quality is requested", func() {
+				GetPageTextQuality, err := PdfiumInstance.GetPageTextQuality(&requests.GetPageTextQuality{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(GetPageTextQuality).To(Not(BeNil()))
+				Expect(GetPageTextQuality.UnicodeMapErrorsChecked).To(BeFalse())
+			})
+
 			Context("when the structured page text is requested", func() {
 				Context("when PixelPositions is enabled", func() {
 					It("returns the correct font information", func() {
@@ -86,3 +101,55 @@ var _ = Describe("text", func() {
 		})
 	})
 })
+
+var _ = Describe("text options", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("a normal PDF file", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/hello_world.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("is opened", func() {
+			It("returns an error when generated chars should be removed", func() {
+				pageText, err := PdfiumInstance.GetPageText(&requests.GetPageText{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					TextOptions: requests.TextOptions{
+						RemoveGeneratedChars: true,
+					},
+				})
+				Expect(err).To(MatchError(pdfium_errors.ErrExperimentalUnsupported.Error()))
+				Expect(pageText).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,187 @@
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("text options", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling SearchPageText", func() {
+				SearchPageText, err := PdfiumInstance.SearchPageText(&requests.SearchPageText{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Index: 0,
+						},
+					},
+					Query: "world",
+				})
+				Expect(err).To(MatchError("document not given"))
+				Expect(SearchPageText).To(BeNil())
+			})
+		})
+	})
+
+	Context("a normal PDF file", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/hello_world.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("is opened", func() {
+			It("returns an error when an unknown normalization form is given", func() {
+				pageText, err := PdfiumInstance.GetPageText(&requests.GetPageText{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					TextOptions: requests.TextOptions{
+						Normalization: "NFX",
+					},
+				})
+				Expect(err).To(MatchError("unsupported text normalization form NFX"))
+				Expect(pageText).To(BeNil())
+			})
+
+			It("returns the same text when the options don't change anything", func() {
+				pageText, err := PdfiumInstance.GetPageText(&requests.GetPageText{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+				})
+				Expect(err).To(BeNil())
+
+				pageTextWithOptions, err := PdfiumInstance.GetPageText(&requests.GetPageText{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					TextOptions: requests.TextOptions{
+						ExpandLigatures:     true,
+						JoinHyphenatedWords: true,
+						Normalization:       requests.TextNormalizationFormNFC,
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(pageTextWithOptions).To(Equal(pageText))
+			})
+
+			It("applies the text options to the structured chars", func() {
+				pageTextStructured, err := PdfiumInstance.GetPageTextStructured(&requests.GetPageTextStructured{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					Mode: requests.GetPageTextStructuredModeChars,
+					TextOptions: requests.TextOptions{
+						Normalization: requests.TextNormalizationFormNFKC,
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(pageTextStructured.Chars).To(Not(BeEmpty()))
+				Expect(pageTextStructured.Chars[0].Text).To(Equal("H"))
+			})
+
+			It("returns an error when searching without a query", func() {
+				SearchPageText, err := PdfiumInstance.SearchPageText(&requests.SearchPageText{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+				})
+				Expect(err).To(MatchError("no query given"))
+				Expect(SearchPageText).To(BeNil())
+			})
+
+			It("returns the search matches", func() {
+				SearchPageText, err := PdfiumInstance.SearchPageText(&requests.SearchPageText{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					Query: "WORLD",
+				})
+				Expect(err).To(BeNil())
+				Expect(SearchPageText.Matches).To(HaveLen(2))
+				Expect(SearchPageText.Matches[0].Text).To(Equal("world"))
+				Expect(SearchPageText.Matches[0].StartIndex).To(Equal(7))
+				Expect(SearchPageText.Matches[0].Count).To(Equal(5))
+				Expect(SearchPageText.Matches[0].Rects).To(HaveLen(1))
+			})
+
+			It("returns no search matches when the case doesn't match", func() {
+				SearchPageText, err := PdfiumInstance.SearchPageText(&requests.SearchPageText{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					Query:     "WORLD",
+					MatchCase: true,
+				})
+				Expect(err).To(BeNil())
+				Expect(SearchPageText.Matches).To(BeEmpty())
+			})
+
+			It("returns no search matches when the whole word doesn't match", func() {
+				SearchPageText, err := PdfiumInstance.SearchPageText(&requests.SearchPageText{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					Query:          "worl",
+					MatchWholeWord: true,
+				})
+				Expect(err).To(BeNil())
+				Expect(SearchPageText.Matches).To(BeEmpty())
+			})
+		})
+	})
+})
@@ -0,0 +1,86 @@
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("text quality", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling GetPageTextQuality", func() {
+				GetPageTextQuality, err := PdfiumInstance.GetPageTextQuality(&requests.GetPageTextQuality{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Index: 0,
+						},
+					},
+				})
+				Expect(err).To(MatchError("document not given"))
+				Expect(GetPageTextQuality).To(BeNil())
+			})
+		})
+	})
+
+	Context("a PDF file with a text layer", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/hello_world.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("is opened", func() {
+			It("returns that the page has a text layer", func() {
+				GetPageTextQuality, err := PdfiumInstance.GetPageTextQuality(&requests.GetPageTextQuality{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(GetPageTextQuality).To(Equal(&responses.GetPageTextQuality{
+					Page:       0,
+					Status:     responses.GetPageTextQualityStatusText,
+					NeedsOCR:   false,
+					Confidence: 1,
+					CharCount:  26,
+
+					// Only checked with experimental support, see the text tests.
+					UnicodeMapErrorsChecked: GetPageTextQuality.UnicodeMapErrorsChecked,
+				}))
+			})
+		})
+	})
+})
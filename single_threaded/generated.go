@@ -11,6 +11,90 @@ import (
 	"github.com/klippa-app/go-pdfium/responses"
 )
 
+func (i *pdfiumInstance) AddHeaderFooter(request *requests.AddHeaderFooter) (resp *responses.AddHeaderFooter, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "AddHeaderFooter", panicError)
+		}
+	}()
+
+	return i.pdfium.AddHeaderFooter(request)
+}
+
+func (i *pdfiumInstance) AddPageTextLayer(request *requests.AddPageTextLayer) (resp *responses.AddPageTextLayer, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "AddPageTextLayer", panicError)
+		}
+	}()
+
+	return i.pdfium.AddPageTextLayer(request)
+}
+
+func (i *pdfiumInstance) AuditFormAccessibility(request *requests.AuditFormAccessibility) (resp *responses.AuditFormAccessibility, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "AuditFormAccessibility", panicError)
+		}
+	}()
+
+	return i.pdfium.AuditFormAccessibility(request)
+}
+
+func (i *pdfiumInstance) DrawPage(request *requests.DrawPage) (resp *responses.DrawPage, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "DrawPage", panicError)
+		}
+	}()
+
+	return i.pdfium.DrawPage(request)
+}
+
+func (i *pdfiumInstance) EditFormFields(request *requests.EditFormFields) (resp *responses.EditFormFields, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "EditFormFields", panicError)
+		}
+	}()
+
+	return i.pdfium.EditFormFields(request)
+}
+
+func (i *pdfiumInstance) ExportFormData(request *requests.ExportFormData) (resp *responses.ExportFormData, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "ExportFormData", panicError)
+		}
+	}()
+
+	return i.pdfium.ExportFormData(request)
+}
+
 func (i *pdfiumInstance) FORM_CanRedo(request *requests.FORM_CanRedo) (resp *responses.FORM_CanRedo, err error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -5849,6 +5933,34 @@ func (i *pdfiumInstance) FSDK_SetUnSpObjProcessHandler(request *requests.FSDK_Se
 	return i.pdfium.FSDK_SetUnSpObjProcessHandler(request)
 }
 
+func (i *pdfiumInstance) FillForm(request *requests.FillForm) (resp *responses.FillForm, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FillForm", panicError)
+		}
+	}()
+
+	return i.pdfium.FillForm(request)
+}
+
+func (i *pdfiumInstance) FlattenAnnotations(request *requests.FlattenAnnotations) (resp *responses.FlattenAnnotations, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FlattenAnnotations", panicError)
+		}
+	}()
+
+	return i.pdfium.FlattenAnnotations(request)
+}
+
 func (i *pdfiumInstance) GetActionInfo(request *requests.GetActionInfo) (resp *responses.GetActionInfo, err error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -5905,6 +6017,62 @@ func (i *pdfiumInstance) GetDestInfo(request *requests.GetDestInfo) (resp *respo
 	return i.pdfium.GetDestInfo(request)
 }
 
+func (i *pdfiumInstance) GetDocumentImages(request *requests.GetDocumentImages) (resp *responses.GetDocumentImages, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetDocumentImages", panicError)
+		}
+	}()
+
+	return i.pdfium.GetDocumentImages(request)
+}
+
+func (i *pdfiumInstance) GetDocumentMarkup(request *requests.GetDocumentMarkup) (resp *responses.GetDocumentMarkup, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetDocumentMarkup", panicError)
+		}
+	}()
+
+	return i.pdfium.GetDocumentMarkup(request)
+}
+
+func (i *pdfiumInstance) GetFormFields(request *requests.GetFormFields) (resp *responses.GetFormFields, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetFormFields", panicError)
+		}
+	}()
+
+	return i.pdfium.GetFormFields(request)
+}
+
+func (i *pdfiumInstance) GetFormSchema(request *requests.GetFormSchema) (resp *responses.GetFormSchema, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetFormSchema", panicError)
+		}
+	}()
+
+	return i.pdfium.GetFormSchema(request)
+}
+
 func (i *pdfiumInstance) GetJavaScriptActions(request *requests.GetJavaScriptActions) (resp *responses.GetJavaScriptActions, err error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -5933,6 +6101,20 @@ func (i *pdfiumInstance) GetMetaData(request *requests.GetMetaData) (resp *respo
 	return i.pdfium.GetMetaData(request)
 }
 
+func (i *pdfiumInstance) GetPageImages(request *requests.GetPageImages) (resp *responses.GetPageImages, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetPageImages", panicError)
+		}
+	}()
+
+	return i.pdfium.GetPageImages(request)
+}
+
 func (i *pdfiumInstance) GetPageSize(request *requests.GetPageSize) (resp *responses.GetPageSize, err error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -5975,6 +6157,20 @@ func (i *pdfiumInstance) GetPageText(request *requests.GetPageText) (resp *respo
 	return i.pdfium.GetPageText(request)
 }
 
+func (i *pdfiumInstance) GetPageTextQuality(request *requests.GetPageTextQuality) (resp *responses.GetPageTextQuality, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetPageTextQuality", panicError)
+		}
+	}()
+
+	return i.pdfium.GetPageTextQuality(request)
+}
+
 func (i *pdfiumInstance) GetPageTextStructured(request *requests.GetPageTextStructured) (resp *responses.GetPageTextStructured, err error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -5989,6 +6185,90 @@ func (i *pdfiumInstance) GetPageTextStructured(request *requests.GetPageTextStru
 	return i.pdfium.GetPageTextStructured(request)
 }
 
+func (i *pdfiumInstance) ImportFormData(request *requests.ImportFormData) (resp *responses.ImportFormData, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "ImportFormData", panicError)
+		}
+	}()
+
+	return i.pdfium.ImportFormData(request)
+}
+
+func (i *pdfiumInstance) ImposeBooklet(request *requests.ImposeBooklet) (resp *responses.ImposeBooklet, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "ImposeBooklet", panicError)
+		}
+	}()
+
+	return i.pdfium.ImposeBooklet(request)
+}
+
+func (i *pdfiumInstance) ImposeNUp(request *requests.ImposeNUp) (resp *responses.ImposeNUp, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "ImposeNUp", panicError)
+		}
+	}()
+
+	return i.pdfium.ImposeNUp(request)
+}
+
+func (i *pdfiumInstance) ImposeTiles(request *requests.ImposeTiles) (resp *responses.ImposeTiles, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "ImposeTiles", panicError)
+		}
+	}()
+
+	return i.pdfium.ImposeTiles(request)
+}
+
+func (i *pdfiumInstance) MergeDocuments(request *requests.MergeDocuments) (resp *responses.MergeDocuments, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "MergeDocuments", panicError)
+		}
+	}()
+
+	return i.pdfium.MergeDocuments(request)
+}
+
+func (i *pdfiumInstance) NormalizePages(request *requests.NormalizePages) (resp *responses.NormalizePages, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "NormalizePages", panicError)
+		}
+	}()
+
+	return i.pdfium.NormalizePages(request)
+}
+
 func (i *pdfiumInstance) OpenDocument(request *requests.OpenDocument) (resp *responses.OpenDocument, err error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -6003,6 +6283,34 @@ func (i *pdfiumInstance) OpenDocument(request *requests.OpenDocument) (resp *res
 	return i.pdfium.OpenDocument(request)
 }
 
+func (i *pdfiumInstance) OptimizeImages(request *requests.OptimizeImages) (resp *responses.OptimizeImages, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "OptimizeImages", panicError)
+		}
+	}()
+
+	return i.pdfium.OptimizeImages(request)
+}
+
+func (i *pdfiumInstance) RedactDocument(request *requests.RedactDocument) (resp *responses.RedactDocument, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "RedactDocument", panicError)
+		}
+	}()
+
+	return i.pdfium.RedactDocument(request)
+}
+
 func (i *pdfiumInstance) RenderPageInDPI(request *requests.RenderPageInDPI) (resp *responses.RenderPageInDPI, err error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -6072,3 +6380,73 @@ func (i *pdfiumInstance) RenderToFile(request *requests.RenderToFile) (resp *res
 
 	return i.pdfium.RenderToFile(request)
 }
+
+func (i *pdfiumInstance) ReorderPages(request *requests.ReorderPages) (resp *responses.ReorderPages, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "ReorderPages", panicError)
+		}
+	}()
+
+	return i.pdfium.ReorderPages(request)
+}
+
+func (i *pdfiumInstance) RunFormActions(request *requests.RunFormActions) (resp *responses.RunFormActions, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "RunFormActions", panicError)
+		}
+	}()
+
+	return i.pdfium.RunFormActions(request)
+}
+
+func (i *pdfiumInstance) SearchPageText(request *requests.SearchPageText) (resp *responses.SearchPageText, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "SearchPageText", panicError)
+		}
+	}()
+
+	return i.pdfium.SearchPageText(request)
+}
+
+func (i *pdfiumInstance) SplitDocument(request *requests.SplitDocument) (resp *responses.SplitDocument, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "SplitDocument", panicError)
+		}
+	}()
+
+	return i.pdfium.SplitDocument(request)
+}
+
+func (i *pdfiumInstance) StampDocument(request *requests.StampDocument) (resp *responses.StampDocument, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "StampDocument", panicError)
+		}
+	}()
+
+	return i.pdfium.StampDocument(request)
+}
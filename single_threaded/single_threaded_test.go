@@ -4,6 +4,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/klippa-app/go-pdfium"
 	"github.com/klippa-app/go-pdfium/shared_tests"
 	"github.com/klippa-app/go-pdfium/single_threaded"
 
@@ -18,6 +19,9 @@ var _ = BeforeSuite(func() {
 
 	pool := single_threaded.Init(single_threaded.Config{})
 	shared_tests.PdfiumPool = pool
+	shared_tests.NewPdfiumPool = func() pdfium.Pool {
+		return single_threaded.Init(single_threaded.Config{})
+	}
 
 	instance, err := pool.GetInstance(time.Second * 30)
 	Expect(err).To(BeNil())
@@ -118,6 +118,9 @@ type FPDF_FORMFILLINFO struct {
 	// An application passes this value to the FFI_KillTimer method to kill
 	// the timer. Nonzero if it is successful; otherwise, it is zero.
 	//
+	// The timer function waits until the instance is not busy, so it can't be
+	// called from inside a callback.
+	//
 	// Implementation required!
 	FFI_SetTimer func(elapse int, timerFunc func(idEvent int)) int
 